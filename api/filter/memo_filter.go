@@ -0,0 +1,197 @@
+package filter
+
+import (
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/pkg/errors"
+	v1alpha1 "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+
+	"github.com/usememos/memos/store"
+)
+
+// UserNamePrefix is the prefix of the user resource names, e.g. "users/steven".
+const UserNamePrefix = "users/"
+
+// MemoFilterCELAttributes are the CEL attributes for the memo filter.
+var MemoFilterCELAttributes = []cel.EnvOption{
+	cel.Variable("visibility", cel.StringType),
+	cel.Variable("row_status", cel.StringType),
+	cel.Variable("creator", cel.StringType),
+	cel.Variable("created_ts", cel.IntType),
+	cel.Variable("updated_ts", cel.IntType),
+	cel.Variable("content", cel.StringType),
+	cel.Variable("tag", cel.StringType),
+	cel.Variable("pinned", cel.BoolType),
+	cel.Variable("has_resource", cel.BoolType),
+	// Deprecated: use created_ts with the comparison operators instead.
+	cel.Variable("created_ts_before", cel.IntType),
+	cel.Variable("created_ts_after", cel.IntType),
+}
+
+// celComparisonOperators maps the CEL comparison functions to the memo filter operators.
+var celComparisonOperators = map[string]store.MemoFilterOperator{
+	"_==_": store.MemoFilterEqual,
+	"_!=_": store.MemoFilterNotEqual,
+	"_<_":  store.MemoFilterLess,
+	"_<=_": store.MemoFilterLessEqual,
+	"_>_":  store.MemoFilterGreater,
+	"_>=_": store.MemoFilterGreaterEqual,
+}
+
+// reversedOperators maps the comparison operators to the ones with swapped operands.
+var reversedOperators = map[store.MemoFilterOperator]store.MemoFilterOperator{
+	store.MemoFilterEqual:        store.MemoFilterEqual,
+	store.MemoFilterNotEqual:     store.MemoFilterNotEqual,
+	store.MemoFilterLess:         store.MemoFilterGreater,
+	store.MemoFilterLessEqual:    store.MemoFilterGreaterEqual,
+	store.MemoFilterGreater:      store.MemoFilterLess,
+	store.MemoFilterGreaterEqual: store.MemoFilterLessEqual,
+}
+
+// ParseMemoFilter compiles the CEL expression into a memo filter,
+// e.g. `tag == "work" && (pinned || content.contains("todo")) && !has_resource`.
+// The memos are pinned or not by the user of the ID, who is the current one, and none of them is pinned for 0.
+func ParseMemoFilter(expression string, userID int32) (*store.MemoFilter, error) {
+	e, err := cel.NewEnv(MemoFilterCELAttributes...)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := e.Compile(expression)
+	if issues != nil {
+		return nil, errors.Errorf("found issue %v", issues)
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, errors.Errorf("filter must be a boolean expression, got %v", ast.OutputType())
+	}
+	expr, err := cel.AstToParsedExpr(ast)
+	if err != nil {
+		return nil, err
+	}
+	return convertCELExprToMemoFilter(expr.GetExpr(), userID)
+}
+
+func convertCELExprToMemoFilter(expr *v1alpha1.Expr, userID int32) (*store.MemoFilter, error) {
+	if identExpr := expr.GetIdentExpr(); identExpr != nil {
+		// A bare boolean identifier, e.g. `pinned`.
+		return buildMemoFilterComparison(identExpr.Name, store.MemoFilterEqual, &v1alpha1.Constant{
+			ConstantKind: &v1alpha1.Constant_BoolValue{BoolValue: true},
+		}, userID)
+	}
+	callExpr := expr.GetCallExpr()
+	if callExpr == nil {
+		return nil, errors.New("unsupported expression")
+	}
+
+	switch callExpr.Function {
+	case "_&&_", "_||_":
+		operator := store.MemoFilterAnd
+		if callExpr.Function == "_||_" {
+			operator = store.MemoFilterOr
+		}
+		filter := &store.MemoFilter{Operator: operator}
+		for _, arg := range callExpr.Args {
+			child, err := convertCELExprToMemoFilter(arg, userID)
+			if err != nil {
+				return nil, err
+			}
+			// Flatten the nested operands of the same operator.
+			if child.Operator == operator {
+				filter.Children = append(filter.Children, child.Children...)
+			} else {
+				filter.Children = append(filter.Children, child)
+			}
+		}
+		return filter, nil
+	case "!_":
+		child, err := convertCELExprToMemoFilter(callExpr.Args[0], userID)
+		if err != nil {
+			return nil, err
+		}
+		return &store.MemoFilter{
+			Operator: store.MemoFilterNot,
+			Children: []*store.MemoFilter{child},
+		}, nil
+	case "contains":
+		if callExpr.Target.GetIdentExpr().GetName() != "content" || len(callExpr.Args) != 1 || callExpr.Args[0].GetConstExpr() == nil {
+			return nil, errors.New("contains is only supported as content.contains(string)")
+		}
+		return &store.MemoFilter{
+			Operator: store.MemoFilterContains,
+			Field:    store.MemoFilterFieldContent,
+			Value:    callExpr.Args[0].GetConstExpr().GetStringValue(),
+		}, nil
+	}
+
+	operator, ok := celComparisonOperators[callExpr.Function]
+	if !ok || len(callExpr.Args) != 2 {
+		return nil, errors.Errorf("unsupported function %q", callExpr.Function)
+	}
+	left, right := callExpr.Args[0], callExpr.Args[1]
+	if left.GetConstExpr() != nil && right.GetIdentExpr() != nil {
+		left, right, operator = right, left, reversedOperators[operator]
+	}
+	if left.GetIdentExpr() == nil || right.GetConstExpr() == nil {
+		return nil, errors.New("comparison must be between an identifier and a constant")
+	}
+	return buildMemoFilterComparison(left.GetIdentExpr().Name, operator, right.GetConstExpr(), userID)
+}
+
+func buildMemoFilterComparison(name string, operator store.MemoFilterOperator, value *v1alpha1.Constant, userID int32) (*store.MemoFilter, error) {
+	isEquality := operator == store.MemoFilterEqual || operator == store.MemoFilterNotEqual
+	switch name {
+	case "visibility":
+		visibility := store.Visibility(value.GetStringValue())
+		if visibility != store.Public && visibility != store.Protected && visibility != store.Private {
+			return nil, errors.Errorf("invalid visibility %q", value.GetStringValue())
+		}
+		if isEquality {
+			return &store.MemoFilter{Operator: operator, Field: store.MemoFilterFieldVisibility, Value: visibility}, nil
+		}
+	case "row_status":
+		rowStatus := store.RowStatus(value.GetStringValue())
+		if rowStatus != store.Normal && rowStatus != store.Archived {
+			return nil, errors.Errorf("invalid row status %q", value.GetStringValue())
+		}
+		if isEquality {
+			return &store.MemoFilter{Operator: operator, Field: store.MemoFilterFieldRowStatus, Value: rowStatus}, nil
+		}
+	case "creator":
+		username, ok := strings.CutPrefix(value.GetStringValue(), UserNamePrefix)
+		if !ok || username == "" || strings.Contains(username, "/") {
+			return nil, errors.Errorf("invalid creator %q", value.GetStringValue())
+		}
+		if isEquality {
+			return &store.MemoFilter{Operator: operator, Field: store.MemoFilterFieldCreator, Value: username}, nil
+		}
+	case "created_ts":
+		return &store.MemoFilter{Operator: operator, Field: store.MemoFilterFieldCreatedTs, Value: value.GetInt64Value()}, nil
+	case "updated_ts":
+		return &store.MemoFilter{Operator: operator, Field: store.MemoFilterFieldUpdatedTs, Value: value.GetInt64Value()}, nil
+	case "created_ts_before":
+		if operator == store.MemoFilterEqual {
+			return &store.MemoFilter{Operator: store.MemoFilterLess, Field: store.MemoFilterFieldCreatedTs, Value: value.GetInt64Value()}, nil
+		}
+	case "created_ts_after":
+		if operator == store.MemoFilterEqual {
+			return &store.MemoFilter{Operator: store.MemoFilterGreater, Field: store.MemoFilterFieldCreatedTs, Value: value.GetInt64Value()}, nil
+		}
+	case "tag":
+		if isEquality {
+			return &store.MemoFilter{Operator: operator, Field: store.MemoFilterFieldTag, Value: value.GetStringValue()}, nil
+		}
+	case "pinned":
+		if isEquality {
+			return &store.MemoFilter{Operator: operator, Field: store.MemoFilterFieldPinned, Value: value.GetBoolValue(), UserID: userID}, nil
+		}
+	case "has_resource":
+		if isEquality {
+			return &store.MemoFilter{Operator: operator, Field: store.MemoFilterFieldHasResource, Value: value.GetBoolValue()}, nil
+		}
+	case "content":
+		return nil, errors.New("content only supports content.contains(string)")
+	default:
+		return nil, errors.Errorf("unknown identifier %q", name)
+	}
+	return nil, errors.Errorf("operator %s is not supported on %q", operator, name)
+}
@@ -0,0 +1,88 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/store"
+)
+
+func TestParseMemoFilter(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   *store.MemoFilter
+	}{
+		{
+			expression: `visibility == "PUBLIC"`,
+			expected:   &store.MemoFilter{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldVisibility, Value: store.Public},
+		},
+		{
+			expression: `created_ts_after == 1 && created_ts_before == 2 && 3 >= updated_ts`,
+			expected: &store.MemoFilter{
+				Operator: store.MemoFilterAnd,
+				Children: []*store.MemoFilter{
+					{Operator: store.MemoFilterGreater, Field: store.MemoFilterFieldCreatedTs, Value: int64(1)},
+					{Operator: store.MemoFilterLess, Field: store.MemoFilterFieldCreatedTs, Value: int64(2)},
+					{Operator: store.MemoFilterLessEqual, Field: store.MemoFilterFieldUpdatedTs, Value: int64(3)},
+				},
+			},
+		},
+		{
+			expression: `tag == "work" && (pinned || content.contains("todo")) && !has_resource`,
+			expected: &store.MemoFilter{
+				Operator: store.MemoFilterAnd,
+				Children: []*store.MemoFilter{
+					{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldTag, Value: "work"},
+					{
+						Operator: store.MemoFilterOr,
+						Children: []*store.MemoFilter{
+							{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldPinned, Value: true, UserID: 101},
+							{Operator: store.MemoFilterContains, Field: store.MemoFilterFieldContent, Value: "todo"},
+						},
+					},
+					{
+						Operator: store.MemoFilterNot,
+						Children: []*store.MemoFilter{
+							{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldHasResource, Value: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			expression: `creator != "users/steven" || row_status == "ARCHIVED"`,
+			expected: &store.MemoFilter{
+				Operator: store.MemoFilterOr,
+				Children: []*store.MemoFilter{
+					{Operator: store.MemoFilterNotEqual, Field: store.MemoFilterFieldCreator, Value: "steven"},
+					{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldRowStatus, Value: store.Archived},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		filter, err := ParseMemoFilter(test.expression, 101)
+		require.NoError(t, err, test.expression)
+		require.Equal(t, test.expected, filter, test.expression)
+	}
+}
+
+func TestParseMemoFilterError(t *testing.T) {
+	expressions := []string{
+		`unknown == 1`,
+		`visibility == "SECRET"`,
+		`visibility > "PUBLIC"`,
+		`content == "hello"`,
+		`tag`,
+		`creator == "steven"`,
+		`created_ts_before > 1`,
+		`tag == tag`,
+	}
+
+	for _, expression := range expressions {
+		_, err := ParseMemoFilter(expression, 101)
+		require.Error(t, err, expression)
+	}
+}
@@ -0,0 +1,236 @@
+package resource
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/usememos/memos/internal/log"
+	"github.com/usememos/memos/plugin/preview"
+	"github.com/usememos/memos/plugin/storage"
+	"github.com/usememos/memos/plugin/storage/local"
+	"github.com/usememos/memos/plugin/webp"
+	"github.com/usememos/memos/store"
+)
+
+const (
+	// DefaultPreviewSize is the width of the thumbnails served when the size isn't requested.
+	DefaultPreviewSize = 512
+	// previewQueueSize is the number of the resources waiting for their previews to be generated.
+	previewQueueSize = 256
+	// previewTaskTimeout bounds the time to generate the previews of a resource, including the renderers such as
+	// ffmpeg and pdftoppm, which are killed once it's exceeded, so a malformed file doesn't hold a worker forever.
+	previewTaskTimeout = 2 * time.Minute
+)
+
+// PreviewSizes are the widths of the previews generated for each resource, in ascending order.
+// The images narrower than a size are kept in their own widths.
+var PreviewSizes = []int{256, DefaultPreviewSize, 1024}
+
+// previewTask is a resource to generate the previews of, along with the store of it.
+type previewTask struct {
+	store      *store.Store
+	resourceID int32
+}
+
+var (
+	previewQueue = make(chan previewTask, previewQueueSize)
+	// pendingPreviews are the tasks queued or being generated, which aren't queued again.
+	pendingPreviews sync.Map
+	// failedPreviews are the tasks failed, which aren't queued again until the server restarts.
+	failedPreviews sync.Map
+)
+
+// EnqueueResourcePreviews queues the resource to generate its previews in the background, if they can be rendered.
+// The resource is dropped when the queue is full, as its previews are queued again once they're requested.
+func EnqueueResourcePreviews(s *store.Store, resource *store.Resource) {
+	if !preview.IsSupported(resource.Type) {
+		return
+	}
+	task := previewTask{
+		store:      s,
+		resourceID: resource.ID,
+	}
+	if _, ok := failedPreviews.Load(task); ok {
+		return
+	}
+	if _, loaded := pendingPreviews.LoadOrStore(task, struct{}{}); loaded {
+		return
+	}
+	select {
+	case previewQueue <- task:
+	default:
+		pendingPreviews.Delete(task)
+	}
+}
+
+// RunPreviewWorker generates the previews of the resources queued one by one until the context is done.
+func RunPreviewWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task := <-previewQueue:
+			taskCtx, cancel := context.WithTimeout(ctx, previewTaskTimeout)
+			err := GenerateResourcePreviews(taskCtx, task.store, task.resourceID)
+			cancel()
+			if err != nil {
+				failedPreviews.Store(task, struct{}{})
+				log.Warn("failed to generate resource previews", zap.Int32("resourceId", task.resourceID), zap.Error(err))
+			}
+			pendingPreviews.Delete(task)
+		}
+	}
+}
+
+// GenerateResourcePreviews renders the preview of the resource, and saves it in each of PreviewSizes into the storage of the resource.
+// The WebP variant of each size is saved as well, if it's smaller.
+func GenerateResourcePreviews(ctx context.Context, s *store.Store, resourceID int32) error {
+	resource, err := s.GetResource(ctx, &store.FindResource{ID: &resourceID})
+	if err != nil {
+		return errors.Wrap(err, "failed to find resource")
+	}
+	// The resource is deleted since queued.
+	if resource == nil {
+		return nil
+	}
+	backend, key, err := GetResourceBackend(ctx, s, resource)
+	if err != nil {
+		return err
+	}
+	if backend == nil {
+		return nil
+	}
+
+	reader, err := backend.Get(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "failed to read resource")
+	}
+	defer reader.Close()
+	src, err := preview.Render(ctx, reader, resource.Type, PreviewSizes[len(PreviewSizes)-1])
+	if err != nil {
+		return err
+	}
+
+	previewBackend := getPreviewBackend(s, backend)
+	format := getPreviewFormat(resource.Type)
+	for _, size := range PreviewSizes {
+		img := src
+		if src.Bounds().Dx() > size {
+			img = imaging.Resize(src, size, 0, imaging.Lanczos)
+		}
+		blob := &bytes.Buffer{}
+		if err := imaging.Encode(blob, img, format, imaging.JPEGQuality(85)); err != nil {
+			return errors.Wrap(err, "failed to encode preview")
+		}
+		if err := putPreview(ctx, previewBackend, getResourcePreviewKey(resource.ID, size, getPreviewFormatName(format)), blob.Bytes()); err != nil {
+			return err
+		}
+
+		webpKey := getResourcePreviewKey(resource.ID, size, "webp")
+		webpBlob := &bytes.Buffer{}
+		if err := webp.Encode(webpBlob, img); err != nil || webpBlob.Len() >= blob.Len() {
+			// The variant left by a former generation is deleted.
+			if err := previewBackend.Delete(ctx, webpKey); err != nil {
+				return errors.Wrap(err, "failed to delete preview")
+			}
+			continue
+		}
+		if err := putPreview(ctx, previewBackend, webpKey, webpBlob.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteResourcePreviews deletes the previews of the resource from its storage.
+func DeleteResourcePreviews(ctx context.Context, s *store.Store, resource *store.Resource) error {
+	backend, _, err := GetResourceBackend(ctx, s, resource)
+	if err != nil {
+		return err
+	}
+	if backend == nil {
+		return nil
+	}
+	previewBackend := getPreviewBackend(s, backend)
+	for _, size := range PreviewSizes {
+		for _, formatName := range []string{"jpeg", "png", "webp"} {
+			if err := previewBackend.Delete(ctx, getResourcePreviewKey(resource.ID, size, formatName)); err != nil {
+				return errors.Wrap(err, "failed to delete preview")
+			}
+		}
+	}
+	return nil
+}
+
+// openResourcePreview opens the preview of the resource closest to the size, preferring WebP if it's accepted.
+// It returns the preview with its size and format name, or ok as false if the preview isn't generated yet.
+func openResourcePreview(ctx context.Context, s *store.Store, backend storage.Backend, resource *store.Resource, size int, acceptWebP bool) (*storage.ReadSeeker, int, string, bool, error) {
+	previewSize := PreviewSizes[len(PreviewSizes)-1]
+	for _, candidate := range PreviewSizes {
+		if candidate >= size {
+			previewSize = candidate
+			break
+		}
+	}
+	formatNames := []string{getPreviewFormatName(getPreviewFormat(resource.Type))}
+	if acceptWebP {
+		formatNames = append([]string{"webp"}, formatNames...)
+	}
+
+	previewBackend := getPreviewBackend(s, backend)
+	for _, formatName := range formatNames {
+		key := getResourcePreviewKey(resource.ID, previewSize, formatName)
+		objectInfo, err := previewBackend.Stat(ctx, key)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				continue
+			}
+			return nil, 0, "", false, err
+		}
+		return storage.NewReadSeeker(ctx, previewBackend, key, objectInfo.Size), previewSize, formatName, true, nil
+	}
+	return nil, 0, "", false, nil
+}
+
+// getPreviewBackend returns the backend of the previews of the resources in the backend,
+// which is the local storage for the resources in the database.
+func getPreviewBackend(s *store.Store, backend storage.Backend) storage.Backend {
+	if _, ok := backend.(*databaseBackend); ok {
+		return local.New(s.Profile.Data)
+	}
+	return backend
+}
+
+// getPreviewFormat returns PNG for the images which may be transparent, and JPEG for the others.
+func getPreviewFormat(mimeType string) imaging.Format {
+	switch strings.ToLower(mimeType) {
+	case "image/png", "image/gif", "image/webp":
+		return imaging.PNG
+	default:
+		return imaging.JPEG
+	}
+}
+
+func getPreviewFormatName(format imaging.Format) string {
+	return strings.ToLower(format.String())
+}
+
+func getResourcePreviewKey(resourceID int32, size int, formatName string) string {
+	return fmt.Sprintf("%s/%d/%d.%s", thumbnailImagePath, resourceID, size, formatName)
+}
+
+func putPreview(ctx context.Context, backend storage.Backend, key string, blob []byte) error {
+	contentType := "image/" + key[strings.LastIndex(key, ".")+1:]
+	if err := backend.Put(ctx, key, bytes.NewReader(blob), int64(len(blob)), contentType); err != nil {
+		return errors.Wrap(err, "failed to save preview")
+	}
+	return nil
+}
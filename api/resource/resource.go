@@ -2,23 +2,23 @@ package resource
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"path"
-	"path/filepath"
+	"strconv"
 	"strings"
-	"sync/atomic"
 	"time"
 
-	"github.com/disintegration/imaging"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"github.com/usememos/memos/internal/log"
 	"github.com/usememos/memos/internal/util"
+	"github.com/usememos/memos/plugin/preview"
+	"github.com/usememos/memos/plugin/storage"
+	"github.com/usememos/memos/plugin/storage/s3"
 	"github.com/usememos/memos/server/profile"
 	"github.com/usememos/memos/store"
 )
@@ -27,7 +27,7 @@ const (
 	// The key name used to store user id in the context
 	// user id is extracted from the jwt token subject field.
 	userIDContextKey = "user-id"
-	// thumbnailImagePath is the directory to store image thumbnails.
+	// thumbnailImagePath is the directory of the previews of the resources in their storages.
 	thumbnailImagePath = ".thumbnail_cache"
 )
 
@@ -56,8 +56,7 @@ func (s *Service) streamResource(c echo.Context) error {
 	}
 
 	resource, err := s.Store.GetResource(ctx, &store.FindResource{
-		ID:      &resourceID,
-		GetBlob: true,
+		ID: &resourceID,
 	})
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find resource by ID: %v", resourceID)).SetInternal(err)
@@ -65,101 +64,126 @@ func (s *Service) streamResource(c echo.Context) error {
 	if resource == nil {
 		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Resource not found: %d", resourceID))
 	}
+	cacheControl := "max-age=31536000, immutable"
 	// Check the related memo visibility.
 	if resource.MemoID != nil {
-		memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
-			ID: resource.MemoID,
-		})
+		memo, err := findResourceMemo(ctx, s.Store, *resource.MemoID)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find memo by ID: %v", resource.MemoID)).SetInternal(err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find memo by ID: %v", *resource.MemoID)).SetInternal(err)
 		}
-		if memo != nil && memo.Visibility != store.Public {
+		// The resources of the missing memos and the memos in the trash are only served to their creators, like the private ones.
+		creatorOnly, creatorID := memo == nil, resource.CreatorID
+		if memo != nil {
+			creatorOnly, creatorID = memo.RowStatus == store.Deleted || memo.Visibility == store.Private, memo.CreatorID
+		}
+		if creatorOnly || memo.Visibility != store.Public {
 			userID, ok := c.Get(userIDContextKey).(int32)
-			if !ok || (memo.Visibility == store.Private && userID != resource.CreatorID) {
+			if !ok || (creatorOnly && userID != creatorID) {
 				return echo.NewHTTPError(http.StatusUnauthorized, "Resource visibility not match")
 			}
+			// The shared caches must not keep the resources of the non-public memos.
+			cacheControl = "private, max-age=31536000, immutable"
 		}
 	}
 
-	blob := resource.Blob
-	if resource.InternalPath != "" {
-		resourcePath := resource.InternalPath
-		src, err := os.Open(resourcePath)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to open the local resource: %s", resourcePath)).SetInternal(err)
-		}
-		defer src.Close()
-		blob, err = io.ReadAll(src)
+	backend, key, err := GetResourceBackend(ctx, s.Store, resource)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find the storage of resource: %d", resourceID)).SetInternal(err)
+	}
+	isThumbnail := c.QueryParam("thumbnail") == "1" && preview.IsSupported(resource.Type)
+	// The private S3 objects are redirected to their presigned URLs if enabled, except the thumbnails.
+	if s3Client, ok := backend.(*s3.Client); ok && s3Client.Config.Private && s3Client.Config.PresignExpiry > 0 && !isThumbnail {
+		link, err := s3Client.PresignGetObject(ctx, key, time.Duration(s3Client.Config.PresignExpiry)*time.Second)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to read the local resource: %s", resourcePath)).SetInternal(err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to presign the resource: %d", resourceID)).SetInternal(err)
 		}
+		// The presigned URLs expire, so the redirections aren't cached.
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+		return c.Redirect(http.StatusFound, link)
 	}
 
-	if c.QueryParam("thumbnail") == "1" && util.HasPrefixes(resource.Type, "image/png", "image/jpeg") {
-		ext := filepath.Ext(resource.Filename)
-		thumbnailPath := filepath.Join(s.Profile.Data, thumbnailImagePath, fmt.Sprintf("%d%s", resource.ID, ext))
-		thumbnailBlob, err := getOrGenerateThumbnailImage(blob, thumbnailPath)
+	header := c.Response().Header()
+	etag := getResourceETag(resource)
+	resourceType := strings.ToLower(resource.Type)
+	var content io.ReadSeeker
+	if isThumbnail && backend != nil {
+		// The thumbnails are negotiated by the Accept header.
+		header.Add(echo.HeaderVary, echo.HeaderAccept)
+		size := DefaultPreviewSize
+		if value, err := strconv.Atoi(c.QueryParam("size")); err == nil && value > 0 {
+			size = value
+		}
+		acceptWebP := strings.Contains(c.Request().Header.Get(echo.HeaderAccept), "image/webp")
+		reader, previewSize, formatName, ok, err := openResourcePreview(ctx, s.Store, backend, resource, size, acceptWebP)
 		if err != nil {
-			log.Warn(fmt.Sprintf("failed to get or generate local thumbnail with path %s", thumbnailPath), zap.Error(err))
+			log.Warn(fmt.Sprintf("failed to open the previews of resource %d", resource.ID), zap.Error(err))
+		}
+		if ok {
+			defer reader.Close()
+			content = reader
+			etag = strings.TrimSuffix(etag, `"`) + fmt.Sprintf(`-thumbnail-%d.%s"`, previewSize, formatName)
+			resourceType = "image/" + formatName
 		} else {
-			blob = thumbnailBlob
+			// The previews are generated in the background, and the resource itself is served meanwhile.
+			EnqueueResourcePreviews(s.Store, resource)
 		}
 	}
 
-	c.Response().Writer.Header().Set(echo.HeaderCacheControl, "max-age=31536000, immutable")
-	c.Response().Writer.Header().Set(echo.HeaderContentSecurityPolicy, "default-src 'self'")
-	resourceType := strings.ToLower(resource.Type)
+	// The blob is read by the ranges requested, including the blob in the database.
+	if content == nil {
+		content = bytes.NewReader(nil)
+		if backend != nil {
+			objectInfo, err := backend.Stat(ctx, key)
+			if err != nil {
+				if errors.Is(err, storage.ErrNotFound) {
+					return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Resource blob not found: %d", resourceID)).SetInternal(err)
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to open the resource: %d", resourceID)).SetInternal(err)
+			}
+			reader := storage.NewReadSeeker(ctx, backend, key, objectInfo.Size)
+			defer reader.Close()
+			content = reader
+		}
+	}
+
+	header.Set(echo.HeaderCacheControl, cacheControl)
+	header.Set(echo.HeaderContentSecurityPolicy, "default-src 'self'")
+	// The browsers don't sniff the blob as another type than the one sniffed on upload.
+	header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+	header.Set("ETag", etag)
 	if strings.HasPrefix(resourceType, "text") {
 		resourceType = echo.MIMETextPlainCharsetUTF8
-	} else if strings.HasPrefix(resourceType, "video") || strings.HasPrefix(resourceType, "audio") {
-		http.ServeContent(c.Response(), c.Request(), resource.Filename, time.Unix(resource.UpdatedTs, 0), bytes.NewReader(blob))
-		return nil
 	}
-	c.Response().Writer.Header().Set("Content-Disposition", fmt.Sprintf(`filename="%s"`, resource.Filename))
-	return c.Stream(http.StatusOK, resourceType, bytes.NewReader(blob))
+	if resourceType != "" {
+		header.Set(echo.HeaderContentType, resourceType)
+	}
+	if !strings.HasPrefix(resourceType, "video") && !strings.HasPrefix(resourceType, "audio") {
+		header.Set("Content-Disposition", fmt.Sprintf(`filename="%s"`, resource.Filename))
+	}
+	// The ranges and the conditional requests of If-None-Match and If-Modified-Since are handled by ServeContent.
+	http.ServeContent(c.Response(), c.Request(), resource.Filename, time.Unix(resource.UpdatedTs, 0), content)
+	return nil
 }
 
-var availableGeneratorAmount int32 = 32
-
-func getOrGenerateThumbnailImage(srcBlob []byte, dstPath string) ([]byte, error) {
-	if _, err := os.Stat(dstPath); err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			return nil, errors.Wrap(err, "failed to check thumbnail image stat")
-		}
-
-		if atomic.LoadInt32(&availableGeneratorAmount) <= 0 {
-			return nil, errors.New("not enough available generator amount")
-		}
-		atomic.AddInt32(&availableGeneratorAmount, -1)
-		defer func() {
-			atomic.AddInt32(&availableGeneratorAmount, 1)
-		}()
-
-		reader := bytes.NewReader(srcBlob)
-		src, err := imaging.Decode(reader, imaging.AutoOrientation(true))
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to decode thumbnail image")
-		}
-		thumbnailImage := imaging.Resize(src, 512, 0, imaging.Lanczos)
-
-		dstDir := path.Dir(dstPath)
-		if err := os.MkdirAll(dstDir, os.ModePerm); err != nil {
-			return nil, errors.Wrap(err, "failed to create thumbnail dir")
-		}
-
-		if err := imaging.Save(thumbnailImage, dstPath); err != nil {
-			return nil, errors.Wrap(err, "failed to resize thumbnail image")
-		}
+// findResourceMemo returns the memo of the resource, including the memo in the trash.
+func findResourceMemo(ctx context.Context, s *store.Store, memoID int32) (*store.Memo, error) {
+	memo, err := s.GetMemo(ctx, &store.FindMemo{
+		ID: &memoID,
+	})
+	if err != nil || memo != nil {
+		return memo, err
 	}
+	deletedStatus := store.Deleted
+	return s.GetMemo(ctx, &store.FindMemo{
+		ID:        &memoID,
+		RowStatus: &deletedStatus,
+	})
+}
 
-	dstFile, err := os.Open(dstPath)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to open the local resource")
-	}
-	defer dstFile.Close()
-	dstBlob, err := io.ReadAll(dstFile)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to read the local resource")
+// getResourceETag returns the strong ETag of the resource, by its hash or by its version if it's saved before the hashes.
+func getResourceETag(resource *store.Resource) string {
+	if resource.Hash != "" {
+		return fmt.Sprintf(`"%s"`, resource.Hash)
 	}
-	return dstBlob, nil
+	return fmt.Sprintf(`"%d-%d-%d"`, resource.ID, resource.UpdatedTs, resource.Size)
 }
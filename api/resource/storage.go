@@ -0,0 +1,286 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/plugin/storage"
+	"github.com/usememos/memos/plugin/storage/local"
+	"github.com/usememos/memos/plugin/storage/s3"
+	"github.com/usememos/memos/plugin/storage/sftp"
+	"github.com/usememos/memos/plugin/storage/webdav"
+	"github.com/usememos/memos/store"
+)
+
+// The storage types are the same as the ones of api/v1.
+const (
+	storageTypeS3     = "S3"
+	storageTypeWebDAV = "WEBDAV"
+	storageTypeSFTP   = "SFTP"
+)
+
+// NewStorageBackend creates the backend of the storage by its type.
+func NewStorageBackend(ctx context.Context, storage *store.Storage) (storage.Backend, error) {
+	switch storage.Type {
+	case storageTypeS3:
+		config := &s3.Config{}
+		if err := json.Unmarshal([]byte(storage.Config), config); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal s3 config")
+		}
+		return s3.NewClient(ctx, config)
+	case storageTypeWebDAV:
+		config := &webdav.Config{}
+		if err := json.Unmarshal([]byte(storage.Config), config); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal webdav config")
+		}
+		return webdav.NewClient(config)
+	case storageTypeSFTP:
+		config := &sftp.Config{}
+		if err := json.Unmarshal([]byte(storage.Config), config); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal sftp config")
+		}
+		return sftp.NewClient(config)
+	default:
+		return nil, errors.Errorf("unsupported storage type: %s", storage.Type)
+	}
+}
+
+// DeleteResourceWithBlob deletes the resource, and its blob in the storage such as S3, WebDAV and SFTP if it was the last
+// resource referencing it. The blob is deleted after the resource, so that no resource can start sharing it in between.
+// The blob in the local storage is deleted by the store.
+func DeleteResourceWithBlob(ctx context.Context, s *store.Store, resource *store.Resource) error {
+	last, err := s.DeleteResource(ctx, &store.DeleteResource{
+		ID: resource.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if resource.ExternalLink == "" && resource.StorageID == 0 {
+		return nil
+	}
+
+	backend, key, err := GetResourceBackend(ctx, s, resource)
+	if err != nil {
+		return err
+	}
+	if backend == nil {
+		return nil
+	}
+	if last {
+		if err := backend.Delete(ctx, key); err != nil {
+			return errors.Wrap(err, "failed to delete blob from storage")
+		}
+	}
+	if err := DeleteResourcePreviews(ctx, s, resource); err != nil {
+		return errors.Wrap(err, "failed to delete resource previews")
+	}
+	return nil
+}
+
+// GetResourceBackend returns the backend in which the blob of the resource is, and the key of the blob in it.
+// The backend is nil if the resource is an external link out of the storages.
+func GetResourceBackend(ctx context.Context, s *store.Store, resource *store.Resource) (storage.Backend, string, error) {
+	if resource.StorageID != 0 {
+		storage, err := s.GetStorage(ctx, &store.FindStorage{ID: &resource.StorageID})
+		if err != nil {
+			return nil, "", errors.Wrap(err, "failed to find storage")
+		}
+		if storage == nil {
+			return nil, "", errors.Errorf("storage %d not found", resource.StorageID)
+		}
+		backend, err := NewStorageBackend(ctx, storage)
+		if err != nil {
+			return nil, "", err
+		}
+		return backend, resource.InternalPath, nil
+	}
+	if resource.InternalPath != "" {
+		return local.New(s.Profile.Data), resource.InternalPath, nil
+	}
+	if resource.ExternalLink == "" {
+		return &databaseBackend{store: s}, strconv.Itoa(int(resource.ID)), nil
+	}
+
+	_, s3Client, key, err := FindS3Object(ctx, s, resource.ExternalLink)
+	if err != nil {
+		return nil, "", err
+	}
+	if s3Client == nil {
+		return nil, "", nil
+	}
+	return s3Client, key, nil
+}
+
+// FindS3Object finds the S3 storage in which the link is an object, and returns the storage, its client and the key of the object.
+// The storage is nil if the link is out of the storages, e.g. an external link.
+func FindS3Object(ctx context.Context, s *store.Store, link string) (*store.Storage, *s3.Client, string, error) {
+	storages, err := s.ListStorages(ctx, &store.FindStorage{})
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "failed to list storages")
+	}
+	for _, storage := range storages {
+		if storage.Type != storageTypeS3 {
+			continue
+		}
+		backend, err := NewStorageBackend(ctx, storage)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		s3Client := backend.(*s3.Client)
+		key, ok := s3Client.GetObjectKey(link)
+		if !ok {
+			continue
+		}
+		return storage, s3Client, key, nil
+	}
+	return nil, nil, "", nil
+}
+
+// databaseBackend keeps the blobs in the resource table, keyed by the resource ID.
+// The blob is put by the resource creation, so Put only replaces the blob of an existing resource.
+// The blob shared by the resources of the same hash is read from the one holding it.
+type databaseBackend struct {
+	store *store.Store
+}
+
+func (b *databaseBackend) Put(ctx context.Context, key string, r io.Reader, _ int64, _ string) error {
+	id, err := parseResourceKey(key)
+	if err != nil {
+		return err
+	}
+	blob, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if blob == nil {
+		blob = []byte{}
+	}
+	_, err = b.store.UpdateResource(ctx, &store.UpdateResource{
+		ID:   id,
+		Blob: blob,
+	})
+	return err
+}
+
+func (b *databaseBackend) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return b.GetRange(ctx, key, 0, -1)
+}
+
+// GetRange reads the blob by the chunks of the range, each of which is read by the database.
+func (b *databaseBackend) GetRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	resource, err := b.getResource(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	holderID, size, err := b.store.GetResourceBlobHolder(ctx, resource)
+	if err != nil {
+		return nil, err
+	}
+	end := size
+	if length >= 0 && offset+length < size {
+		end = offset + length
+	}
+	return io.NopCloser(&databaseBlobReader{
+		ctx:    ctx,
+		store:  b.store,
+		id:     holderID,
+		offset: offset,
+		end:    end,
+	}), nil
+}
+
+// Delete clears the blob, as the resource itself is deleted by the store.
+func (b *databaseBackend) Delete(ctx context.Context, key string) error {
+	id, err := parseResourceKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = b.store.UpdateResource(ctx, &store.UpdateResource{
+		ID:   id,
+		Blob: []byte{},
+	})
+	return err
+}
+
+func (b *databaseBackend) Stat(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	resource, err := b.getResource(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	_, size, err := b.store.GetResourceBlobHolder(ctx, resource)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.ObjectInfo{
+		Size:    size,
+		ModTime: time.Unix(resource.UpdatedTs, 0),
+	}, nil
+}
+
+func (b *databaseBackend) getResource(ctx context.Context, key string) (*store.Resource, error) {
+	id, err := parseResourceKey(key)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := b.store.GetResource(ctx, &store.FindResource{
+		ID: &id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resource == nil {
+		return nil, storage.ErrNotFound
+	}
+	return resource, nil
+}
+
+func parseResourceKey(key string) (int32, error) {
+	id, err := strconv.ParseInt(key, 10, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid resource key %s", key)
+	}
+	return int32(id), nil
+}
+
+// databaseBlobChunkSize is the size of the chunks the blobs in the database are read by.
+const databaseBlobChunkSize = 1 << 20
+
+// databaseBlobReader reads the blob of the resource from the offset to the end by the chunks.
+type databaseBlobReader struct {
+	ctx    context.Context
+	store  *store.Store
+	id     int32
+	offset int64
+	end    int64
+	chunk  []byte
+}
+
+func (r *databaseBlobReader) Read(p []byte) (int, error) {
+	if len(r.chunk) == 0 {
+		if r.offset >= r.end {
+			return 0, io.EOF
+		}
+		blob, err := r.store.GetResourceBlobRange(r.ctx, &store.FindResourceBlob{
+			ID:     r.id,
+			Offset: r.offset,
+			Length: min(r.end-r.offset, databaseBlobChunkSize),
+		})
+		if err != nil {
+			return 0, err
+		}
+		// The blob is changed or deleted in the meantime.
+		if blob == nil || len(blob.Blob) == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		r.chunk = blob.Blob
+		r.offset += int64(len(blob.Blob))
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
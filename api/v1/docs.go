@@ -183,6 +183,31 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/export": {
+            "get": {
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export the memos and resources of the current user as a ZIP archive",
+                "responses": {
+                    "200": {
+                        "description": "ZIP archive of Markdown files, resource files and manifest.json",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to export user data"
+                    }
+                }
+            }
+        },
         "/api/v1/idp": {
             "get": {
                 "description": "*clientSecret is only available for host user",
@@ -377,6 +402,111 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/import": {
+            "post": {
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Import an export archive into the current user",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Export archive",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Counts of the imported memos, resources and relations",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Upload file not found | Failed to import user data"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to open file"
+                    }
+                }
+            }
+        },
+        "/api/v1/import/{source}": {
+            "post": {
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Import the export of another app, i.e. flomo, obsidian, keep or enex",
+                "parameters": [
+                    {
+                        "enum": [
+                            "flomo",
+                            "obsidian",
+                            "keep",
+                            "enex"
+                        ],
+                        "type": "string",
+                        "description": "Source format",
+                        "name": "source",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "ZIP archive of the export, or the ENEX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Username of the user to import into, defaults to the current user",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report what would be imported",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import report",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ImportSourceReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported import source | Upload file not found | Failed to parse export | User not found"
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to open file | Failed to import"
+                    }
+                }
+            }
+        },
         "/api/v1/memo": {
             "get": {
                 "produces": [
@@ -402,7 +532,8 @@ const docTemplate = `{
                     {
                         "enum": [
                             "NORMAL",
-                            "ARCHIVED"
+                            "ARCHIVED",
+                            "DELETED"
                         ],
                         "type": "string",
                         "description": "Row status",
@@ -453,6 +584,9 @@ const docTemplate = `{
                     "400": {
                         "description": "Missing user to find memo"
                     },
+                    "403": {
+                        "description": "Only the creator can list the memos in the trash"
+                    },
                     "500": {
                         "description": "Failed to get memo display with updated ts setting value | Failed to fetch memo list | Failed to compose memo response"
                     }
@@ -632,6 +766,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "description": "The memo is moved to the trash, and it's deleted permanently if it's already in the trash.",
                 "produces": [
                     "application/json"
                 ],
@@ -914,6 +1049,46 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/memo/{memoId}/restore": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memo"
+                ],
+                "summary": "Restore memo from the trash by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Memo ID to restore",
+                        "name": "memoId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored memo",
+                        "schema": {
+                            "$ref": "#/definitions/store.Memo"
+                        }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "404": {
+                        "description": "Memo not found in trash: %d"
+                    },
+                    "500": {
+                        "description": "Failed to find memo | Failed to restore memo | Failed to compose memo response"
+                    }
+                }
+            }
+        },
         "/api/v1/ping": {
             "get": {
                 "produces": [
@@ -1044,7 +1219,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Upload file not found | File size exceeds allowed limit of %d MiB | Failed to parse upload data"
+                        "description": "Upload file not found | File size exceeds allowed limit of %d MiB | Failed to parse upload data | Failed to strip the metadata of the image"
                     },
                     "401": {
                         "description": "Missing user in session"
@@ -1055,6 +1230,227 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/resource/upload": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resource"
+                ],
+                "summary": "Create a resumable upload of a resource",
+                "parameters": [
+                    {
+                        "description": "Request object.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateResourceUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Created resource upload",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ResourceUpload"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted create resource upload request | Invalid size | File size exceeds allowed limit of %d MiB"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to find storage service | Failed to create resource upload"
+                    }
+                }
+            }
+        },
+        "/api/v1/resource/upload/{uploadId}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resource"
+                ],
+                "summary": "Cancel a resource upload",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Upload ID",
+                        "name": "uploadId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource upload deleted",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "404": {
+                        "description": "Resource upload not found: %d"
+                    },
+                    "409": {
+                        "description": "Resource upload is in progress"
+                    },
+                    "500": {
+                        "description": "Failed to find resource upload | Failed to delete resource upload"
+                    }
+                }
+            },
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resource"
+                ],
+                "summary": "Get the offset of a resource upload",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Upload ID",
+                        "name": "uploadId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource upload",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ResourceUpload"
+                        }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "404": {
+                        "description": "Resource upload not found: %d"
+                    },
+                    "500": {
+                        "description": "Failed to find resource upload"
+                    }
+                }
+            },
+            "patch": {
+                "description": "The chunk is the request body, which starts at the Upload-Offset header. It must be the offset of the upload.",
+                "consumes": [
+                    "application/offset+octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resource"
+                ],
+                "summary": "Upload a chunk of a resource upload",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Upload ID",
+                        "name": "uploadId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of the chunk",
+                        "name": "Upload-Offset",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource upload",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ResourceUpload"
+                        }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s | Invalid Upload-Offset"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "404": {
+                        "description": "Resource upload not found: %d"
+                    },
+                    "409": {
+                        "description": "Resource upload is in progress | Upload-Offset mismatches the offset %d"
+                    },
+                    "413": {
+                        "description": "Chunk exceeds the upload size"
+                    },
+                    "500": {
+                        "description": "Failed to find resource upload | Failed to save chunk | Failed to update resource upload"
+                    }
+                }
+            }
+        },
+        "/api/v1/resource/upload/{uploadId}/finalize": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resource"
+                ],
+                "summary": "Finalize a resource upload into a resource",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Upload ID",
+                        "name": "uploadId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Created resource",
+                        "schema": {
+                            "$ref": "#/definitions/store.Resource"
+                        }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s | Failed to strip the metadata of the image"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "404": {
+                        "description": "Resource upload not found: %d"
+                    },
+                    "409": {
+                        "description": "Resource upload is in progress | Resource upload is incomplete, %d of %d bytes received"
+                    },
+                    "500": {
+                        "description": "Failed to find resource upload | Failed to finalize resource upload"
+                    }
+                }
+            }
+        },
         "/api/v1/resource/{resourceId}": {
             "delete": {
                 "produces": [
@@ -1224,7 +1620,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Malformatted post storage request"
+                        "description": "Malformatted post storage request | Host key is required for SFTP storage unless insecureIgnoreHostKey is set"
                     },
                     "401": {
                         "description": "Missing user in session"
@@ -1305,7 +1701,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s | Malformatted patch storage request | Malformatted post storage request"
+                        "description": "ID is not a number: %s | Malformatted patch storage request | Malformatted post storage request | Host key is required for SFTP storage unless insecureIgnoreHostKey is set"
                     },
                     "401": {
                         "description": "Missing user in session | Unauthorized"
@@ -1914,12 +2310,48 @@ const docTemplate = `{
                         "description": "Thumbnail",
                         "name": "thumbnail",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Width of the thumbnail, rounded up to 256, 512 or 1024",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "image/webp to prefer the WebP thumbnail",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte ranges of the resource",
+                        "name": "Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the cached resource",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the cached resource",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Requested resource"
                     },
+                    "206": {
+                        "description": "Requested ranges of resource"
+                    },
+                    "304": {
+                        "description": "Resource not modified"
+                    },
                     "400": {
                         "description": "ID is not a number: %s | Failed to get resource visibility"
                     },
@@ -1927,10 +2359,13 @@ const docTemplate = `{
                         "description": "Resource visibility not match"
                     },
                     "404": {
-                        "description": "Resource not found: %d"
+                        "description": "Resource not found: %d | Resource blob not found: %d"
+                    },
+                    "416": {
+                        "description": "Requested range not satisfiable"
                     },
                     "500": {
-                        "description": "Failed to find resource by ID: %v | Failed to open the local resource: %s | Failed to read the local resource: %s"
+                        "description": "Failed to find resource by ID: %v | Failed to open the resource: %d"
                     }
                 }
             }
@@ -1951,6 +2386,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a saved filter of the user, which requires the access token of the user",
+                        "name": "filter",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1960,8 +2401,11 @@ const docTemplate = `{
                     "400": {
                         "description": "User id is not a number"
                     },
+                    "404": {
+                        "description": "Saved filter not found"
+                    },
                     "500": {
-                        "description": "Failed to get system customized profile | Failed to find memo list | Failed to generate rss"
+                        "description": "Failed to get system customized profile | Failed to find access token user | Failed to find saved filters | Failed to parse saved filter | Failed to find memo list | Failed to generate rss"
                     }
                 }
             }
@@ -1986,7 +2430,7 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "driver": {
-                    "description": "Driver is the database driver\nsqlite, mysql",
+                    "description": "Driver is the database driver\nsqlite, mysql, postgres",
                     "type": "string"
                 },
                 "dsn": {
@@ -2219,11 +2663,13 @@ const docTemplate = `{
             "type": "string",
             "enum": [
                 "NORMAL",
-                "ARCHIVED"
+                "ARCHIVED",
+                "DELETED"
             ],
             "x-enum-varnames": [
                 "Normal",
-                "Archived"
+                "Archived",
+                "Deleted"
             ]
         },
         "store.Storage": {
@@ -2374,6 +2820,20 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.CreateResourceUploadRequest": {
+            "type": "object",
+            "properties": {
+                "filename": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.CreateStorageRequest": {
             "type": "object",
             "properties": {
@@ -2525,6 +2985,46 @@ const docTemplate = `{
                 "IdentityProviderOAuth2Type"
             ]
         },
+        "v1.ImportResult": {
+            "type": "object",
+            "properties": {
+                "memoCount": {
+                    "type": "integer"
+                },
+                "relationCount": {
+                    "type": "integer"
+                },
+                "resourceCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.ImportSourceReport": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "memoCount": {
+                    "type": "integer"
+                },
+                "relationCount": {
+                    "type": "integer"
+                },
+                "resourceCount": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "v1.MemoRelationType": {
             "type": "string",
             "enum": [
@@ -2571,6 +3071,38 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.ResourceUpload": {
+            "type": "object",
+            "properties": {
+                "createdTs": {
+                    "type": "integer"
+                },
+                "creatorId": {
+                    "description": "Standard fields",
+                    "type": "integer"
+                },
+                "filename": {
+                    "description": "Domain specific fields",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "description": "Offset is the size received, where the next chunk starts.",
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updatedTs": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.Role": {
             "type": "string",
             "enum": [
@@ -2588,11 +3120,13 @@ const docTemplate = `{
             "type": "string",
             "enum": [
                 "NORMAL",
-                "ARCHIVED"
+                "ARCHIVED",
+                "DELETED"
             ],
             "x-enum-varnames": [
                 "Normal",
-                "Archived"
+                "Archived",
+                "Deleted"
             ]
         },
         "v1.SSOSignIn": {
@@ -2636,6 +3170,12 @@ const docTemplate = `{
             "properties": {
                 "s3Config": {
                     "$ref": "#/definitions/v1.StorageS3Config"
+                },
+                "sftpConfig": {
+                    "$ref": "#/definitions/v1.StorageSFTPConfig"
+                },
+                "webdavConfig": {
+                    "$ref": "#/definitions/v1.StorageWebDAVConfig"
                 }
             }
         },
@@ -2654,6 +3194,14 @@ const docTemplate = `{
                 "path": {
                     "type": "string"
                 },
+                "presignExpiry": {
+                    "description": "PresignExpiry is the seconds of the presigned URLs the private objects are redirected to, or 0 to proxy them.",
+                    "type": "integer"
+                },
+                "private": {
+                    "description": "Private keeps the objects private, which are served through /o/r/:resourceId after the visibility check.",
+                    "type": "boolean"
+                },
                 "region": {
                     "type": "string"
                 },
@@ -2668,15 +3216,66 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.StorageSFTPConfig": {
+            "type": "object",
+            "properties": {
+                "host": {
+                    "type": "string"
+                },
+                "hostKey": {
+                    "description": "HostKey is required unless InsecureIgnoreHostKey is set explicitly.",
+                    "type": "string"
+                },
+                "insecureIgnoreHostKey": {
+                    "type": "boolean"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "privateKey": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.StorageType": {
             "type": "string",
             "enum": [
-                "S3"
+                "S3",
+                "WEBDAV",
+                "SFTP"
             ],
             "x-enum-varnames": [
-                "StorageS3"
+                "StorageS3",
+                "StorageWebDAV",
+                "StorageSFTP"
             ]
         },
+        "v1.StorageWebDAVConfig": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.SystemSetting": {
             "type": "object",
             "properties": {
@@ -2708,7 +3307,10 @@ const docTemplate = `{
                 "local-storage-path",
                 "telegram-bot-token",
                 "memo-display-with-updated-ts",
-                "auto-backup-interval"
+                "auto-backup-interval",
+                "memo-revision-retention",
+                "trash-retention",
+                "strip-image-metadata"
             ],
             "x-enum-varnames": [
                 "SystemSettingServerIDName",
@@ -2724,7 +3326,10 @@ const docTemplate = `{
                 "SystemSettingLocalStoragePathName",
                 "SystemSettingTelegramBotTokenName",
                 "SystemSettingMemoDisplayWithUpdatedTsName",
-                "SystemSettingAutoBackupIntervalName"
+                "SystemSettingAutoBackupIntervalName",
+                "SystemSettingMemoRevisionRetentionName",
+                "SystemSettingTrashRetentionName",
+                "SystemSettingStripImageMetadataName"
             ]
         },
         "v1.SystemStatus": {
@@ -2780,12 +3385,24 @@ const docTemplate = `{
                     "description": "Memo display with updated timestamp.",
                     "type": "boolean"
                 },
+                "memoRevisionRetention": {
+                    "description": "Memo revision retention as days.",
+                    "type": "integer"
+                },
                 "profile": {
                     "$ref": "#/definitions/profile.Profile"
                 },
                 "storageServiceId": {
                     "description": "Storage service ID.",
                     "type": "integer"
+                },
+                "stripImageMetadata": {
+                    "description": "Strip the location and device data out of the uploaded photos.",
+                    "type": "boolean"
+                },
+                "trashRetention": {
+                    "description": "Trash retention as days.",
+                    "type": "integer"
                 }
             }
         },
@@ -0,0 +1,534 @@
+package v1
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	apiresource "github.com/usememos/memos/api/resource"
+	"github.com/usememos/memos/internal/log"
+	"github.com/usememos/memos/store"
+)
+
+const (
+	// ExportFormat is the format version of the export archives.
+	ExportFormat = 1
+
+	exportManifestName   = "manifest.json"
+	frontmatterDelimiter = "---\n"
+)
+
+// ExportManifest is the manifest.json in the export archive.
+type ExportManifest struct {
+	Format    int                       `json:"format"`
+	Version   string                    `json:"version"`
+	CreatedTs int64                     `json:"createdTs"`
+	Username  string                    `json:"username"`
+	Memos     []*ExportManifestMemo     `json:"memos"`
+	Resources []*ExportManifestResource `json:"resources"`
+}
+
+type ExportManifestMemo struct {
+	ID   int32  `json:"id"`
+	Path string `json:"path"`
+}
+
+type ExportManifestResource struct {
+	ID        int32  `json:"id"`
+	CreatedTs int64  `json:"createdTs"`
+	Filename  string `json:"filename"`
+	Type      string `json:"type"`
+	// Size is only informative, the size of the file in the archive is imported instead.
+	Size int64 `json:"size"`
+	// CapturedTs is the time when the photo was taken, which is kept as the blob may not have its EXIF.
+	CapturedTs int64 `json:"capturedTs,omitempty"`
+	// Path is the file of the blob in the archive, which is empty for the external links out of the storages.
+	Path         string `json:"path,omitempty"`
+	ExternalLink string `json:"externalLink,omitempty"`
+}
+
+// MemoFrontmatter is the frontmatter of the Markdown files of the memos in the export archive.
+type MemoFrontmatter struct {
+	ID         int32                      `yaml:"id"`
+	Visibility Visibility                 `yaml:"visibility"`
+	RowStatus  RowStatus                  `yaml:"rowStatus"`
+	CreatedAt  time.Time                  `yaml:"createdAt"`
+	UpdatedAt  time.Time                  `yaml:"updatedAt"`
+	Pinned     bool                       `yaml:"pinned"`
+	Resources  []int32                    `yaml:"resources,omitempty"`
+	Relations  []*MemoFrontmatterRelation `yaml:"relations,omitempty"`
+}
+
+type MemoFrontmatterRelation struct {
+	RelatedMemoID int32            `yaml:"relatedMemoId"`
+	Type          MemoRelationType `yaml:"type"`
+}
+
+type ImportResult struct {
+	MemoCount     int `json:"memoCount"`
+	ResourceCount int `json:"resourceCount"`
+	RelationCount int `json:"relationCount"`
+}
+
+func (s *APIV1Service) registerExportRoutes(g *echo.Group) {
+	g.GET("/export", s.ExportUserData)
+	g.POST("/import", s.ImportUserData)
+	g.POST("/import/:source", s.ImportFromSource)
+}
+
+// ExportUserData godoc
+//
+//	@Summary	Export the memos and resources of the current user as a ZIP archive
+//	@Tags		export
+//	@Produce	application/zip
+//	@Success	200	{file}	file	"ZIP archive of Markdown files, resource files and manifest.json"
+//	@Failure	401	{object}	nil		"Missing user in session"
+//	@Failure	500	{object}	nil		"Failed to find user | Failed to export user data"
+//	@Router		/api/v1/export [GET]
+func (s *APIV1Service) ExportUserData(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(userIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{ID: &userID})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if user == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "application/zip")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="memos-%s-%s.zip"`, user.Username, time.Now().Format("20060102")))
+	c.Response().WriteHeader(http.StatusOK)
+	// The archive is streamed, so an error can only break the response after the header is sent.
+	if err := ExportUserData(ctx, s.Store, user, c.Response()); err != nil {
+		return errors.Wrap(err, "Failed to export user data")
+	}
+	return nil
+}
+
+// ImportUserData godoc
+//
+//	@Summary	Import an export archive into the current user
+//	@Tags		export
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		file	formData	file			true	"Export archive"
+//	@Success	200		{object}	ImportResult	"Counts of the imported memos, resources and relations"
+//	@Failure	400		{object}	nil				"Upload file not found | Failed to import user data"
+//	@Failure	401		{object}	nil				"Missing user in session"
+//	@Failure	500		{object}	nil				"Failed to find user | Failed to open file"
+//	@Router		/api/v1/import [POST]
+func (s *APIV1Service) ImportUserData(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(userIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{ID: &userID})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if user == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil || file == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Upload file not found").SetInternal(err)
+	}
+	sourceFile, err := file.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to open file").SetInternal(err)
+	}
+	defer sourceFile.Close()
+
+	result, err := ImportUserData(ctx, s.Store, user, sourceFile, file.Size)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to import user data: %v", err)).SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// ExportUserData writes the memos of the user, excluding those in the trash, and their resources as a ZIP archive.
+// Every memo is a Markdown file with a frontmatter, and the blobs of the resources are pulled from their storages.
+func ExportUserData(ctx context.Context, s *store.Store, user *store.User, w io.Writer) error {
+	memos, err := s.ListMemos(ctx, &store.FindMemo{
+		CreatorID: &user.ID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to list memos")
+	}
+	resources, err := s.ListResources(ctx, &store.FindResource{
+		CreatorID: &user.ID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to list resources")
+	}
+
+	manifest := &ExportManifest{
+		Format:    ExportFormat,
+		Version:   s.Profile.Version,
+		CreatedTs: time.Now().Unix(),
+		Username:  user.Username,
+		Memos:     []*ExportManifestMemo{},
+		Resources: []*ExportManifestResource{},
+	}
+	zipWriter := zip.NewWriter(w)
+	for _, resource := range resources {
+		manifestResource, err := exportResource(ctx, s, zipWriter, resource)
+		if err != nil {
+			return err
+		}
+		manifest.Resources = append(manifest.Resources, manifestResource)
+	}
+
+	// The memos are sorted from the oldest to the newest, so that they are imported in order.
+	sort.Slice(memos, func(i, j int) bool {
+		return memos[i].ID < memos[j].ID
+	})
+	for _, memo := range memos {
+		frontmatter := &MemoFrontmatter{
+			ID:         memo.ID,
+			Visibility: Visibility(memo.Visibility),
+			RowStatus:  RowStatus(memo.RowStatus),
+			CreatedAt:  time.Unix(memo.CreatedTs, 0).UTC(),
+			UpdatedAt:  time.Unix(memo.UpdatedTs, 0).UTC(),
+			Pinned:     memo.Pinned,
+			Resources:  memo.ResourceIDList,
+		}
+		for _, relation := range memo.RelationList {
+			if relation.MemoID != memo.ID {
+				continue
+			}
+			frontmatter.Relations = append(frontmatter.Relations, &MemoFrontmatterRelation{
+				RelatedMemoID: relation.RelatedMemoID,
+				Type:          MemoRelationType(relation.Type),
+			})
+		}
+		content, err := marshalMemoMarkdown(frontmatter, memo.Content)
+		if err != nil {
+			return err
+		}
+
+		memoPath := fmt.Sprintf("memos/%d.md", memo.ID)
+		file, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:     memoPath,
+			Method:   zip.Deflate,
+			Modified: frontmatter.UpdatedAt,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Failed to create %s", memoPath)
+		}
+		if _, err := file.Write(content); err != nil {
+			return errors.Wrapf(err, "Failed to write %s", memoPath)
+		}
+		manifest.Memos = append(manifest.Memos, &ExportManifestMemo{
+			ID:   memo.ID,
+			Path: memoPath,
+		})
+	}
+
+	file, err := zipWriter.Create(exportManifestName)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create manifest")
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return errors.Wrap(err, "Failed to write manifest")
+	}
+	return zipWriter.Close()
+}
+
+func exportResource(ctx context.Context, s *store.Store, zipWriter *zip.Writer, resource *store.Resource) (*ExportManifestResource, error) {
+	manifestResource := &ExportManifestResource{
+		ID:         resource.ID,
+		CreatedTs:  resource.CreatedTs,
+		Filename:   resource.Filename,
+		Type:       resource.Type,
+		Size:       resource.Size,
+		CapturedTs: resource.CapturedTs,
+	}
+	reader, err := OpenResourceBlob(ctx, s, resource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to open the blob of resource %d", resource.ID)
+	}
+	if reader == nil {
+		manifestResource.ExternalLink = resource.ExternalLink
+		return manifestResource, nil
+	}
+	defer reader.Close()
+
+	manifestResource.Path = fmt.Sprintf("resources/%d/%s", resource.ID, path.Base(resource.Filename))
+	file, err := zipWriter.CreateHeader(&zip.FileHeader{
+		Name:     manifestResource.Path,
+		Method:   zip.Store,
+		Modified: time.Unix(resource.CreatedTs, 0),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create %s", manifestResource.Path)
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		return nil, errors.Wrapf(err, "Failed to write %s", manifestResource.Path)
+	}
+	return manifestResource, nil
+}
+
+// OpenResourceBlob opens the blob of the resource from its storage.
+// It returns nil if the resource is an external link out of the storages.
+func OpenResourceBlob(ctx context.Context, s *store.Store, resource *store.Resource) (io.ReadCloser, error) {
+	backend, key, err := apiresource.GetResourceBackend(ctx, s, resource)
+	if err != nil {
+		return nil, err
+	}
+	if backend == nil {
+		return nil, nil
+	}
+	return backend.Get(ctx, key)
+}
+
+// ImportUserData imports the memos, resources and relations of an export archive into the user.
+// They are created with new IDs, and the references between them are remapped to the new IDs.
+// The import is all or nothing, as the records created are deleted along with the blobs saved if any of them fails.
+func ImportUserData(ctx context.Context, s *store.Store, user *store.User, r io.ReaderAt, size int64) (*ImportResult, error) {
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open archive")
+	}
+	manifest := &ExportManifest{}
+	if err := readArchiveFile(zipReader, exportManifestName, func(file fs.File) error {
+		return json.NewDecoder(file).Decode(manifest)
+	}); err != nil {
+		return nil, err
+	}
+	if manifest.Format != ExportFormat {
+		return nil, errors.Errorf("Unsupported export format %d", manifest.Format)
+	}
+
+	result := &ImportResult{}
+	imported := &importedRecords{}
+	if err := importUserData(ctx, s, user, zipReader, manifest, result, imported); err != nil {
+		if rollbackErr := imported.rollback(context.WithoutCancel(ctx), s); rollbackErr != nil {
+			log.Error("failed to roll back the import", zap.Error(rollbackErr))
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+func importUserData(ctx context.Context, s *store.Store, user *store.User, zipReader *zip.Reader, manifest *ExportManifest, result *ImportResult, imported *importedRecords) error {
+	disablePublicMemos, err := isPublicMemosDisabledForUser(ctx, s, user)
+	if err != nil {
+		return err
+	}
+
+	maxUploadSizeBytes := getMaxUploadSizeBytes(ctx, s)
+
+	resourceIDs := map[int32]int32{}
+	for _, manifestResource := range manifest.Resources {
+		create := &store.Resource{
+			CreatorID:  user.ID,
+			CreatedTs:  manifestResource.CreatedTs,
+			Filename:   manifestResource.Filename,
+			Type:       manifestResource.Type,
+			CapturedTs: manifestResource.CapturedTs,
+		}
+		if manifestResource.Path != "" {
+			if err := readArchiveFile(zipReader, manifestResource.Path, func(file fs.File) error {
+				// The size is the one of the file in the archive, which is checked by the reader, rather than the one in the manifest.
+				info, err := file.Stat()
+				if err != nil {
+					return err
+				}
+				if info.Size() > maxUploadSizeBytes {
+					return errors.Errorf("File size exceeds allowed limit of %d MiB", maxUploadSizeBytes/MebiByte)
+				}
+				create.Size = info.Size()
+				return SaveResourceBlob(ctx, s, create, file)
+			}); err != nil {
+				return err
+			}
+		} else if manifestResource.ExternalLink != "" {
+			// Only allow those external links scheme with http/https, the same as the resources created by the API.
+			linkURL, err := url.Parse(manifestResource.ExternalLink)
+			if err != nil {
+				return errors.Wrapf(err, "Invalid external link of resource %d", manifestResource.ID)
+			}
+			if linkURL.Scheme != "http" && linkURL.Scheme != "https" {
+				return errors.Errorf("Invalid external link scheme of resource %d", manifestResource.ID)
+			}
+			create.ExternalLink = manifestResource.ExternalLink
+		}
+		resource, err := s.CreateResource(ctx, create)
+		if err != nil {
+			return errors.Wrap(err, "Failed to create resource")
+		}
+		imported.resources = append(imported.resources, resource)
+		apiresource.EnqueueResourcePreviews(s, resource)
+		resourceIDs[manifestResource.ID] = resource.ID
+		result.ResourceCount++
+	}
+
+	memoIDs := map[int32]int32{}
+	relations := []*store.MemoRelation{}
+	for _, manifestMemo := range manifest.Memos {
+		var frontmatter *MemoFrontmatter
+		var content string
+		if err := readArchiveFile(zipReader, manifestMemo.Path, func(file fs.File) error {
+			frontmatter, content, err = unmarshalMemoMarkdown(file)
+			return err
+		}); err != nil {
+			return err
+		}
+		if len(content) > maxContentLength {
+			return errors.Errorf("Content size overflow of memo %d", frontmatter.ID)
+		}
+
+		visibility := store.Visibility(frontmatter.Visibility)
+		if visibility != store.Public && visibility != store.Protected {
+			visibility = store.Private
+		}
+		if disablePublicMemos {
+			visibility = store.Private
+		}
+		rowStatus := store.Normal
+		if frontmatter.RowStatus == Archived {
+			rowStatus = store.Archived
+		}
+		memo, err := s.CreateMemo(ctx, &store.Memo{
+			CreatorID:  user.ID,
+			CreatedTs:  frontmatter.CreatedAt.Unix(),
+			UpdatedTs:  frontmatter.UpdatedAt.Unix(),
+			RowStatus:  rowStatus,
+			Content:    content,
+			Visibility: visibility,
+		})
+		if err != nil {
+			return errors.Wrap(err, "Failed to create memo")
+		}
+		imported.memoIDs = append(imported.memoIDs, memo.ID)
+		memoIDs[frontmatter.ID] = memo.ID
+		result.MemoCount++
+
+		if frontmatter.Pinned {
+			if _, err := s.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+				MemoID: memo.ID,
+				UserID: user.ID,
+				Pinned: true,
+			}); err != nil {
+				return errors.Wrap(err, "Failed to upsert memo organizer")
+			}
+		}
+		for _, resourceID := range frontmatter.Resources {
+			newResourceID, ok := resourceIDs[resourceID]
+			if !ok {
+				continue
+			}
+			if _, err := s.UpdateResource(ctx, &store.UpdateResource{
+				ID:     newResourceID,
+				MemoID: &memo.ID,
+			}); err != nil {
+				return errors.Wrap(err, "Failed to update resource")
+			}
+		}
+		for _, relation := range frontmatter.Relations {
+			relations = append(relations, &store.MemoRelation{
+				MemoID:        memo.ID,
+				RelatedMemoID: relation.RelatedMemoID,
+				Type:          store.MemoRelationType(relation.Type),
+			})
+		}
+	}
+
+	// The relations are created after all the memos, since a memo may be related to a later one.
+	// Those related to the memos out of the archive are dropped.
+	for _, relation := range relations {
+		relatedMemoID, ok := memoIDs[relation.RelatedMemoID]
+		if !ok {
+			continue
+		}
+		relation.RelatedMemoID = relatedMemoID
+		if _, err := s.UpsertMemoRelation(ctx, relation); err != nil {
+			return errors.Wrap(err, "Failed to upsert memo relation")
+		}
+		result.RelationCount++
+	}
+	return nil
+}
+
+func isPublicMemosDisabledForUser(ctx context.Context, s *store.Store, user *store.User) (bool, error) {
+	if user.Role != store.RoleUser {
+		return false, nil
+	}
+	disablePublicMemosSystemSetting, err := s.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: SystemSettingDisablePublicMemosName.String(),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to find system setting")
+	}
+	if disablePublicMemosSystemSetting == nil {
+		return false, nil
+	}
+	disablePublicMemos := false
+	if err := json.Unmarshal([]byte(disablePublicMemosSystemSetting.Value), &disablePublicMemos); err != nil {
+		return false, errors.Wrap(err, "Failed to unmarshal system setting")
+	}
+	return disablePublicMemos, nil
+}
+
+func readArchiveFile(zipReader *zip.Reader, name string, read func(fs.File) error) error {
+	file, err := zipReader.Open(name)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to open %s", name)
+	}
+	defer file.Close()
+	if err := read(file); err != nil {
+		return errors.Wrapf(err, "Failed to read %s", name)
+	}
+	return nil
+}
+
+func marshalMemoMarkdown(frontmatter *MemoFrontmatter, content string) ([]byte, error) {
+	data, err := yaml.Marshal(frontmatter)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to marshal frontmatter")
+	}
+	return []byte(frontmatterDelimiter + string(data) + frontmatterDelimiter + content), nil
+}
+
+func unmarshalMemoMarkdown(reader io.Reader) (*MemoFrontmatter, string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, "", err
+	}
+	markdown := string(data)
+	if !strings.HasPrefix(markdown, frontmatterDelimiter) {
+		return nil, "", errors.New("Missing frontmatter")
+	}
+	rawFrontmatter, content, ok := strings.Cut(strings.TrimPrefix(markdown, frontmatterDelimiter), "\n"+frontmatterDelimiter)
+	if !ok {
+		return nil, "", errors.New("Unclosed frontmatter")
+	}
+	frontmatter := &MemoFrontmatter{}
+	if err := yaml.Unmarshal([]byte(rawFrontmatter), frontmatter); err != nil {
+		return nil, "", errors.Wrap(err, "Failed to unmarshal frontmatter")
+	}
+	return frontmatter, content, nil
+}
@@ -0,0 +1,271 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	apiresource "github.com/usememos/memos/api/resource"
+	"github.com/usememos/memos/internal/log"
+	"github.com/usememos/memos/plugin/importer"
+	"github.com/usememos/memos/store"
+)
+
+// ImportSourceReport is the report of importing the export of another app.
+type ImportSourceReport struct {
+	Source        string   `json:"source"`
+	DryRun        bool     `json:"dryRun"`
+	MemoCount     int      `json:"memoCount"`
+	ResourceCount int      `json:"resourceCount"`
+	RelationCount int      `json:"relationCount"`
+	Warnings      []string `json:"warnings"`
+}
+
+// ImportFromSource godoc
+//
+//	@Summary	Import the export of another app, i.e. flomo, obsidian, keep or enex
+//	@Tags		export
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		source		path		string				true	"Source format"	Enums(flomo, obsidian, keep, enex)
+//	@Param		file		formData	file				true	"ZIP archive of the export, or the ENEX file"
+//	@Param		username	query		string				false	"Username of the user to import into, defaults to the current user"
+//	@Param		dryRun		query		bool				false	"Only report what would be imported"
+//	@Success	200			{object}	ImportSourceReport	"Import report"
+//	@Failure	400			{object}	nil					"Unsupported import source | Upload file not found | Failed to parse export | User not found"
+//	@Failure	401			{object}	nil					"Missing user in session | Unauthorized"
+//	@Failure	500			{object}	nil					"Failed to find user | Failed to open file | Failed to import"
+//	@Router		/api/v1/import/{source} [POST]
+func (s *APIV1Service) ImportFromSource(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(userIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	currentUser, err := s.Store.GetUser(ctx, &store.FindUser{ID: &userID})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if currentUser == nil || currentUser.Role != store.RoleHost {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
+	}
+
+	source := c.Param("source")
+	sourceImporter, err := importer.GetImporter(source)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported import source: %s", source)).SetInternal(err)
+	}
+	dryRun := false
+	if value := c.QueryParam("dryRun"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid dryRun: %s", value)).SetInternal(err)
+		}
+	}
+	user := currentUser
+	if username := c.QueryParam("username"); username != "" {
+		user, err = s.Store.GetUser(ctx, &store.FindUser{Username: &username})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+		}
+		if user == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("User not found: %s", username))
+		}
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil || file == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Upload file not found").SetInternal(err)
+	}
+	sourceFile, err := file.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to open file").SetInternal(err)
+	}
+	defer sourceFile.Close()
+	fsys, err := importer.OpenFS(sourceFile, file.Size, file.Filename)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to parse export: %v", err)).SetInternal(err)
+	}
+	result, err := sourceImporter.Import(fsys)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to parse export: %v", err)).SetInternal(err)
+	}
+
+	report, err := ImportSourceResult(ctx, s.Store, user, result, dryRun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to import").SetInternal(err)
+	}
+	report.Source = source
+	return c.JSON(http.StatusOK, report)
+}
+
+// ImportSourceResult creates the records converted by an importer for the user, or only reports them in a dry run
+// without reading the blobs. The memos are created with new IDs, and the resources and the relations are remapped to them.
+// The import is all or nothing, as the records created are deleted along with the blobs saved if any of them fails.
+func ImportSourceResult(ctx context.Context, s *store.Store, user *store.User, result *importer.Result, dryRun bool) (*ImportSourceReport, error) {
+	if err := validateImportResult(ctx, s, result); err != nil {
+		return nil, err
+	}
+	report := &ImportSourceReport{
+		DryRun:   dryRun,
+		Warnings: result.Warnings,
+	}
+	if report.Warnings == nil {
+		report.Warnings = []string{}
+	}
+	if dryRun {
+		report.MemoCount = len(result.Memos)
+		report.ResourceCount = len(result.Resources)
+		report.RelationCount = len(result.Relations)
+		return report, nil
+	}
+
+	imported := &importedRecords{}
+	if err := importSourceResult(ctx, s, user, result, report, imported); err != nil {
+		if rollbackErr := imported.rollback(context.WithoutCancel(ctx), s); rollbackErr != nil {
+			log.Error("failed to roll back the import", zap.Error(rollbackErr))
+		}
+		return nil, err
+	}
+	return report, nil
+}
+
+// validateImportResult checks the memos and the resources against the limits of the API, before any of them is created.
+// The sizes of the resources are the ones of the files in the source, which are checked by the reader.
+func validateImportResult(ctx context.Context, s *store.Store, result *importer.Result) error {
+	for _, memo := range result.Memos {
+		if len(memo.Content) > maxContentLength {
+			return errors.Errorf("Content size overflow of memo %d", memo.ID)
+		}
+	}
+	maxUploadSizeBytes := getMaxUploadSizeBytes(ctx, s)
+	for _, resource := range result.Resources {
+		if resource.Size > maxUploadSizeBytes {
+			return errors.Errorf("File size of resource %s exceeds allowed limit of %d MiB", resource.Filename, maxUploadSizeBytes/MebiByte)
+		}
+	}
+	return nil
+}
+
+// importedRecords are the records created by an import, which are deleted if it fails.
+type importedRecords struct {
+	memoIDs   []int32
+	resources []*store.Resource
+}
+
+// rollback deletes the records created, and the blobs of the resources.
+// The relations and the organizers of the memos are deleted along with them.
+func (r *importedRecords) rollback(ctx context.Context, s *store.Store) error {
+	for _, resource := range r.resources {
+		if err := apiresource.DeleteResourceWithBlob(ctx, s, resource); err != nil {
+			return errors.Wrapf(err, "failed to delete resource %d", resource.ID)
+		}
+	}
+	for _, memoID := range r.memoIDs {
+		if err := s.DeleteMemo(ctx, &store.DeleteMemo{ID: memoID}); err != nil {
+			return errors.Wrapf(err, "failed to delete memo %d", memoID)
+		}
+	}
+	return nil
+}
+
+func importSourceResult(ctx context.Context, s *store.Store, user *store.User, result *importer.Result, report *ImportSourceReport, imported *importedRecords) error {
+	disablePublicMemos, err := isPublicMemosDisabledForUser(ctx, s, user)
+	if err != nil {
+		return err
+	}
+	memoIDs := map[int32]int32{}
+	for _, memo := range result.Memos {
+		visibility := memo.Visibility
+		if disablePublicMemos {
+			visibility = store.Private
+		}
+		created, err := s.CreateMemo(ctx, &store.Memo{
+			CreatorID:  user.ID,
+			CreatedTs:  memo.CreatedTs,
+			UpdatedTs:  memo.UpdatedTs,
+			RowStatus:  memo.RowStatus,
+			Content:    memo.Content,
+			Visibility: visibility,
+		})
+		if err != nil {
+			return errors.Wrap(err, "Failed to create memo")
+		}
+		imported.memoIDs = append(imported.memoIDs, created.ID)
+		memoIDs[memo.ID] = created.ID
+		report.MemoCount++
+
+		if memo.Pinned {
+			if _, err := s.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+				MemoID: created.ID,
+				UserID: user.ID,
+				Pinned: true,
+			}); err != nil {
+				return errors.Wrap(err, "Failed to upsert memo organizer")
+			}
+		}
+	}
+
+	for _, resource := range result.Resources {
+		create := &store.Resource{
+			CreatorID: user.ID,
+			CreatedTs: resource.CreatedTs,
+			UpdatedTs: resource.UpdatedTs,
+			Filename:  resource.Filename,
+			Type:      resource.Type,
+			Size:      resource.Size,
+		}
+		if resource.MemoID != nil {
+			if memoID, ok := memoIDs[*resource.MemoID]; ok {
+				create.MemoID = &memoID
+			}
+		}
+		if err := saveImportedResourceBlob(ctx, s, create, resource); err != nil {
+			return err
+		}
+		created, err := s.CreateResource(ctx, create)
+		if err != nil {
+			return errors.Wrap(err, "Failed to create resource")
+		}
+		imported.resources = append(imported.resources, created)
+		apiresource.EnqueueResourcePreviews(s, created)
+		report.ResourceCount++
+	}
+
+	for _, relation := range result.Relations {
+		memoID, ok := memoIDs[relation.MemoID]
+		if !ok {
+			continue
+		}
+		relatedMemoID, ok := memoIDs[relation.RelatedMemoID]
+		if !ok {
+			continue
+		}
+		if _, err := s.UpsertMemoRelation(ctx, &store.MemoRelation{
+			MemoID:        memoID,
+			RelatedMemoID: relatedMemoID,
+			Type:          relation.Type,
+		}); err != nil {
+			return errors.Wrap(err, "Failed to upsert memo relation")
+		}
+		report.RelationCount++
+	}
+	return nil
+}
+
+// saveImportedResourceBlob streams the blob of the resource imported into the storage.
+func saveImportedResourceBlob(ctx context.Context, s *store.Store, create *store.Resource, resource *importer.Resource) error {
+	reader, err := resource.Open()
+	if err != nil {
+		return errors.Wrapf(err, "Failed to open resource %s", resource.Filename)
+	}
+	defer reader.Close()
+	if err := SaveResourceBlob(ctx, s, create, reader); err != nil {
+		return errors.Wrap(err, "Failed to save resource blob")
+	}
+	return nil
+}
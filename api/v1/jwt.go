@@ -109,6 +109,27 @@ func JWTMiddleware(server *APIV1Service, next echo.HandlerFunc, secret string) e
 	}
 }
 
+// findAccessTokenUserID returns the ID of the user of the valid access token in the request, or 0 if there's none.
+// It's for the routes out of JWTMiddleware, which are served to the anonymous callers too.
+func (s *APIV1Service) findAccessTokenUserID(c echo.Context) (int32, error) {
+	accessToken := findAccessToken(c)
+	if accessToken == "" {
+		return 0, nil
+	}
+	userID, err := getUserIDFromAccessToken(accessToken, s.Secret)
+	if err != nil {
+		return 0, nil
+	}
+	accessTokens, err := s.Store.GetUserAccessTokens(c.Request().Context(), userID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get user access tokens")
+	}
+	if !validateAccessToken(accessToken, accessTokens) {
+		return 0, nil
+	}
+	return userID, nil
+}
+
 func getUserIDFromAccessToken(accessToken, secret string) (int32, error) {
 	claims := &auth.ClaimsMessage{}
 	_, err := jwt.ParseWithClaims(accessToken, claims, func(t *jwt.Token) (any, error) {
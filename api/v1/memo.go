@@ -12,8 +12,11 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	apiresource "github.com/usememos/memos/api/resource"
 	"github.com/usememos/memos/internal/log"
 	"github.com/usememos/memos/internal/util"
+	"github.com/usememos/memos/plugin/gomark"
+	"github.com/usememos/memos/plugin/gomark/renderer"
 	storepb "github.com/usememos/memos/proto/gen/store"
 	"github.com/usememos/memos/server/service/metric"
 	"github.com/usememos/memos/store"
@@ -125,6 +128,7 @@ func (s *APIV1Service) registerMemoRoutes(g *echo.Group) {
 	g.GET("/memo/:memoId", s.GetMemo)
 	g.PATCH("/memo/:memoId", s.UpdateMemo)
 	g.DELETE("/memo/:memoId", s.DeleteMemo)
+	g.POST("/memo/:memoId/restore", s.RestoreMemo)
 }
 
 // GetMemoList godoc
@@ -142,6 +146,7 @@ func (s *APIV1Service) registerMemoRoutes(g *echo.Group) {
 //	@Param		offset			query		int				false	"Offset"
 //	@Success	200				{object}	[]store.Memo	"Memo list"
 //	@Failure	400				{object}	nil				"Missing user to find memo"
+//	@Failure	403				{object}	nil				"Only the creator can list the memos in the trash"
 //	@Failure	500				{object}	nil				"Failed to get memo display with updated ts setting value | Failed to fetch memo list | Failed to compose memo response"
 //	@Router		/api/v1/memo [GET]
 func (s *APIV1Service) GetMemoList(c echo.Context) error {
@@ -182,6 +187,10 @@ func (s *APIV1Service) GetMemoList(c echo.Context) error {
 
 	rowStatus := store.RowStatus(c.QueryParam("rowStatus"))
 	if rowStatus != "" {
+		// The memos in the trash are listed only for their creator, whatever their visibility.
+		if rowStatus == store.Deleted && (!ok || *findMemoMessage.CreatorID != currentUserID) {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the creator can list the memos in the trash")
+		}
 		findMemoMessage.RowStatus = &rowStatus
 	}
 	pinnedStr := c.QueryParam("pinned")
@@ -392,6 +401,8 @@ func (s *APIV1Service) CreateMemo(c echo.Context) error {
 
 	// send notification by telegram bot if memo is not Private
 	if memoResponse.Visibility != Private {
+		// Telegram doesn't render markdown, so send the plain text instead.
+		plainContent := renderer.NewStringRenderer().Render(gomark.Parse(memoResponse.Content).Nodes)
 		// fetch all telegram UserID
 		userSettings, err := s.Store.ListUserSettings(ctx, &store.FindUserSetting{Key: UserSettingTelegramUserIDKey.String()})
 		if err != nil {
@@ -413,7 +424,7 @@ func (s *APIV1Service) CreateMemo(c echo.Context) error {
 			}
 
 			// send notification to telegram
-			content := memoResponse.CreatorName + " Says:\n\n" + memoResponse.Content
+			content := memoResponse.CreatorName + " Says:\n\n" + plainContent
 			_, err = s.telegramBot.SendMessage(ctx, tgUserID, content)
 			if err != nil {
 				log.Error("Failed to send Telegram notification", zap.Error(err))
@@ -609,16 +620,17 @@ func (s *APIV1Service) GetMemo(c echo.Context) error {
 
 // DeleteMemo godoc
 //
-//	@Summary	Delete memo by ID
-//	@Tags		memo
-//	@Produce	json
-//	@Param		memoId	path		int		true	"Memo ID to delete"
-//	@Success	200		{boolean}	true	"Memo deleted"
-//	@Failure	400		{object}	nil		"ID is not a number: %s"
-//	@Failure	401		{object}	nil		"Missing user in session | Unauthorized"
-//	@Failure	404		{object}	nil		"Memo not found: %d"
-//	@Failure	500		{object}	nil		"Failed to find memo | Failed to delete memo ID: %v"
-//	@Router		/api/v1/memo/{memoId} [DELETE]
+//	@Summary		Delete memo by ID
+//	@Description	The memo is moved to the trash, and it's deleted permanently if it's already in the trash.
+//	@Tags			memo
+//	@Produce		json
+//	@Param			memoId	path		int		true	"Memo ID to delete"
+//	@Success		200		{boolean}	true	"Memo deleted"
+//	@Failure		400		{object}	nil		"ID is not a number: %s"
+//	@Failure		401		{object}	nil		"Missing user in session | Unauthorized"
+//	@Failure		404		{object}	nil		"Memo not found: %d"
+//	@Failure		500		{object}	nil		"Failed to find memo | Failed to delete memo ID: %v"
+//	@Router			/api/v1/memo/{memoId} [DELETE]
 func (s *APIV1Service) DeleteMemo(c echo.Context) error {
 	ctx := c.Request().Context()
 	userID, ok := c.Get(userIDContextKey).(int32)
@@ -636,6 +648,16 @@ func (s *APIV1Service) DeleteMemo(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find memo").SetInternal(err)
 	}
+	if memo == nil {
+		deletedStatus := store.Deleted
+		memo, err = s.Store.GetMemo(ctx, &store.FindMemo{
+			ID:        &memoID,
+			RowStatus: &deletedStatus,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find memo").SetInternal(err)
+		}
+	}
 	if memo == nil {
 		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Memo not found: %d", memoID))
 	}
@@ -643,14 +665,105 @@ func (s *APIV1Service) DeleteMemo(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
 	}
 
-	if err := s.Store.DeleteMemo(ctx, &store.DeleteMemo{
-		ID: memoID,
+	if memo.RowStatus == store.Deleted {
+		if err := PurgeMemo(ctx, s.Store, memo); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete memo ID: %v", memoID)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, true)
+	}
+
+	// The memo is purged from the trash after the trash retention, counting from the updated time.
+	currentTs := time.Now().Unix()
+	deletedStatus := store.Deleted
+	if err := s.Store.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        memoID,
+		UpdatedTs: &currentTs,
+		RowStatus: &deletedStatus,
 	}); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete memo ID: %v", memoID)).SetInternal(err)
 	}
 	return c.JSON(http.StatusOK, true)
 }
 
+// RestoreMemo godoc
+//
+//	@Summary	Restore memo from the trash by ID
+//	@Tags		memo
+//	@Produce	json
+//	@Param		memoId	path		int			true	"Memo ID to restore"
+//	@Success	200		{object}	store.Memo	"Restored memo"
+//	@Failure	400		{object}	nil			"ID is not a number: %s"
+//	@Failure	401		{object}	nil			"Missing user in session | Unauthorized"
+//	@Failure	404		{object}	nil			"Memo not found in trash: %d"
+//	@Failure	500		{object}	nil			"Failed to find memo | Failed to restore memo | Failed to compose memo response"
+//	@Router		/api/v1/memo/{memoId}/restore [POST]
+func (s *APIV1Service) RestoreMemo(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(userIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	memoID, err := util.ConvertStringToInt32(c.Param("memoId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("memoId"))).SetInternal(err)
+	}
+
+	deletedStatus := store.Deleted
+	memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
+		ID:        &memoID,
+		RowStatus: &deletedStatus,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find memo").SetInternal(err)
+	}
+	if memo == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Memo not found in trash: %d", memoID))
+	}
+	if memo.CreatorID != userID {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
+	}
+
+	currentTs := time.Now().Unix()
+	normalStatus := store.Normal
+	if err := s.Store.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        memoID,
+		UpdatedTs: &currentTs,
+		RowStatus: &normalStatus,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to restore memo").SetInternal(err)
+	}
+	memo, err = s.Store.GetMemo(ctx, &store.FindMemo{ID: &memoID})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find memo").SetInternal(err)
+	}
+	if memo == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Memo not found: %d", memoID))
+	}
+	memoResponse, err := s.convertMemoFromStore(ctx, memo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to compose memo response").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, memoResponse)
+}
+
+// PurgeMemo permanently deletes the memo with its resources, including their blobs in the local storage and S3.
+func PurgeMemo(ctx context.Context, s *store.Store, memo *store.Memo) error {
+	resources, err := s.ListResources(ctx, &store.FindResource{
+		MemoID: &memo.ID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list resources")
+	}
+	for _, resource := range resources {
+		if err := apiresource.DeleteResourceWithBlob(ctx, s, resource); err != nil {
+			return errors.Wrapf(err, "failed to delete resource %d", resource.ID)
+		}
+	}
+	return s.DeleteMemo(ctx, &store.DeleteMemo{
+		ID: memo.ID,
+	})
+}
+
 // UpdateMemo godoc
 //
 //	@Summary		Update a memo
@@ -716,6 +829,9 @@ func (s *APIV1Service) UpdateMemo(c echo.Context) error {
 		Content:   patchMemoRequest.Content,
 	}
 	if patchMemoRequest.RowStatus != nil {
+		if *patchMemoRequest.RowStatus == Deleted {
+			return echo.NewHTTPError(http.StatusBadRequest, "Delete the memo to move it to the trash")
+		}
 		rowStatus := store.RowStatus(patchMemoRequest.RowStatus.String())
 		updateMemoMessage.RowStatus = &rowStatus
 	}
@@ -747,7 +863,7 @@ func (s *APIV1Service) UpdateMemo(c echo.Context) error {
 			}
 		}
 		for _, resourceID := range removedResourceIDList {
-			if err := s.Store.DeleteResource(ctx, &store.DeleteResource{
+			if _, err := s.Store.DeleteResource(ctx, &store.DeleteResource{
 				ID: resourceID,
 			}); err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete resource").SetInternal(err)
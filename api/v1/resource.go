@@ -7,7 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -18,8 +18,10 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	apiresource "github.com/usememos/memos/api/resource"
 	"github.com/usememos/memos/internal/log"
 	"github.com/usememos/memos/internal/util"
+	"github.com/usememos/memos/plugin/storage/local"
 	"github.com/usememos/memos/plugin/storage/s3"
 	"github.com/usememos/memos/store"
 )
@@ -39,6 +41,8 @@ type Resource struct {
 	ExternalLink string `json:"externalLink"`
 	Type         string `json:"type"`
 	Size         int64  `json:"size"`
+	// CapturedTs is the time when the photo was taken, which is 0 if it's unknown.
+	CapturedTs int64 `json:"capturedTs"`
 }
 
 type CreateResourceRequest struct {
@@ -169,7 +173,7 @@ func (s *APIV1Service) CreateResource(c echo.Context) error {
 //	@Produce	json
 //	@Param		file	formData	file			true	"File to upload"
 //	@Success	200		{object}	store.Resource	"Created resource"
-//	@Failure	400		{object}	nil				"Upload file not found | File size exceeds allowed limit of %d MiB | Failed to parse upload data"
+//	@Failure	400		{object}	nil				"Upload file not found | File size exceeds allowed limit of %d MiB | Failed to parse upload data | Failed to strip the metadata of the image"
 //	@Failure	401		{object}	nil				"Missing user in session"
 //	@Failure	500		{object}	nil				"Failed to get uploading file | Failed to open file | Failed to save resource | Failed to create resource | Failed to create activity"
 //	@Router		/api/v1/resource/blob [POST]
@@ -180,16 +184,7 @@ func (s *APIV1Service) UploadResource(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
 	}
 
-	// This is the backend default max upload size limit.
-	maxUploadSetting := s.Store.GetSystemSettingValueWithDefault(ctx, SystemSettingMaxUploadSizeMiBName.String(), "32")
-	var settingMaxUploadSizeBytes int
-	if settingMaxUploadSizeMiB, err := strconv.Atoi(maxUploadSetting); err == nil {
-		settingMaxUploadSizeBytes = settingMaxUploadSizeMiB * MebiByte
-	} else {
-		log.Warn("Failed to parse max upload size", zap.Error(err))
-		settingMaxUploadSizeBytes = 0
-	}
-
+	settingMaxUploadSizeBytes := getMaxUploadSizeBytes(ctx, s.Store)
 	file, err := c.FormFile("file")
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get uploading file").SetInternal(err)
@@ -198,7 +193,7 @@ func (s *APIV1Service) UploadResource(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Upload file not found").SetInternal(err)
 	}
 
-	if file.Size > int64(settingMaxUploadSizeBytes) {
+	if file.Size > settingMaxUploadSizeBytes {
 		message := fmt.Sprintf("File size exceeds allowed limit of %d MiB", settingMaxUploadSizeBytes/MebiByte)
 		return echo.NewHTTPError(http.StatusBadRequest, message).SetInternal(err)
 	}
@@ -219,6 +214,9 @@ func (s *APIV1Service) UploadResource(c echo.Context) error {
 		Size:      file.Size,
 	}
 	err = SaveResourceBlob(ctx, s.Store, create, sourceFile)
+	if errors.Is(err, errImageMetadataNotStripped) {
+		return echo.NewHTTPError(http.StatusBadRequest, errImageMetadataNotStripped.Error()).SetInternal(err)
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save resource").SetInternal(err)
 	}
@@ -227,6 +225,7 @@ func (s *APIV1Service) UploadResource(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create resource").SetInternal(err)
 	}
+	apiresource.EnqueueResourcePreviews(s.Store, resource)
 	return c.JSON(http.StatusOK, convertResourceFromStore(resource))
 }
 
@@ -265,9 +264,7 @@ func (s *APIV1Service) DeleteResource(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Resource not found: %d", resourceID))
 	}
 
-	if err := s.Store.DeleteResource(ctx, &store.DeleteResource{
-		ID: resourceID,
-	}); err != nil {
+	if err := apiresource.DeleteResourceWithBlob(ctx, s.Store, resource); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete resource").SetInternal(err)
 	}
 	return c.JSON(http.StatusOK, true)
@@ -370,6 +367,7 @@ func convertResourceFromStore(resource *store.Resource) *Resource {
 		ExternalLink: resource.ExternalLink,
 		Type:         resource.Type,
 		Size:         resource.Size,
+		CapturedTs:   resource.CapturedTs,
 	}
 }
 
@@ -378,20 +376,68 @@ func convertResourceFromStore(resource *store.Resource) *Resource {
 // Depend on the storage config, some fields of *store.ResourceCreate will be changed:
 // 1. *DatabaseStorage*: `create.Blob`.
 // 2. *LocalStorage*: `create.InternalPath`.
-// 3. Others( external service): `create.ExternalLink`.
+// 3. *S3*: `create.ExternalLink`.
+// 4. Others( WebDAV, SFTP and private S3): `create.StorageID` and `create.InternalPath` as the key in the storage.
 func SaveResourceBlob(ctx context.Context, s *store.Store, create *store.Resource, r io.Reader) error {
+	storageServiceID, err := getStorageServiceID(ctx, s)
+	if err != nil {
+		return err
+	}
+	r, cleanup, err := processResourceBlob(ctx, s, create, r)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	return SaveResourceBlobToStorage(ctx, s, storageServiceID, create, r)
+}
+
+// getStorageServiceID returns the storage the resources are saved into by the setting.
+func getStorageServiceID(ctx context.Context, s *store.Store) (int32, error) {
 	systemSettingStorageServiceID, err := s.GetSystemSetting(ctx, &store.FindSystemSetting{Name: SystemSettingStorageServiceIDName.String()})
 	if err != nil {
-		return errors.Wrap(err, "Failed to find SystemSettingStorageServiceIDName")
+		return 0, errors.Wrap(err, "Failed to find SystemSettingStorageServiceIDName")
 	}
 
 	storageServiceID := DefaultStorage
 	if systemSettingStorageServiceID != nil {
 		err = json.Unmarshal([]byte(systemSettingStorageServiceID.Value), &storageServiceID)
 		if err != nil {
-			return errors.Wrap(err, "Failed to unmarshal storage service id")
+			return 0, errors.Wrap(err, "Failed to unmarshal storage service id")
 		}
 	}
+	return storageServiceID, nil
+}
+
+// getMaxUploadSizeBytes returns the max upload size by the setting.
+func getMaxUploadSizeBytes(ctx context.Context, s *store.Store) int64 {
+	// This is the backend default max upload size limit.
+	maxUploadSetting := s.GetSystemSettingValueWithDefault(ctx, SystemSettingMaxUploadSizeMiBName.String(), "32")
+	settingMaxUploadSizeMiB, err := strconv.Atoi(maxUploadSetting)
+	if err != nil {
+		log.Warn("Failed to parse max upload size", zap.Error(err))
+		return 0
+	}
+	return int64(settingMaxUploadSizeMiB) * MebiByte
+}
+
+// SaveResourceBlobToStorage saves the blob into the storage of the ID, i.e. DatabaseStorage, LocalStorage or the ID of a storage.
+// The blob is hashed as `create.Hash` unless it's given, and the same blob in the storage is shared instead of saved again.
+func SaveResourceBlobToStorage(ctx context.Context, s *store.Store, storageServiceID int32, create *store.Resource, r io.Reader) error {
+	if create.Hash == "" {
+		hash, reader, cleanup, err := hashResourceBlob(r)
+		if err != nil {
+			return errors.Wrap(err, "Failed to hash file")
+		}
+		defer cleanup()
+		create.Hash, r = hash, reader
+	}
+	shared, err := shareResourceBlob(ctx, s, storageServiceID, create)
+	if err != nil {
+		return errors.Wrap(err, "Failed to find the same file")
+	}
+	if shared {
+		return nil
+	}
 
 	// `DatabaseStorage` means store blob into database
 	if storageServiceID == DatabaseStorage {
@@ -420,25 +466,14 @@ func SaveResourceBlob(ctx context.Context, s *store.Store, create *store.Resourc
 		}
 		filePath = filepath.Join(s.Profile.Data, replacePathTemplate(filePath, create.Filename))
 
-		dir := filepath.Dir(filePath)
-		if err = os.MkdirAll(dir, os.ModePerm); err != nil {
-			return errors.Wrap(err, "Failed to create directory")
+		if err := local.New(s.Profile.Data).Put(ctx, filePath, r, create.Size, create.Type); err != nil {
+			return errors.Wrap(err, "Failed to save file")
 		}
-		dst, err := os.Create(filePath)
-		if err != nil {
-			return errors.Wrap(err, "Failed to create file")
-		}
-		defer dst.Close()
-		_, err = io.Copy(dst, r)
-		if err != nil {
-			return errors.Wrap(err, "Failed to copy file")
-		}
-
 		create.InternalPath = filePath
 		return nil
 	}
 
-	// Others: store blob into external service, such as S3
+	// Others: store blob into external service, such as S3, WebDAV and SFTP
 	storage, err := s.GetStorage(ctx, &store.FindStorage{ID: &storageServiceID})
 	if err != nil {
 		return errors.Wrap(err, "Failed to find StorageServiceID")
@@ -450,36 +485,45 @@ func SaveResourceBlob(ctx context.Context, s *store.Store, create *store.Resourc
 	if err != nil {
 		return errors.Wrap(err, "Failed to ConvertStorageFromStore")
 	}
-
-	if storageMessage.Type != StorageS3 {
-		return errors.Errorf("Unsupported storage type: %s", storageMessage.Type)
-	}
-
-	s3Config := storageMessage.Config.S3Config
-	s3Client, err := s3.NewClient(ctx, &s3.Config{
-		AccessKey: s3Config.AccessKey,
-		SecretKey: s3Config.SecretKey,
-		EndPoint:  s3Config.EndPoint,
-		Region:    s3Config.Region,
-		Bucket:    s3Config.Bucket,
-		URLPrefix: s3Config.URLPrefix,
-		URLSuffix: s3Config.URLSuffix,
-	})
+	backend, err := apiresource.NewStorageBackend(ctx, storage)
 	if err != nil {
-		return errors.Wrap(err, "Failed to create s3 client")
+		return errors.Wrap(err, "Failed to create storage backend")
 	}
 
-	filePath := s3Config.Path
-	if !strings.Contains(filePath, "{filename}") {
-		filePath = filepath.Join(filePath, "{filename}")
+	pathTemplate := ""
+	switch storageMessage.Type {
+	case StorageS3:
+		pathTemplate = storageMessage.Config.S3Config.Path
+		if !storageMessage.Config.S3Config.Private {
+			// The public S3 objects are linked by their URLs.
+			link, err := backend.(*s3.Client).UploadFile(ctx, getStorageFilePath(pathTemplate, create.Filename), create.Type, r)
+			if err != nil {
+				return errors.Wrap(err, "Failed to upload via s3 client")
+			}
+			create.ExternalLink = link
+			return nil
+		}
+	case StorageWebDAV:
+		pathTemplate = storageMessage.Config.WebDAVConfig.Path
+	case StorageSFTP:
+		pathTemplate = storageMessage.Config.SFTPConfig.Path
+	default:
+		return errors.Errorf("Unsupported storage type: %s", storageMessage.Type)
 	}
-	filePath = replacePathTemplate(filePath, create.Filename)
 
-	link, err := s3Client.UploadFile(ctx, filePath, create.Type, r)
-	if err != nil {
-		return errors.Wrap(err, "Failed to upload via s3 client")
+	filePath := getStorageFilePath(pathTemplate, create.Filename)
+	if err := backend.Put(ctx, filePath, r, create.Size, create.Type); err != nil {
+		return errors.Wrapf(err, "Failed to upload to %s storage", storageMessage.Type)
 	}
-
-	create.ExternalLink = link
+	create.StorageID = storage.ID
+	create.InternalPath = filePath
 	return nil
 }
+
+// getStorageFilePath returns the key of the file in the storage by the path template.
+func getStorageFilePath(pathTemplate, filename string) string {
+	if !strings.Contains(pathTemplate, "{filename}") {
+		pathTemplate = path.Join(pathTemplate, "{filename}")
+	}
+	return replacePathTemplate(pathTemplate, filename)
+}
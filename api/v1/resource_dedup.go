@@ -0,0 +1,224 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/store"
+)
+
+// DeduplicateResult is the result of deduplicating the blobs of the resources.
+type DeduplicateResult struct {
+	// HashedCount is the number of the resources hashed, which were saved before the hashes.
+	HashedCount int
+	// SharedCount is the number of the resources switched to share the same blobs.
+	SharedCount int
+	// FreedSize is the bytes of the blobs deleted.
+	FreedSize int64
+}
+
+// DeduplicateResourceBlobs hashes the resources without hashes, and switches the resources of the same blob in a storage to share one of them.
+// The blobs no longer referenced are deleted. Nothing is changed in a dry run.
+func DeduplicateResourceBlobs(ctx context.Context, s *store.Store, dryRun bool) (*DeduplicateResult, error) {
+	resources, err := s.ListResources(ctx, &store.FindResource{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list resources")
+	}
+
+	result := &DeduplicateResult{}
+	// The oldest resource of a blob is kept, as the resources are listed from the newest.
+	groups, keys := map[string][]*store.Resource{}, []string{}
+	for i := len(resources) - 1; i >= 0; i-- {
+		resource := resources[i]
+		storageID, ok, err := GetResourceStorageID(ctx, s, resource)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to find storage of resource")
+		}
+		if !ok {
+			continue
+		}
+		if resource.Hash == "" {
+			if err := hashSavedResourceBlob(ctx, s, resource, dryRun); err != nil {
+				return nil, errors.Wrapf(err, "Failed to hash resource %d", resource.ID)
+			}
+			result.HashedCount++
+		}
+		key := fmt.Sprintf("%d:%s", storageID, resource.Hash)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], resource)
+	}
+
+	freedLocations := map[string]bool{}
+	for _, key := range keys {
+		group := groups[key]
+		kept := group[0]
+		for _, resource := range group[1:] {
+			if isDatabaseBlob(resource) {
+				// The blobs in the database of the same hash are shared once only one of them holds the blob.
+				blob, err := getOwnResourceBlob(ctx, s, resource)
+				if err != nil {
+					return nil, err
+				}
+				if len(blob) == 0 {
+					continue
+				}
+				result.SharedCount++
+				result.FreedSize += int64(len(blob))
+				if dryRun {
+					continue
+				}
+				if _, err := s.UpdateResource(ctx, &store.UpdateResource{
+					ID:   resource.ID,
+					Blob: []byte{},
+				}); err != nil {
+					return nil, errors.Wrapf(err, "Failed to share the blob of resource %d", resource.ID)
+				}
+				continue
+			}
+
+			location := getResourceBlobLocation(resource)
+			if location == getResourceBlobLocation(kept) {
+				continue
+			}
+			result.SharedCount++
+			if dryRun {
+				if !freedLocations[location] {
+					freedLocations[location] = true
+					result.FreedSize += resource.Size
+				}
+				continue
+			}
+			if _, err := s.UpdateResource(ctx, &store.UpdateResource{
+				ID:           resource.ID,
+				StorageID:    &kept.StorageID,
+				InternalPath: &kept.InternalPath,
+				ExternalLink: &kept.ExternalLink,
+			}); err != nil {
+				return nil, errors.Wrapf(err, "Failed to share the blob of resource %d", resource.ID)
+			}
+			// The blob is deleted with the last resource switched away from it.
+			references, err := s.ListResourceBlobReferences(ctx, resource)
+			if err != nil {
+				return nil, errors.Wrap(err, "Failed to list resource blob references")
+			}
+			if len(references) == 0 {
+				if err := deleteResourceBlob(ctx, s, resource); err != nil {
+					return nil, errors.Wrapf(err, "Failed to delete the blob of resource %d", resource.ID)
+				}
+				result.FreedSize += resource.Size
+			}
+		}
+	}
+	return result, nil
+}
+
+// hashSavedResourceBlob hashes the blob of the resource saved before the hashes, and saves the hash unless in a dry run.
+func hashSavedResourceBlob(ctx context.Context, s *store.Store, resource *store.Resource, dryRun bool) error {
+	reader, err := OpenResourceBlob(ctx, s, resource)
+	if err != nil {
+		return errors.Wrap(err, "Failed to open blob")
+	}
+	defer reader.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return errors.Wrap(err, "Failed to read blob")
+	}
+	resource.Hash = hex.EncodeToString(hash.Sum(nil))
+	if dryRun {
+		return nil
+	}
+	_, err = s.UpdateResource(ctx, &store.UpdateResource{
+		ID:   resource.ID,
+		Hash: &resource.Hash,
+	})
+	return err
+}
+
+// hashResourceBlob returns the hex SHA-256 hash of the blob, and the reader of the blob from where it was.
+// The blob is kept in a temporary file if the reader can't seek, which is removed by the cleanup.
+func hashResourceBlob(r io.Reader) (string, io.Reader, func(), error) {
+	hash := sha256.New()
+	if seeker, ok := r.(io.ReadSeeker); ok {
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if _, err := io.Copy(hash, seeker); err != nil {
+			return "", nil, nil, err
+		}
+		if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+			return "", nil, nil, err
+		}
+		return hex.EncodeToString(hash.Sum(nil)), seeker, func() {}, nil
+	}
+
+	file, err := os.CreateTemp("", "memos-resource-*")
+	if err != nil {
+		return "", nil, nil, err
+	}
+	cleanup := func() {
+		file.Close()
+		os.Remove(file.Name())
+	}
+	if _, err := io.Copy(io.MultiWriter(file, hash), r); err != nil {
+		cleanup()
+		return "", nil, nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return "", nil, nil, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), file, cleanup, nil
+}
+
+// shareResourceBlob switches the resource to the blob of the same hash in the storage, and returns false if there is none.
+func shareResourceBlob(ctx context.Context, s *store.Store, storageID int32, create *store.Resource) (bool, error) {
+	resources, err := s.ListResources(ctx, &store.FindResource{
+		Hash: &create.Hash,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, resource := range resources {
+		resourceStorageID, ok, err := GetResourceStorageID(ctx, s, resource)
+		if err != nil {
+			return false, err
+		}
+		if !ok || resourceStorageID != storageID {
+			continue
+		}
+		create.StorageID = resource.StorageID
+		create.InternalPath = resource.InternalPath
+		create.ExternalLink = resource.ExternalLink
+		create.SharedBlob = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// getOwnResourceBlob returns the blob in the database of the resource itself, which is empty if it shares the blob of another resource.
+func getOwnResourceBlob(ctx context.Context, s *store.Store, resource *store.Resource) ([]byte, error) {
+	resource, err := s.GetResource(ctx, &store.FindResource{
+		ID:      &resource.ID,
+		GetBlob: true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to find resource")
+	}
+	if resource == nil {
+		return nil, errors.New("Resource not found")
+	}
+	return resource.Blob, nil
+}
+
+func getResourceBlobLocation(resource *store.Resource) string {
+	return fmt.Sprintf("%d:%s:%s", resource.StorageID, resource.InternalPath, resource.ExternalLink)
+}
@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/usememos/memos/internal/log"
+	"github.com/usememos/memos/plugin/exif"
+	"github.com/usememos/memos/store"
+)
+
+// errImageMetadataNotStripped is returned for the images whose metadata can't be stripped, which are rejected by the setting.
+var errImageMetadataNotStripped = errors.New("Failed to strip the metadata of the image")
+
+// processResourceEXIF reads the EXIF of the photo to save, keeping its capture time as `create.CapturedTs`,
+// and strips the location and device data out of it if it's enabled by the setting.
+// If the metadata can't be parsed, the image is encoded again from its pixels, or rejected if it can't be decoded either.
+// It returns the blob to save, and resets `create.Size` and `create.Hash` if the blob is changed.
+// The orientation returned is the one the blob is still displayed in by its EXIF, which is 1 if it's upright or stripped.
+func processResourceEXIF(ctx context.Context, s *store.Store, create *store.Resource, r io.ReadSeeker) (io.ReadSeeker, int, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+	blob, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "Failed to read file")
+	}
+	stripped, metadata, err := exif.Strip(blob)
+	if err != nil {
+		if !isImageMetadataStripped(ctx, s) {
+			// The images which can't be parsed are saved as is, as well as they're served.
+			log.Warn("Failed to read the EXIF of resource", zap.String("filename", create.Filename), zap.Error(err))
+			return bytes.NewReader(blob), 1, nil
+		}
+		// The metadata may be left in the parts which can't be parsed, so only the pixels are kept if they can be decoded.
+		reencoded, reencodeErr := exif.Reencode(blob)
+		if reencodeErr != nil {
+			return nil, 0, errors.Wrap(errImageMetadataNotStripped, reencodeErr.Error())
+		}
+		log.Warn("Encoded the resource again to strip its EXIF", zap.String("filename", create.Filename), zap.Error(err))
+		create.Size = int64(len(reencoded))
+		create.Hash = ""
+		return bytes.NewReader(reencoded), 1, nil
+	}
+	if !metadata.CapturedTime.IsZero() {
+		create.CapturedTs = metadata.CapturedTime.Unix()
+	}
+	if !isImageMetadataStripped(ctx, s) || bytes.Equal(stripped, blob) {
+		return bytes.NewReader(blob), metadata.Orientation, nil
+	}
+	create.Size = int64(len(stripped))
+	create.Hash = ""
+	return bytes.NewReader(stripped), 1, nil
+}
+
+// isImageMetadataStripped returns whether the metadata of the uploaded images is stripped by the setting, which is by default.
+func isImageMetadataStripped(ctx context.Context, s *store.Store) bool {
+	value := s.GetSystemSettingValueWithDefault(ctx, SystemSettingStripImageMetadataName.String(), "true")
+	stripped, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Warn("Failed to parse strip image metadata setting", zap.Error(err))
+		return true
+	}
+	return stripped
+}
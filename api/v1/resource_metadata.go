@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"math"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/usememos/memos/internal/log"
+	"github.com/usememos/memos/plugin/exif"
+	"github.com/usememos/memos/plugin/probe"
+	"github.com/usememos/memos/store"
+)
+
+// processResourceBlob sniffs the MIME type of the blob to save as `create.Type`, strips the EXIF of the photos,
+// and probes the dimensions and the duration of the media into `create`.
+// It returns the blob to save from its start, along with the cleanup func to call once it's saved.
+func processResourceBlob(ctx context.Context, s *store.Store, create *store.Resource, r io.Reader) (io.Reader, func(), error) {
+	blob, cleanup, err := spoolResourceBlob(r)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to read file")
+	}
+	head := make([]byte, probe.SniffLength)
+	n, err := io.ReadFull(blob, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		cleanup()
+		return nil, nil, errors.Wrap(err, "Failed to read file")
+	}
+	// The type claimed by the client isn't trusted, so that the HTML disguised as an image isn't served as one.
+	create.Type = probe.Sniff(head[:n], create.Type)
+
+	orientation := 1
+	if exif.IsSupported(create.Type) {
+		blob, orientation, err = processResourceEXIF(ctx, s, create, blob)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+	}
+	info, err := probe.Probe(blob, create.Type)
+	if err != nil {
+		// The media which can't be probed are saved without their metadata.
+		log.Warn("Failed to probe resource", zap.String("filename", create.Filename), zap.Error(err))
+	} else {
+		create.Width, create.Height = int32(min(info.Width, math.MaxInt32)), int32(min(info.Height, math.MaxInt32))
+		// The photos in the orientations from 5 to 8 are displayed rotated by 90 or 270 degrees.
+		if orientation >= 5 {
+			create.Width, create.Height = create.Height, create.Width
+		}
+		create.Duration = info.Duration.Milliseconds()
+	}
+	if _, err := blob.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, errors.Wrap(err, "Failed to read file")
+	}
+	return blob, cleanup, nil
+}
+
+// spoolResourceBlob returns the blob as a seeker from its start, which is spooled into a temporary file unless it's one already.
+func spoolResourceBlob(r io.Reader) (io.ReadSeeker, func(), error) {
+	if seeker, ok := r.(io.ReadSeeker); ok {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil && offset == 0 {
+			return seeker, func() {}, nil
+		}
+	}
+
+	file, err := os.CreateTemp("", "memos-resource-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		file.Close()
+		os.Remove(file.Name())
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return file, cleanup, nil
+}
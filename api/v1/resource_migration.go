@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	apiresource "github.com/usememos/memos/api/resource"
+	"github.com/usememos/memos/internal/log"
+	"github.com/usememos/memos/store"
+)
+
+// GetResourceStorageID returns the storage of the blob of the resource, i.e. DatabaseStorage, LocalStorage or the ID of a storage.
+// It returns false if the resource is an external link out of the storages.
+func GetResourceStorageID(ctx context.Context, s *store.Store, resource *store.Resource) (int32, bool, error) {
+	if resource.StorageID != 0 {
+		return resource.StorageID, true, nil
+	}
+	if resource.InternalPath != "" {
+		return LocalStorage, true, nil
+	}
+	if resource.ExternalLink == "" {
+		return DatabaseStorage, true, nil
+	}
+	storage, _, _, err := apiresource.FindS3Object(ctx, s, resource.ExternalLink)
+	if err != nil {
+		return 0, false, err
+	}
+	if storage == nil {
+		return 0, false, nil
+	}
+	return storage.ID, true, nil
+}
+
+// MoveResourceBlob moves the blob of the resource into the storage, i.e. DatabaseStorage, LocalStorage or the ID of a storage.
+//
+// The copy is verified by its size and SHA-256 checksum before the resource is switched to it in a single update,
+// and the original blob is only deleted after that, so an interrupted move leaves the resource intact in either storage.
+func MoveResourceBlob(ctx context.Context, s *store.Store, resource *store.Resource, storageID int32) (*store.Resource, error) {
+	fromStorageID, ok, err := GetResourceStorageID(ctx, s, resource)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Errorf("Resource %d is an external link out of the storages", resource.ID)
+	}
+	if fromStorageID == storageID {
+		return resource, nil
+	}
+
+	reader, err := OpenResourceBlob(ctx, s, resource)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open blob")
+	}
+	defer reader.Close()
+	hash, counter := sha256.New(), new(byteCounter)
+	target := &store.Resource{
+		Filename: resource.Filename,
+		Type:     resource.Type,
+		Size:     resource.Size,
+	}
+	if err := SaveResourceBlobToStorage(ctx, s, storageID, target, io.TeeReader(reader, io.MultiWriter(hash, counter))); err != nil {
+		return nil, errors.Wrap(err, "Failed to save blob")
+	}
+	if err := verifyResourceBlob(ctx, s, target, int64(*counter), hash.Sum(nil)); err != nil {
+		if deleteErr := deleteResourceBlob(ctx, s, target); deleteErr != nil {
+			log.Warn("Failed to delete the unverified blob", zap.Int32("resourceId", resource.ID), zap.Error(deleteErr))
+		}
+		return nil, err
+	}
+	if resource.Size != 0 && resource.Size != int64(*counter) {
+		log.Warn("Resource size mismatches its blob", zap.Int32("resourceId", resource.ID), zap.Int64("size", resource.Size), zap.Int64("blobSize", int64(*counter)))
+	}
+
+	update := &store.UpdateResource{
+		ID:           resource.ID,
+		InternalPath: &target.InternalPath,
+		ExternalLink: &target.ExternalLink,
+		StorageID:    &target.StorageID,
+		Hash:         &target.Hash,
+		Blob:         target.Blob,
+	}
+	if update.Blob == nil {
+		update.Blob = []byte{}
+	}
+	updated, err := s.UpdateResource(ctx, update)
+	if err != nil {
+		if deleteErr := deleteResourceBlob(ctx, s, target); deleteErr != nil {
+			log.Warn("Failed to delete the moved blob", zap.Int32("resourceId", resource.ID), zap.Error(deleteErr))
+		}
+		return nil, errors.Wrap(err, "Failed to update resource")
+	}
+
+	// The blob in the database has been cleared by the update.
+	if err := deleteResourceBlob(ctx, s, resource); err != nil {
+		log.Warn("Failed to delete the original blob", zap.Int32("resourceId", resource.ID), zap.Error(err))
+	}
+	// The previews are kept along with the blob, so they're generated again in the storage moved into.
+	if err := apiresource.DeleteResourcePreviews(ctx, s, resource); err != nil {
+		log.Warn("Failed to delete the original previews", zap.Int32("resourceId", resource.ID), zap.Error(err))
+	}
+	apiresource.EnqueueResourcePreviews(s, updated)
+	return updated, nil
+}
+
+// verifyResourceBlob reads the saved blob back and checks its size and checksum.
+func verifyResourceBlob(ctx context.Context, s *store.Store, resource *store.Resource, size int64, checksum []byte) error {
+	var reader io.ReadCloser
+	if isDatabaseBlob(resource) {
+		blob := resource.Blob
+		// The blob isn't saved again if it's shared with the resources of the same hash.
+		if blob == nil {
+			var err error
+			if blob, err = s.GetResourceBlob(ctx, resource); err != nil {
+				return errors.Wrap(err, "Failed to find the shared blob")
+			}
+		}
+		reader = io.NopCloser(bytes.NewReader(blob))
+	} else {
+		var err error
+		if reader, err = OpenResourceBlob(ctx, s, resource); err != nil {
+			return errors.Wrap(err, "Failed to open the saved blob")
+		}
+		if reader == nil {
+			return errors.New("The saved blob is out of the storages")
+		}
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	n, err := io.Copy(hash, reader)
+	if err != nil {
+		return errors.Wrap(err, "Failed to read the saved blob")
+	}
+	if n != size || !bytes.Equal(hash.Sum(nil), checksum) {
+		return errors.Errorf("Checksum of the saved blob mismatches, got %d bytes", n)
+	}
+	return nil
+}
+
+// deleteResourceBlob deletes the blob of the resource from its storage, unless other resources still share it.
+// The blob in the database is deleted with the resource, or cleared by the update of it.
+func deleteResourceBlob(ctx context.Context, s *store.Store, resource *store.Resource) error {
+	if isDatabaseBlob(resource) {
+		return nil
+	}
+	references, err := s.ListResourceBlobReferences(ctx, resource)
+	if err != nil {
+		return errors.Wrap(err, "Failed to list resource blob references")
+	}
+	if len(references) > 0 {
+		return nil
+	}
+	backend, key, err := apiresource.GetResourceBackend(ctx, s, resource)
+	if err != nil {
+		return err
+	}
+	if backend == nil {
+		return nil
+	}
+	if err := backend.Delete(ctx, key); err != nil {
+		return errors.Wrap(err, "Failed to delete blob from storage")
+	}
+	return nil
+}
+
+func isDatabaseBlob(resource *store.Resource) bool {
+	return resource.StorageID == 0 && resource.InternalPath == "" && resource.ExternalLink == ""
+}
+
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,591 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	apiresource "github.com/usememos/memos/api/resource"
+	"github.com/usememos/memos/internal/log"
+	"github.com/usememos/memos/internal/util"
+	"github.com/usememos/memos/plugin/storage"
+	"github.com/usememos/memos/plugin/storage/local"
+	"github.com/usememos/memos/store"
+)
+
+// ResourceUpload is a resumable upload of a resource.
+// The blob is uploaded as chunks at the offset, and the upload is finalized into the resource once the whole blob is received.
+type ResourceUpload struct {
+	ID int32 `json:"id"`
+
+	// Standard fields
+	CreatorID int32 `json:"creatorId"`
+	CreatedTs int64 `json:"createdTs"`
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Domain specific fields
+	Filename string `json:"filename"`
+	Type     string `json:"type"`
+	Size     int64  `json:"size"`
+	// Offset is the size received, where the next chunk starts.
+	Offset int64 `json:"offset"`
+}
+
+type CreateResourceUploadRequest struct {
+	Filename string `json:"filename"`
+	Type     string `json:"type"`
+	Size     int64  `json:"size"`
+}
+
+const (
+	// UploadOffsetHeader is the header of the offset of the upload, where the chunk starts in the requests.
+	UploadOffsetHeader = "Upload-Offset"
+	// UploadLengthHeader is the header of the size of the upload.
+	UploadLengthHeader = "Upload-Length"
+	// ResourceUploadExpiration is how long the uploads are kept since their last chunks.
+	ResourceUploadExpiration = 24 * time.Hour
+	// resourceUploadCachePath is the directory of the parts of the uploads in the storages.
+	resourceUploadCachePath = ".upload_cache"
+)
+
+// resourceUploadLocks keeps the uploads from receiving the chunks and being finalized or deleted at the same time.
+var resourceUploadLocks sync.Map
+
+func (s *APIV1Service) registerResourceUploadRoutes(g *echo.Group) {
+	g.POST("/resource/upload", s.CreateResourceUpload)
+	g.GET("/resource/upload/:uploadId", s.GetResourceUpload)
+	g.HEAD("/resource/upload/:uploadId", s.GetResourceUpload)
+	g.PATCH("/resource/upload/:uploadId", s.UploadResourceChunk)
+	g.POST("/resource/upload/:uploadId/finalize", s.FinalizeResourceUpload)
+	g.DELETE("/resource/upload/:uploadId", s.DeleteResourceUpload)
+}
+
+// CreateResourceUpload godoc
+//
+//	@Summary	Create a resumable upload of a resource
+//	@Tags		resource
+//	@Accept		json
+//	@Produce	json
+//	@Param		body	body		CreateResourceUploadRequest	true	"Request object."
+//	@Success	200		{object}	ResourceUpload				"Created resource upload"
+//	@Failure	400		{object}	nil							"Malformatted create resource upload request | Invalid size | File size exceeds allowed limit of %d MiB"
+//	@Failure	401		{object}	nil							"Missing user in session"
+//	@Failure	500		{object}	nil							"Failed to find storage service | Failed to create resource upload"
+//	@Router		/api/v1/resource/upload [POST]
+func (s *APIV1Service) CreateResourceUpload(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(userIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	request := &CreateResourceUploadRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted create resource upload request").SetInternal(err)
+	}
+	if request.Size < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid size")
+	}
+	settingMaxUploadSizeBytes := getMaxUploadSizeBytes(ctx, s.Store)
+	if request.Size > settingMaxUploadSizeBytes {
+		message := fmt.Sprintf("File size exceeds allowed limit of %d MiB", settingMaxUploadSizeBytes/MebiByte)
+		return echo.NewHTTPError(http.StatusBadRequest, message)
+	}
+	storageServiceID, err := getStorageServiceID(ctx, s.Store)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find storage service").SetInternal(err)
+	}
+
+	upload, err := s.Store.CreateResourceUpload(ctx, &store.ResourceUpload{
+		CreatorID: userID,
+		Filename:  request.Filename,
+		Type:      request.Type,
+		Size:      request.Size,
+		StorageID: storageServiceID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create resource upload").SetInternal(err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/resource/upload/%d", upload.ID))
+	return s.writeResourceUpload(c, upload)
+}
+
+// GetResourceUpload godoc
+//
+//	@Summary	Get the offset of a resource upload
+//	@Tags		resource
+//	@Produce	json
+//	@Param		uploadId	path		int				true	"Upload ID"
+//	@Success	200			{object}	ResourceUpload	"Resource upload"
+//	@Failure	400			{object}	nil				"ID is not a number: %s"
+//	@Failure	401			{object}	nil				"Missing user in session"
+//	@Failure	404			{object}	nil				"Resource upload not found: %d"
+//	@Failure	500			{object}	nil				"Failed to find resource upload"
+//	@Router		/api/v1/resource/upload/{uploadId} [GET]
+func (s *APIV1Service) GetResourceUpload(c echo.Context) error {
+	uploadID, err := util.ConvertStringToInt32(c.Param("uploadId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("uploadId"))).SetInternal(err)
+	}
+	upload, err := s.findResourceUpload(c, uploadID)
+	if err != nil {
+		return err
+	}
+	return s.writeResourceUpload(c, upload)
+}
+
+// UploadResourceChunk godoc
+//
+//	@Summary		Upload a chunk of a resource upload
+//	@Description	The chunk is the request body, which starts at the Upload-Offset header. It must be the offset of the upload.
+//	@Tags			resource
+//	@Accept			application/offset+octet-stream
+//	@Produce		json
+//	@Param			uploadId		path		int				true	"Upload ID"
+//	@Param			Upload-Offset	header		int				true	"Offset of the chunk"
+//	@Success		200				{object}	ResourceUpload	"Resource upload"
+//	@Failure		400				{object}	nil				"ID is not a number: %s | Invalid Upload-Offset"
+//	@Failure		401				{object}	nil				"Missing user in session"
+//	@Failure		404				{object}	nil				"Resource upload not found: %d"
+//	@Failure		409				{object}	nil				"Resource upload is in progress | Upload-Offset mismatches the offset %d"
+//	@Failure		413				{object}	nil				"Chunk exceeds the upload size"
+//	@Failure		500				{object}	nil				"Failed to find resource upload | Failed to save chunk | Failed to update resource upload"
+//	@Router			/api/v1/resource/upload/{uploadId} [PATCH]
+func (s *APIV1Service) UploadResourceChunk(c echo.Context) error {
+	ctx := c.Request().Context()
+	uploadID, err := util.ConvertStringToInt32(c.Param("uploadId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("uploadId"))).SetInternal(err)
+	}
+	unlock, ok := lockResourceUpload(uploadID)
+	if !ok {
+		return echo.NewHTTPError(http.StatusConflict, "Resource upload is in progress")
+	}
+	defer unlock()
+	upload, err := s.findResourceUpload(c, uploadID)
+	if err != nil {
+		return err
+	}
+
+	offset, err := strconv.ParseInt(c.Request().Header.Get(UploadOffsetHeader), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid Upload-Offset").SetInternal(err)
+	}
+	if offset != upload.Offset {
+		c.Response().Header().Set(UploadOffsetHeader, strconv.FormatInt(upload.Offset, 10))
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Upload-Offset mismatches the offset %d", upload.Offset))
+	}
+	remaining := upload.Size - upload.Offset
+	if c.Request().ContentLength > remaining {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "Chunk exceeds the upload size")
+	}
+
+	uploadHash, err := restoreResourceUploadHash(upload)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save chunk").SetInternal(err)
+	}
+	backend, err := getResourceUploadBackend(ctx, s.Store, upload)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save chunk").SetInternal(err)
+	}
+	// The chunk is streamed into the storage as the next part, and the part is overwritten if the chunk fails.
+	counter := new(byteCounter)
+	body := io.TeeReader(io.LimitReader(c.Request().Body, remaining+1), io.MultiWriter(uploadHash, counter))
+	if err := backend.Put(ctx, getResourceUploadPartKey(upload.ID, upload.PartCount), body, c.Request().ContentLength, "application/octet-stream"); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save chunk").SetInternal(err)
+	}
+	if int64(*counter) > remaining {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "Chunk exceeds the upload size")
+	}
+	if *counter == 0 {
+		return s.writeResourceUpload(c, upload)
+	}
+
+	hashState, err := uploadHash.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update resource upload").SetInternal(err)
+	}
+	updatedTs := time.Now().Unix()
+	offset += int64(*counter)
+	partCount := upload.PartCount + 1
+	upload, err = s.Store.UpdateResourceUpload(ctx, &store.UpdateResourceUpload{
+		ID:        upload.ID,
+		UpdatedTs: &updatedTs,
+		Offset:    &offset,
+		PartCount: &partCount,
+		HashState: hashState,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update resource upload").SetInternal(err)
+	}
+	return s.writeResourceUpload(c, upload)
+}
+
+// FinalizeResourceUpload godoc
+//
+//	@Summary	Finalize a resource upload into a resource
+//	@Tags		resource
+//	@Produce	json
+//	@Param		uploadId	path		int				true	"Upload ID"
+//	@Success	200			{object}	store.Resource	"Created resource"
+//	@Failure	400			{object}	nil				"ID is not a number: %s | Failed to strip the metadata of the image"
+//	@Failure	401			{object}	nil				"Missing user in session"
+//	@Failure	404			{object}	nil				"Resource upload not found: %d"
+//	@Failure	409			{object}	nil				"Resource upload is in progress | Resource upload is incomplete, %d of %d bytes received"
+//	@Failure	500			{object}	nil				"Failed to find resource upload | Failed to finalize resource upload"
+//	@Router		/api/v1/resource/upload/{uploadId}/finalize [POST]
+func (s *APIV1Service) FinalizeResourceUpload(c echo.Context) error {
+	ctx := c.Request().Context()
+	uploadID, err := util.ConvertStringToInt32(c.Param("uploadId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("uploadId"))).SetInternal(err)
+	}
+	unlock, ok := lockResourceUpload(uploadID)
+	if !ok {
+		return echo.NewHTTPError(http.StatusConflict, "Resource upload is in progress")
+	}
+	defer unlock()
+	upload, err := s.findResourceUpload(c, uploadID)
+	if err != nil {
+		return err
+	}
+	if upload.Offset != upload.Size {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Resource upload is incomplete, %d of %d bytes received", upload.Offset, upload.Size))
+	}
+
+	resource, err := finalizeResourceUpload(ctx, s.Store, upload)
+	if errors.Is(err, errImageMetadataNotStripped) {
+		return echo.NewHTTPError(http.StatusBadRequest, errImageMetadataNotStripped.Error()).SetInternal(err)
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to finalize resource upload").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, convertResourceFromStore(resource))
+}
+
+// DeleteResourceUpload godoc
+//
+//	@Summary	Cancel a resource upload
+//	@Tags		resource
+//	@Produce	json
+//	@Param		uploadId	path		int		true	"Upload ID"
+//	@Success	200			{boolean}	true	"Resource upload deleted"
+//	@Failure	400			{object}	nil		"ID is not a number: %s"
+//	@Failure	401			{object}	nil		"Missing user in session"
+//	@Failure	404			{object}	nil		"Resource upload not found: %d"
+//	@Failure	409			{object}	nil		"Resource upload is in progress"
+//	@Failure	500			{object}	nil		"Failed to find resource upload | Failed to delete resource upload"
+//	@Router		/api/v1/resource/upload/{uploadId} [DELETE]
+func (s *APIV1Service) DeleteResourceUpload(c echo.Context) error {
+	ctx := c.Request().Context()
+	uploadID, err := util.ConvertStringToInt32(c.Param("uploadId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("uploadId"))).SetInternal(err)
+	}
+	unlock, ok := lockResourceUpload(uploadID)
+	if !ok {
+		return echo.NewHTTPError(http.StatusConflict, "Resource upload is in progress")
+	}
+	defer unlock()
+	upload, err := s.findResourceUpload(c, uploadID)
+	if err != nil {
+		return err
+	}
+
+	if err := deleteResourceUpload(ctx, s.Store, upload); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete resource upload").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, true)
+}
+
+// PurgeResourceUploads deletes the uploads abandoned for longer than ResourceUploadExpiration, along with their parts.
+// It returns the number of the uploads deleted.
+func PurgeResourceUploads(ctx context.Context, s *store.Store) (int, error) {
+	updatedTsBefore := time.Now().Add(-ResourceUploadExpiration).Unix()
+	uploads, err := s.ListResourceUploads(ctx, &store.FindResourceUpload{
+		UpdatedTsBefore: &updatedTsBefore,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to list resource uploads")
+	}
+
+	count := 0
+	for _, upload := range uploads {
+		unlock, ok := lockResourceUpload(upload.ID)
+		// The upload is receiving a chunk right now.
+		if !ok {
+			continue
+		}
+		err := deleteResourceUpload(ctx, s, upload)
+		unlock()
+		if err != nil {
+			return count, errors.Wrapf(err, "Failed to delete resource upload %d", upload.ID)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// findResourceUpload finds the upload of the user in session.
+func (s *APIV1Service) findResourceUpload(c echo.Context, uploadID int32) (*store.ResourceUpload, error) {
+	userID, ok := c.Get(userIDContextKey).(int32)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	upload, err := s.Store.GetResourceUpload(c.Request().Context(), &store.FindResourceUpload{
+		ID:        &uploadID,
+		CreatorID: &userID,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to find resource upload").SetInternal(err)
+	}
+	if upload == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Resource upload not found: %d", uploadID))
+	}
+	return upload, nil
+}
+
+// writeResourceUpload writes the upload with its offset and size in the headers as well.
+func (*APIV1Service) writeResourceUpload(c echo.Context, upload *store.ResourceUpload) error {
+	c.Response().Header().Set(UploadOffsetHeader, strconv.FormatInt(upload.Offset, 10))
+	c.Response().Header().Set(UploadLengthHeader, strconv.FormatInt(upload.Size, 10))
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusOK, convertResourceUploadFromStore(upload))
+}
+
+// finalizeResourceUpload saves the parts of the upload as the blob of a new resource, and deletes the upload.
+func finalizeResourceUpload(ctx context.Context, s *store.Store, upload *store.ResourceUpload) (*store.Resource, error) {
+	uploadHash, err := restoreResourceUploadHash(upload)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := getResourceUploadBackend(ctx, s, upload)
+	if err != nil {
+		return nil, err
+	}
+	// The parts are read as a seekable blob, so the type is sniffed from the first part and the media are probed by the ranges
+	// they need, without spooling the blob before saving it.
+	reader, err := newResourceUploadReader(ctx, backend, upload)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	create := &store.Resource{
+		CreatorID: upload.CreatorID,
+		Filename:  upload.Filename,
+		Type:      upload.Type,
+		Size:      upload.Size,
+		Hash:      hex.EncodeToString(uploadHash.Sum(nil)),
+	}
+	blob, cleanup, err := processResourceBlob(ctx, s, create, reader)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	if err := SaveResourceBlobToStorage(ctx, s, upload.StorageID, create, blob); err != nil {
+		return nil, errors.Wrap(err, "Failed to save resource")
+	}
+	resource, err := s.CreateResource(ctx, create)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create resource")
+	}
+	apiresource.EnqueueResourcePreviews(s, resource)
+	if err := deleteResourceUpload(ctx, s, upload); err != nil {
+		log.Warn("Failed to delete the finalized resource upload", zap.Int32("uploadId", upload.ID), zap.Error(err))
+	}
+	return resource, nil
+}
+
+// deleteResourceUpload deletes the upload and its parts.
+func deleteResourceUpload(ctx context.Context, s *store.Store, upload *store.ResourceUpload) error {
+	if upload.StorageID > 0 {
+		backend, err := getResourceUploadBackend(ctx, s, upload)
+		if err != nil {
+			return err
+		}
+		// The part after the last one may be left by a chunk failed to receive.
+		for i := int32(0); i <= upload.PartCount; i++ {
+			if err := backend.Delete(ctx, getResourceUploadPartKey(upload.ID, i)); err != nil {
+				return errors.Wrap(err, "Failed to delete part")
+			}
+		}
+	} else {
+		if err := os.RemoveAll(filepath.Join(s.Profile.Data, resourceUploadCachePath, strconv.Itoa(int(upload.ID)))); err != nil {
+			return errors.Wrap(err, "Failed to delete parts")
+		}
+	}
+	if err := s.DeleteResourceUpload(ctx, &store.DeleteResourceUpload{ID: upload.ID}); err != nil {
+		return err
+	}
+	resourceUploadLocks.Delete(upload.ID)
+	return nil
+}
+
+// getResourceUploadBackend returns the backend in which the parts of the upload are kept, i.e. the storage of the upload,
+// or the local storage if the upload is into the database or the local storage.
+func getResourceUploadBackend(ctx context.Context, s *store.Store, upload *store.ResourceUpload) (storage.Backend, error) {
+	if upload.StorageID <= 0 {
+		return local.New(s.Profile.Data), nil
+	}
+	storage, err := s.GetStorage(ctx, &store.FindStorage{ID: &upload.StorageID})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to find storage")
+	}
+	if storage == nil {
+		return nil, errors.Errorf("Storage %d not found", upload.StorageID)
+	}
+	return apiresource.NewStorageBackend(ctx, storage)
+}
+
+func getResourceUploadPartKey(uploadID int32, index int32) string {
+	return fmt.Sprintf("%s/%d/%d", resourceUploadCachePath, uploadID, index)
+}
+
+// restoreResourceUploadHash returns the hash of the blob received by the upload, which continues with the next chunks.
+func restoreResourceUploadHash(upload *store.ResourceUpload) (hash.Hash, error) {
+	uploadHash := sha256.New()
+	if len(upload.HashState) > 0 {
+		if err := uploadHash.(encoding.BinaryUnmarshaler).UnmarshalBinary(upload.HashState); err != nil {
+			return nil, errors.Wrap(err, "Failed to restore hash")
+		}
+	}
+	return uploadHash, nil
+}
+
+// lockResourceUpload locks the upload, and returns false if it's locked already.
+func lockResourceUpload(uploadID int32) (func(), bool) {
+	value, _ := resourceUploadLocks.LoadOrStore(uploadID, &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	if !mutex.TryLock() {
+		return nil, false
+	}
+	return mutex.Unlock, true
+}
+
+// resourceUploadReader reads the parts of the upload as one blob, each of which is opened from the offset when it's reached.
+// It seeks by the sizes of the parts without reading them.
+type resourceUploadReader struct {
+	ctx     context.Context
+	backend storage.Backend
+	upload  *store.ResourceUpload
+	sizes   []int64
+	size    int64
+	offset  int64
+	part    io.ReadCloser
+}
+
+func newResourceUploadReader(ctx context.Context, backend storage.Backend, upload *store.ResourceUpload) (*resourceUploadReader, error) {
+	reader := &resourceUploadReader{
+		ctx:     ctx,
+		backend: backend,
+		upload:  upload,
+		sizes:   make([]int64, upload.PartCount),
+	}
+	for i := range reader.sizes {
+		objectInfo, err := backend.Stat(ctx, getResourceUploadPartKey(upload.ID, int32(i)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to stat part %d", i)
+		}
+		reader.sizes[i] = objectInfo.Size
+		reader.size += objectInfo.Size
+	}
+	return reader, nil
+}
+
+func (r *resourceUploadReader) Read(p []byte) (int, error) {
+	for {
+		if r.part == nil {
+			index, offset, ok := r.locate(r.offset)
+			if !ok {
+				return 0, io.EOF
+			}
+			part, err := r.backend.GetRange(r.ctx, getResourceUploadPartKey(r.upload.ID, index), offset, -1)
+			if err != nil {
+				return 0, err
+			}
+			r.part = part
+		}
+		n, err := r.part.Read(p)
+		r.offset += int64(n)
+		if err == io.EOF {
+			r.part.Close()
+			r.part = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (r *resourceUploadReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	if offset != r.offset {
+		if err := r.Close(); err != nil {
+			return 0, err
+		}
+		r.offset = offset
+	}
+	return offset, nil
+}
+
+// locate returns the part in which the offset of the blob is, and the offset in the part.
+func (r *resourceUploadReader) locate(offset int64) (int32, int64, bool) {
+	for i, size := range r.sizes {
+		if offset < size {
+			return int32(i), offset, true
+		}
+		offset -= size
+	}
+	return 0, 0, false
+}
+
+func (r *resourceUploadReader) Close() error {
+	if r.part == nil {
+		return nil
+	}
+	err := r.part.Close()
+	r.part = nil
+	return err
+}
+
+func convertResourceUploadFromStore(upload *store.ResourceUpload) *ResourceUpload {
+	return &ResourceUpload{
+		ID:        upload.ID,
+		CreatorID: upload.CreatorID,
+		CreatedTs: upload.CreatedTs,
+		UpdatedTs: upload.UpdatedTs,
+		Filename:  upload.Filename,
+		Type:      upload.Type,
+		Size:      upload.Size,
+		Offset:    upload.Offset,
+	}
+}
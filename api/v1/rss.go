@@ -1,7 +1,6 @@
 package v1
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -13,9 +12,11 @@ import (
 	"github.com/gorilla/feeds"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
-	"github.com/yuin/goldmark"
 
+	"github.com/usememos/memos/api/filter"
 	"github.com/usememos/memos/internal/util"
+	"github.com/usememos/memos/plugin/gomark"
+	"github.com/usememos/memos/plugin/gomark/renderer"
 	"github.com/usememos/memos/store"
 )
 
@@ -66,10 +67,12 @@ func (s *APIV1Service) GetExploreRSS(c echo.Context) error {
 //	@Summary	Get RSS for a user
 //	@Tags		rss
 //	@Produce	xml
-//	@Param		id	path		int	true	"User ID"
-//	@Success	200	{object}	nil	"RSS"
-//	@Failure	400	{object}	nil	"User id is not a number"
-//	@Failure	500	{object}	nil	"Failed to get system customized profile | Failed to find memo list | Failed to generate rss"
+//	@Param		id		path		int		true	"User ID"
+//	@Param		filter	query		string	false	"Name of a saved filter of the user, which requires the access token of the user"
+//	@Success	200		{object}	nil		"RSS"
+//	@Failure	400		{object}	nil		"User id is not a number"
+//	@Failure	404		{object}	nil		"Saved filter not found"
+//	@Failure	500		{object}	nil		"Failed to get system customized profile | Failed to find access token user | Failed to find saved filters | Failed to parse saved filter | Failed to find memo list | Failed to generate rss"
 //	@Router		/u/{id}/rss.xml [GET]
 func (s *APIV1Service) GetUserRSS(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -89,6 +92,33 @@ func (s *APIV1Service) GetUserRSS(c echo.Context) error {
 		RowStatus:      &normalStatus,
 		VisibilityList: []store.Visibility{store.Public},
 	}
+	if name := c.QueryParam("filter"); name != "" {
+		// The saved filters are private, so they're only used with the access token of their user.
+		// The other callers get the same response as for a missing filter, so they can't tell the filters exist.
+		callerID, err := s.findAccessTokenUserID(c)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find access token user").SetInternal(err)
+		}
+		if callerID == id {
+			savedFilters, err := s.Store.GetUserSavedFilters(ctx, id)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find saved filters").SetInternal(err)
+			}
+			for _, savedFilter := range savedFilters {
+				if savedFilter.Name != name {
+					continue
+				}
+				memoFilter, err := filter.ParseMemoFilter(savedFilter.Filter, id)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to parse saved filter").SetInternal(err)
+				}
+				memoFind.Filter = memoFilter
+			}
+		}
+		if memoFind.Filter == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Saved filter not found: %s", name))
+		}
+	}
 	memoList, err := s.Store.ListMemos(ctx, &memoFind)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find memo list").SetInternal(err)
@@ -198,12 +228,8 @@ func getRSSItemDescription(content string) string {
 		description = content
 	}
 
-	// TODO: use our `./plugin/gomark` parser to handle markdown-like content.
-	var buf bytes.Buffer
-	if err := goldmark.Convert([]byte(description), &buf); err != nil {
-		panic(err)
-	}
-	return buf.String()
+	document := gomark.Parse(description)
+	return renderer.NewHTMLRenderer().Render(document.Nodes)
 }
 
 func isTitleDefined(content string) bool {
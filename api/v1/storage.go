@@ -1,13 +1,17 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 
 	"github.com/usememos/memos/internal/util"
+	"github.com/usememos/memos/plugin/storage/s3"
+	"github.com/usememos/memos/plugin/storage/sftp"
 	"github.com/usememos/memos/store"
 )
 
@@ -23,7 +27,9 @@ const (
 type StorageType string
 
 const (
-	StorageS3 StorageType = "S3"
+	StorageS3     StorageType = "S3"
+	StorageWebDAV StorageType = "WEBDAV"
+	StorageSFTP   StorageType = "SFTP"
 )
 
 func (t StorageType) String() string {
@@ -31,7 +37,9 @@ func (t StorageType) String() string {
 }
 
 type StorageConfig struct {
-	S3Config *StorageS3Config `json:"s3Config"`
+	S3Config     *StorageS3Config     `json:"s3Config"`
+	WebDAVConfig *StorageWebDAVConfig `json:"webdavConfig"`
+	SFTPConfig   *StorageSFTPConfig   `json:"sftpConfig"`
 }
 
 type StorageS3Config struct {
@@ -43,6 +51,29 @@ type StorageS3Config struct {
 	Bucket    string `json:"bucket"`
 	URLPrefix string `json:"urlPrefix"`
 	URLSuffix string `json:"urlSuffix"`
+	// Private keeps the objects private, which are served through /o/r/:resourceId after the visibility check.
+	Private bool `json:"private"`
+	// PresignExpiry is the seconds of the presigned URLs the private objects are redirected to, or 0 to proxy them.
+	PresignExpiry int64 `json:"presignExpiry"`
+}
+
+type StorageWebDAVConfig struct {
+	URL      string `json:"url"`
+	Path     string `json:"path"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type StorageSFTPConfig struct {
+	Host       string `json:"host"`
+	Port       int    `json:"port"`
+	Path       string `json:"path"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	PrivateKey string `json:"privateKey"`
+	// HostKey is required unless InsecureIgnoreHostKey is set explicitly.
+	HostKey               string `json:"hostKey"`
+	InsecureIgnoreHostKey bool   `json:"insecureIgnoreHostKey"`
 }
 
 type Storage struct {
@@ -122,7 +153,7 @@ func (s *APIV1Service) GetStorageList(c echo.Context) error {
 //	@Produce	json
 //	@Param		body	body		CreateStorageRequest	true	"Request object."
 //	@Success	200		{object}	store.Storage			"Created storage"
-//	@Failure	400		{object}	nil						"Malformatted post storage request"
+//	@Failure	400		{object}	nil						"Malformatted post storage request | Host key is required for SFTP storage unless insecureIgnoreHostKey is set"
 //	@Failure	401		{object}	nil						"Missing user in session"
 //	@Failure	500		{object}	nil						"Failed to find user | Failed to create storage | Failed to convert storage"
 //	@Router		/api/v1/storage [POST]
@@ -149,12 +180,15 @@ func (s *APIV1Service) CreateStorage(c echo.Context) error {
 	}
 
 	configString := ""
-	if create.Type == StorageS3 && create.Config.S3Config != nil {
-		configBytes, err := json.Marshal(create.Config.S3Config)
+	if create.Config != nil {
+		config, err := marshalStorageConfig(create.Type, create.Config)
+		if errors.Is(err, sftp.ErrHostKeyRequired) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Host key is required for SFTP storage unless insecureIgnoreHostKey is set")
+		}
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted post storage request").SetInternal(err)
 		}
-		configString = string(configBytes)
+		configString = config
 	}
 
 	storage, err := s.Store.CreateStorage(ctx, &store.Storage{
@@ -237,7 +271,7 @@ func (s *APIV1Service) DeleteStorage(c echo.Context) error {
 //	@Param		storageId	path		int						true	"Storage ID"
 //	@Param		patch		body		UpdateStorageRequest	true	"Patch request"
 //	@Success	200			{object}	store.Storage			"Updated resource"
-//	@Failure	400			{object}	nil						"ID is not a number: %s | Malformatted patch storage request | Malformatted post storage request"
+//	@Failure	400			{object}	nil						"ID is not a number: %s | Malformatted patch storage request | Malformatted post storage request | Host key is required for SFTP storage unless insecureIgnoreHostKey is set"
 //	@Failure	401			{object}	nil						"Missing user in session | Unauthorized"
 //	@Failure	500			{object}	nil						"Failed to find user | Failed to patch storage | Failed to convert storage"
 //	@Router		/api/v1/storage/{storageId} [PATCH]
@@ -274,12 +308,14 @@ func (s *APIV1Service) UpdateStorage(c echo.Context) error {
 		storageUpdate.Name = update.Name
 	}
 	if update.Config != nil {
-		if update.Type == StorageS3 {
-			configBytes, err := json.Marshal(update.Config.S3Config)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusBadRequest, "Malformatted post storage request").SetInternal(err)
-			}
-			configString := string(configBytes)
+		configString, err := marshalStorageConfig(update.Type, update.Config)
+		if errors.Is(err, sftp.ErrHostKeyRequired) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Host key is required for SFTP storage unless insecureIgnoreHostKey is set")
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted post storage request").SetInternal(err)
+		}
+		if configString != "" {
 			storageUpdate.Config = &configString
 		}
 	}
@@ -302,7 +338,8 @@ func ConvertStorageFromStore(storage *store.Storage) (*Storage, error) {
 		Type:   StorageType(storage.Type),
 		Config: &StorageConfig{},
 	}
-	if storageMessage.Type == StorageS3 {
+	switch storageMessage.Type {
+	case StorageS3:
 		s3Config := &StorageS3Config{}
 		if err := json.Unmarshal([]byte(storage.Config), s3Config); err != nil {
 			return nil, err
@@ -310,6 +347,73 @@ func ConvertStorageFromStore(storage *store.Storage) (*Storage, error) {
 		storageMessage.Config = &StorageConfig{
 			S3Config: s3Config,
 		}
+	case StorageWebDAV:
+		webdavConfig := &StorageWebDAVConfig{}
+		if err := json.Unmarshal([]byte(storage.Config), webdavConfig); err != nil {
+			return nil, err
+		}
+		storageMessage.Config = &StorageConfig{
+			WebDAVConfig: webdavConfig,
+		}
+	case StorageSFTP:
+		sftpConfig := &StorageSFTPConfig{}
+		if err := json.Unmarshal([]byte(storage.Config), sftpConfig); err != nil {
+			return nil, err
+		}
+		storageMessage.Config = &StorageConfig{
+			SFTPConfig: sftpConfig,
+		}
 	}
 	return storageMessage, nil
 }
+
+// marshalStorageConfig marshals the config of the storage type, or returns empty if the config of the type is missing.
+func marshalStorageConfig(storageType StorageType, config *StorageConfig) (string, error) {
+	var typedConfig any
+	switch {
+	case storageType == StorageS3 && config.S3Config != nil:
+		typedConfig = config.S3Config
+	case storageType == StorageWebDAV && config.WebDAVConfig != nil:
+		typedConfig = config.WebDAVConfig
+	case storageType == StorageSFTP && config.SFTPConfig != nil:
+		if config.SFTPConfig.HostKey == "" && !config.SFTPConfig.InsecureIgnoreHostKey {
+			return "", sftp.ErrHostKeyRequired
+		}
+		typedConfig = config.SFTPConfig
+	default:
+		return "", nil
+	}
+	configBytes, err := json.Marshal(typedConfig)
+	if err != nil {
+		return "", err
+	}
+	return string(configBytes), nil
+}
+
+// NewS3Client creates the client of the storage, which must be a S3 storage.
+func NewS3Client(ctx context.Context, storage *store.Storage) (*s3.Client, error) {
+	storageMessage, err := ConvertStorageFromStore(storage)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to ConvertStorageFromStore")
+	}
+	if storageMessage.Type != StorageS3 {
+		return nil, errors.Errorf("Unsupported storage type: %s", storageMessage.Type)
+	}
+
+	s3Config := storageMessage.Config.S3Config
+	s3Client, err := s3.NewClient(ctx, &s3.Config{
+		AccessKey:     s3Config.AccessKey,
+		SecretKey:     s3Config.SecretKey,
+		EndPoint:      s3Config.EndPoint,
+		Region:        s3Config.Region,
+		Bucket:        s3Config.Bucket,
+		URLPrefix:     s3Config.URLPrefix,
+		URLSuffix:     s3Config.URLSuffix,
+		Private:       s3Config.Private,
+		PresignExpiry: s3Config.PresignExpiry,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create s3 client")
+	}
+	return s3Client, nil
+}
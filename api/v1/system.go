@@ -40,6 +40,12 @@ type SystemStatus struct {
 	LocalStoragePath string `json:"localStoragePath"`
 	// Memo display with updated timestamp.
 	MemoDisplayWithUpdatedTs bool `json:"memoDisplayWithUpdatedTs"`
+	// Memo revision retention as days.
+	MemoRevisionRetention int `json:"memoRevisionRetention"`
+	// Trash retention as days.
+	TrashRetention int `json:"trashRetention"`
+	// Strip the location and device data out of the uploaded photos.
+	StripImageMetadata bool `json:"stripImageMetadata"`
 }
 
 func (s *APIV1Service) registerSystemRoutes(g *echo.Group) {
@@ -86,6 +92,9 @@ func (s *APIV1Service) GetSystemStatus(c echo.Context) error {
 		},
 		StorageServiceID: DefaultStorage,
 		LocalStoragePath: "assets/{timestamp}_{filename}",
+		TrashRetention:   DefaultTrashRetention,
+		// Strip the metadata of images by default.
+		StripImageMetadata: true,
 	}
 
 	hostUserType := store.RoleHost
@@ -142,6 +151,12 @@ func (s *APIV1Service) GetSystemStatus(c echo.Context) error {
 			systemStatus.LocalStoragePath = baseValue.(string)
 		case SystemSettingMemoDisplayWithUpdatedTsName.String():
 			systemStatus.MemoDisplayWithUpdatedTs = baseValue.(bool)
+		case SystemSettingMemoRevisionRetentionName.String():
+			systemStatus.MemoRevisionRetention = int(baseValue.(float64))
+		case SystemSettingTrashRetentionName.String():
+			systemStatus.TrashRetention = int(baseValue.(float64))
+		case SystemSettingStripImageMetadataName.String():
+			systemStatus.StripImageMetadata = baseValue.(bool)
 		default:
 			log.Warn("Unknown system setting name", zap.String("setting name", systemSetting.Name))
 		}
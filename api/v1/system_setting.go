@@ -40,9 +40,20 @@ const (
 	SystemSettingTelegramBotTokenName SystemSettingName = "telegram-bot-token"
 	// SystemSettingMemoDisplayWithUpdatedTsName is the name of memo display with updated ts.
 	SystemSettingMemoDisplayWithUpdatedTsName SystemSettingName = "memo-display-with-updated-ts"
-	// SystemSettingAutoBackupIntervalName is the name of auto backup interval as seconds.
+	// SystemSettingAutoBackupIntervalName is the name of auto backup interval as seconds,
+	// which is only used when the backup config has not been set.
 	SystemSettingAutoBackupIntervalName SystemSettingName = "auto-backup-interval"
+	// SystemSettingMemoRevisionRetentionName is the name of memo revision retention as days, 0 means forever.
+	SystemSettingMemoRevisionRetentionName SystemSettingName = "memo-revision-retention"
+	// SystemSettingTrashRetentionName is the name of trash retention as days, the memos in the trash are purged after it.
+	SystemSettingTrashRetentionName SystemSettingName = "trash-retention"
+	// SystemSettingStripImageMetadataName is the name of whether to strip the location and device data out of the uploaded photos, which is by default.
+	SystemSettingStripImageMetadataName SystemSettingName = "strip-image-metadata"
 )
+
+// DefaultTrashRetention is the default days to keep the memos in the trash.
+const DefaultTrashRetention = 30
+
 const systemSettingUnmarshalError = `failed to unmarshal value from system setting "%v"`
 
 // CustomizedProfile is the struct definition for SystemSettingCustomizedProfileName system setting item.
@@ -250,6 +261,14 @@ func (upsert UpsertSystemSettingRequest) Validate() error {
 		if value < 0 {
 			return errors.New("must be positive")
 		}
+	case SystemSettingMemoRevisionRetentionName, SystemSettingTrashRetentionName:
+		var value int
+		if err := json.Unmarshal([]byte(upsert.Value), &value); err != nil {
+			return errors.Errorf(systemSettingUnmarshalError, settingName)
+		}
+		if value < 0 {
+			return errors.New("must be positive")
+		}
 	case SystemSettingTelegramBotTokenName:
 		if upsert.Value == "" {
 			return nil
@@ -266,7 +285,7 @@ func (upsert UpsertSystemSettingRequest) Validate() error {
 		if len(fragments) != 2 {
 			return errors.Errorf(systemSettingUnmarshalError, settingName)
 		}
-	case SystemSettingMemoDisplayWithUpdatedTsName:
+	case SystemSettingMemoDisplayWithUpdatedTsName, SystemSettingStripImageMetadataName:
 		var value bool
 		if err := json.Unmarshal([]byte(upsert.Value), &value); err != nil {
 			return errors.Errorf(systemSettingUnmarshalError, settingName)
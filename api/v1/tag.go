@@ -4,12 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"regexp"
 	"sort"
 
 	"github.com/labstack/echo/v4"
 	"golang.org/x/exp/slices"
 
+	"github.com/usememos/memos/plugin/gomark"
+	"github.com/usememos/memos/plugin/gomark/ast"
 	"github.com/usememos/memos/store"
 )
 
@@ -199,15 +200,14 @@ func convertTagFromStore(tag *store.Tag) *Tag {
 	}
 }
 
-var tagRegexp = regexp.MustCompile(`#([^\s#,]+)`)
-
 func findTagListFromMemoContent(memoContent string) []string {
 	tagMapSet := make(map[string]bool)
-	matches := tagRegexp.FindAllStringSubmatch(memoContent, -1)
-	for _, v := range matches {
-		tagName := v[1]
-		tagMapSet[tagName] = true
-	}
+	document := gomark.Parse(memoContent)
+	ast.Walk(document.Nodes, func(node ast.Node) {
+		if tag, ok := node.(*ast.Tag); ok {
+			tagMapSet[tag.Content] = true
+		}
+	})
 
 	tagList := []string{}
 	for tag := range tagMapSet {
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -37,10 +38,18 @@ func TestFindTagListFromMemoContent(t *testing.T) {
 			memoContent: "#tag1 http://123123.com?123123#tag2 \n#tag3  #tag4 http://123123.com?123123#tag2) ",
 			want:        []string{"tag1", "tag2", "tag2)", "tag3", "tag4"},
 		},
+		{
+			memoContent: "#tag1,#tag2 #tag3\t#tag4\u3000#tag5",
+			want:        []string{"tag1", "tag2", "tag3", "tag4", "tag5"},
+		},
+		{
+			memoContent: "| a | b |\n| - | - |\n| #tag1 | **#tag2** |",
+			want:        []string{"tag1", "tag2"},
+		},
 	}
 	for _, test := range tests {
 		result := findTagListFromMemoContent(test.memoContent)
-		if len(result) != len(test.want) {
+		if !reflect.DeepEqual(result, test.want) {
 			t.Errorf("Find tag list %s: got result %v, want %v.", test.memoContent, result, test.want)
 		}
 	}
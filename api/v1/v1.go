@@ -76,9 +76,11 @@ func (s *APIV1Service) Register(rootGroup *echo.Group) {
 	s.registerTagRoutes(apiV1Group)
 	s.registerStorageRoutes(apiV1Group)
 	s.registerResourceRoutes(apiV1Group)
+	s.registerResourceUploadRoutes(apiV1Group)
 	s.registerMemoRoutes(apiV1Group)
 	s.registerMemoOrganizerRoutes(apiV1Group)
 	s.registerMemoRelationRoutes(apiV1Group)
+	s.registerExportRoutes(apiV1Group)
 
 	// Register public routes.
 	publicGroup := rootGroup.Group("/o")
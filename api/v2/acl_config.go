@@ -6,6 +6,7 @@ var authenticationAllowlistMethods = map[string]bool{
 	"/memos.api.v2.SystemService/GetSystemInfo": true,
 	"/memos.api.v2.UserService/GetUser":         true,
 	"/memos.api.v2.MemoService/ListMemos":       true,
+	"/memos.api.v2.MemoService/SearchMemos":     true,
 }
 
 // isUnauthorizeAllowedMethod returns whether the method is exempted from authentication.
@@ -2,6 +2,12 @@ package v2
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
 	"github.com/usememos/memos/store"
@@ -13,6 +19,8 @@ func convertRowStatusFromStore(rowStatus store.RowStatus) apiv2pb.RowStatus {
 		return apiv2pb.RowStatus_ACTIVE
 	case store.Archived:
 		return apiv2pb.RowStatus_ARCHIVED
+	case store.Deleted:
+		return apiv2pb.RowStatus_DELETED
 	default:
 		return apiv2pb.RowStatus_ROW_STATUS_UNSPECIFIED
 	}
@@ -24,6 +32,8 @@ func convertRowStatusToStore(rowStatus apiv2pb.RowStatus) store.RowStatus {
 		return store.Normal
 	case apiv2pb.RowStatus_ARCHIVED:
 		return store.Archived
+	case apiv2pb.RowStatus_DELETED:
+		return store.Deleted
 	default:
 		return store.Normal
 	}
@@ -42,3 +52,63 @@ func getCurrentUser(ctx context.Context, s *store.Store) (*store.User, error) {
 	}
 	return user, nil
 }
+
+// pageToken is the content of the opaque page token in keyset pagination.
+type pageToken struct {
+	Pinned bool    `json:"pinned,omitempty"`
+	Rank   float64 `json:"rank,omitempty"`
+	Ts     int64   `json:"ts"`
+	ID     int32   `json:"id"`
+}
+
+func marshalPageToken(cursor *store.PageCursor) (string, error) {
+	bytes, err := json.Marshal(&pageToken{
+		Pinned: cursor.Pinned,
+		Rank:   cursor.Rank,
+		Ts:     cursor.Ts,
+		ID:     cursor.ID,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal page token")
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func unmarshalPageToken(token string) (*store.PageCursor, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode page token")
+	}
+	pageToken := &pageToken{}
+	if err := json.Unmarshal(bytes, pageToken); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal page token")
+	}
+	return &store.PageCursor{
+		Pinned: pageToken.Pinned,
+		Rank:   pageToken.Rank,
+		Ts:     pageToken.Ts,
+		ID:     pageToken.ID,
+	}, nil
+}
+
+// getPageCursor returns the cursor of the page token and the limit to find one more item than the page size,
+// which tells whether there is a next page.
+func getPageCursor(token string, pageSize int32) (*store.PageCursor, *int, error) {
+	if pageSize < 0 {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "page size must not be negative")
+	}
+	var cursor *store.PageCursor
+	if token != "" {
+		var err error
+		cursor, err = unmarshalPageToken(token)
+		if err != nil {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "invalid page token: %v", err)
+		}
+	}
+	var limit *int
+	if pageSize > 0 {
+		limit = new(int)
+		*limit = int(pageSize) + 1
+	}
+	return cursor, limit, nil
+}
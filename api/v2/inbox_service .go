@@ -14,14 +14,20 @@ import (
 	"github.com/usememos/memos/store"
 )
 
-func (s *APIV2Service) ListInboxes(ctx context.Context, _ *apiv2pb.ListInboxesRequest) (*apiv2pb.ListInboxesResponse, error) {
+func (s *APIV2Service) ListInboxes(ctx context.Context, request *apiv2pb.ListInboxesRequest) (*apiv2pb.ListInboxesResponse, error) {
 	user, err := getCurrentUser(ctx, s.Store)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get user")
 	}
+	cursor, limit, err := getPageCursor(request.PageToken, request.PageSize)
+	if err != nil {
+		return nil, err
+	}
 
 	inboxes, err := s.Store.ListInboxes(ctx, &store.FindInbox{
 		ReceiverID: &user.ID,
+		Limit:      limit,
+		Cursor:     cursor,
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list inbox: %v", err)
@@ -30,6 +36,17 @@ func (s *APIV2Service) ListInboxes(ctx context.Context, _ *apiv2pb.ListInboxesRe
 	response := &apiv2pb.ListInboxesResponse{
 		Inboxes: []*apiv2pb.Inbox{},
 	}
+	if request.PageSize > 0 && len(inboxes) > int(request.PageSize) {
+		inboxes = inboxes[:request.PageSize]
+		lastInbox := inboxes[len(inboxes)-1]
+		response.NextPageToken, err = marshalPageToken(&store.PageCursor{
+			Ts: lastInbox.CreatedTs,
+			ID: lastInbox.ID,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal page token: %v", err)
+		}
+	}
 	for _, inbox := range inboxes {
 		inboxMessage, err := s.convertInboxFromStore(ctx, inbox)
 		if err != nil {
@@ -2,13 +2,15 @@ package v2
 
 import (
 	"context"
+	"fmt"
+	"time"
 
-	"github.com/google/cel-go/cel"
-	"github.com/pkg/errors"
-	v1alpha1 "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"github.com/pmezard/go-difflib/difflib"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/usememos/memos/api/filter"
+	"github.com/usememos/memos/plugin/gomark"
 	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
 	"github.com/usememos/memos/store"
 )
@@ -39,61 +41,155 @@ func (s *APIV2Service) CreateMemo(ctx context.Context, request *apiv2pb.CreateMe
 }
 
 func (s *APIV2Service) ListMemos(ctx context.Context, request *apiv2pb.ListMemosRequest) (*apiv2pb.ListMemosResponse, error) {
+	user, _ := getCurrentUser(ctx, s.Store)
 	memoFind := &store.FindMemo{}
+	filters := []*store.MemoFilter{}
+	var visibilityFilter *store.MemoFilter
+	memoFind.CreatorID, memoFind.VisibilityList, visibilityFilter = findMemoVisibility(user, request.CreatorId)
+	if visibilityFilter != nil {
+		filters = append(filters, visibilityFilter)
+	}
 	if request.Filter != "" {
-		filter, err := parseListMemosFilter(request.Filter)
+		// None of the memos is pinned for the visitors.
+		userID := int32(0)
+		if user != nil {
+			userID = user.ID
+		}
+		memoFilter, err := filter.ParseMemoFilter(request.Filter, userID)
 		if err != nil {
 			return nil, status.Errorf(codes.InvalidArgument, "invalid filter: %v", err)
 		}
-		if filter.Visibility != nil {
-			memoFind.VisibilityList = []store.Visibility{*filter.Visibility}
+		filters = append(filters, memoFilter)
+	}
+	if request.SavedFilter != "" {
+		if user == nil {
+			return nil, status.Errorf(codes.PermissionDenied, "permission denied")
 		}
-		if filter.CreatedTsBefore != nil {
-			memoFind.CreatedTsBefore = filter.CreatedTsBefore
+		savedFilter, err := s.getUserSavedFilter(ctx, user.ID, request.SavedFilter)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get saved filter: %v", err)
 		}
-		if filter.CreatedTsAfter != nil {
-			memoFind.CreatedTsAfter = filter.CreatedTsAfter
+		if savedFilter == nil {
+			return nil, status.Errorf(codes.NotFound, "saved filter %q not found", request.SavedFilter)
 		}
+		memoFilter, err := filter.ParseMemoFilter(savedFilter.Filter, user.ID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid saved filter: %v", err)
+		}
+		filters = append(filters, memoFilter)
 	}
-	user, _ := getCurrentUser(ctx, s.Store)
-	// If the user is not authenticated, only public memos are visible.
-	if user == nil {
-		memoFind.VisibilityList = []store.Visibility{store.Public}
-	}
-
-	if request.CreatorId != nil {
-		memoFind.CreatorID = request.CreatorId
-	}
-
-	// Remove the private memos from the list if the user is not the creator.
-	if user != nil && request.CreatorId != nil && *request.CreatorId != user.ID {
-		var filteredVisibility []store.Visibility
-		for _, v := range memoFind.VisibilityList {
-			if v != store.Private {
-				filteredVisibility = append(filteredVisibility, v)
-			}
+	if len(filters) == 1 {
+		memoFind.Filter = filters[0]
+	} else if len(filters) > 1 {
+		memoFind.Filter = &store.MemoFilter{
+			Operator: store.MemoFilterAnd,
+			Children: filters,
 		}
-		memoFind.VisibilityList = filteredVisibility
 	}
 
-	if request.PageSize != 0 {
-		offset := int(request.Page * request.PageSize)
-		limit := int(request.PageSize)
-		memoFind.Offset = &offset
-		memoFind.Limit = &limit
+	cursor, limit, err := getPageCursor(request.PageToken, request.PageSize)
+	if err != nil {
+		return nil, err
 	}
+	memoFind.Cursor, memoFind.Limit = cursor, limit
 	memos, err := s.Store.ListMemos(ctx, memoFind)
 	if err != nil {
 		return nil, err
 	}
 
+	nextPageToken := ""
+	if request.PageSize > 0 && len(memos) > int(request.PageSize) {
+		memos = memos[:request.PageSize]
+		lastMemo := memos[len(memos)-1]
+		nextPageToken, err = marshalPageToken(&store.PageCursor{
+			Pinned: lastMemo.Pinned,
+			Ts:     lastMemo.CreatedTs,
+			ID:     lastMemo.ID,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal page token: %v", err)
+		}
+	}
+
 	memoMessages := make([]*apiv2pb.Memo, len(memos))
 	for i, memo := range memos {
 		memoMessages[i] = convertMemoFromStore(memo)
 	}
 
 	response := &apiv2pb.ListMemosResponse{
-		Memos: memoMessages,
+		Memos:         memoMessages,
+		NextPageToken: nextPageToken,
+	}
+	return response, nil
+}
+
+func (s *APIV2Service) SearchMemos(ctx context.Context, request *apiv2pb.SearchMemosRequest) (*apiv2pb.SearchMemosResponse, error) {
+	if len(store.SplitSearchQuery(request.Query)) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "query is required")
+	}
+
+	user, _ := getCurrentUser(ctx, s.Store)
+	normalStatus := store.Normal
+	memoSearch := &store.SearchMemo{
+		Query:     request.Query,
+		RowStatus: &normalStatus,
+	}
+	memoSearch.CreatorID, memoSearch.VisibilityList, memoSearch.Filter = findMemoVisibility(user, request.CreatorId)
+	cursor, limit, err := getPageCursor(request.PageToken, request.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	memoSearch.Cursor, memoSearch.Limit = cursor, limit
+	searchResults, err := s.Store.SearchMemos(ctx, memoSearch)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to search memos: %v", err)
+	}
+
+	nextPageToken := ""
+	if request.PageSize > 0 && len(searchResults) > int(request.PageSize) {
+		searchResults = searchResults[:request.PageSize]
+		lastResult := searchResults[len(searchResults)-1]
+		nextPageToken, err = marshalPageToken(&store.PageCursor{
+			Rank: lastResult.Rank,
+			Ts:   lastResult.CreatedTs,
+			ID:   lastResult.MemoID,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal page token: %v", err)
+		}
+	}
+
+	results := []*apiv2pb.MemoSearchResult{}
+	if len(searchResults) > 0 {
+		memoIDList := make([]int32, len(searchResults))
+		for i, searchResult := range searchResults {
+			memoIDList[i] = searchResult.MemoID
+		}
+		memos, err := s.Store.ListMemos(ctx, &store.FindMemo{
+			IDList: memoIDList,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list memos: %v", err)
+		}
+		memoMap := make(map[int32]*store.Memo, len(memos))
+		for _, memo := range memos {
+			memoMap[memo.ID] = memo
+		}
+		for _, searchResult := range searchResults {
+			memo, ok := memoMap[searchResult.MemoID]
+			if !ok {
+				continue
+			}
+			results = append(results, &apiv2pb.MemoSearchResult{
+				Memo:    convertMemoFromStore(memo),
+				Snippet: searchResult.Snippet,
+			})
+		}
+	}
+
+	response := &apiv2pb.SearchMemosResponse{
+		Results:       results,
+		NextPageToken: nextPageToken,
 	}
 	return response, nil
 }
@@ -152,91 +248,281 @@ func (s *APIV2Service) CreateMemoComment(ctx context.Context, request *apiv2pb.C
 }
 
 func (s *APIV2Service) ListMemoComments(ctx context.Context, request *apiv2pb.ListMemoCommentsRequest) (*apiv2pb.ListMemoCommentsResponse, error) {
-	memoRelationComment := store.MemoRelationComment
-	memoRelations, err := s.Store.ListMemoRelations(ctx, &store.FindMemoRelation{
-		RelatedMemoID: &request.Id,
-		Type:          &memoRelationComment,
-	})
+	cursor, limit, err := getPageCursor(request.PageToken, request.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	user, _ := getCurrentUser(ctx, s.Store)
+	// Comments are paginated in the same order as the memos, and only the visible ones are listed.
+	memoFind := &store.FindMemo{
+		ParentID: &request.Id,
+		Cursor:   cursor,
+		Limit:    limit,
+	}
+	memoFind.CreatorID, memoFind.VisibilityList, memoFind.Filter = findMemoVisibility(user, nil)
+	memos, err := s.Store.ListMemos(ctx, memoFind)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to list memo relations")
+		return nil, status.Errorf(codes.Internal, "failed to list memos")
 	}
 
-	var memos []*apiv2pb.Memo
-	for _, memoRelation := range memoRelations {
-		memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
-			ID: &memoRelation.MemoID,
+	nextPageToken := ""
+	if request.PageSize > 0 && len(memos) > int(request.PageSize) {
+		memos = memos[:request.PageSize]
+		lastMemo := memos[len(memos)-1]
+		nextPageToken, err = marshalPageToken(&store.PageCursor{
+			Pinned: lastMemo.Pinned,
+			Ts:     lastMemo.CreatedTs,
+			ID:     lastMemo.ID,
 		})
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to get memo")
-		}
-		if memo != nil {
-			memos = append(memos, convertMemoFromStore(memo))
+			return nil, status.Errorf(codes.Internal, "failed to marshal page token: %v", err)
 		}
 	}
 
+	memoMessages := []*apiv2pb.Memo{}
+	for _, memo := range memos {
+		memoMessages = append(memoMessages, convertMemoFromStore(memo))
+	}
+
 	response := &apiv2pb.ListMemoCommentsResponse{
-		Memos: memos,
+		Memos:         memoMessages,
+		NextPageToken: nextPageToken,
 	}
 	return response, nil
 }
 
-// ListMemosFilterCELAttributes are the CEL attributes for ListMemosFilter.
-var ListMemosFilterCELAttributes = []cel.EnvOption{
-	cel.Variable("visibility", cel.StringType),
-	cel.Variable("created_ts_before", cel.IntType),
-	cel.Variable("created_ts_after", cel.IntType),
+func (s *APIV2Service) ToggleMemoTask(ctx context.Context, request *apiv2pb.ToggleMemoTaskRequest) (*apiv2pb.ToggleMemoTaskResponse, error) {
+	memo, err := s.getCreatorMemo(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	// The tasks of the archived memos and the memos in the trash are read-only.
+	if memo.RowStatus != store.Normal {
+		return nil, status.Errorf(codes.FailedPrecondition, "memo %d is not normal", memo.ID)
+	}
+
+	content, err := gomark.ToggleTask(memo.Content, int(request.Index))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to toggle task: %v", err)
+	}
+	currentTs := time.Now().Unix()
+	if err := s.Store.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        memo.ID,
+		UpdatedTs: &currentTs,
+		Content:   &content,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update memo")
+	}
+	memo, err = s.Store.GetMemo(ctx, &store.FindMemo{
+		ID: &request.Id,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get memo")
+	}
+
+	response := &apiv2pb.ToggleMemoTaskResponse{
+		Memo: convertMemoFromStore(memo),
+	}
+	return response, nil
 }
 
-type ListMemosFilter struct {
-	Visibility      *store.Visibility
-	CreatedTsBefore *int64
-	CreatedTsAfter  *int64
+func (s *APIV2Service) ListTodos(ctx context.Context, _ *apiv2pb.ListTodosRequest) (*apiv2pb.ListTodosResponse, error) {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user")
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+
+	normalStatus := store.Normal
+	memos, err := s.Store.ListMemos(ctx, &store.FindMemo{
+		CreatorID: &user.ID,
+		RowStatus: &normalStatus,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list memos")
+	}
+
+	todos := []*apiv2pb.Todo{}
+	for _, memo := range memos {
+		for _, task := range gomark.ListTasks(memo.Content) {
+			if task.Complete {
+				continue
+			}
+			todos = append(todos, &apiv2pb.Todo{
+				MemoId:  memo.ID,
+				Index:   int32(task.Index),
+				Line:    int32(task.Line),
+				Content: task.Content,
+			})
+		}
+	}
+
+	response := &apiv2pb.ListTodosResponse{
+		Todos: todos,
+	}
+	return response, nil
 }
 
-func parseListMemosFilter(expression string) (*ListMemosFilter, error) {
-	e, err := cel.NewEnv(ListMemosFilterCELAttributes...)
+func (s *APIV2Service) ListMemoRevisions(ctx context.Context, request *apiv2pb.ListMemoRevisionsRequest) (*apiv2pb.ListMemoRevisionsResponse, error) {
+	memo, err := s.getCreatorMemo(ctx, request.Id)
 	if err != nil {
 		return nil, err
 	}
-	ast, issues := e.Compile(expression)
-	if issues != nil {
-		return nil, errors.Errorf("found issue %v", issues)
+
+	memoRevisions, err := s.Store.ListMemoRevisions(ctx, &store.FindMemoRevision{
+		MemoID: &memo.ID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list memo revisions: %v", err)
+	}
+
+	response := &apiv2pb.ListMemoRevisionsResponse{
+		Revisions: []*apiv2pb.MemoRevision{},
+	}
+	for _, memoRevision := range memoRevisions {
+		response.Revisions = append(response.Revisions, convertMemoRevisionFromStore(memoRevision))
 	}
-	filter := &ListMemosFilter{}
-	expr, err := cel.AstToParsedExpr(ast)
+	return response, nil
+}
+
+func (s *APIV2Service) GetMemoRevisionDiff(ctx context.Context, request *apiv2pb.GetMemoRevisionDiffRequest) (*apiv2pb.GetMemoRevisionDiffResponse, error) {
+	memo, err := s.getCreatorMemo(ctx, request.Id)
 	if err != nil {
 		return nil, err
 	}
-	callExpr := expr.GetExpr().GetCallExpr()
-	findField(callExpr, filter)
-	return filter, nil
-}
 
-func findField(callExpr *v1alpha1.Expr_Call, filter *ListMemosFilter) {
-	if len(callExpr.Args) == 2 {
-		idExpr := callExpr.Args[0].GetIdentExpr()
-		if idExpr != nil {
-			if idExpr.Name == "visibility" {
-				visibility := store.Visibility(callExpr.Args[1].GetConstExpr().GetStringValue())
-				filter.Visibility = &visibility
-			}
-			if idExpr.Name == "created_ts_before" {
-				createdTsBefore := callExpr.Args[1].GetConstExpr().GetInt64Value()
-				filter.CreatedTsBefore = &createdTsBefore
-			}
-			if idExpr.Name == "created_ts_after" {
-				createdTsAfter := callExpr.Args[1].GetConstExpr().GetInt64Value()
-				filter.CreatedTsAfter = &createdTsAfter
-			}
-			return
+	// getRevisionContent returns the name and content of the revision, or of the current memo if the id is 0.
+	getRevisionContent := func(revisionID int32) (string, string, error) {
+		if revisionID == 0 {
+			return "current", memo.Content, nil
 		}
+		memoRevision, err := s.Store.GetMemoRevision(ctx, &store.FindMemoRevision{
+			ID:     &revisionID,
+			MemoID: &memo.ID,
+		})
+		if err != nil {
+			return "", "", status.Errorf(codes.Internal, "failed to get memo revision: %v", err)
+		}
+		if memoRevision == nil {
+			return "", "", status.Errorf(codes.NotFound, "memo revision %d not found", revisionID)
+		}
+		return fmt.Sprintf("revision %d", memoRevision.ID), memoRevision.Content, nil
 	}
-	for _, arg := range callExpr.Args {
-		callExpr := arg.GetCallExpr()
-		if callExpr != nil {
-			findField(callExpr, filter)
+	baseName, baseContent, err := getRevisionContent(request.BaseRevisionId)
+	if err != nil {
+		return nil, err
+	}
+	targetName, targetContent, err := getRevisionContent(request.TargetRevisionId)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(baseContent),
+		B:        difflib.SplitLines(targetContent),
+		FromFile: baseName,
+		ToFile:   targetName,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to diff memo revisions: %v", err)
+	}
+
+	response := &apiv2pb.GetMemoRevisionDiffResponse{
+		Diff: diff,
+	}
+	return response, nil
+}
+
+func (s *APIV2Service) RestoreMemoRevision(ctx context.Context, request *apiv2pb.RestoreMemoRevisionRequest) (*apiv2pb.RestoreMemoRevisionResponse, error) {
+	memo, err := s.getCreatorMemo(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	memoRevision, err := s.Store.GetMemoRevision(ctx, &store.FindMemoRevision{
+		ID:     &request.RevisionId,
+		MemoID: &memo.ID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get memo revision: %v", err)
+	}
+	if memoRevision == nil {
+		return nil, status.Errorf(codes.NotFound, "memo revision not found")
+	}
+
+	// The current content is kept as a new revision by the update, so the restore can be undone.
+	currentTs := time.Now().Unix()
+	if err := s.Store.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:         memo.ID,
+		UpdatedTs:  &currentTs,
+		Content:    &memoRevision.Content,
+		Visibility: &memoRevision.Visibility,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update memo: %v", err)
+	}
+	memo, err = s.Store.GetMemo(ctx, &store.FindMemo{
+		ID: &memo.ID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get memo")
+	}
+
+	response := &apiv2pb.RestoreMemoRevisionResponse{
+		Memo: convertMemoFromStore(memo),
+	}
+	return response, nil
+}
+
+// getCreatorMemo returns the memo if the current user is its creator.
+func (s *APIV2Service) getCreatorMemo(ctx context.Context, id int32) (*store.Memo, error) {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user")
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+
+	memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
+		ID: &id,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get memo")
+	}
+	if memo == nil {
+		return nil, status.Errorf(codes.NotFound, "memo not found")
+	}
+	if memo.CreatorID != user.ID {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	return memo, nil
+}
+
+// findMemoVisibility returns the creator, visibility and filter conditions of the memos visible to the user.
+// If the user is not authenticated, only public memos are visible.
+// Otherwise, the private memos of others are never visible, so the memos of all creators are
+// the user's own memos and the public and protected memos of others.
+func findMemoVisibility(user *store.User, creatorID *int32) (*int32, []store.Visibility, *store.MemoFilter) {
+	if user == nil {
+		return creatorID, []store.Visibility{store.Public}, nil
+	}
+	if creatorID == nil {
+		return nil, nil, &store.MemoFilter{
+			Operator: store.MemoFilterOr,
+			Children: []*store.MemoFilter{
+				{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldCreator, Value: user.Username},
+				{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldVisibility, Value: store.Public},
+				{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldVisibility, Value: store.Protected},
+			},
 		}
 	}
+	if *creatorID == user.ID {
+		return creatorID, []store.Visibility{store.Public, store.Protected, store.Private}, nil
+	}
+	return creatorID, []store.Visibility{store.Public, store.Protected}, nil
 }
 
 func convertMemoFromStore(memo *store.Memo) *apiv2pb.Memo {
@@ -264,3 +550,13 @@ func convertVisibilityFromStore(visibility store.Visibility) apiv2pb.Visibility
 		return apiv2pb.Visibility_VISIBILITY_UNSPECIFIED
 	}
 }
+
+func convertMemoRevisionFromStore(memoRevision *store.MemoRevision) *apiv2pb.MemoRevision {
+	return &apiv2pb.MemoRevision{
+		Id:         memoRevision.ID,
+		MemoId:     memoRevision.MemoID,
+		CreatedTs:  memoRevision.CreatedTs,
+		Content:    memoRevision.Content,
+		Visibility: convertVisibilityFromStore(memoRevision.Visibility),
+	}
+}
@@ -6,25 +6,44 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	apiresource "github.com/usememos/memos/api/resource"
 	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
 	"github.com/usememos/memos/store"
 )
 
-func (s *APIV2Service) ListResources(ctx context.Context, _ *apiv2pb.ListResourcesRequest) (*apiv2pb.ListResourcesResponse, error) {
+func (s *APIV2Service) ListResources(ctx context.Context, request *apiv2pb.ListResourcesRequest) (*apiv2pb.ListResourcesResponse, error) {
 	user, err := getCurrentUser(ctx, s.Store)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
 	}
+	cursor, limit, err := getPageCursor(request.PageToken, request.PageSize)
+	if err != nil {
+		return nil, err
+	}
 	resources, err := s.Store.ListResources(ctx, &store.FindResource{
 		CreatorID: &user.ID,
+		Limit:     limit,
+		Cursor:    cursor,
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list resources: %v", err)
 	}
 
 	response := &apiv2pb.ListResourcesResponse{}
+	if request.PageSize > 0 && len(resources) > int(request.PageSize) {
+		resources = resources[:request.PageSize]
+		lastResource := resources[len(resources)-1]
+		response.NextPageToken, err = marshalPageToken(&store.PageCursor{
+			Ts: lastResource.CreatedTs,
+			ID: lastResource.ID,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal page token: %v", err)
+		}
+	}
 	for _, resource := range resources {
 		response.Resources = append(response.Resources, s.convertResourceFromStore(ctx, resource))
 	}
@@ -73,10 +92,8 @@ func (s *APIV2Service) DeleteResource(ctx context.Context, request *apiv2pb.Dele
 	if resource == nil {
 		return nil, status.Errorf(codes.NotFound, "resource not found")
 	}
-	// Delete the resource from the database.
-	if err := s.Store.DeleteResource(ctx, &store.DeleteResource{
-		ID: resource.ID,
-	}); err != nil {
+	// Delete the resource with its blob and previews in the storage.
+	if err := apiresource.DeleteResourceWithBlob(ctx, s.Store, resource); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete resource: %v", err)
 	}
 	return &apiv2pb.DeleteResourceResponse{}, nil
@@ -93,7 +110,7 @@ func (s *APIV2Service) convertResourceFromStore(ctx context.Context, resource *s
 		}
 	}
 
-	return &apiv2pb.Resource{
+	resourceMessage := &apiv2pb.Resource{
 		Id:           resource.ID,
 		CreatedTs:    timestamppb.New(time.Unix(resource.CreatedTs, 0)),
 		Filename:     resource.Filename,
@@ -101,5 +118,15 @@ func (s *APIV2Service) convertResourceFromStore(ctx context.Context, resource *s
 		Type:         resource.Type,
 		Size:         resource.Size,
 		MemoId:       memoID,
+		Width:        resource.Width,
+		Height:       resource.Height,
+		Checksum:     resource.Hash,
+	}
+	if resource.Duration != 0 {
+		resourceMessage.Duration = durationpb.New(time.Duration(resource.Duration) * time.Millisecond)
+	}
+	if resource.CapturedTs != 0 {
+		resourceMessage.CapturedTs = timestamppb.New(time.Unix(resource.CapturedTs, 0))
 	}
+	return resourceMessage
 }
@@ -7,7 +7,9 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/usememos/memos/internal/cron"
 	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
+	storepb "github.com/usememos/memos/proto/gen/store"
 	"github.com/usememos/memos/store"
 )
 
@@ -25,6 +27,32 @@ func (s *APIV2Service) GetSystemInfo(ctx context.Context, _ *apiv2pb.GetSystemIn
 			return nil, status.Errorf(codes.Internal, "failed to get db size: %v", err)
 		}
 		defaultSystemInfo.DbSize = size
+
+		backupConfig, err := s.Store.GetBackupConfig(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get backup config: %v", err)
+		}
+		if backupConfig != nil {
+			defaultSystemInfo.BackupConfig = &apiv2pb.BackupConfig{
+				Enabled:   backupConfig.Enabled,
+				Cron:      backupConfig.Cron,
+				MaxKeep:   backupConfig.MaxKeep,
+				StorageId: backupConfig.StorageId,
+			}
+		}
+		backupStatus, err := s.Store.GetBackupStatus(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get backup status: %v", err)
+		}
+		if backupStatus != nil {
+			defaultSystemInfo.BackupStatus = &apiv2pb.BackupStatus{
+				RunTs:     backupStatus.RunTs,
+				Filename:  backupStatus.Filename,
+				Size:      backupStatus.Size,
+				Error:     backupStatus.Error,
+				RemoteKey: backupStatus.RemoteKey,
+			}
+		}
 	}
 
 	response := &apiv2pb.GetSystemInfoResponse{
@@ -79,6 +107,36 @@ func (s *APIV2Service) UpdateSystemInfo(ctx context.Context, request *apiv2pb.Up
 			if err != nil {
 				return nil, status.Errorf(codes.Internal, "failed to update additional_style system setting: %v", err)
 			}
+		} else if path == "backup_config" {
+			backupConfig := request.SystemInfo.BackupConfig
+			if backupConfig == nil {
+				return nil, status.Errorf(codes.InvalidArgument, "backup config is required")
+			}
+			if backupConfig.MaxKeep < 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "max keep must not be negative")
+			}
+			if backupConfig.Enabled {
+				if _, err := cron.NewSchedule(backupConfig.Cron); err != nil {
+					return nil, status.Errorf(codes.InvalidArgument, "invalid cron: %v", err)
+				}
+			}
+			if backupConfig.StorageId != 0 {
+				storage, err := s.Store.GetStorage(ctx, &store.FindStorage{ID: &backupConfig.StorageId})
+				if err != nil {
+					return nil, status.Errorf(codes.Internal, "failed to get storage: %v", err)
+				}
+				if storage == nil {
+					return nil, status.Errorf(codes.InvalidArgument, "storage %d not found", backupConfig.StorageId)
+				}
+			}
+			if err := s.Store.UpsertBackupConfig(ctx, &storepb.BackupConfig{
+				Enabled:   backupConfig.Enabled,
+				Cron:      backupConfig.Cron,
+				MaxKeep:   backupConfig.MaxKeep,
+				StorageId: backupConfig.StorageId,
+			}); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to update backup_config system setting: %v", err)
+			}
 		}
 	}
 
@@ -17,6 +17,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/api/filter"
 	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
 	storepb "github.com/usememos/memos/proto/gen/store"
 	"github.com/usememos/memos/store"
@@ -327,6 +328,184 @@ func (s *APIV2Service) UpsertAccessTokenToStore(ctx context.Context, user *store
 	return nil
 }
 
+func (s *APIV2Service) ListUserSavedFilters(ctx context.Context, request *apiv2pb.ListUserSavedFiltersRequest) (*apiv2pb.ListUserSavedFiltersResponse, error) {
+	user, err := s.getSavedFiltersUser(ctx, request.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	userSavedFilters, err := s.Store.GetUserSavedFilters(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list saved filters: %v", err)
+	}
+	savedFilters := []*apiv2pb.SavedFilter{}
+	for _, userSavedFilter := range userSavedFilters {
+		savedFilters = append(savedFilters, convertSavedFilterFromStore(userSavedFilter))
+	}
+
+	response := &apiv2pb.ListUserSavedFiltersResponse{
+		SavedFilters: savedFilters,
+	}
+	return response, nil
+}
+
+func (s *APIV2Service) CreateUserSavedFilter(ctx context.Context, request *apiv2pb.CreateUserSavedFilterRequest) (*apiv2pb.CreateUserSavedFilterResponse, error) {
+	user, err := s.getSavedFiltersUser(ctx, request.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSavedFilter(request.SavedFilter); err != nil {
+		return nil, err
+	}
+
+	userSavedFilters, err := s.Store.GetUserSavedFilters(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list saved filters: %v", err)
+	}
+	for _, userSavedFilter := range userSavedFilters {
+		if userSavedFilter.Name == request.SavedFilter.Name {
+			return nil, status.Errorf(codes.AlreadyExists, "saved filter %q already exists", request.SavedFilter.Name)
+		}
+	}
+	updatedUserSavedFilters := append(slices.Clone(userSavedFilters), &storepb.SavedFiltersUserSetting_SavedFilter{
+		Name:   request.SavedFilter.Name,
+		Filter: request.SavedFilter.Filter,
+	})
+	if err := s.upsertSavedFiltersToStore(ctx, user.ID, updatedUserSavedFilters); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to upsert user setting: %v", err)
+	}
+
+	response := &apiv2pb.CreateUserSavedFilterResponse{
+		SavedFilter: request.SavedFilter,
+	}
+	return response, nil
+}
+
+func (s *APIV2Service) UpdateUserSavedFilter(ctx context.Context, request *apiv2pb.UpdateUserSavedFilterRequest) (*apiv2pb.UpdateUserSavedFilterResponse, error) {
+	user, err := s.getSavedFiltersUser(ctx, request.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSavedFilter(request.SavedFilter); err != nil {
+		return nil, err
+	}
+
+	userSavedFilters, err := s.Store.GetUserSavedFilters(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list saved filters: %v", err)
+	}
+	found := false
+	updatedUserSavedFilters := []*storepb.SavedFiltersUserSetting_SavedFilter{}
+	for _, userSavedFilter := range userSavedFilters {
+		if userSavedFilter.Name == request.Name {
+			found = true
+			updatedUserSavedFilters = append(updatedUserSavedFilters, &storepb.SavedFiltersUserSetting_SavedFilter{
+				Name:   request.SavedFilter.Name,
+				Filter: request.SavedFilter.Filter,
+			})
+			continue
+		}
+		if userSavedFilter.Name == request.SavedFilter.Name {
+			return nil, status.Errorf(codes.AlreadyExists, "saved filter %q already exists", request.SavedFilter.Name)
+		}
+		updatedUserSavedFilters = append(updatedUserSavedFilters, userSavedFilter)
+	}
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "saved filter %q not found", request.Name)
+	}
+	if err := s.upsertSavedFiltersToStore(ctx, user.ID, updatedUserSavedFilters); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to upsert user setting: %v", err)
+	}
+
+	response := &apiv2pb.UpdateUserSavedFilterResponse{
+		SavedFilter: request.SavedFilter,
+	}
+	return response, nil
+}
+
+func (s *APIV2Service) DeleteUserSavedFilter(ctx context.Context, request *apiv2pb.DeleteUserSavedFilterRequest) (*apiv2pb.DeleteUserSavedFilterResponse, error) {
+	user, err := s.getSavedFiltersUser(ctx, request.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	userSavedFilters, err := s.Store.GetUserSavedFilters(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list saved filters: %v", err)
+	}
+	updatedUserSavedFilters := []*storepb.SavedFiltersUserSetting_SavedFilter{}
+	for _, userSavedFilter := range userSavedFilters {
+		if userSavedFilter.Name == request.Name {
+			continue
+		}
+		updatedUserSavedFilters = append(updatedUserSavedFilters, userSavedFilter)
+	}
+	if len(updatedUserSavedFilters) == len(userSavedFilters) {
+		return nil, status.Errorf(codes.NotFound, "saved filter %q not found", request.Name)
+	}
+	if err := s.upsertSavedFiltersToStore(ctx, user.ID, updatedUserSavedFilters); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to upsert user setting: %v", err)
+	}
+
+	return &apiv2pb.DeleteUserSavedFilterResponse{}, nil
+}
+
+// getSavedFiltersUser returns the current user if it's the requested one, as the saved filters are private.
+func (s *APIV2Service) getSavedFiltersUser(ctx context.Context, username string) (*store.User, error) {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get current user: %v", err)
+	}
+	if user == nil || user.Username != username {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	return user, nil
+}
+
+func (s *APIV2Service) getUserSavedFilter(ctx context.Context, userID int32, name string) (*storepb.SavedFiltersUserSetting_SavedFilter, error) {
+	userSavedFilters, err := s.Store.GetUserSavedFilters(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, userSavedFilter := range userSavedFilters {
+		if userSavedFilter.Name == name {
+			return userSavedFilter, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *APIV2Service) upsertSavedFiltersToStore(ctx context.Context, userID int32, savedFilters []*storepb.SavedFiltersUserSetting_SavedFilter) error {
+	_, err := s.Store.UpsertUserSettingV1(ctx, &storepb.UserSetting{
+		UserId: userID,
+		Key:    storepb.UserSettingKey_USER_SETTING_SAVED_FILTERS,
+		Value: &storepb.UserSetting_SavedFilters{
+			SavedFilters: &storepb.SavedFiltersUserSetting{
+				SavedFilters: savedFilters,
+			},
+		},
+	})
+	return err
+}
+
+func validateSavedFilter(savedFilter *apiv2pb.SavedFilter) error {
+	if savedFilter == nil || savedFilter.Name == "" {
+		return status.Errorf(codes.InvalidArgument, "saved filter name is required")
+	}
+	// The filter is only validated, so it's parsed for no user.
+	if _, err := filter.ParseMemoFilter(savedFilter.Filter, 0); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid filter: %v", err)
+	}
+	return nil
+}
+
+func convertSavedFilterFromStore(savedFilter *storepb.SavedFiltersUserSetting_SavedFilter) *apiv2pb.SavedFilter {
+	return &apiv2pb.SavedFilter{
+		Name:   savedFilter.Name,
+		Filter: savedFilter.Filter,
+	}
+}
+
 func convertUserFromStore(user *store.User) *apiv2pb.User {
 	return &apiv2pb.User{
 		Id:         int32(user.ID),
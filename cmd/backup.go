@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/usememos/memos/plugin/storage"
+	_profile "github.com/usememos/memos/server/profile"
+	"github.com/usememos/memos/server/service/backup"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/store/db"
+)
+
+var (
+	backupCmdFlagStorage = "storage"
+	backupCmd            = &cobra.Command{
+		Use:   "backup",
+		Short: "Manage the backups uploaded to the storage",
+	}
+
+	backupListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the backups of this instance in the storage",
+		Run: func(cmd *cobra.Command, _ []string) {
+			storageID, err := cmd.Flags().GetInt32(backupCmdFlagStorage)
+			if err != nil {
+				fmt.Printf("failed to get storage id, error: %+v\n", err)
+				return
+			}
+
+			if err := listRemoteBackups(profile, storageID); err != nil {
+				fmt.Printf("failed to list backups, error: %+v\n", err)
+				return
+			}
+		},
+	}
+
+	backupDownloadCmdFlagOutput = "output"
+	backupDownloadCmd           = &cobra.Command{
+		Use:   "download <key>",
+		Short: "Download a backup from the storage",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			storageID, err := cmd.Flags().GetInt32(backupCmdFlagStorage)
+			if err != nil {
+				fmt.Printf("failed to get storage id, error: %+v\n", err)
+				return
+			}
+			output, err := cmd.Flags().GetString(backupDownloadCmdFlagOutput)
+			if err != nil {
+				fmt.Printf("failed to get output file, error: %+v\n", err)
+				return
+			}
+
+			if err := downloadRemoteBackup(profile, storageID, args[0], output); err != nil {
+				fmt.Printf("failed to download backup, error: %+v\n", err)
+				return
+			}
+
+			println("done")
+		},
+	}
+)
+
+func init() {
+	backupCmd.PersistentFlags().Int32(backupCmdFlagStorage, 0, "Storage id, defaults to the one of the backup config")
+	backupDownloadCmd.Flags().String(backupDownloadCmdFlagOutput, "", "Output file, defaults to the name of the backup")
+
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupDownloadCmd)
+	rootCmd.AddCommand(backupCmd)
+}
+
+func listRemoteBackups(profile *_profile.Profile, storageID int32) error {
+	ctx := context.Background()
+	backend, dir, err := getRemoteBackupBackend(ctx, profile, storageID)
+	if err != nil {
+		return err
+	}
+
+	backups, err := backup.ListRemoteBackups(ctx, backend, dir)
+	if err != nil {
+		return err
+	}
+	for _, object := range backups {
+		fmt.Printf("%s\t%d\t%s\n", object.Key, object.Size, object.ModTime.Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
+
+func downloadRemoteBackup(profile *_profile.Profile, storageID int32, key string, output string) error {
+	ctx := context.Background()
+	backend, _, err := getRemoteBackupBackend(ctx, profile, storageID)
+	if err != nil {
+		return err
+	}
+
+	reader, err := backend.Get(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "failed to get backup")
+	}
+	defer reader.Close()
+	if output == "" {
+		output = path.Base(key)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return errors.Wrap(err, "failed to create output file")
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, reader); err != nil {
+		return errors.Wrap(err, "failed to download backup")
+	}
+	return nil
+}
+
+// getRemoteBackupBackend returns the backend of the storage, which defaults to the one of the backup config,
+// and the directory of the backups of this instance in it.
+func getRemoteBackupBackend(ctx context.Context, profile *_profile.Profile, storageID int32) (storage.Backend, string, error) {
+	dbDriver, err := db.NewDBDriver(profile)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create db driver")
+	}
+	defer dbDriver.Close()
+	if err := dbDriver.Migrate(ctx); err != nil {
+		return nil, "", errors.Wrap(err, "failed to migrate db")
+	}
+
+	s := store.New(dbDriver, profile)
+	if storageID == 0 {
+		backupConfig, err := s.GetBackupConfig(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		if backupConfig == nil || backupConfig.StorageId == 0 {
+			return nil, "", errors.New("no storage is set in the backup config, please specify one with --storage")
+		}
+		storageID = backupConfig.StorageId
+	}
+	backend, err := backup.GetRemoteBackend(ctx, s, storageID)
+	if err != nil {
+		return nil, "", err
+	}
+	dir, err := backup.GetRemoteBackupDir(ctx, s)
+	if err != nil {
+		return nil, "", err
+	}
+	return backend, dir, nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	_profile "github.com/usememos/memos/server/profile"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/store/db"
+)
+
+var (
+	dedupeCmdFlagDryRun = "dry-run"
+	dedupeCmd           = &cobra.Command{
+		Use:   "dedupe",
+		Short: "Deduplicate the blobs of the resources",
+		Long: `Hash the resources saved before the hashes, and switch the resources of the same blob in a storage to share one of them.
+The oldest copy is kept, and the other copies are deleted once no resource refers to them.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			dryRun, err := cmd.Flags().GetBool(dedupeCmdFlagDryRun)
+			if err != nil {
+				fmt.Printf("failed to get dry-run, error: %+v\n", err)
+				return
+			}
+
+			if err := dedupeResources(profile, dryRun); err != nil {
+				fmt.Printf("failed to deduplicate resources, error: %+v\n", err)
+				return
+			}
+			println("done")
+		},
+	}
+)
+
+func init() {
+	dedupeCmd.Flags().Bool(dedupeCmdFlagDryRun, false, "Only count the resources to deduplicate")
+
+	rootCmd.AddCommand(dedupeCmd)
+}
+
+func dedupeResources(profile *_profile.Profile, dryRun bool) error {
+	ctx := context.Background()
+	dbDriver, err := db.NewDBDriver(profile)
+	if err != nil {
+		return errors.Wrap(err, "failed to create db driver")
+	}
+	if err := dbDriver.Migrate(ctx); err != nil {
+		dbDriver.Close()
+		return errors.Wrap(err, "failed to migrate db")
+	}
+	s := store.New(dbDriver, profile)
+	defer s.Close()
+
+	result, err := apiv1.DeduplicateResourceBlobs(ctx, s, dryRun)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Printf("would hash %d resources, share the blobs of %d resources and free %d bytes\n", result.HashedCount, result.SharedCount, result.FreedSize)
+	} else {
+		fmt.Printf("hashed %d resources, shared the blobs of %d resources and freed %d bytes\n", result.HashedCount, result.SharedCount, result.FreedSize)
+	}
+	return nil
+}
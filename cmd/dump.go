@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	_profile "github.com/usememos/memos/server/profile"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/store/db"
+)
+
+var (
+	dumpCmdFlagOutput = "output"
+	dumpCmd           = &cobra.Command{
+		Use:   "dump",
+		Short: "Dump the database into a driver-agnostic archive",
+		Run: func(cmd *cobra.Command, _ []string) {
+			output, err := cmd.Flags().GetString(dumpCmdFlagOutput)
+			if err != nil {
+				fmt.Printf("failed to get output file, error: %+v\n", err)
+				return
+			}
+
+			if err := dump(profile, output); err != nil {
+				fmt.Printf("failed to dump, error: %+v\n", err)
+				return
+			}
+
+			println("done")
+		},
+	}
+
+	restoreCmdFlagInput = "input"
+	restoreCmd          = &cobra.Command{
+		Use:   "restore",
+		Short: "Restore a dump archive into an empty database",
+		Run: func(cmd *cobra.Command, _ []string) {
+			input, err := cmd.Flags().GetString(restoreCmdFlagInput)
+			if err != nil {
+				fmt.Printf("failed to get input file, error: %+v\n", err)
+				return
+			}
+
+			if err := restore(profile, input); err != nil {
+				fmt.Printf("failed to restore, error: %+v\n", err)
+				return
+			}
+
+			println("done")
+		},
+	}
+)
+
+func init() {
+	dumpCmd.Flags().String(dumpCmdFlagOutput, "memos.dump.gz", "Output archive file")
+	restoreCmd.Flags().String(restoreCmdFlagInput, "memos.dump.gz", "Input archive file")
+
+	rootCmd.AddCommand(dumpCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
+
+func dump(profile *_profile.Profile, output string) error {
+	ctx := context.Background()
+	dbDriver, err := db.NewDBDriver(profile)
+	if err != nil {
+		return errors.Wrap(err, "failed to create db driver")
+	}
+	defer dbDriver.Close()
+	if err := dbDriver.Migrate(ctx); err != nil {
+		return errors.Wrap(err, "failed to migrate db")
+	}
+
+	return store.DumpToFile(ctx, dbDriver, profile.Version, output)
+}
+
+func restore(profile *_profile.Profile, input string) error {
+	ctx := context.Background()
+	file, err := os.Open(input)
+	if err != nil {
+		return errors.Wrap(err, "failed to open input file")
+	}
+	defer file.Close()
+
+	dbDriver, err := db.NewDBDriver(profile)
+	if err != nil {
+		return errors.Wrap(err, "failed to create db driver")
+	}
+	defer dbDriver.Close()
+	if err := dbDriver.Migrate(ctx); err != nil {
+		return errors.Wrap(err, "failed to migrate db")
+	}
+
+	header, err := store.Restore(ctx, dbDriver, file)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("restored the dump of memos %s\n", header.Version)
+	return nil
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	_profile "github.com/usememos/memos/server/profile"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/store/db"
+)
+
+var (
+	exportCmdFlagUsername = "username"
+	exportCmdFlagOutput   = "output"
+	exportCmd             = &cobra.Command{
+		Use:   "export",
+		Short: "Export the memos and resources of a user as a ZIP archive",
+		Run: func(cmd *cobra.Command, _ []string) {
+			username, err := cmd.Flags().GetString(exportCmdFlagUsername)
+			if err != nil {
+				fmt.Printf("failed to get username, error: %+v\n", err)
+				return
+			}
+			output, err := cmd.Flags().GetString(exportCmdFlagOutput)
+			if err != nil {
+				fmt.Printf("failed to get output file, error: %+v\n", err)
+				return
+			}
+			if output == "" {
+				output = fmt.Sprintf("memos-%s.zip", username)
+			}
+
+			if err := exportUserData(profile, username, output); err != nil {
+				fmt.Printf("failed to export, error: %+v\n", err)
+				return
+			}
+
+			println("done")
+		},
+	}
+
+	importCmdFlagUsername = "username"
+	importCmdFlagInput    = "input"
+	importCmd             = &cobra.Command{
+		Use:   "import",
+		Short: "Import an export archive into a user",
+		Run: func(cmd *cobra.Command, _ []string) {
+			username, err := cmd.Flags().GetString(importCmdFlagUsername)
+			if err != nil {
+				fmt.Printf("failed to get username, error: %+v\n", err)
+				return
+			}
+			input, err := cmd.Flags().GetString(importCmdFlagInput)
+			if err != nil {
+				fmt.Printf("failed to get input file, error: %+v\n", err)
+				return
+			}
+
+			if err := importUserData(profile, username, input); err != nil {
+				fmt.Printf("failed to import, error: %+v\n", err)
+				return
+			}
+		},
+	}
+)
+
+func init() {
+	exportCmd.Flags().String(exportCmdFlagUsername, "", "Username of the user to export")
+	exportCmd.Flags().String(exportCmdFlagOutput, "", "Output archive file, defaults to memos-<username>.zip")
+	importCmd.PersistentFlags().String(importCmdFlagUsername, "", "Username of the user to import into")
+	importCmd.PersistentFlags().String(importCmdFlagInput, "", "Input archive file")
+
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+}
+
+func exportUserData(profile *_profile.Profile, username string, output string) error {
+	ctx := context.Background()
+	s, user, err := openStoreForUser(ctx, profile, username)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	file, err := os.Create(output)
+	if err != nil {
+		return errors.Wrap(err, "failed to create output file")
+	}
+	defer file.Close()
+	return apiv1.ExportUserData(ctx, s, user, file)
+}
+
+func importUserData(profile *_profile.Profile, username string, input string) error {
+	ctx := context.Background()
+	file, err := os.Open(input)
+	if err != nil {
+		return errors.Wrap(err, "failed to open input file")
+	}
+	defer file.Close()
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to stat input file")
+	}
+
+	s, user, err := openStoreForUser(ctx, profile, username)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	result, err := apiv1.ImportUserData(ctx, s, user, file, fileInfo.Size())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("imported %d memos, %d resources and %d relations\n", result.MemoCount, result.ResourceCount, result.RelationCount)
+	return nil
+}
+
+func openStoreForUser(ctx context.Context, profile *_profile.Profile, username string) (*store.Store, *store.User, error) {
+	if username == "" {
+		return nil, nil, errors.New("username is required")
+	}
+	dbDriver, err := db.NewDBDriver(profile)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create db driver")
+	}
+	if err := dbDriver.Migrate(ctx); err != nil {
+		dbDriver.Close()
+		return nil, nil, errors.Wrap(err, "failed to migrate db")
+	}
+
+	s := store.New(dbDriver, profile)
+	user, err := s.GetUser(ctx, &store.FindUser{Username: &username})
+	if err != nil {
+		s.Close()
+		return nil, nil, errors.Wrap(err, "failed to find user")
+	}
+	if user == nil {
+		s.Close()
+		return nil, nil, errors.Errorf("user %s not found", username)
+	}
+	return s, user, nil
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/plugin/importer"
+	_profile "github.com/usememos/memos/server/profile"
+)
+
+var (
+	importSourceCmdFlagDryRun = "dry-run"
+	importSourceCmdShorts     = map[string]string{
+		"flomo":    "Import a flomo HTML export, the folder or its ZIP archive",
+		"obsidian": "Import an Obsidian vault, the folder or its ZIP archive",
+		"keep":     "Import the Google Keep notes of a Google Takeout, the folder or its ZIP archive",
+		"enex":     "Import Evernote ENEX files, a file, a folder or a ZIP archive",
+	}
+)
+
+func init() {
+	for _, source := range importer.ListSources() {
+		source := source
+		importSourceCmd := &cobra.Command{
+			Use:   source,
+			Short: importSourceCmdShorts[source],
+			Run: func(cmd *cobra.Command, _ []string) {
+				username, err := cmd.Flags().GetString(importCmdFlagUsername)
+				if err != nil {
+					fmt.Printf("failed to get username, error: %+v\n", err)
+					return
+				}
+				input, err := cmd.Flags().GetString(importCmdFlagInput)
+				if err != nil {
+					fmt.Printf("failed to get input, error: %+v\n", err)
+					return
+				}
+				dryRun, err := cmd.Flags().GetBool(importSourceCmdFlagDryRun)
+				if err != nil {
+					fmt.Printf("failed to get dry-run, error: %+v\n", err)
+					return
+				}
+
+				if err := importFromSource(profile, source, username, input, dryRun); err != nil {
+					fmt.Printf("failed to import, error: %+v\n", err)
+					return
+				}
+			},
+		}
+		importSourceCmd.Flags().Bool(importSourceCmdFlagDryRun, false, "Only report what would be imported")
+		importCmd.AddCommand(importSourceCmd)
+	}
+}
+
+func importFromSource(profile *_profile.Profile, source string, username string, input string, dryRun bool) error {
+	ctx := context.Background()
+	sourceImporter, err := importer.GetImporter(source)
+	if err != nil {
+		return err
+	}
+	if input == "" {
+		return errors.New("input is required")
+	}
+	fsys, closeFS, err := openImportInput(input)
+	if err != nil {
+		return err
+	}
+	defer closeFS()
+	result, err := sourceImporter.Import(fsys)
+	if err != nil {
+		return err
+	}
+
+	s, user, err := openStoreForUser(ctx, profile, username)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	report, err := apiv1.ImportSourceResult(ctx, s, user, result, dryRun)
+	if err != nil {
+		return err
+	}
+	for _, warning := range report.Warnings {
+		fmt.Printf("warning: %s\n", warning)
+	}
+	if dryRun {
+		fmt.Printf("would import %d memos, %d resources and %d relations\n", report.MemoCount, report.ResourceCount, report.RelationCount)
+	} else {
+		fmt.Printf("imported %d memos, %d resources and %d relations\n", report.MemoCount, report.ResourceCount, report.RelationCount)
+	}
+	return nil
+}
+
+// openImportInput opens the input, a folder, a ZIP archive or a single file, as a FS.
+func openImportInput(input string) (fs.FS, func(), error) {
+	fileInfo, err := os.Stat(input)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to stat input")
+	}
+	if fileInfo.IsDir() {
+		return os.DirFS(input), func() {}, nil
+	}
+
+	file, err := os.Open(input)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to open input")
+	}
+	fsys, err := importer.OpenFS(file, fileInfo.Size(), input)
+	if err != nil {
+		file.Close()
+		return nil, nil, errors.Wrap(err, "failed to read input")
+	}
+	if _, ok := fsys.(*zip.Reader); !ok {
+		// The other files are read into memory.
+		file.Close()
+		return fsys, func() {}, nil
+	}
+	return fsys, func() { file.Close() }, nil
+}
@@ -4,87 +4,58 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"time"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	apiv1 "github.com/usememos/memos/api/v1"
+	_profile "github.com/usememos/memos/server/profile"
 	"github.com/usememos/memos/store"
-	"github.com/usememos/memos/store/db/sqlite"
+	"github.com/usememos/memos/store/db"
 )
 
 var (
-	mvrssCmdFlagFrom = "from"
-	mvrssCmdFlagTo   = "to"
-	mvrssCmd         = &cobra.Command{
+	mvrssCmdFlagFrom        = "from"
+	mvrssCmdFlagTo          = "to"
+	mvrssCmdFlagConcurrency = "concurrency"
+	mvrssCmdFlagDryRun      = "dry-run"
+	mvrssCmd                = &cobra.Command{
 		Use:   "mvrss", // `mvrss` is a shortened for 'means move resource'
 		Short: "Move resource between storage",
-		Run: func(cmd *cobra.Command, _ []string) {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
+		Long: `Move the blobs of the resources between the database, the local storage and the S3, WebDAV and SFTP storages.
+The storages are "db", "local", or the ID or name of a storage.
 
+Every blob is verified by its checksum after copied, and the resource is switched to the copy before the original is deleted.
+An interrupted move can be resumed by running it again, as the resources already moved are no longer in the source storage.`,
+		Run: func(cmd *cobra.Command, _ []string) {
 			from, err := cmd.Flags().GetString(mvrssCmdFlagFrom)
 			if err != nil {
 				fmt.Printf("failed to get from storage, error: %+v\n", err)
 				return
 			}
-
 			to, err := cmd.Flags().GetString(mvrssCmdFlagTo)
 			if err != nil {
 				fmt.Printf("failed to get to storage, error: %+v\n", err)
 				return
 			}
-
-			if from != "local" || to != "db" {
-				fmt.Printf("only local=>db be supported currently\n")
-				return
-			}
-
-			driver, err := sqlite.NewDB(profile)
+			concurrency, err := cmd.Flags().GetInt(mvrssCmdFlagConcurrency)
 			if err != nil {
-				fmt.Printf("failed to create db driver, error: %+v\n", err)
-				return
-			}
-			if err := driver.Migrate(ctx); err != nil {
-				fmt.Printf("failed to migrate db, error: %+v\n", err)
+				fmt.Printf("failed to get concurrency, error: %+v\n", err)
 				return
 			}
-
-			s := store.New(driver, profile)
-			resources, err := s.ListResources(ctx, &store.FindResource{})
+			dryRun, err := cmd.Flags().GetBool(mvrssCmdFlagDryRun)
 			if err != nil {
-				fmt.Printf("failed to list resources, error: %+v\n", err)
+				fmt.Printf("failed to get dry-run, error: %+v\n", err)
 				return
 			}
 
-			var emptyString string
-			for _, res := range resources {
-				if res.InternalPath == "" {
-					continue
-				}
-
-				buf, err := os.ReadFile(res.InternalPath)
-				if err != nil {
-					fmt.Printf("Resource %5d failed to read file: %s\n", res.ID, err)
-					continue
-				}
-
-				if len(buf) != int(res.Size) {
-					fmt.Printf("Resource %5d size of file %d != %d\n", res.ID, len(buf), res.Size)
-					continue
-				}
-
-				update := store.UpdateResource{
-					ID:           res.ID,
-					Blob:         buf,
-					InternalPath: &emptyString,
-				}
-				_, err = s.UpdateResource(ctx, &update)
-				if err != nil {
-					fmt.Printf("Resource %5d failed to update: %s\n", res.ID, err)
-					continue
-				}
-
-				fmt.Printf("Resource %5d copy %12d bytes from %s\n", res.ID, len(buf), res.InternalPath)
+			if err := moveResources(profile, from, to, concurrency, dryRun); err != nil {
+				fmt.Printf("failed to move resources, error: %+v\n", err)
+				return
 			}
 			println("done")
 		},
@@ -94,6 +65,128 @@ var (
 func init() {
 	mvrssCmd.Flags().String(mvrssCmdFlagFrom, "local", "From storage")
 	mvrssCmd.Flags().String(mvrssCmdFlagTo, "db", "To Storage")
+	mvrssCmd.Flags().Int(mvrssCmdFlagConcurrency, 4, "Number of the resources moved at the same time")
+	mvrssCmd.Flags().Bool(mvrssCmdFlagDryRun, false, "Only list the resources to move")
 
 	rootCmd.AddCommand(mvrssCmd)
 }
+
+func moveResources(profile *_profile.Profile, from string, to string, concurrency int, dryRun bool) error {
+	ctx := context.Background()
+	if concurrency < 1 {
+		return errors.New("concurrency must be positive")
+	}
+	dbDriver, err := db.NewDBDriver(profile)
+	if err != nil {
+		return errors.Wrap(err, "failed to create db driver")
+	}
+	if err := dbDriver.Migrate(ctx); err != nil {
+		dbDriver.Close()
+		return errors.Wrap(err, "failed to migrate db")
+	}
+	s := store.New(dbDriver, profile)
+	defer s.Close()
+
+	fromStorageID, err := findStorageID(ctx, s, from)
+	if err != nil {
+		return err
+	}
+	toStorageID, err := findStorageID(ctx, s, to)
+	if err != nil {
+		return err
+	}
+	if fromStorageID == toStorageID {
+		return errors.New("from and to storages are the same")
+	}
+
+	resources, err := s.ListResources(ctx, &store.FindResource{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list resources")
+	}
+	// The moves in progress are finished on interruption, and the rest are left to the next run.
+	stopCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	movedCount, movedSize, failedCount := 0, int64(0), 0
+	semaphore := make(chan struct{}, concurrency)
+	for _, resource := range resources {
+		storageID, ok, err := apiv1.GetResourceStorageID(ctx, s, resource)
+		if err != nil {
+			return errors.Wrap(err, "failed to find storage of resource")
+		}
+		if !ok || storageID != fromStorageID {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("Resource %5d would move %12d bytes of %s\n", resource.ID, resource.Size, resource.Filename)
+			movedCount++
+			movedSize += resource.Size
+			continue
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-stopCtx.Done():
+		}
+		if stopCtx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(resource *store.Resource) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			_, err := apiv1.MoveResourceBlob(ctx, s, resource, toStorageID)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				fmt.Printf("Resource %5d failed to move: %s\n", resource.ID, err)
+				failedCount++
+				return
+			}
+			fmt.Printf("Resource %5d moved %12d bytes of %s\n", resource.ID, resource.Size, resource.Filename)
+			movedCount++
+			movedSize += resource.Size
+		}(resource)
+	}
+	wg.Wait()
+
+	switch {
+	case dryRun:
+		fmt.Printf("would move %d resources, %d bytes\n", movedCount, movedSize)
+	case stopCtx.Err() != nil:
+		fmt.Printf("interrupted after moving %d resources, %d bytes, run it again to resume\n", movedCount, movedSize)
+	default:
+		fmt.Printf("moved %d resources, %d bytes\n", movedCount, movedSize)
+	}
+	if failedCount > 0 {
+		return errors.Errorf("failed to move %d resources", failedCount)
+	}
+	return nil
+}
+
+// findStorageID finds the storage by "db", "local", or the ID or name of a storage.
+func findStorageID(ctx context.Context, s *store.Store, value string) (int32, error) {
+	switch value {
+	case "db":
+		return apiv1.DatabaseStorage, nil
+	case "local":
+		return apiv1.LocalStorage, nil
+	}
+
+	storages, err := s.ListStorages(ctx, &store.FindStorage{})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list storages")
+	}
+	id, err := strconv.ParseInt(value, 10, 32)
+	for _, storage := range storages {
+		if (err == nil && storage.ID == int32(id)) || storage.Name == value {
+			return storage.ID, nil
+		}
+	}
+	return 0, errors.Errorf("storage %s not found", value)
+}
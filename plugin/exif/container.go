@@ -0,0 +1,135 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/png"
+
+	"github.com/pkg/errors"
+	xwebp "golang.org/x/image/webp"
+
+	"github.com/usememos/memos/plugin/webp"
+)
+
+// WebP VP8X flags of the metadata chunks.
+const (
+	webpFlagEXIF = 0x08
+	webpFlagXMP  = 0x04
+)
+
+var (
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+	// pngMetadataChunks are the PNG chunks stripped, i.e. EXIF, the texts which include XMP, and the modification time.
+	pngMetadataChunks = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+)
+
+// stripPNG returns the PNG image without its metadata chunks, along with the metadata read from its EXIF.
+// The image rotated by its EXIF orientation is encoded again without the ancillary chunks, such as the color profile.
+func stripPNG(data []byte) ([]byte, *Metadata, error) {
+	metadata := &Metadata{Orientation: 1}
+	stripped := &bytes.Buffer{}
+	stripped.Write(pngSignature)
+	pos := len(pngSignature)
+	for {
+		if pos+12 > len(data) {
+			return nil, nil, errors.New("unexpected end of image")
+		}
+		length := binary.BigEndian.Uint32(data[pos:])
+		if length > uint32(len(data)-pos-12) {
+			return nil, nil, errors.Errorf("invalid chunk length at %d", pos)
+		}
+		chunkType, end := string(data[pos+4:pos+8]), pos+12+int(length)
+		if chunkType == "eXIf" {
+			if err := readTIFF(data[pos+8:end-4], metadata); err != nil {
+				return nil, nil, errors.Wrap(err, "failed to read EXIF")
+			}
+		}
+		if !pngMetadataChunks[chunkType] {
+			stripped.Write(data[pos:end])
+		}
+		pos = end
+		if chunkType == "IEND" {
+			break
+		}
+	}
+
+	if metadata.Orientation < 2 || metadata.Orientation > 8 {
+		return stripped.Bytes(), metadata, nil
+	}
+	img, err := png.Decode(bytes.NewReader(stripped.Bytes()))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode image")
+	}
+	rotated := &bytes.Buffer{}
+	if err := png.Encode(rotated, orient(img, metadata.Orientation)); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encode image")
+	}
+	return rotated.Bytes(), metadata, nil
+}
+
+func isWebP(data []byte) bool {
+	return len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+// stripWebP returns the WebP image without its EXIF and XMP chunks, along with the metadata read from its EXIF.
+// The image rotated by its EXIF orientation is encoded again in the lossless format, which fails if it's animated.
+func stripWebP(data []byte) ([]byte, *Metadata, error) {
+	riffEnd := 8 + int64(binary.LittleEndian.Uint32(data[4:]))
+	if riffEnd > int64(len(data)) {
+		return nil, nil, errors.New("unexpected end of image")
+	}
+
+	metadata := &Metadata{Orientation: 1}
+	chunks := &bytes.Buffer{}
+	vp8xOffset := -1
+	pos := int64(12)
+	for pos < riffEnd {
+		if pos+8 > riffEnd {
+			return nil, nil, errors.New("unexpected end of image")
+		}
+		fourCC, size := string(data[pos:pos+4]), int64(binary.LittleEndian.Uint32(data[pos+4:]))
+		end := pos + 8 + size
+		if end > riffEnd {
+			return nil, nil, errors.Errorf("invalid chunk size at %d", pos)
+		}
+		// The chunks are padded to even sizes.
+		end = min(end+size%2, riffEnd)
+		switch fourCC {
+		case "EXIF":
+			// Some encoders write the EXIF with the header of the JPEG segment.
+			if err := readTIFF(bytes.TrimPrefix(data[pos+8:pos+8+size], exifHeader), metadata); err != nil {
+				return nil, nil, errors.Wrap(err, "failed to read EXIF")
+			}
+		case "XMP ":
+		default:
+			if fourCC == "VP8X" && size > 0 {
+				vp8xOffset = chunks.Len()
+			}
+			chunks.Write(data[pos:end])
+		}
+		pos = end
+	}
+	payload := chunks.Bytes()
+	if vp8xOffset >= 0 {
+		payload[vp8xOffset+8] &^= webpFlagEXIF | webpFlagXMP
+	}
+
+	stripped := &bytes.Buffer{}
+	stripped.WriteString("RIFF")
+	_ = binary.Write(stripped, binary.LittleEndian, uint32(4+len(payload)))
+	stripped.WriteString("WEBP")
+	stripped.Write(payload)
+	if metadata.Orientation < 2 || metadata.Orientation > 8 {
+		return stripped.Bytes(), metadata, nil
+	}
+	img, err := xwebp.Decode(bytes.NewReader(stripped.Bytes()))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode image")
+	}
+	rotated := &bytes.Buffer{}
+	if err := webp.Encode(rotated, orient(img, metadata.Orientation)); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encode image")
+	}
+	return rotated.Bytes(), metadata, nil
+}
@@ -0,0 +1,347 @@
+// Package exif strips the metadata out of the JPEG, PNG and WebP images, i.e. EXIF, XMP, IPTC, texts and comments,
+// which include the locations where the photos were taken and the devices which took them.
+// The orientation in EXIF is applied to the pixels before it's stripped, and the capture time is read from EXIF.
+// The other formats such as HEIC aren't supported.
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/plugin/webp"
+)
+
+// JPEG markers.
+const (
+	markerSOI   = 0xD8
+	markerEOI   = 0xD9
+	markerSOS   = 0xDA
+	markerRST0  = 0xD0
+	markerRST7  = 0xD7
+	markerTEM   = 0x01
+	markerAPP0  = 0xE0
+	markerAPP1  = 0xE1
+	markerAPP2  = 0xE2
+	markerAPP14 = 0xEE
+	markerAPP15 = 0xEF
+	markerCOM   = 0xFE
+)
+
+// TIFF tags read from EXIF.
+const (
+	tagOrientation        = 0x0112
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+)
+
+// jpegQuality is the quality of the images encoded again after they're rotated, which is high to keep the details of the photos.
+const jpegQuality = 95
+
+var (
+	exifHeader = []byte("Exif\x00\x00")
+	jfifHeader = []byte("JFIF\x00")
+	iccHeader  = []byte("ICC_PROFILE\x00")
+	// adobeHeader is the header of the segment telling the color transform of the image, which is needed to decode it.
+	adobeHeader = []byte("Adobe")
+
+	// tiffTypeSizes are the sizes of the TIFF field types by their IDs.
+	tiffTypeSizes = map[uint16]uint32{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8}
+)
+
+// Metadata is the metadata read from EXIF.
+type Metadata struct {
+	// Orientation is the EXIF orientation from 1 to 8, which is 1 if it's absent.
+	Orientation int
+	// CapturedTime is the time when the photo was taken, which is zero if it's absent.
+	// It's in UTC if the time zone is absent, as EXIF keeps the local time without it.
+	CapturedTime time.Time
+}
+
+// IsSupported returns whether the metadata of the images of the MIME type can be stripped.
+func IsSupported(mimeType string) bool {
+	switch strings.ToLower(mimeType) {
+	case "image/jpeg", "image/png", "image/webp":
+		return true
+	default:
+		return false
+	}
+}
+
+// Strip returns the image without its metadata, along with the metadata read from its EXIF.
+// The image is kept as is unless it's rotated by its EXIF orientation, in which case it's encoded again.
+func Strip(data []byte) ([]byte, *Metadata, error) {
+	switch {
+	case bytes.HasPrefix(data, pngSignature):
+		return stripPNG(data)
+	case isWebP(data):
+		return stripWebP(data)
+	default:
+		return stripJPEG(data)
+	}
+}
+
+// Reencode decodes the image and encodes its pixels again in the same format, without any of its metadata.
+// It's for the images whose metadata can't be parsed to be stripped, while their pixels can still be decoded.
+func Reencode(data []byte) ([]byte, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode image")
+	}
+	encoded := &bytes.Buffer{}
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(encoded, img, &jpeg.Options{Quality: jpegQuality})
+	case "png":
+		err = png.Encode(encoded, img)
+	case "webp":
+		err = webp.Encode(encoded, img)
+	default:
+		return nil, errors.Errorf("unsupported image format: %s", format)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode image")
+	}
+	return encoded.Bytes(), nil
+}
+
+// stripJPEG returns the JPEG image without its metadata except the color profile, along with the metadata read from its EXIF.
+// The data after the end of the image, such as the embedded previews of the cameras, is dropped as well.
+func stripJPEG(data []byte) ([]byte, *Metadata, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != markerSOI {
+		return nil, nil, errors.New("not a JPEG image")
+	}
+
+	metadata := &Metadata{Orientation: 1}
+	iccSegments := [][]byte{}
+	stripped := &bytes.Buffer{}
+	stripped.Write([]byte{0xFF, markerSOI})
+	pos := 2
+	for {
+		if pos >= len(data) || data[pos] != 0xFF {
+			return nil, nil, errors.Errorf("invalid marker at %d", pos)
+		}
+		// A marker may be preceded by any number of fill bytes.
+		for pos < len(data) && data[pos] == 0xFF {
+			pos++
+		}
+		if pos >= len(data) {
+			return nil, nil, errors.New("unexpected end of image")
+		}
+		marker := data[pos]
+		pos++
+		if marker == markerEOI {
+			break
+		}
+		if (marker >= markerRST0 && marker <= markerRST7) || marker == markerTEM {
+			stripped.Write([]byte{0xFF, marker})
+			continue
+		}
+
+		if pos+2 > len(data) {
+			return nil, nil, errors.New("unexpected end of image")
+		}
+		length := int(binary.BigEndian.Uint16(data[pos:]))
+		if length < 2 || pos+length > len(data) {
+			return nil, nil, errors.Errorf("invalid segment length at %d", pos)
+		}
+		segment := data[pos : pos+length]
+		payload := segment[2:]
+		pos += length
+
+		keep := true
+		switch {
+		case marker == markerAPP0:
+			keep = bytes.HasPrefix(payload, jfifHeader)
+		case marker == markerAPP1:
+			if bytes.HasPrefix(payload, exifHeader) {
+				// The metadata is read from the first EXIF, and the others are stripped as is.
+				if err := readTIFF(payload[len(exifHeader):], metadata); err != nil {
+					return nil, nil, errors.Wrap(err, "failed to read EXIF")
+				}
+			}
+			keep = false
+		case marker == markerAPP2:
+			keep = bytes.HasPrefix(payload, iccHeader)
+			if keep {
+				iccSegments = append(iccSegments, segment)
+			}
+		case marker == markerAPP14:
+			keep = bytes.HasPrefix(payload, adobeHeader)
+		case marker > markerAPP0 && marker <= markerAPP15, marker == markerCOM:
+			keep = false
+		}
+		if keep {
+			stripped.Write([]byte{0xFF, marker})
+			stripped.Write(segment)
+		}
+
+		if marker == markerSOS {
+			// The entropy-coded data follows the scan header, in which 0xFF is followed by 0x00 or a restart marker.
+			start := pos
+			for pos < len(data) && !(data[pos] == 0xFF && pos+1 < len(data) && data[pos+1] != 0x00 && (data[pos+1] < markerRST0 || data[pos+1] > markerRST7)) {
+				pos++
+			}
+			stripped.Write(data[start:pos])
+			// The truncated images are decoded as far as they go, so are they kept.
+			if pos >= len(data) {
+				break
+			}
+		}
+	}
+	stripped.Write([]byte{0xFF, markerEOI})
+
+	if metadata.Orientation < 2 || metadata.Orientation > 8 {
+		return stripped.Bytes(), metadata, nil
+	}
+	rotated, err := rotate(stripped.Bytes(), metadata.Orientation, iccSegments)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rotated, metadata, nil
+}
+
+// rotate encodes the image again with the orientation applied to the pixels, and the color profile segments kept.
+func rotate(data []byte, orientation int, iccSegments [][]byte) ([]byte, error) {
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode image")
+	}
+	// The color profile of the CMYK images doesn't apply to them encoded in YCbCr.
+	if _, ok := img.(*image.CMYK); ok {
+		iccSegments = nil
+	}
+
+	rotated := orient(img, orientation)
+	encoded := &bytes.Buffer{}
+	if err := jpeg.Encode(encoded, rotated, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, errors.Wrap(err, "failed to encode image")
+	}
+
+	result := &bytes.Buffer{}
+	result.Write([]byte{0xFF, markerSOI})
+	for _, segment := range iccSegments {
+		result.Write([]byte{0xFF, markerAPP2})
+		result.Write(segment)
+	}
+	result.Write(encoded.Bytes()[2:])
+	return result.Bytes(), nil
+}
+
+// orient returns the image with the EXIF orientation applied to its pixels.
+func orient(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return imaging.FlipH(img)
+	case 3:
+		return imaging.Rotate180(img)
+	case 4:
+		return imaging.FlipV(img)
+	case 5:
+		return imaging.Transpose(img)
+	case 6:
+		return imaging.Rotate270(img)
+	case 7:
+		return imaging.Transverse(img)
+	case 8:
+		return imaging.Rotate90(img)
+	default:
+		return img
+	}
+}
+
+// readTIFF reads the metadata from the TIFF structure of EXIF.
+func readTIFF(data []byte, metadata *Metadata) error {
+	if len(data) < 8 {
+		return errors.New("invalid TIFF header")
+	}
+	var order binary.ByteOrder
+	switch string(data[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return errors.New("invalid TIFF header")
+	}
+
+	ifd0, err := readIFD(data, order, order.Uint32(data[4:]))
+	if err != nil {
+		return err
+	}
+	if value, ok := ifd0[tagOrientation]; ok && len(value) >= 2 {
+		metadata.Orientation = int(order.Uint16(value))
+	}
+	dateTime := string(ifd0[tagDateTime])
+	offsetTime := ""
+	if value, ok := ifd0[tagExifIFD]; ok && len(value) >= 4 {
+		exifIFD, err := readIFD(data, order, order.Uint32(value))
+		if err != nil {
+			return err
+		}
+		if value, ok := exifIFD[tagDateTimeOriginal]; ok {
+			dateTime = string(value)
+			offsetTime = string(exifIFD[tagOffsetTimeOriginal])
+		}
+	}
+	metadata.CapturedTime = parseDateTime(dateTime, offsetTime)
+	return nil
+}
+
+// readIFD reads the values of the fields in the IFD at the offset by their tags.
+func readIFD(data []byte, order binary.ByteOrder, offset uint32) (map[uint16][]byte, error) {
+	if uint64(offset)+2 > uint64(len(data)) {
+		return nil, errors.Errorf("invalid IFD offset %d", offset)
+	}
+	count := uint32(order.Uint16(data[offset:]))
+	if uint64(offset)+2+uint64(count)*12 > uint64(len(data)) {
+		return nil, errors.Errorf("invalid IFD at %d", offset)
+	}
+
+	fields := map[uint16][]byte{}
+	for i := uint32(0); i < count; i++ {
+		entry := data[offset+2+i*12 : offset+2+(i+1)*12]
+		typeSize, ok := tiffTypeSizes[order.Uint16(entry[2:])]
+		if !ok {
+			continue
+		}
+		size := uint64(typeSize) * uint64(order.Uint32(entry[4:]))
+		// The values no longer than 4 bytes are kept in the entries themselves.
+		if size <= 4 {
+			fields[order.Uint16(entry)] = entry[8 : 8+size]
+			continue
+		}
+		valueOffset := uint64(order.Uint32(entry[8:]))
+		if valueOffset+size > uint64(len(data)) {
+			continue
+		}
+		fields[order.Uint16(entry)] = data[valueOffset : valueOffset+size]
+	}
+	return fields, nil
+}
+
+// parseDateTime parses the EXIF date time, e.g. "2006:01:02 15:04:05", with the offset such as "+08:00".
+// It returns zero if the date time is absent or invalid.
+func parseDateTime(dateTime, offsetTime string) time.Time {
+	dateTime = strings.TrimRight(dateTime, "\x00 ")
+	offsetTime = strings.TrimRight(offsetTime, "\x00 ")
+	if offsetTime != "" {
+		if t, err := time.Parse("2006:01:02 15:04:05-07:00", dateTime+offsetTime); err == nil {
+			return t
+		}
+	}
+	t, err := time.Parse("2006:01:02 15:04:05", dateTime)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
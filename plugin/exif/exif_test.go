@@ -0,0 +1,281 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	xwebp "golang.org/x/image/webp"
+
+	"github.com/usememos/memos/plugin/webp"
+)
+
+func TestStrip(t *testing.T) {
+	encoded := &bytes.Buffer{}
+	require.NoError(t, jpeg.Encode(encoded, buildImage(), &jpeg.Options{Quality: 100}))
+
+	tests := []struct {
+		name         string
+		orientation  uint16
+		bounds       image.Rectangle
+		red          image.Point
+		blue         image.Point
+		capturedTime time.Time
+	}{
+		{
+			name:         "upright",
+			orientation:  1,
+			bounds:       image.Rect(0, 0, 40, 20),
+			red:          image.Pt(5, 10),
+			blue:         image.Pt(35, 10),
+			capturedTime: time.Date(2023, 5, 1, 10, 30, 0, 0, time.FixedZone("", 9*60*60)),
+		},
+		{
+			name:         "rotated clockwise",
+			orientation:  6,
+			bounds:       image.Rect(0, 0, 20, 40),
+			red:          image.Pt(10, 5),
+			blue:         image.Pt(10, 35),
+			capturedTime: time.Date(2023, 5, 1, 10, 30, 0, 0, time.FixedZone("", 9*60*60)),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := &bytes.Buffer{}
+			data.Write(encoded.Bytes()[:2])
+			writeSegment(data, markerAPP1, append([]byte("Exif\x00\x00"), buildTIFF(test.orientation)...))
+			writeSegment(data, markerAPP1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>GPSLatitude</x:xmpmeta>"))
+			writeSegment(data, markerAPP2, []byte("ICC_PROFILE\x00\x01\x01test_profile"))
+			writeSegment(data, markerCOM, []byte("test_comment"))
+			data.Write(encoded.Bytes()[2:])
+			// The trailing data, such as the embedded previews.
+			data.WriteString("test_trailer")
+
+			stripped, metadata, err := Strip(data.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, int(test.orientation), metadata.Orientation)
+			require.True(t, test.capturedTime.Equal(metadata.CapturedTime), metadata.CapturedTime)
+			for _, removed := range []string{"Exif", "TestPhone", "GPSLatitude", "test_comment", "test_trailer"} {
+				require.NotContains(t, string(stripped), removed)
+			}
+			require.Contains(t, string(stripped), "test_profile")
+
+			img, err := jpeg.Decode(bytes.NewReader(stripped))
+			require.NoError(t, err)
+			require.Equal(t, test.bounds, img.Bounds())
+			requireColor(t, color.RGBA{R: 0xff, A: 0xff}, img.At(test.red.X, test.red.Y))
+			requireColor(t, color.RGBA{B: 0xff, A: 0xff}, img.At(test.blue.X, test.blue.Y))
+		})
+	}
+
+	t.Run("without metadata", func(t *testing.T) {
+		stripped, metadata, err := Strip(encoded.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, encoded.Bytes(), stripped)
+		require.Equal(t, &Metadata{Orientation: 1}, metadata)
+	})
+
+	t.Run("not a JPEG image", func(t *testing.T) {
+		_, _, err := Strip([]byte("test_resource"))
+		require.Error(t, err)
+	})
+}
+
+func TestStripPNG(t *testing.T) {
+	encoded := &bytes.Buffer{}
+	require.NoError(t, png.Encode(encoded, buildImage()))
+	// The metadata chunks are inserted after the signature and IHDR, which take 33 bytes.
+	data := &bytes.Buffer{}
+	data.Write(encoded.Bytes()[:33])
+	writePNGChunk(data, "eXIf", buildTIFF(6))
+	writePNGChunk(data, "iTXt", []byte("XML:com.adobe.xmp\x00\x00\x00\x00\x00<x:xmpmeta>GPSLatitude</x:xmpmeta>"))
+	writePNGChunk(data, "tEXt", []byte("Comment\x00test_comment"))
+	data.Write(encoded.Bytes()[33:])
+
+	stripped, metadata, err := Strip(data.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, 6, metadata.Orientation)
+	for _, removed := range []string{"eXIf", "TestPhone", "GPSLatitude", "test_comment"} {
+		require.NotContains(t, string(stripped), removed)
+	}
+	img, err := png.Decode(bytes.NewReader(stripped))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+	requireColor(t, color.RGBA{R: 0xff, A: 0xff}, img.At(10, 5))
+	requireColor(t, color.RGBA{B: 0xff, A: 0xff}, img.At(10, 35))
+
+	_, _, err = Strip(encoded.Bytes()[:40])
+	require.Error(t, err)
+}
+
+func TestStripWebP(t *testing.T) {
+	encoded := &bytes.Buffer{}
+	require.NoError(t, webp.Encode(encoded, buildImage()))
+	vp8x := make([]byte, 10)
+	vp8x[0] = webpFlagEXIF | webpFlagXMP
+	vp8x[4], vp8x[7] = 39, 19
+	chunks := &bytes.Buffer{}
+	writeWebPChunk(chunks, "VP8X", vp8x)
+	chunks.Write(encoded.Bytes()[12:])
+	// The EXIF of odd size is padded.
+	writeWebPChunk(chunks, "EXIF", append(buildTIFF(1), 0))
+	writeWebPChunk(chunks, "XMP ", []byte("<x:xmpmeta>GPSLatitude</x:xmpmeta>"))
+	data := &bytes.Buffer{}
+	data.WriteString("RIFF")
+	_ = binary.Write(data, binary.LittleEndian, uint32(4+chunks.Len()))
+	data.WriteString("WEBP")
+	data.Write(chunks.Bytes())
+
+	stripped, metadata, err := Strip(data.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, 1, metadata.Orientation)
+	require.False(t, metadata.CapturedTime.IsZero())
+	for _, removed := range []string{"EXIF", "XMP ", "TestPhone", "GPSLatitude"} {
+		require.NotContains(t, string(stripped), removed)
+	}
+	require.Equal(t, byte(0), stripped[20]&(webpFlagEXIF|webpFlagXMP))
+	require.Equal(t, uint32(len(stripped)-8), binary.LittleEndian.Uint32(stripped[4:]))
+	img, err := xwebp.Decode(bytes.NewReader(stripped))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 40, 20), img.Bounds())
+
+	_, _, err = Strip(data.Bytes()[:data.Len()-10])
+	require.Error(t, err)
+}
+
+func TestReencode(t *testing.T) {
+	encoded := &bytes.Buffer{}
+	require.NoError(t, jpeg.Encode(encoded, buildImage(), nil))
+	// The EXIF which can't be parsed fails the stripping, while the pixels can still be encoded again.
+	data := &bytes.Buffer{}
+	data.Write(encoded.Bytes()[:2])
+	writeSegment(data, markerAPP1, []byte("Exif\x00\x00GPSLatitude"))
+	data.Write(encoded.Bytes()[2:])
+	_, _, err := Strip(data.Bytes())
+	require.Error(t, err)
+
+	reencoded, err := Reencode(data.Bytes())
+	require.NoError(t, err)
+	require.NotContains(t, string(reencoded), "GPSLatitude")
+	img, err := jpeg.Decode(bytes.NewReader(reencoded))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 40, 20), img.Bounds())
+
+	_, err = Reencode([]byte("test_resource"))
+	require.Error(t, err)
+}
+
+func TestParseDateTime(t *testing.T) {
+	require.Equal(t, time.Date(2023, 5, 1, 10, 30, 0, 0, time.UTC), parseDateTime("2023:05:01 10:30:00\x00", ""))
+	require.True(t, time.Date(2023, 5, 1, 10, 30, 0, 0, time.FixedZone("", -5*60*60)).Equal(parseDateTime("2023:05:01 10:30:00", "-05:00")))
+	require.True(t, parseDateTime("0000:00:00 00:00:00", "").IsZero())
+	require.True(t, parseDateTime("", "").IsZero())
+}
+
+// buildImage builds the image with the red left half and the blue right half.
+func buildImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			if x < 20 {
+				img.Set(x, y, color.RGBA{R: 0xff, A: 0xff})
+			} else {
+				img.Set(x, y, color.RGBA{B: 0xff, A: 0xff})
+			}
+		}
+	}
+	return img
+}
+
+func writePNGChunk(buf *bytes.Buffer, chunkType string, payload []byte) {
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(payload)))
+	buf.WriteString(chunkType)
+	buf.Write(payload)
+	_ = binary.Write(buf, binary.BigEndian, crc32.ChecksumIEEE(append([]byte(chunkType), payload...)))
+}
+
+func writeWebPChunk(buf *bytes.Buffer, fourCC string, payload []byte) {
+	buf.WriteString(fourCC)
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(payload)))
+	buf.Write(payload)
+	if len(payload)%2 == 1 {
+		buf.WriteByte(0)
+	}
+}
+
+func writeSegment(buf *bytes.Buffer, marker byte, payload []byte) {
+	buf.Write([]byte{0xFF, marker})
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(payload)+2))
+	buf.Write(payload)
+}
+
+// buildTIFF builds the little-endian TIFF structure of EXIF with the device, the location and the capture time.
+func buildTIFF(orientation uint16) []byte {
+	type field struct {
+		tag       uint16
+		fieldType uint16
+		count     uint32
+		value     []byte
+	}
+	ascii := func(tag uint16, value string) field {
+		return field{tag: tag, fieldType: 2, count: uint32(len(value) + 1), value: append([]byte(value), 0)}
+	}
+	long := func(tag uint16, value uint32) field {
+		return field{tag: tag, fieldType: 4, count: 1, value: binary.LittleEndian.AppendUint32(nil, value)}
+	}
+
+	// The IFDs are laid out one after another with their values following them.
+	const ifd0Offset, exifOffset, gpsOffset = 8, 200, 400
+	ifds := map[uint32][]field{
+		ifd0Offset: {
+			ascii(0x010F, "TestPhone"),
+			{tag: tagOrientation, fieldType: 3, count: 1, value: binary.LittleEndian.AppendUint16(nil, orientation)},
+			ascii(tagDateTime, "2023:06:01 00:00:00"),
+			long(tagExifIFD, exifOffset),
+			long(0x8825, gpsOffset),
+		},
+		exifOffset: {
+			ascii(tagDateTimeOriginal, "2023:05:01 10:30:00"),
+			ascii(tagOffsetTimeOriginal, "+09:00"),
+		},
+		gpsOffset: {
+			ascii(0x0001, "N"),
+		},
+	}
+	data := make([]byte, 600)
+	copy(data, "II*\x00")
+	binary.LittleEndian.PutUint32(data[4:], ifd0Offset)
+	for offset, fields := range ifds {
+		binary.LittleEndian.PutUint16(data[offset:], uint16(len(fields)))
+		valueOffset := offset + 2 + uint32(len(fields))*12 + 4
+		for i, field := range fields {
+			entry := data[offset+2+uint32(i)*12:]
+			binary.LittleEndian.PutUint16(entry, field.tag)
+			binary.LittleEndian.PutUint16(entry[2:], field.fieldType)
+			binary.LittleEndian.PutUint32(entry[4:], field.count)
+			if len(field.value) <= 4 {
+				copy(entry[8:12], field.value)
+				continue
+			}
+			binary.LittleEndian.PutUint32(entry[8:], valueOffset)
+			copy(data[valueOffset:], field.value)
+			valueOffset += uint32(len(field.value))
+		}
+	}
+	return data
+}
+
+func requireColor(t *testing.T, expected color.Color, actual color.Color) {
+	er, eg, eb, _ := expected.RGBA()
+	ar, ag, ab, _ := actual.RGBA()
+	for _, pair := range [][2]uint32{{er, ar}, {eg, ag}, {eb, ab}} {
+		require.InDelta(t, float64(pair[0]>>8), float64(pair[1]>>8), 16, "expected %v, got %v", expected, actual)
+	}
+}
@@ -1,19 +1,72 @@
 package ast
 
-type Node struct {
-	Type     string
-	Text     string
-	Children []*Node
+type NodeType int
+
+const (
+	UnknownNode NodeType = iota
+	// Block nodes.
+	LineBreakNode
+	ParagraphNode
+	CodeBlockNode
+	HeadingNode
+	HorizontalRuleNode
+	BlockquoteNode
+	OrderedListNode
+	UnorderedListNode
+	TaskListNode
+	TableNode
+	// Inline nodes.
+	TextNode
+	BoldNode
+	ItalicNode
+	CodeNode
+	ImageNode
+	LinkNode
+	TagNode
+	StrikethroughNode
+)
+
+type Node interface {
+	Type() NodeType
 }
 
 type Document struct {
-	Nodes []*Node
+	Nodes []Node
 }
 
 func NewDocument() *Document {
 	return &Document{}
 }
 
-func (d *Document) AddNode(node *Node) {
+func (d *Document) AddNode(node Node) {
 	d.Nodes = append(d.Nodes, node)
 }
+
+// Walk traverses the nodes in depth-first order and calls fn for each node.
+func Walk(nodes []Node, fn func(node Node)) {
+	for _, node := range nodes {
+		fn(node)
+		switch n := node.(type) {
+		case *Paragraph:
+			Walk(n.Children, fn)
+		case *Heading:
+			Walk(n.Children, fn)
+		case *Bold:
+			Walk(n.Children, fn)
+		case *Blockquote:
+			Walk(n.Children, fn)
+		case *OrderedList:
+			Walk(n.Children, fn)
+		case *UnorderedList:
+			Walk(n.Children, fn)
+		case *TaskList:
+			Walk(n.Children, fn)
+		case *Italic:
+			Walk(n.Children, fn)
+		case *Strikethrough:
+			Walk(n.Children, fn)
+		case *Table:
+			Walk(n.Children, fn)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package ast
+
+type BaseBlock struct{}
+
+type LineBreak struct {
+	BaseBlock
+}
+
+func (*LineBreak) Type() NodeType {
+	return LineBreakNode
+}
+
+type Paragraph struct {
+	BaseBlock
+
+	Children []Node
+}
+
+func (*Paragraph) Type() NodeType {
+	return ParagraphNode
+}
+
+type CodeBlock struct {
+	BaseBlock
+
+	Language string
+	Content  string
+}
+
+func (*CodeBlock) Type() NodeType {
+	return CodeBlockNode
+}
+
+type Heading struct {
+	BaseBlock
+
+	Level    int
+	Children []Node
+}
+
+func (*Heading) Type() NodeType {
+	return HeadingNode
+}
+
+type HorizontalRule struct {
+	BaseBlock
+
+	// Symbol is "*", "-" or "_".
+	Symbol string
+}
+
+func (*HorizontalRule) Type() NodeType {
+	return HorizontalRuleNode
+}
+
+type Blockquote struct {
+	BaseBlock
+
+	Children []Node
+}
+
+func (*Blockquote) Type() NodeType {
+	return BlockquoteNode
+}
+
+// OrderedList is a single item of an ordered list.
+type OrderedList struct {
+	BaseBlock
+
+	// Indent is the number of leading spaces.
+	Indent   int
+	Number   string
+	Children []Node
+}
+
+func (*OrderedList) Type() NodeType {
+	return OrderedListNode
+}
+
+// UnorderedList is a single item of an unordered list.
+type UnorderedList struct {
+	BaseBlock
+
+	// Indent is the number of leading spaces.
+	Indent int
+	// Symbol is "*", "-" or "+".
+	Symbol   string
+	Children []Node
+}
+
+func (*UnorderedList) Type() NodeType {
+	return UnorderedListNode
+}
+
+// TaskList is a single item of a task list, e.g. "- [ ] todo".
+type TaskList struct {
+	BaseBlock
+
+	// Indent is the number of leading spaces.
+	Indent int
+	// Symbol is "*", "-" or "+".
+	Symbol   string
+	Complete bool
+	// CheckboxPos is the byte offset of the mark between the brackets, i.e. the space or "x", in the content.
+	CheckboxPos int
+	Children    []Node
+}
+
+func (*TaskList) Type() NodeType {
+	return TaskListNode
+}
+
+type Table struct {
+	BaseBlock
+
+	Header []string
+	// Delimiter holds the delimiter cells, e.g. "---" or ":---:".
+	Delimiter []string
+	Rows      [][]string
+	// Children holds the inline nodes of the cells, the header first and then the rows,
+	// which are walked like the other blocks while the cells are rendered from their text.
+	Children []Node
+}
+
+func (*Table) Type() NodeType {
+	return TableNode
+}
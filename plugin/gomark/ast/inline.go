@@ -0,0 +1,89 @@
+package ast
+
+type BaseInline struct{}
+
+type Text struct {
+	BaseInline
+
+	Content string
+}
+
+func (*Text) Type() NodeType {
+	return TextNode
+}
+
+type Bold struct {
+	BaseInline
+
+	// Symbol is "*" or "_".
+	Symbol   string
+	Children []Node
+}
+
+func (*Bold) Type() NodeType {
+	return BoldNode
+}
+
+type Italic struct {
+	BaseInline
+
+	// Symbol is "*" or "_".
+	Symbol   string
+	Children []Node
+}
+
+func (*Italic) Type() NodeType {
+	return ItalicNode
+}
+
+type Code struct {
+	BaseInline
+
+	Content string
+}
+
+func (*Code) Type() NodeType {
+	return CodeNode
+}
+
+type Image struct {
+	BaseInline
+
+	AltText string
+	URL     string
+}
+
+func (*Image) Type() NodeType {
+	return ImageNode
+}
+
+type Link struct {
+	BaseInline
+
+	Text string
+	URL  string
+}
+
+func (*Link) Type() NodeType {
+	return LinkNode
+}
+
+type Tag struct {
+	BaseInline
+
+	Content string
+}
+
+func (*Tag) Type() NodeType {
+	return TagNode
+}
+
+type Strikethrough struct {
+	BaseInline
+
+	Children []Node
+}
+
+func (*Strikethrough) Type() NodeType {
+	return StrikethroughNode
+}
@@ -1 +1,13 @@
 package gomark
+
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+// Parse parses the memo content into a document.
+func Parse(content string) *ast.Document {
+	tokens := tokenizer.Tokenize(content)
+	return parser.Parse(tokens)
+}
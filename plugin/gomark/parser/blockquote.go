@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+type BlockquoteParser struct {
+	ContentTokens []*tokenizer.Token
+}
+
+func NewBlockquoteParser() *BlockquoteParser {
+	return &BlockquoteParser{}
+}
+
+func (*BlockquoteParser) Match(tokens []*tokenizer.Token) *BlockquoteParser {
+	if len(tokens) < 3 {
+		return nil
+	}
+	if tokens[0].Type != tokenizer.GreaterThan || tokens[1].Type != tokenizer.Space {
+		return nil
+	}
+
+	contentTokens := []*tokenizer.Token{}
+	for _, token := range tokens[2:] {
+		if token.Type == tokenizer.Newline {
+			break
+		}
+		contentTokens = append(contentTokens, token)
+	}
+	if len(contentTokens) == 0 {
+		return nil
+	}
+
+	return &BlockquoteParser{
+		ContentTokens: contentTokens,
+	}
+}
+
+func (p *BlockquoteParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	blockquote := p.Match(tokens)
+	if blockquote == nil {
+		return nil, 0
+	}
+
+	// The quoted task is kept as a task, so it can be listed and toggled like the others.
+	children := []ast.Node{}
+	if taskList, _ := NewTaskListParser().Parse(blockquote.ContentTokens); taskList != nil {
+		children = append(children, taskList)
+	} else {
+		children = ParseInline(blockquote.ContentTokens)
+	}
+	return &ast.Blockquote{
+		Children: children,
+	}, len(blockquote.ContentTokens) + 2
+}
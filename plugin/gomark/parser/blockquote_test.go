@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+func TestBlockquoteParser(t *testing.T) {
+	tests := []struct {
+		text       string
+		blockquote *BlockquoteParser
+	}{
+		{
+			text:       ">Hello",
+			blockquote: nil,
+		},
+		{
+			text:       "> ",
+			blockquote: nil,
+		},
+		{
+			text: "> Hello\nworld",
+			blockquote: &BlockquoteParser{
+				ContentTokens: []*tokenizer.Token{
+					{
+						Type:  tokenizer.Text,
+						Value: "Hello",
+						Pos:   2,
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tokens := tokenizer.Tokenize(test.text)
+		require.Equal(t, test.blockquote, NewBlockquoteParser().Match(tokens))
+	}
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"github.com/usememos/memos/plugin/gomark/ast"
 	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
 )
 
@@ -47,3 +48,15 @@ func (*BoldParser) Match(tokens []*tokenizer.Token) *BoldParser {
 		ContentTokens: contentTokens,
 	}
 }
+
+func (p *BoldParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	bold := p.Match(tokens)
+	if bold == nil {
+		return nil, 0
+	}
+
+	return &ast.Bold{
+		Symbol:   tokens[0].Type,
+		Children: ParseInline(bold.ContentTokens),
+	}, len(bold.ContentTokens) + 4
+}
@@ -24,6 +24,7 @@ func TestBoldParser(t *testing.T) {
 					{
 						Type:  tokenizer.Text,
 						Value: "Hello",
+						Pos:   2,
 					},
 				},
 			},
@@ -35,14 +36,17 @@ func TestBoldParser(t *testing.T) {
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   2,
 					},
 					{
 						Type:  tokenizer.Text,
 						Value: "Hello",
+						Pos:   3,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   8,
 					},
 				},
 			},
@@ -67,14 +71,17 @@ func TestBoldParser(t *testing.T) {
 					{
 						Type:  tokenizer.Text,
 						Value: "Hello",
+						Pos:   2,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   7,
 					},
 					{
 						Type:  tokenizer.Text,
 						Value: `\n`,
+						Pos:   8,
 					},
 				},
 			},
@@ -1,6 +1,9 @@
 package parser
 
-import "github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
 
 type CodeParser struct {
 	Content string
@@ -36,3 +39,15 @@ func (*CodeParser) Match(tokens []*tokenizer.Token) *CodeParser {
 		Content: content,
 	}
 }
+
+func (p *CodeParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	code := p.Match(tokens)
+	if code == nil {
+		return nil, 0
+	}
+
+	// The content contains no backtick, so the code ends at the second one.
+	return &ast.Code{
+		Content: code.Content,
+	}, findTokenIndex(tokens, tokenizer.Backtick, 1) + 1
+}
@@ -1,6 +1,9 @@
 package parser
 
-import "github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
 
 type CodeBlockParser struct {
 	Language string
@@ -50,3 +53,21 @@ func (*CodeBlockParser) Match(tokens []*tokenizer.Token) *CodeBlockParser {
 		Content:  content,
 	}
 }
+
+func (p *CodeBlockParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	codeBlock := p.Match(tokens)
+	if codeBlock == nil {
+		return nil, 0
+	}
+
+	// Opening fence, optional language, newline, content, newline and closing fence.
+	// Re-tokenizing the content yields the same tokens it was built from.
+	size := 3 + 1 + len(tokenizer.Tokenize(codeBlock.Content)) + 1 + 3
+	if codeBlock.Language != "" {
+		size++
+	}
+	return &ast.CodeBlock{
+		Language: codeBlock.Language,
+		Content:  codeBlock.Content,
+	}, size
+}
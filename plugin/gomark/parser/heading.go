@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"github.com/usememos/memos/plugin/gomark/ast"
 	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
 )
 
@@ -51,3 +52,15 @@ func (*HeadingParser) Match(tokens []*tokenizer.Token) *HeadingParser {
 		ContentTokens: contentTokens,
 	}
 }
+
+func (p *HeadingParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	heading := p.Match(tokens)
+	if heading == nil {
+		return nil, 0
+	}
+
+	return &ast.Heading{
+		Level:    heading.Level,
+		Children: ParseInline(heading.ContentTokens),
+	}, heading.Level + 1 + len(heading.ContentTokens)
+}
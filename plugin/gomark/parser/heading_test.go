@@ -25,14 +25,17 @@ func TestHeadingParser(t *testing.T) {
 					{
 						Type:  tokenizer.Text,
 						Value: "Hello",
+						Pos:   3,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   8,
 					},
 					{
 						Type:  tokenizer.Text,
 						Value: "World",
+						Pos:   9,
 					},
 				},
 			},
@@ -45,22 +48,27 @@ func TestHeadingParser(t *testing.T) {
 					{
 						Type:  tokenizer.Hash,
 						Value: "#",
+						Pos:   2,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   3,
 					},
 					{
 						Type:  tokenizer.Text,
 						Value: "Hello",
+						Pos:   4,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   9,
 					},
 					{
 						Type:  tokenizer.Text,
 						Value: "World",
+						Pos:   10,
 					},
 				},
 			},
@@ -78,10 +86,12 @@ Hello World`,
 					{
 						Type:  tokenizer.Text,
 						Value: "123",
+						Pos:   2,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   5,
 					},
 				},
 			},
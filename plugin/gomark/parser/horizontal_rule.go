@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+type HorizontalRuleParser struct {
+	Symbol string
+}
+
+func NewHorizontalRuleParser() *HorizontalRuleParser {
+	return &HorizontalRuleParser{}
+}
+
+func (*HorizontalRuleParser) Match(tokens []*tokenizer.Token) *HorizontalRuleParser {
+	if len(tokens) < 3 {
+		return nil
+	}
+	if tokens[0].Type != tokens[1].Type || tokens[0].Type != tokens[2].Type {
+		return nil
+	}
+	symbol := tokens[0].Type
+	if symbol != tokenizer.Hyphen && symbol != tokenizer.Star && symbol != tokenizer.Underline {
+		return nil
+	}
+	if len(tokens) > 3 && tokens[3].Type != tokenizer.Newline {
+		return nil
+	}
+
+	return &HorizontalRuleParser{
+		Symbol: symbol,
+	}
+}
+
+func (p *HorizontalRuleParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	horizontalRule := p.Match(tokens)
+	if horizontalRule == nil {
+		return nil, 0
+	}
+
+	return &ast.HorizontalRule{
+		Symbol: horizontalRule.Symbol,
+	}, 3
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+func TestHorizontalRuleParser(t *testing.T) {
+	tests := []struct {
+		text           string
+		horizontalRule *HorizontalRuleParser
+	}{
+		{
+			text: "---",
+			horizontalRule: &HorizontalRuleParser{
+				Symbol: "-",
+			},
+		},
+		{
+			text: "***\nHello",
+			horizontalRule: &HorizontalRuleParser{
+				Symbol: "*",
+			},
+		},
+		{
+			text:           "--",
+			horizontalRule: nil,
+		},
+		{
+			text:           "-*-",
+			horizontalRule: nil,
+		},
+		{
+			text:           "--- Hello",
+			horizontalRule: nil,
+		},
+	}
+
+	for _, test := range tests {
+		tokens := tokenizer.Tokenize(test.text)
+		require.Equal(t, test.horizontalRule, NewHorizontalRuleParser().Match(tokens))
+	}
+}
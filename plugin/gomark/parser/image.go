@@ -1,6 +1,9 @@
 package parser
 
-import "github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
 
 type ImageParser struct {
 	AltText string
@@ -53,3 +56,17 @@ func (*ImageParser) Match(tokens []*tokenizer.Token) *ImageParser {
 		URL:     url,
 	}
 }
+
+func (p *ImageParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	image := p.Match(tokens)
+	if image == nil {
+		return nil, 0
+	}
+
+	// The alt text contains no right square bracket and the url contains no right parenthesis.
+	cursor := findTokenIndex(tokens, tokenizer.RightSquareBracket, 2)
+	return &ast.Image{
+		AltText: image.AltText,
+		URL:     image.URL,
+	}, findTokenIndex(tokens, tokenizer.RightParenthesis, cursor+2) + 1
+}
@@ -1,6 +1,9 @@
 package parser
 
-import "github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
 
 type ItalicParser struct {
 	ContentTokens []*tokenizer.Token
@@ -40,3 +43,15 @@ func (*ItalicParser) Match(tokens []*tokenizer.Token) *ItalicParser {
 		ContentTokens: contentTokens,
 	}
 }
+
+func (p *ItalicParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	italic := p.Match(tokens)
+	if italic == nil {
+		return nil, 0
+	}
+
+	return &ast.Italic{
+		Symbol:   tokens[0].Type,
+		Children: ParseInline(italic.ContentTokens),
+	}, len(italic.ContentTokens) + 2
+}
@@ -24,6 +24,7 @@ func TestItalicParser(t *testing.T) {
 					{
 						Type:  tokenizer.Text,
 						Value: "Hello",
+						Pos:   1,
 					},
 				},
 			},
@@ -35,14 +36,17 @@ func TestItalicParser(t *testing.T) {
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   1,
 					},
 					{
 						Type:  tokenizer.Text,
 						Value: "Hello",
+						Pos:   2,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   7,
 					},
 				},
 			},
@@ -58,6 +62,7 @@ func TestItalicParser(t *testing.T) {
 					{
 						Type:  tokenizer.Text,
 						Value: "1",
+						Pos:   1,
 					},
 				},
 			},
@@ -69,14 +74,17 @@ func TestItalicParser(t *testing.T) {
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   1,
 					},
 					{
 						Type:  tokenizer.Text,
 						Value: `\n`,
+						Pos:   2,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   4,
 					},
 				},
 			},
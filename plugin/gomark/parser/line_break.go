@@ -0,0 +1,30 @@
+package parser
+
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+type LineBreakParser struct{}
+
+func NewLineBreakParser() *LineBreakParser {
+	return &LineBreakParser{}
+}
+
+func (*LineBreakParser) Match(tokens []*tokenizer.Token) *LineBreakParser {
+	if len(tokens) == 0 {
+		return nil
+	}
+	if tokens[0].Type != tokenizer.Newline {
+		return nil
+	}
+	return &LineBreakParser{}
+}
+
+func (p *LineBreakParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	if p.Match(tokens) == nil {
+		return nil, 0
+	}
+
+	return &ast.LineBreak{}, 1
+}
@@ -1,6 +1,9 @@
 package parser
 
-import "github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
 
 type LinkParser struct {
 	ContentTokens []*tokenizer.Token
@@ -49,6 +52,7 @@ func (*LinkParser) Match(tokens []*tokenizer.Token) *LinkParser {
 		contentTokens = append(contentTokens, &tokenizer.Token{
 			Type:  tokenizer.Text,
 			Value: url,
+			Pos:   tokens[cursor+2].Pos,
 		})
 	}
 	return &LinkParser{
@@ -56,3 +60,17 @@ func (*LinkParser) Match(tokens []*tokenizer.Token) *LinkParser {
 		URL:           url,
 	}
 }
+
+func (p *LinkParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	link := p.Match(tokens)
+	if link == nil {
+		return nil, 0
+	}
+
+	// The text contains no right square bracket and the url contains no right parenthesis.
+	cursor := findTokenIndex(tokens, tokenizer.RightSquareBracket, 1)
+	return &ast.Link{
+		Text: stringifyTokens(link.ContentTokens),
+		URL:  link.URL,
+	}, findTokenIndex(tokens, tokenizer.RightParenthesis, cursor+2) + 1
+}
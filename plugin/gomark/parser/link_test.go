@@ -20,6 +20,7 @@ func TestLinkParser(t *testing.T) {
 					{
 						Type:  tokenizer.Text,
 						Value: "https://example.com",
+						Pos:   3,
 					},
 				},
 				URL: "https://example.com",
@@ -40,14 +41,17 @@ func TestLinkParser(t *testing.T) {
 					{
 						Type:  tokenizer.Text,
 						Value: "hello",
+						Pos:   1,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   6,
 					},
 					{
 						Type:  tokenizer.Text,
 						Value: "world",
+						Pos:   7,
 					},
 				},
 				URL: "https://example.com",
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+type OrderedListParser struct {
+	Indent        int
+	Number        string
+	ContentTokens []*tokenizer.Token
+}
+
+func NewOrderedListParser() *OrderedListParser {
+	return &OrderedListParser{}
+}
+
+func (*OrderedListParser) Match(tokens []*tokenizer.Token) *OrderedListParser {
+	indent := countIndent(tokens)
+	if len(tokens) < indent+3 {
+		return nil
+	}
+	// The dot isn't a special character, so "1." is a single text token.
+	if tokens[indent].Type != tokenizer.Text || !strings.HasSuffix(tokens[indent].Value, ".") {
+		return nil
+	}
+	number := strings.TrimSuffix(tokens[indent].Value, ".")
+	if number == "" || strings.IndexFunc(number, func(r rune) bool { return !unicode.IsDigit(r) }) != -1 {
+		return nil
+	}
+	if tokens[indent+1].Type != tokenizer.Space {
+		return nil
+	}
+
+	contentTokens := []*tokenizer.Token{}
+	for _, token := range tokens[indent+2:] {
+		if token.Type == tokenizer.Newline {
+			break
+		}
+		contentTokens = append(contentTokens, token)
+	}
+	if len(contentTokens) == 0 {
+		return nil
+	}
+
+	return &OrderedListParser{
+		Indent:        indent,
+		Number:        number,
+		ContentTokens: contentTokens,
+	}
+}
+
+func (p *OrderedListParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	orderedList := p.Match(tokens)
+	if orderedList == nil {
+		return nil, 0
+	}
+
+	return &ast.OrderedList{
+		Indent:   orderedList.Indent,
+		Number:   orderedList.Number,
+		Children: ParseInline(orderedList.ContentTokens),
+	}, orderedList.Indent + 2 + len(orderedList.ContentTokens)
+}
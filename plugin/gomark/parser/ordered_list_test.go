@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+func TestOrderedListParser(t *testing.T) {
+	tests := []struct {
+		text        string
+		orderedList *OrderedListParser
+	}{
+		{
+			text:        "1.Hello",
+			orderedList: nil,
+		},
+		{
+			text:        "a. Hello",
+			orderedList: nil,
+		},
+		{
+			text: "12. Hello world",
+			orderedList: &OrderedListParser{
+				Number: "12",
+				ContentTokens: []*tokenizer.Token{
+					{
+						Type:  tokenizer.Text,
+						Value: "Hello",
+						Pos:   4,
+					},
+					{
+						Type:  tokenizer.Space,
+						Value: " ",
+						Pos:   9,
+					},
+					{
+						Type:  tokenizer.Text,
+						Value: "world",
+						Pos:   10,
+					},
+				},
+			},
+		},
+		{
+			text: "    1. Hello",
+			orderedList: &OrderedListParser{
+				Indent: 4,
+				Number: "1",
+				ContentTokens: []*tokenizer.Token{
+					{
+						Type:  tokenizer.Text,
+						Value: "Hello",
+						Pos:   7,
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tokens := tokenizer.Tokenize(test.text)
+		require.Equal(t, test.orderedList, NewOrderedListParser().Match(tokens))
+	}
+}
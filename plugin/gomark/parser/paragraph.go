@@ -1,6 +1,9 @@
 package parser
 
-import "github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
 
 type ParagraphParser struct {
 	ContentTokens []*tokenizer.Token
@@ -28,3 +31,14 @@ func (*ParagraphParser) Match(tokens []*tokenizer.Token) *ParagraphParser {
 		ContentTokens: contentTokens,
 	}
 }
+
+func (p *ParagraphParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	paragraph := p.Match(tokens)
+	if paragraph == nil {
+		return nil, 0
+	}
+
+	return &ast.Paragraph{
+		Children: ParseInline(paragraph.ContentTokens),
+	}, len(paragraph.ContentTokens)
+}
@@ -24,14 +24,17 @@ func TestParagraphParser(t *testing.T) {
 					{
 						Type:  tokenizer.Text,
 						Value: "Hello",
+						Pos:   0,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   5,
 					},
 					{
 						Type:  tokenizer.Text,
 						Value: "world",
+						Pos:   6,
 					},
 				},
 			},
@@ -44,10 +47,12 @@ world`,
 					{
 						Type:  tokenizer.Text,
 						Value: "Hello",
+						Pos:   0,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   5,
 					},
 				},
 			},
@@ -60,18 +65,22 @@ world`,
 					{
 						Type:  tokenizer.Text,
 						Value: "Hello",
+						Pos:   0,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   5,
 					},
 					{
 						Type:  tokenizer.Text,
 						Value: `\n`,
+						Pos:   6,
 					},
 					{
 						Type:  tokenizer.Space,
 						Value: " ",
+						Pos:   8,
 					},
 				},
 			},
@@ -1 +1,111 @@
 package parser
+
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+// NodeParser parses the leading tokens into a node.
+// It returns a nil node if the tokens don't match, otherwise the node and the number of consumed tokens.
+type NodeParser interface {
+	Parse(tokens []*tokenizer.Token) (ast.Node, int)
+}
+
+// The order matters: the first matched parser wins.
+var defaultBlockParsers = []NodeParser{
+	NewCodeBlockParser(),
+	NewTableParser(),
+	NewHorizontalRuleParser(),
+	NewHeadingParser(),
+	NewBlockquoteParser(),
+	NewTaskListParser(),
+	NewUnorderedListParser(),
+	NewOrderedListParser(),
+	NewLineBreakParser(),
+	NewParagraphParser(),
+}
+
+// The order matters: the first matched parser wins.
+var defaultInlineParsers = []NodeParser{
+	NewCodeParser(),
+	NewImageParser(),
+	NewLinkParser(),
+	NewBoldParser(),
+	NewItalicParser(),
+	NewStrikethroughParser(),
+	NewTagParser(),
+	NewTextParser(),
+}
+
+// Parse parses the tokens into a document.
+func Parse(tokens []*tokenizer.Token) *ast.Document {
+	document := ast.NewDocument()
+	for _, node := range parseNodes(tokens, defaultBlockParsers) {
+		document.AddNode(node)
+	}
+	return document
+}
+
+// ParseInline parses the tokens into inline nodes.
+func ParseInline(tokens []*tokenizer.Token) []ast.Node {
+	return parseNodes(tokens, defaultInlineParsers)
+}
+
+func parseNodes(tokens []*tokenizer.Token, parsers []NodeParser) []ast.Node {
+	nodes := []ast.Node{}
+	for len(tokens) > 0 {
+		matched := false
+		for _, parser := range parsers {
+			node, size := parser.Parse(tokens)
+			if node == nil || size == 0 {
+				continue
+			}
+			// Merge adjacent text nodes.
+			if text, ok := node.(*ast.Text); ok && len(nodes) > 0 {
+				if prevText, ok := nodes[len(nodes)-1].(*ast.Text); ok {
+					prevText.Content += text.Content
+					tokens, matched = tokens[size:], true
+					break
+				}
+			}
+			nodes = append(nodes, node)
+			tokens, matched = tokens[size:], true
+			break
+		}
+		// Skip the token which no parser can handle.
+		if !matched {
+			tokens = tokens[1:]
+		}
+	}
+	return nodes
+}
+
+// findTokenIndex returns the index of the first token with the given type at or after start, or -1 if not found.
+func findTokenIndex(tokens []*tokenizer.Token, tokenType tokenizer.TokenType, start int) int {
+	for i := start; i < len(tokens); i++ {
+		if tokens[i].Type == tokenType {
+			return i
+		}
+	}
+	return -1
+}
+
+// countIndent returns the number of leading space tokens.
+func countIndent(tokens []*tokenizer.Token) int {
+	indent := 0
+	for _, token := range tokens {
+		if token.Type != tokenizer.Space {
+			break
+		}
+		indent++
+	}
+	return indent
+}
+
+func stringifyTokens(tokens []*tokenizer.Token) string {
+	text := ""
+	for _, token := range tokens {
+		text += token.Value
+	}
+	return text
+}
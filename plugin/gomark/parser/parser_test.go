@@ -0,0 +1,208 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+func TestParser(t *testing.T) {
+	tests := []struct {
+		text  string
+		nodes []ast.Node
+	}{
+		{
+			text:  "",
+			nodes: nil,
+		},
+		{
+			text: "Hello world!",
+			nodes: []ast.Node{
+				&ast.Paragraph{
+					Children: []ast.Node{
+						&ast.Text{
+							Content: "Hello world!",
+						},
+					},
+				},
+			},
+		},
+		{
+			text: "**Hello** world!",
+			nodes: []ast.Node{
+				&ast.Paragraph{
+					Children: []ast.Node{
+						&ast.Bold{
+							Symbol: "*",
+							Children: []ast.Node{
+								&ast.Text{
+									Content: "Hello",
+								},
+							},
+						},
+						&ast.Text{
+							Content: " world!",
+						},
+					},
+				},
+			},
+		},
+		{
+			text: "# Hello _world_\nHello #tag `code`",
+			nodes: []ast.Node{
+				&ast.Heading{
+					Level: 1,
+					Children: []ast.Node{
+						&ast.Text{
+							Content: "Hello ",
+						},
+						&ast.Italic{
+							Symbol: "_",
+							Children: []ast.Node{
+								&ast.Text{
+									Content: "world",
+								},
+							},
+						},
+					},
+				},
+				&ast.LineBreak{},
+				&ast.Paragraph{
+					Children: []ast.Node{
+						&ast.Text{
+							Content: "Hello ",
+						},
+						&ast.Tag{
+							Content: "tag",
+						},
+						&ast.Text{
+							Content: " ",
+						},
+						&ast.Code{
+							Content: "code",
+						},
+					},
+				},
+			},
+		},
+		{
+			text: "![alt](https://example.com/a.png) [link](https://example.com)",
+			nodes: []ast.Node{
+				&ast.Paragraph{
+					Children: []ast.Node{
+						&ast.Image{
+							AltText: "alt",
+							URL:     "https://example.com/a.png",
+						},
+						&ast.Text{
+							Content: " ",
+						},
+						&ast.Link{
+							Text: "link",
+							URL:  "https://example.com",
+						},
+					},
+				},
+			},
+		},
+		{
+			text: "```go\nfmt.Println(\"#hello\")\n```\n\nEnd",
+			nodes: []ast.Node{
+				&ast.CodeBlock{
+					Language: "go",
+					Content:  "fmt.Println(\"#hello\")",
+				},
+				&ast.LineBreak{},
+				&ast.LineBreak{},
+				&ast.Paragraph{
+					Children: []ast.Node{
+						&ast.Text{
+							Content: "End",
+						},
+					},
+				},
+			},
+		},
+		{
+			text: "- [ ] todo #work\n1. ~~one~~\n> quote\n---\n| a |\n| - |\n| 1 |",
+			nodes: []ast.Node{
+				&ast.TaskList{
+					Symbol:      "-",
+					Complete:    false,
+					CheckboxPos: 3,
+					Children: []ast.Node{
+						&ast.Text{
+							Content: "todo ",
+						},
+						&ast.Tag{
+							Content: "work",
+						},
+					},
+				},
+				&ast.LineBreak{},
+				&ast.OrderedList{
+					Number: "1",
+					Children: []ast.Node{
+						&ast.Strikethrough{
+							Children: []ast.Node{
+								&ast.Text{
+									Content: "one",
+								},
+							},
+						},
+					},
+				},
+				&ast.LineBreak{},
+				&ast.Blockquote{
+					Children: []ast.Node{
+						&ast.Text{
+							Content: "quote",
+						},
+					},
+				},
+				&ast.LineBreak{},
+				&ast.HorizontalRule{
+					Symbol: "-",
+				},
+				&ast.LineBreak{},
+				&ast.Table{
+					Header:    []string{"a"},
+					Delimiter: []string{"-"},
+					Rows:      [][]string{{"1"}},
+					Children: []ast.Node{
+						&ast.Text{Content: "a"},
+						&ast.Text{Content: "1"},
+					},
+				},
+			},
+		},
+		{
+			text: "> - [x] quoted",
+			nodes: []ast.Node{
+				&ast.Blockquote{
+					Children: []ast.Node{
+						&ast.TaskList{
+							Symbol:      "-",
+							Complete:    true,
+							CheckboxPos: 5,
+							Children: []ast.Node{
+								&ast.Text{
+									Content: "quoted",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tokens := tokenizer.Tokenize(test.text)
+		document := Parse(tokens)
+		require.Equal(t, test.nodes, document.Nodes, test.text)
+	}
+}
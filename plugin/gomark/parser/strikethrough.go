@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+type StrikethroughParser struct {
+	ContentTokens []*tokenizer.Token
+}
+
+func NewStrikethroughParser() *StrikethroughParser {
+	return &StrikethroughParser{}
+}
+
+func (*StrikethroughParser) Match(tokens []*tokenizer.Token) *StrikethroughParser {
+	if len(tokens) < 5 {
+		return nil
+	}
+	if tokens[0].Type != tokenizer.Tilde || tokens[1].Type != tokenizer.Tilde {
+		return nil
+	}
+
+	contentTokens := []*tokenizer.Token{}
+	cursor, matched := 2, false
+	for ; cursor < len(tokens)-1; cursor++ {
+		token, nextToken := tokens[cursor], tokens[cursor+1]
+		if token.Type == tokenizer.Newline || nextToken.Type == tokenizer.Newline {
+			return nil
+		}
+		if token.Type == tokenizer.Tilde && nextToken.Type == tokenizer.Tilde {
+			matched = true
+			break
+		}
+		contentTokens = append(contentTokens, token)
+	}
+	if !matched || len(contentTokens) == 0 {
+		return nil
+	}
+
+	return &StrikethroughParser{
+		ContentTokens: contentTokens,
+	}
+}
+
+func (p *StrikethroughParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	strikethrough := p.Match(tokens)
+	if strikethrough == nil {
+		return nil, 0
+	}
+
+	return &ast.Strikethrough{
+		Children: ParseInline(strikethrough.ContentTokens),
+	}, len(strikethrough.ContentTokens) + 4
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+func TestStrikethroughParser(t *testing.T) {
+	tests := []struct {
+		text          string
+		strikethrough *StrikethroughParser
+	}{
+		{
+			text:          "~~Hello~",
+			strikethrough: nil,
+		},
+		{
+			text:          "~~~~",
+			strikethrough: nil,
+		},
+		{
+			text: "~~Hello world~~",
+			strikethrough: &StrikethroughParser{
+				ContentTokens: []*tokenizer.Token{
+					{
+						Type:  tokenizer.Text,
+						Value: "Hello",
+						Pos:   2,
+					},
+					{
+						Type:  tokenizer.Space,
+						Value: " ",
+						Pos:   7,
+					},
+					{
+						Type:  tokenizer.Text,
+						Value: "world",
+						Pos:   8,
+					},
+				},
+			},
+		},
+		{
+			text:          "~~Hello\nworld~~",
+			strikethrough: nil,
+		},
+	}
+
+	for _, test := range tests {
+		tokens := tokenizer.Tokenize(test.text)
+		require.Equal(t, test.strikethrough, NewStrikethroughParser().Match(tokens))
+	}
+}
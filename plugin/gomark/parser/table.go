@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+type TableParser struct {
+	Header    []string
+	Delimiter []string
+	Rows      [][]string
+}
+
+func NewTableParser() *TableParser {
+	return &TableParser{}
+}
+
+func (p *TableParser) Match(tokens []*tokenizer.Token) *TableParser {
+	table, _ := p.match(tokens)
+	return table
+}
+
+func (p *TableParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	table, size := p.match(tokens)
+	if table == nil {
+		return nil, 0
+	}
+
+	children := []ast.Node{}
+	for _, row := range append([][]string{table.Header}, table.Rows...) {
+		for _, cell := range row {
+			children = append(children, ParseInline(tokenizer.Tokenize(cell))...)
+		}
+	}
+	return &ast.Table{
+		Header:    table.Header,
+		Delimiter: table.Delimiter,
+		Rows:      table.Rows,
+		Children:  children,
+	}, size
+}
+
+// match returns the matched table and the number of consumed tokens.
+func (*TableParser) match(tokens []*tokenizer.Token) (*TableParser, int) {
+	header, cursor := matchTableRow(tokens)
+	if header == nil || cursor >= len(tokens) || tokens[cursor].Type != tokenizer.Newline {
+		return nil, 0
+	}
+	delimiter, size := matchTableRow(tokens[cursor+1:])
+	if len(delimiter) != len(header) {
+		return nil, 0
+	}
+	for _, cell := range delimiter {
+		if !isTableDelimiterCell(cell) {
+			return nil, 0
+		}
+	}
+	cursor += 1 + size
+
+	rows := [][]string{}
+	for cursor < len(tokens) && tokens[cursor].Type == tokenizer.Newline {
+		row, size := matchTableRow(tokens[cursor+1:])
+		if len(row) != len(header) {
+			break
+		}
+		rows = append(rows, row)
+		cursor += 1 + size
+	}
+
+	return &TableParser{
+		Header:    header,
+		Delimiter: delimiter,
+		Rows:      rows,
+	}, cursor
+}
+
+// matchTableRow matches a line like "| a | b |" and returns the trimmed cells and the number of consumed tokens.
+func matchTableRow(tokens []*tokenizer.Token) ([]string, int) {
+	if len(tokens) < 2 || tokens[0].Type != tokenizer.Pipe {
+		return nil, 0
+	}
+	size := findTokenIndex(tokens, tokenizer.Newline, 0)
+	if size == -1 {
+		size = len(tokens)
+	}
+	if size < 2 || tokens[size-1].Type != tokenizer.Pipe {
+		return nil, 0
+	}
+
+	cells, cellTokens := []string{}, []*tokenizer.Token{}
+	for _, token := range tokens[1:size] {
+		if token.Type == tokenizer.Pipe {
+			cells = append(cells, strings.TrimSpace(stringifyTokens(cellTokens)))
+			cellTokens = []*tokenizer.Token{}
+			continue
+		}
+		cellTokens = append(cellTokens, token)
+	}
+	return cells, size
+}
+
+// isTableDelimiterCell returns true if the cell is like "---", ":---", "---:" or ":---:".
+func isTableDelimiterCell(cell string) bool {
+	cell = strings.TrimSuffix(strings.TrimPrefix(cell, ":"), ":")
+	return cell != "" && strings.Trim(cell, "-") == ""
+}
@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+func TestTableParser(t *testing.T) {
+	tests := []struct {
+		text  string
+		table *TableParser
+	}{
+		{
+			text:  "| a | b |",
+			table: nil,
+		},
+		{
+			text:  "| a | b |\n| --- |",
+			table: nil,
+		},
+		{
+			text:  "| a | b |\n| --- | x |",
+			table: nil,
+		},
+		{
+			text: "| a | b |\n| --- | :---: |",
+			table: &TableParser{
+				Header:    []string{"a", "b"},
+				Delimiter: []string{"---", ":---:"},
+				Rows:      [][]string{},
+			},
+		},
+		{
+			text: "| a | b |\n| --- | --- |\n| 1 | 2 |\n|3|4|\n| 5 |\nHello",
+			table: &TableParser{
+				Header:    []string{"a", "b"},
+				Delimiter: []string{"---", "---"},
+				Rows: [][]string{
+					{"1", "2"},
+					{"3", "4"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tokens := tokenizer.Tokenize(test.text)
+		require.Equal(t, test.table, NewTableParser().Match(tokens))
+	}
+}
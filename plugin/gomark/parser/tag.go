@@ -1,6 +1,9 @@
 package parser
 
-import "github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
 
 type TagParser struct {
 	ContentTokens []*tokenizer.Token
@@ -19,7 +22,7 @@ func (*TagParser) Match(tokens []*tokenizer.Token) *TagParser {
 	}
 	contentTokens := []*tokenizer.Token{}
 	for _, token := range tokens[1:] {
-		if token.Type == tokenizer.Newline || token.Type == tokenizer.Space || token.Type == tokenizer.Hash {
+		if token.Type == tokenizer.Newline || token.Type == tokenizer.Space || token.Type == tokenizer.Hash || tokenizer.IsDelimiter(token) {
 			break
 		}
 		contentTokens = append(contentTokens, token)
@@ -32,3 +35,14 @@ func (*TagParser) Match(tokens []*tokenizer.Token) *TagParser {
 		ContentTokens: contentTokens,
 	}
 }
+
+func (p *TagParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	tag := p.Match(tokens)
+	if tag == nil {
+		return nil, 0
+	}
+
+	return &ast.Tag{
+		Content: stringifyTokens(tag.ContentTokens),
+	}, len(tag.ContentTokens) + 1
+}
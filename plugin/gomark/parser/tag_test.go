@@ -28,6 +28,7 @@ func TestTagParser(t *testing.T) {
 					{
 						Type:  tokenizer.Text,
 						Value: "tag",
+						Pos:   1,
 					},
 				},
 			},
@@ -39,6 +40,43 @@ func TestTagParser(t *testing.T) {
 					{
 						Type:  tokenizer.Text,
 						Value: "tag/subtag",
+						Pos:   1,
+					},
+				},
+			},
+		},
+		{
+			text: "#tag1,tag2",
+			tag: &TagParser{
+				ContentTokens: []*tokenizer.Token{
+					{
+						Type:  tokenizer.Text,
+						Value: "tag1",
+						Pos:   1,
+					},
+				},
+			},
+		},
+		{
+			text: "#tag\tsuffix",
+			tag: &TagParser{
+				ContentTokens: []*tokenizer.Token{
+					{
+						Type:  tokenizer.Text,
+						Value: "tag",
+						Pos:   1,
+					},
+				},
+			},
+		},
+		{
+			text: "#tag\u3000suffix",
+			tag: &TagParser{
+				ContentTokens: []*tokenizer.Token{
+					{
+						Type:  tokenizer.Text,
+						Value: "tag",
+						Pos:   1,
 					},
 				},
 			},
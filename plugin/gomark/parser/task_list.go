@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+type TaskListParser struct {
+	Indent        int
+	Symbol        string
+	Complete      bool
+	CheckboxPos   int
+	ContentTokens []*tokenizer.Token
+}
+
+func NewTaskListParser() *TaskListParser {
+	return &TaskListParser{}
+}
+
+func (*TaskListParser) Match(tokens []*tokenizer.Token) *TaskListParser {
+	indent := countIndent(tokens)
+	if len(tokens) < indent+7 {
+		return nil
+	}
+	symbol := tokens[indent].Type
+	if symbol != tokenizer.Hyphen && symbol != tokenizer.Star && symbol != tokenizer.PlusSign {
+		return nil
+	}
+	if tokens[indent+1].Type != tokenizer.Space {
+		return nil
+	}
+	if tokens[indent+2].Type != tokenizer.LeftSquareBracket || tokens[indent+4].Type != tokenizer.RightSquareBracket {
+		return nil
+	}
+	complete := false
+	if checkbox := tokens[indent+3]; checkbox.Type == tokenizer.Text && (checkbox.Value == "x" || checkbox.Value == "X") {
+		complete = true
+	} else if checkbox.Type != tokenizer.Space {
+		return nil
+	}
+	if tokens[indent+5].Type != tokenizer.Space {
+		return nil
+	}
+
+	contentTokens := []*tokenizer.Token{}
+	for _, token := range tokens[indent+6:] {
+		if token.Type == tokenizer.Newline {
+			break
+		}
+		contentTokens = append(contentTokens, token)
+	}
+	if len(contentTokens) == 0 {
+		return nil
+	}
+
+	return &TaskListParser{
+		Indent:        indent,
+		Symbol:        symbol,
+		Complete:      complete,
+		CheckboxPos:   tokens[indent+3].Pos,
+		ContentTokens: contentTokens,
+	}
+}
+
+func (p *TaskListParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	taskList := p.Match(tokens)
+	if taskList == nil {
+		return nil, 0
+	}
+
+	return &ast.TaskList{
+		Indent:      taskList.Indent,
+		Symbol:      taskList.Symbol,
+		Complete:    taskList.Complete,
+		CheckboxPos: taskList.CheckboxPos,
+		Children:    ParseInline(taskList.ContentTokens),
+	}, taskList.Indent + 6 + len(taskList.ContentTokens)
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+func TestTaskListParser(t *testing.T) {
+	tests := []struct {
+		text     string
+		taskList *TaskListParser
+	}{
+		{
+			text:     "- [] Hello",
+			taskList: nil,
+		},
+		{
+			text:     "- [y] Hello",
+			taskList: nil,
+		},
+		{
+			text: "- [ ] Hello",
+			taskList: &TaskListParser{
+				Symbol:      tokenizer.Hyphen,
+				Complete:    false,
+				CheckboxPos: 3,
+				ContentTokens: []*tokenizer.Token{
+					{
+						Type:  tokenizer.Text,
+						Value: "Hello",
+						Pos:   6,
+					},
+				},
+			},
+		},
+		{
+			text: "  * [x] Hello\n",
+			taskList: &TaskListParser{
+				Indent:      2,
+				Symbol:      tokenizer.Star,
+				Complete:    true,
+				CheckboxPos: 5,
+				ContentTokens: []*tokenizer.Token{
+					{
+						Type:  tokenizer.Text,
+						Value: "Hello",
+						Pos:   8,
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tokens := tokenizer.Tokenize(test.text)
+		require.Equal(t, test.taskList, NewTaskListParser().Match(tokens))
+	}
+}
@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+type TextParser struct {
+	Content string
+}
+
+func NewTextParser() *TextParser {
+	return &TextParser{}
+}
+
+func (*TextParser) Match(tokens []*tokenizer.Token) *TextParser {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return &TextParser{
+		Content: tokens[0].Value,
+	}
+}
+
+func (p *TextParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	text := p.Match(tokens)
+	if text == nil {
+		return nil, 0
+	}
+
+	return &ast.Text{
+		Content: text.Content,
+	}, 1
+}
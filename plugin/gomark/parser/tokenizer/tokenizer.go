@@ -1,5 +1,7 @@
 package tokenizer
 
+import "unicode"
+
 type TokenType = string
 
 const (
@@ -12,6 +14,11 @@ const (
 	LeftParenthesis    TokenType = "("
 	RightParenthesis   TokenType = ")"
 	ExclamationMark    TokenType = "!"
+	Tilde              TokenType = "~"
+	Hyphen             TokenType = "-"
+	PlusSign           TokenType = "+"
+	GreaterThan        TokenType = ">"
+	Pipe               TokenType = "|"
 	Newline            TokenType = "\n"
 	Space              TokenType = " "
 )
@@ -23,48 +30,62 @@ const (
 type Token struct {
 	Type  TokenType
 	Value string
+	// Pos is the byte offset of the token in the tokenized text.
+	Pos int
 }
 
-func NewToken(tp, text string) *Token {
+func NewToken(tp, text string, pos int) *Token {
 	return &Token{
 		Type:  tp,
 		Value: text,
+		Pos:   pos,
 	}
 }
 
 func Tokenize(text string) []*Token {
 	tokens := []*Token{}
-	for _, c := range text {
+	for i, c := range text {
 		switch c {
 		case '_':
-			tokens = append(tokens, NewToken(Underline, "_"))
+			tokens = append(tokens, NewToken(Underline, "_", i))
 		case '*':
-			tokens = append(tokens, NewToken(Star, "*"))
+			tokens = append(tokens, NewToken(Star, "*", i))
 		case '#':
-			tokens = append(tokens, NewToken(Hash, "#"))
+			tokens = append(tokens, NewToken(Hash, "#", i))
 		case '`':
-			tokens = append(tokens, NewToken(Backtick, "`"))
+			tokens = append(tokens, NewToken(Backtick, "`", i))
 		case '[':
-			tokens = append(tokens, NewToken(LeftSquareBracket, "["))
+			tokens = append(tokens, NewToken(LeftSquareBracket, "[", i))
 		case ']':
-			tokens = append(tokens, NewToken(RightSquareBracket, "]"))
+			tokens = append(tokens, NewToken(RightSquareBracket, "]", i))
 		case '(':
-			tokens = append(tokens, NewToken(LeftParenthesis, "("))
+			tokens = append(tokens, NewToken(LeftParenthesis, "(", i))
 		case ')':
-			tokens = append(tokens, NewToken(RightParenthesis, ")"))
+			tokens = append(tokens, NewToken(RightParenthesis, ")", i))
 		case '!':
-			tokens = append(tokens, NewToken(ExclamationMark, "!"))
+			tokens = append(tokens, NewToken(ExclamationMark, "!", i))
+		case '~':
+			tokens = append(tokens, NewToken(Tilde, "~", i))
+		case '-':
+			tokens = append(tokens, NewToken(Hyphen, "-", i))
+		case '+':
+			tokens = append(tokens, NewToken(PlusSign, "+", i))
+		case '>':
+			tokens = append(tokens, NewToken(GreaterThan, ">", i))
+		case '|':
+			tokens = append(tokens, NewToken(Pipe, "|", i))
 		case '\n':
-			tokens = append(tokens, NewToken(Newline, "\n"))
+			tokens = append(tokens, NewToken(Newline, "\n", i))
 		case ' ':
-			tokens = append(tokens, NewToken(Space, " "))
+			tokens = append(tokens, NewToken(Space, " ", i))
 		default:
 			var lastToken *Token
 			if len(tokens) > 0 {
 				lastToken = tokens[len(tokens)-1]
 			}
-			if lastToken == nil || lastToken.Type != Text {
-				tokens = append(tokens, NewToken(Text, string(c)))
+			// The delimiters are tokenized alone, so the words like the tags end at them.
+			if lastToken == nil || lastToken.Type != Text || IsDelimiter(lastToken) || isDelimiterRune(c) {
+				tokens = append(tokens, NewToken(Text, string(c), i))
 			} else {
 				lastToken.Value += string(c)
 			}
@@ -72,3 +93,16 @@ func Tokenize(text string) []*Token {
 	}
 	return tokens
 }
+
+// IsDelimiter returns true if the token is a text of a comma or a whitespace other than the space and the newline, e.g. a tab.
+func IsDelimiter(token *Token) bool {
+	if token.Type != Text {
+		return false
+	}
+	runes := []rune(token.Value)
+	return len(runes) == 1 && isDelimiterRune(runes[0])
+}
+
+func isDelimiterRune(c rune) bool {
+	return c == ',' || unicode.IsSpace(c)
+}
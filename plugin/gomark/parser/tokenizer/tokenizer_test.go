@@ -17,22 +17,27 @@ func TestTokenize(t *testing.T) {
 				{
 					Type:  Star,
 					Value: "*",
+					Pos:   0,
 				},
 				{
 					Type:  Text,
 					Value: "Hello",
+					Pos:   1,
 				},
 				{
 					Type:  Space,
 					Value: " ",
+					Pos:   6,
 				},
 				{
 					Type:  Text,
 					Value: "world",
+					Pos:   7,
 				},
 				{
 					Type:  ExclamationMark,
 					Value: "!",
+					Pos:   12,
 				},
 			},
 		},
@@ -43,30 +48,137 @@ func TestTokenize(t *testing.T) {
 				{
 					Type:  Hash,
 					Value: "#",
+					Pos:   0,
 				},
 				{
 					Type:  Space,
 					Value: " ",
+					Pos:   1,
 				},
 				{
 					Type:  Text,
 					Value: "hello",
+					Pos:   2,
 				},
 				{
 					Type:  Space,
 					Value: " ",
+					Pos:   7,
 				},
 				{
 					Type:  Newline,
 					Value: "\n",
+					Pos:   8,
 				},
 				{
 					Type:  Space,
 					Value: " ",
+					Pos:   9,
 				},
 				{
 					Type:  Text,
 					Value: "world",
+					Pos:   10,
+				},
+			},
+		},
+		{
+			text: "> - ~~a~~ | +",
+			tokens: []*Token{
+				{
+					Type:  GreaterThan,
+					Value: ">",
+					Pos:   0,
+				},
+				{
+					Type:  Space,
+					Value: " ",
+					Pos:   1,
+				},
+				{
+					Type:  Hyphen,
+					Value: "-",
+					Pos:   2,
+				},
+				{
+					Type:  Space,
+					Value: " ",
+					Pos:   3,
+				},
+				{
+					Type:  Tilde,
+					Value: "~",
+					Pos:   4,
+				},
+				{
+					Type:  Tilde,
+					Value: "~",
+					Pos:   5,
+				},
+				{
+					Type:  Text,
+					Value: "a",
+					Pos:   6,
+				},
+				{
+					Type:  Tilde,
+					Value: "~",
+					Pos:   7,
+				},
+				{
+					Type:  Tilde,
+					Value: "~",
+					Pos:   8,
+				},
+				{
+					Type:  Space,
+					Value: " ",
+					Pos:   9,
+				},
+				{
+					Type:  Pipe,
+					Value: "|",
+					Pos:   10,
+				},
+				{
+					Type:  Space,
+					Value: " ",
+					Pos:   11,
+				},
+				{
+					Type:  PlusSign,
+					Value: "+",
+					Pos:   12,
+				},
+			},
+		},
+		{
+			text: "a,b\tc",
+			tokens: []*Token{
+				{
+					Type:  Text,
+					Value: "a",
+					Pos:   0,
+				},
+				{
+					Type:  Text,
+					Value: ",",
+					Pos:   1,
+				},
+				{
+					Type:  Text,
+					Value: "b",
+					Pos:   2,
+				},
+				{
+					Type:  Text,
+					Value: "\t",
+					Pos:   3,
+				},
+				{
+					Type:  Text,
+					Value: "c",
+					Pos:   4,
 				},
 			},
 		},
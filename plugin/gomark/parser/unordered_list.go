@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+type UnorderedListParser struct {
+	Indent        int
+	Symbol        string
+	ContentTokens []*tokenizer.Token
+}
+
+func NewUnorderedListParser() *UnorderedListParser {
+	return &UnorderedListParser{}
+}
+
+func (*UnorderedListParser) Match(tokens []*tokenizer.Token) *UnorderedListParser {
+	indent := countIndent(tokens)
+	if len(tokens) < indent+3 {
+		return nil
+	}
+	symbol := tokens[indent].Type
+	if symbol != tokenizer.Hyphen && symbol != tokenizer.Star && symbol != tokenizer.PlusSign {
+		return nil
+	}
+	if tokens[indent+1].Type != tokenizer.Space {
+		return nil
+	}
+
+	contentTokens := []*tokenizer.Token{}
+	for _, token := range tokens[indent+2:] {
+		if token.Type == tokenizer.Newline {
+			break
+		}
+		contentTokens = append(contentTokens, token)
+	}
+	if len(contentTokens) == 0 {
+		return nil
+	}
+
+	return &UnorderedListParser{
+		Indent:        indent,
+		Symbol:        symbol,
+		ContentTokens: contentTokens,
+	}
+}
+
+func (p *UnorderedListParser) Parse(tokens []*tokenizer.Token) (ast.Node, int) {
+	unorderedList := p.Match(tokens)
+	if unorderedList == nil {
+		return nil, 0
+	}
+
+	return &ast.UnorderedList{
+		Indent:   unorderedList.Indent,
+		Symbol:   unorderedList.Symbol,
+		Children: ParseInline(unorderedList.ContentTokens),
+	}, unorderedList.Indent + 2 + len(unorderedList.ContentTokens)
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+func TestUnorderedListParser(t *testing.T) {
+	tests := []struct {
+		text          string
+		unorderedList *UnorderedListParser
+	}{
+		{
+			text:          "*Hello*",
+			unorderedList: nil,
+		},
+		{
+			text: "- Hello",
+			unorderedList: &UnorderedListParser{
+				Symbol: tokenizer.Hyphen,
+				ContentTokens: []*tokenizer.Token{
+					{
+						Type:  tokenizer.Text,
+						Value: "Hello",
+						Pos:   2,
+					},
+				},
+			},
+		},
+		{
+			text: "  + Hello\n",
+			unorderedList: &UnorderedListParser{
+				Indent: 2,
+				Symbol: tokenizer.PlusSign,
+				ContentTokens: []*tokenizer.Token{
+					{
+						Type:  tokenizer.Text,
+						Value: "Hello",
+						Pos:   4,
+					},
+				},
+			},
+		},
+		{
+			text:          "- \nHello",
+			unorderedList: nil,
+		},
+	}
+
+	for _, test := range tests {
+		tokens := tokenizer.Tokenize(test.text)
+		require.Equal(t, test.unorderedList, NewUnorderedListParser().Match(tokens))
+	}
+}
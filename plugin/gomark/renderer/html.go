@@ -0,0 +1,191 @@
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/usememos/memos/plugin/gomark/ast"
+)
+
+// allowedURLSchemes are the schemes allowed in the href and src attributes.
+var allowedURLSchemes = []string{"http", "https", "mailto", "tel"}
+
+// HTMLRenderer renders the nodes into sanitized HTML.
+// All text is escaped and unsafe urls are dropped, so the output can be embedded as is.
+type HTMLRenderer struct {
+	output *bytes.Buffer
+}
+
+func NewHTMLRenderer() *HTMLRenderer {
+	return &HTMLRenderer{
+		output: new(bytes.Buffer),
+	}
+}
+
+func (r *HTMLRenderer) Render(nodes []ast.Node) string {
+	r.output.Reset()
+	r.renderNodes(nodes)
+	return r.output.String()
+}
+
+func (r *HTMLRenderer) renderNodes(nodes []ast.Node) {
+	for i := 0; i < len(nodes); i++ {
+		switch nodes[i].(type) {
+		case *ast.Blockquote, *ast.OrderedList, *ast.UnorderedList, *ast.TaskList:
+			// Consecutive items of the same type separated by single line breaks are rendered as one element.
+			end := i
+			for end+2 < len(nodes) && nodes[end+1].Type() == ast.LineBreakNode && nodes[end+2].Type() == nodes[i].Type() {
+				end += 2
+			}
+			items := []ast.Node{}
+			for j := i; j <= end; j += 2 {
+				items = append(items, nodes[j])
+			}
+			r.renderItems(items)
+			i = end
+		default:
+			r.renderNode(nodes[i])
+		}
+	}
+}
+
+// renderItems renders the consecutive blockquote or list items of the same type.
+func (r *HTMLRenderer) renderItems(items []ast.Node) {
+	switch n := items[0].(type) {
+	case *ast.Blockquote:
+		r.output.WriteString("<blockquote>")
+		for i, item := range items {
+			if i > 0 {
+				r.output.WriteString("<br>")
+			}
+			r.renderNodes(item.(*ast.Blockquote).Children)
+		}
+		r.output.WriteString("</blockquote>")
+	case *ast.OrderedList:
+		if n.Number != "1" {
+			r.output.WriteString(fmt.Sprintf(`<ol start="%s">`, html.EscapeString(n.Number)))
+		} else {
+			r.output.WriteString("<ol>")
+		}
+		for _, item := range items {
+			r.output.WriteString("<li>")
+			r.renderNodes(item.(*ast.OrderedList).Children)
+			r.output.WriteString("</li>")
+		}
+		r.output.WriteString("</ol>")
+	case *ast.UnorderedList:
+		r.output.WriteString("<ul>")
+		for _, item := range items {
+			r.output.WriteString("<li>")
+			r.renderNodes(item.(*ast.UnorderedList).Children)
+			r.output.WriteString("</li>")
+		}
+		r.output.WriteString("</ul>")
+	case *ast.TaskList:
+		r.output.WriteString("<ul>")
+		for _, item := range items {
+			taskList := item.(*ast.TaskList)
+			if taskList.Complete {
+				r.output.WriteString(`<li><input type="checkbox" checked disabled /> `)
+			} else {
+				r.output.WriteString(`<li><input type="checkbox" disabled /> `)
+			}
+			r.renderNodes(taskList.Children)
+			r.output.WriteString("</li>")
+		}
+		r.output.WriteString("</ul>")
+	}
+}
+
+func (r *HTMLRenderer) renderNode(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.LineBreak:
+		r.output.WriteString("<br>")
+	case *ast.Paragraph:
+		r.output.WriteString("<p>")
+		r.renderNodes(n.Children)
+		r.output.WriteString("</p>")
+	case *ast.CodeBlock:
+		r.output.WriteString("<pre><code")
+		if n.Language != "" {
+			r.output.WriteString(fmt.Sprintf(` class="language-%s"`, html.EscapeString(n.Language)))
+		}
+		r.output.WriteString(">")
+		r.output.WriteString(html.EscapeString(n.Content))
+		r.output.WriteString("</code></pre>")
+	case *ast.Heading:
+		r.output.WriteString(fmt.Sprintf("<h%d>", n.Level))
+		r.renderNodes(n.Children)
+		r.output.WriteString(fmt.Sprintf("</h%d>", n.Level))
+	case *ast.HorizontalRule:
+		r.output.WriteString("<hr>")
+	case *ast.Table:
+		r.output.WriteString("<table><thead><tr>")
+		for _, cell := range n.Header {
+			r.output.WriteString("<th>" + html.EscapeString(cell) + "</th>")
+		}
+		r.output.WriteString("</tr></thead><tbody>")
+		for _, row := range n.Rows {
+			r.output.WriteString("<tr>")
+			for _, cell := range row {
+				r.output.WriteString("<td>" + html.EscapeString(cell) + "</td>")
+			}
+			r.output.WriteString("</tr>")
+		}
+		r.output.WriteString("</tbody></table>")
+	case *ast.Text:
+		r.output.WriteString(html.EscapeString(n.Content))
+	case *ast.Bold:
+		r.output.WriteString("<strong>")
+		r.renderNodes(n.Children)
+		r.output.WriteString("</strong>")
+	case *ast.Italic:
+		r.output.WriteString("<em>")
+		r.renderNodes(n.Children)
+		r.output.WriteString("</em>")
+	case *ast.Strikethrough:
+		r.output.WriteString("<del>")
+		r.renderNodes(n.Children)
+		r.output.WriteString("</del>")
+	case *ast.Code:
+		r.output.WriteString("<code>")
+		r.output.WriteString(html.EscapeString(n.Content))
+		r.output.WriteString("</code>")
+	case *ast.Image:
+		if !isSafeURL(n.URL) {
+			r.output.WriteString(html.EscapeString(n.AltText))
+			return
+		}
+		r.output.WriteString(fmt.Sprintf(`<img src="%s" alt="%s" />`, html.EscapeString(n.URL), html.EscapeString(n.AltText)))
+	case *ast.Link:
+		if !isSafeURL(n.URL) {
+			r.output.WriteString(html.EscapeString(n.Text))
+			return
+		}
+		r.output.WriteString(fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(n.URL), html.EscapeString(n.Text)))
+	case *ast.Tag:
+		r.output.WriteString(`<span class="tag">#`)
+		r.output.WriteString(html.EscapeString(n.Content))
+		r.output.WriteString("</span>")
+	}
+}
+
+// isSafeURL returns true if the url is relative or uses one of the allowed schemes.
+func isSafeURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		return true
+	}
+	for _, scheme := range allowedURLSchemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return true
+		}
+	}
+	return false
+}
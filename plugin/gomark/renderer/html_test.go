@@ -0,0 +1,67 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/gomark/parser"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+func TestHTMLRenderer(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{
+			text:     "Hello world!",
+			expected: `<p>Hello world!</p>`,
+		},
+		{
+			text:     "# Hello **world**!",
+			expected: `<h1>Hello <strong>world</strong>!</h1>`,
+		},
+		{
+			text:     "Hello\n*world* #tag",
+			expected: `<p>Hello</p><br><p><em>world</em> <span class="tag">#tag</span></p>`,
+		},
+		{
+			text:     "```go\nif a < b {}\n```",
+			expected: `<pre><code class="language-go">if a &lt; b {}</code></pre>`,
+		},
+		{
+			text:     "<script>alert(1)</script>",
+			expected: `<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>`,
+		},
+		{
+			text:     "[memos](https://usememos.com) ![logo](/logo.png)",
+			expected: `<p><a href="https://usememos.com">memos</a> <img src="/logo.png" alt="logo" /></p>`,
+		},
+		{
+			text:     "[click](javascript:alert`1`)",
+			expected: `<p>click</p>`,
+		},
+		{
+			text:     "- [ ] todo\n- [x] done",
+			expected: `<ul><li><input type="checkbox" disabled /> todo</li><li><input type="checkbox" checked disabled /> done</li></ul>`,
+		},
+		{
+			text:     "3. first\n4. ~~second~~\n- item",
+			expected: `<ol start="3"><li>first</li><li><del>second</del></li></ol><br><ul><li>item</li></ul>`,
+		},
+		{
+			text:     "> quote\n> more\n---",
+			expected: `<blockquote>quote<br>more</blockquote><br><hr>`,
+		},
+		{
+			text:     "| a | <b> |\n| --- | --- |\n| 1 | 2 |",
+			expected: `<table><thead><tr><th>a</th><th>&lt;b&gt;</th></tr></thead><tbody><tr><td>1</td><td>2</td></tr></tbody></table>`,
+		},
+	}
+
+	for _, test := range tests {
+		document := parser.Parse(tokenizer.Tokenize(test.text))
+		require.Equal(t, test.expected, NewHTMLRenderer().Render(document.Nodes), test.text)
+	}
+}
@@ -0,0 +1,125 @@
+package renderer
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/usememos/memos/plugin/gomark/ast"
+)
+
+// MarkdownRenderer renders the nodes back into normalized markdown.
+// Parsing the output again yields the same nodes.
+type MarkdownRenderer struct {
+	output *bytes.Buffer
+}
+
+func NewMarkdownRenderer() *MarkdownRenderer {
+	return &MarkdownRenderer{
+		output: new(bytes.Buffer),
+	}
+}
+
+func (r *MarkdownRenderer) Render(nodes []ast.Node) string {
+	r.output.Reset()
+	r.renderNodes(nodes)
+	return r.output.String()
+}
+
+func (r *MarkdownRenderer) renderNodes(nodes []ast.Node) {
+	for _, node := range nodes {
+		r.renderNode(node)
+	}
+}
+
+func (r *MarkdownRenderer) renderNode(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.LineBreak:
+		r.output.WriteString("\n")
+	case *ast.Paragraph:
+		r.renderNodes(n.Children)
+	case *ast.CodeBlock:
+		r.output.WriteString("```")
+		r.output.WriteString(n.Language)
+		r.output.WriteString("\n")
+		r.output.WriteString(n.Content)
+		r.output.WriteString("\n```")
+	case *ast.Heading:
+		r.output.WriteString(strings.Repeat("#", n.Level))
+		r.output.WriteString(" ")
+		r.renderNodes(n.Children)
+	case *ast.HorizontalRule:
+		r.output.WriteString(strings.Repeat(n.Symbol, 3))
+	case *ast.Blockquote:
+		r.output.WriteString("> ")
+		r.renderNodes(n.Children)
+	case *ast.OrderedList:
+		r.output.WriteString(strings.Repeat(" ", n.Indent))
+		r.output.WriteString(n.Number)
+		r.output.WriteString(". ")
+		r.renderNodes(n.Children)
+	case *ast.UnorderedList:
+		r.output.WriteString(strings.Repeat(" ", n.Indent))
+		r.output.WriteString(n.Symbol)
+		r.output.WriteString(" ")
+		r.renderNodes(n.Children)
+	case *ast.TaskList:
+		r.output.WriteString(strings.Repeat(" ", n.Indent))
+		r.output.WriteString(n.Symbol)
+		if n.Complete {
+			r.output.WriteString(" [x] ")
+		} else {
+			r.output.WriteString(" [ ] ")
+		}
+		r.renderNodes(n.Children)
+	case *ast.Table:
+		r.renderTableRow(n.Header)
+		r.output.WriteString("\n")
+		r.renderTableRow(n.Delimiter)
+		for _, row := range n.Rows {
+			r.output.WriteString("\n")
+			r.renderTableRow(row)
+		}
+	case *ast.Text:
+		r.output.WriteString(n.Content)
+	case *ast.Bold:
+		r.output.WriteString(n.Symbol + n.Symbol)
+		r.renderNodes(n.Children)
+		r.output.WriteString(n.Symbol + n.Symbol)
+	case *ast.Italic:
+		r.output.WriteString(n.Symbol)
+		r.renderNodes(n.Children)
+		r.output.WriteString(n.Symbol)
+	case *ast.Strikethrough:
+		r.output.WriteString("~~")
+		r.renderNodes(n.Children)
+		r.output.WriteString("~~")
+	case *ast.Code:
+		r.output.WriteString("`")
+		r.output.WriteString(n.Content)
+		r.output.WriteString("`")
+	case *ast.Image:
+		r.output.WriteString("![")
+		r.output.WriteString(n.AltText)
+		r.output.WriteString("](")
+		r.output.WriteString(n.URL)
+		r.output.WriteString(")")
+	case *ast.Link:
+		r.output.WriteString("[")
+		r.output.WriteString(n.Text)
+		r.output.WriteString("](")
+		r.output.WriteString(n.URL)
+		r.output.WriteString(")")
+	case *ast.Tag:
+		r.output.WriteString("#")
+		r.output.WriteString(n.Content)
+	}
+}
+
+func (r *MarkdownRenderer) renderTableRow(cells []string) {
+	r.output.WriteString("|")
+	for _, cell := range cells {
+		r.output.WriteString(" ")
+		r.output.WriteString(cell)
+		r.output.WriteString(" |")
+	}
+}
@@ -0,0 +1,50 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/gomark/parser"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+func TestMarkdownRenderer(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{
+			text:     "Hello world!",
+			expected: "Hello world!",
+		},
+		{
+			text:     "## Hello __world__!\n\n*italic* #tag/sub `code`",
+			expected: "## Hello __world__!\n\n*italic* #tag/sub `code`",
+		},
+		{
+			text:     "```go\nfmt.Println(\"**hello**\")\n```\n[memos](https://usememos.com) ![](/logo.png)",
+			expected: "```go\nfmt.Println(\"**hello**\")\n```\n[memos](https://usememos.com) ![](/logo.png)",
+		},
+		{
+			text:     "[](https://usememos.com)",
+			expected: "[https://usememos.com](https://usememos.com)",
+		},
+		{
+			text:     "- [ ] todo #tag\n  * [x] done\n1. ~~first~~\n2. second\n> quote\n***",
+			expected: "- [ ] todo #tag\n  * [x] done\n1. ~~first~~\n2. second\n> quote\n***",
+		},
+		{
+			text:     "| a | b |\n| :-- | --: |\n| 1 | 2 |",
+			expected: "| a | b |\n| :-- | --: |\n| 1 | 2 |",
+		},
+	}
+
+	for _, test := range tests {
+		document := parser.Parse(tokenizer.Tokenize(test.text))
+		markdown := NewMarkdownRenderer().Render(document.Nodes)
+		require.Equal(t, test.expected, markdown, test.text)
+		// The rendered markdown round-trips without loss.
+		require.Equal(t, document, parser.Parse(tokenizer.Tokenize(markdown)), test.text)
+	}
+}
@@ -1 +1,10 @@
 package renderer
+
+import (
+	"github.com/usememos/memos/plugin/gomark/ast"
+)
+
+// Renderer renders the nodes of a document into a string.
+type Renderer interface {
+	Render(nodes []ast.Node) string
+}
@@ -0,0 +1,75 @@
+package renderer
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/usememos/memos/plugin/gomark/ast"
+)
+
+// StringRenderer renders the nodes into plain text with all markup removed.
+type StringRenderer struct {
+	output *bytes.Buffer
+}
+
+func NewStringRenderer() *StringRenderer {
+	return &StringRenderer{
+		output: new(bytes.Buffer),
+	}
+}
+
+func (r *StringRenderer) Render(nodes []ast.Node) string {
+	r.output.Reset()
+	r.renderNodes(nodes)
+	return r.output.String()
+}
+
+func (r *StringRenderer) renderNodes(nodes []ast.Node) {
+	for _, node := range nodes {
+		r.renderNode(node)
+	}
+}
+
+func (r *StringRenderer) renderNode(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.LineBreak:
+		r.output.WriteString("\n")
+	case *ast.Paragraph:
+		r.renderNodes(n.Children)
+	case *ast.CodeBlock:
+		r.output.WriteString(n.Content)
+	case *ast.Heading:
+		r.renderNodes(n.Children)
+	case *ast.Blockquote:
+		r.renderNodes(n.Children)
+	case *ast.OrderedList:
+		r.renderNodes(n.Children)
+	case *ast.UnorderedList:
+		r.renderNodes(n.Children)
+	case *ast.TaskList:
+		r.renderNodes(n.Children)
+	case *ast.Table:
+		r.output.WriteString(strings.Join(n.Header, "\t"))
+		for _, row := range n.Rows {
+			r.output.WriteString("\n")
+			r.output.WriteString(strings.Join(row, "\t"))
+		}
+	case *ast.Text:
+		r.output.WriteString(n.Content)
+	case *ast.Bold:
+		r.renderNodes(n.Children)
+	case *ast.Italic:
+		r.renderNodes(n.Children)
+	case *ast.Strikethrough:
+		r.renderNodes(n.Children)
+	case *ast.Code:
+		r.output.WriteString(n.Content)
+	case *ast.Image:
+		r.output.WriteString(n.AltText)
+	case *ast.Link:
+		r.output.WriteString(n.Text)
+	case *ast.Tag:
+		r.output.WriteString("#")
+		r.output.WriteString(n.Content)
+	}
+}
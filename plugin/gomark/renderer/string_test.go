@@ -0,0 +1,51 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/gomark/parser"
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
+
+func TestStringRenderer(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{
+			text:     "Hello world!",
+			expected: "Hello world!",
+		},
+		{
+			text:     "# Hello **world**!",
+			expected: "Hello world!",
+		},
+		{
+			text:     "Hello\n*world* #tag `code`",
+			expected: "Hello\nworld #tag code",
+		},
+		{
+			text:     "[memos](https://usememos.com) ![logo](/logo.png)",
+			expected: "memos logo",
+		},
+		{
+			text:     "```\nfmt.Println()\n```",
+			expected: "fmt.Println()",
+		},
+		{
+			text:     "- [x] done\n1. ~~first~~\n> quote\n---",
+			expected: "done\nfirst\nquote\n",
+		},
+		{
+			text:     "| a | b |\n| --- | --- |\n| 1 | 2 |",
+			expected: "a\tb\n1\t2",
+		},
+	}
+
+	for _, test := range tests {
+		document := parser.Parse(tokenizer.Tokenize(test.text))
+		require.Equal(t, test.expected, NewStringRenderer().Render(document.Nodes), test.text)
+	}
+}
@@ -0,0 +1,62 @@
+package gomark
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/plugin/gomark/ast"
+	"github.com/usememos/memos/plugin/gomark/renderer"
+)
+
+// Task is a task list item in the memo content, e.g. "- [ ] todo".
+type Task struct {
+	// Index is the zero-based position of the task among all tasks in the content.
+	Index int
+	// Line is the one-based line number of the task in the content.
+	Line     int
+	Complete bool
+	// Content is the markdown content of the task without the list symbol and checkbox.
+	Content string
+}
+
+// ListTasks returns all the tasks in the content in order, including the quoted ones.
+func ListTasks(content string) []*Task {
+	tasks := []*Task{}
+	for _, taskList := range findTaskLists(content) {
+		tasks = append(tasks, &Task{
+			Index:    len(tasks),
+			Line:     strings.Count(content[:taskList.CheckboxPos], "\n") + 1,
+			Complete: taskList.Complete,
+			Content:  renderer.NewMarkdownRenderer().Render(taskList.Children),
+		})
+	}
+	return tasks
+}
+
+// ToggleTask flips the completion of the task at the given index and returns the updated content.
+// Only the mark between the brackets of the task is rewritten, the rest of the content is kept as is.
+func ToggleTask(content string, index int) (string, error) {
+	taskLists := findTaskLists(content)
+	if index < 0 || index >= len(taskLists) {
+		return "", errors.Errorf("task %d not found", index)
+	}
+
+	target := taskLists[index]
+	mark := " "
+	if !target.Complete {
+		mark = "x"
+	}
+	return content[:target.CheckboxPos] + mark + content[target.CheckboxPos+1:], nil
+}
+
+// findTaskLists returns the task lists in the content in order.
+func findTaskLists(content string) []*ast.TaskList {
+	taskLists := []*ast.TaskList{}
+	ast.Walk(Parse(content).Nodes, func(node ast.Node) {
+		if taskList, ok := node.(*ast.TaskList); ok {
+			taskLists = append(taskLists, taskList)
+		}
+	})
+	return taskLists
+}
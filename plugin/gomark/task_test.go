@@ -0,0 +1,97 @@
+package gomark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTasks(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected []*Task
+	}{
+		{
+			content:  "Hello world!",
+			expected: []*Task{},
+		},
+		{
+			content: "# Todo\n\n- [ ] buy **milk** #home\n```\n- [ ] not a task\n```\n  * [x] done\ntext",
+			expected: []*Task{
+				{
+					Index:    0,
+					Line:     3,
+					Complete: false,
+					Content:  "buy **milk** #home",
+				},
+				{
+					Index:    1,
+					Line:     7,
+					Complete: true,
+					Content:  "done",
+				},
+			},
+		},
+		{
+			content: "**Trip** ✈️\n> - [ ] pack\n\n```\n```\n- [x] book",
+			expected: []*Task{
+				{
+					Index:    0,
+					Line:     2,
+					Complete: false,
+					Content:  "pack",
+				},
+				{
+					Index:    1,
+					Line:     6,
+					Complete: true,
+					Content:  "book",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, ListTasks(test.content), test.content)
+	}
+}
+
+func TestToggleTask(t *testing.T) {
+	tests := []struct {
+		content  string
+		index    int
+		expected string
+		err      bool
+	}{
+		{
+			content:  "- [ ] first\n- [ ] second",
+			index:    1,
+			expected: "- [ ] first\n- [x] second",
+		},
+		{
+			content:  "```\n- [ ] code\n```\n\n  + [X] *done*\n[](https://usememos.com)",
+			index:    0,
+			expected: "```\n- [ ] code\n```\n\n  + [ ] *done*\n[](https://usememos.com)",
+		},
+		{
+			content:  "> * [ ]   __spaced__   \n\n1)  - [X]\tkept",
+			index:    0,
+			expected: "> * [x]   __spaced__   \n\n1)  - [X]\tkept",
+		},
+		{
+			content: "- [ ] first",
+			index:   1,
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		content, err := ToggleTask(test.content, test.index)
+		if test.err {
+			require.Error(t, err, test.content)
+			continue
+		}
+		require.NoError(t, err, test.content)
+		require.Equal(t, test.expected, content, test.content)
+	}
+}
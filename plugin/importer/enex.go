@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/store"
+)
+
+const enexTimeLayout = "20060102T150405Z"
+
+// ENEXImporter imports the ENEX files exported by Evernote, in which the notes are in ENML with their resources in base64.
+type ENEXImporter struct{}
+
+type enexNote struct {
+	Title     string         `xml:"title"`
+	Content   string         `xml:"content"`
+	Created   string         `xml:"created"`
+	Updated   string         `xml:"updated"`
+	Tags      []string       `xml:"tag"`
+	Resources []enexResource `xml:"resource"`
+}
+
+type enexResource struct {
+	Data     string `xml:"data"`
+	Mime     string `xml:"mime"`
+	Filename string `xml:"resource-attributes>file-name"`
+}
+
+func (*ENEXImporter) Import(fsys fs.FS) (*Result, error) {
+	names := []string{}
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && strings.ToLower(path.Ext(name)) == ".enex" {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no ENEX file found")
+	}
+
+	result := &Result{}
+	for _, name := range names {
+		if err := importENEXFile(fsys, name, result); err != nil {
+			return nil, errors.Wrapf(err, "failed to import %s", name)
+		}
+	}
+	return result, nil
+}
+
+func importENEXFile(fsys fs.FS, name string, result *Result) error {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Decode the notes one by one as the exports with resources can be large.
+	decoder := xml.NewDecoder(file)
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok || element.Name.Local != "note" {
+			continue
+		}
+		note := &enexNote{}
+		if err := decoder.DecodeElement(note, &element); err != nil {
+			return err
+		}
+		if err := importENEXNote(note, result); err != nil {
+			return errors.Wrapf(err, "failed to import note %s", note.Title)
+		}
+	}
+}
+
+func importENEXNote(note *enexNote, result *Result) error {
+	content, err := convertHTMLToMarkdown(strings.NewReader(note.Content))
+	if err != nil {
+		return err
+	}
+	if title := strings.TrimSpace(note.Title); title != "" {
+		content = "# " + title + "\n\n" + content
+	}
+	if len(note.Tags) > 0 {
+		tags := []string{}
+		for _, tag := range note.Tags {
+			tags = append(tags, "#"+strings.ReplaceAll(strings.TrimSpace(tag), " ", "-"))
+		}
+		content += "\n\n" + strings.Join(tags, " ")
+	}
+
+	memo := &store.Memo{
+		Content: strings.TrimSpace(content),
+	}
+	if createdTime, err := time.Parse(enexTimeLayout, note.Created); err == nil {
+		memo.CreatedTs = createdTime.Unix()
+	} else {
+		result.Warnings = append(result.Warnings, "invalid created time of note "+note.Title)
+	}
+	if updatedTime, err := time.Parse(enexTimeLayout, note.Updated); err == nil {
+		memo.UpdatedTs = updatedTime.Unix()
+	}
+	result.addMemo(memo)
+
+	for _, resource := range note.Resources {
+		blob, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(resource.Data), ""))
+		if err != nil {
+			result.Warnings = append(result.Warnings, "invalid resource data of note "+note.Title)
+			continue
+		}
+		filename := resource.Filename
+		if filename == "" {
+			filename = "untitled"
+		}
+		result.addBlobResource(memo, filename, resource.Mime, blob)
+	}
+	return nil
+}
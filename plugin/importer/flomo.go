@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+
+	"github.com/usememos/memos/store"
+)
+
+const flomoTimeLayout = "2006-01-02 15:04:05"
+
+// FlomoImporter imports the HTML export of flomo, which is a HTML file with its attachments under the file folder.
+//
+// Each memo of the export is like:
+//
+//	<div class="memo">
+//	  <div class="time">2023-01-02 15:04:05</div>
+//	  <div class="content"><p>Hello #tag</p></div>
+//	  <div class="files"><img src="file/2023-01-02/1/image.png" /></div>
+//	</div>
+type FlomoImporter struct{}
+
+func (*FlomoImporter) Import(fsys fs.FS) (*Result, error) {
+	names, err := fs.Glob(fsys, "*.html")
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no HTML file found in the flomo export")
+	}
+
+	result := &Result{}
+	for _, name := range names {
+		if err := importFlomoFile(fsys, name, result); err != nil {
+			return nil, errors.Wrapf(err, "failed to import %s", name)
+		}
+	}
+	return result, nil
+}
+
+func importFlomoFile(fsys fs.FS, name string, result *Result) error {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	root, err := html.Parse(file)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range findElements(root, "div", "memo") {
+		memo := &store.Memo{}
+		if elements := findElements(node, "div", "time"); len(elements) > 0 {
+			createdTime, err := time.ParseInLocation(flomoTimeLayout, strings.TrimSpace(getText(elements[0])), time.Local)
+			if err != nil {
+				result.Warnings = append(result.Warnings, "invalid memo time "+getText(elements[0]))
+			} else {
+				memo.CreatedTs = createdTime.Unix()
+			}
+		}
+		if elements := findElements(node, "div", "content"); len(elements) > 0 {
+			content, err := renderChildren(elements[0])
+			if err != nil {
+				return err
+			}
+			markdown, err := convertHTMLToMarkdown(strings.NewReader(content))
+			if err != nil {
+				return err
+			}
+			memo.Content = markdown
+		}
+		result.addMemo(memo)
+
+		for _, files := range findElements(node, "div", "files") {
+			attachments := []string{}
+			for _, img := range findElements(files, "img", "") {
+				attachments = append(attachments, getAttribute(img, "src"))
+			}
+			for _, a := range findElements(files, "a", "") {
+				attachments = append(attachments, getAttribute(a, "href"))
+			}
+			for _, attachment := range attachments {
+				if attachment == "" {
+					continue
+				}
+				if unescaped, err := url.PathUnescape(attachment); err == nil {
+					attachment = unescaped
+				}
+				result.addFileResource(fsys, memo, path.Clean(path.Join(path.Dir(name), attachment)))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"time"
+)
+
+var zipMagic = []byte("PK\x03\x04")
+
+// OpenFS opens the uploaded export as a FS, i.e. a ZIP archive as its files or another file, like an ENEX, as the only file named the name.
+func OpenFS(r io.ReaderAt, size int64, name string) (fs.FS, error) {
+	header := make([]byte, len(zipMagic))
+	if _, err := r.ReadAt(header, 0); err == nil && bytes.Equal(header, zipMagic) {
+		return zip.NewReader(r, size)
+	}
+	data, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return nil, err
+	}
+	return &fileFS{
+		name:    path.Base(name),
+		data:    data,
+		modTime: time.Now(),
+	}, nil
+}
+
+// fileFS is a FS of a single file in the root.
+type fileFS struct {
+	name    string
+	data    []byte
+	modTime time.Time
+}
+
+func (f *fileFS) Open(name string) (fs.File, error) {
+	switch {
+	case name == ".":
+		return &fileFSDir{fsys: f}, nil
+	case name == f.name:
+		return &fileFSFile{Reader: bytes.NewReader(f.data), fsys: f}, nil
+	case !fs.ValidPath(name):
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	default:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+}
+
+type fileFSFile struct {
+	*bytes.Reader
+	fsys *fileFS
+}
+
+func (f *fileFSFile) Stat() (fs.FileInfo, error) {
+	return &fileFSInfo{name: f.fsys.name, size: int64(len(f.fsys.data)), modTime: f.fsys.modTime}, nil
+}
+
+func (*fileFSFile) Close() error {
+	return nil
+}
+
+type fileFSDir struct {
+	fsys *fileFS
+	read bool
+}
+
+func (d *fileFSDir) Stat() (fs.FileInfo, error) {
+	return &fileFSInfo{name: ".", modTime: d.fsys.modTime, dir: true}, nil
+}
+
+func (*fileFSDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: ".", Err: fs.ErrInvalid}
+}
+
+func (*fileFSDir) Close() error {
+	return nil
+}
+
+func (d *fileFSDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.read {
+		if n > 0 {
+			return nil, io.EOF
+		}
+		return nil, nil
+	}
+	d.read = true
+	info := &fileFSInfo{name: d.fsys.name, size: int64(len(d.fsys.data)), modTime: d.fsys.modTime}
+	return []fs.DirEntry{fs.FileInfoToDirEntry(info)}, nil
+}
+
+type fileFSInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (i *fileFSInfo) Name() string       { return i.name }
+func (i *fileFSInfo) Size() int64        { return i.size }
+func (i *fileFSInfo) ModTime() time.Time { return i.modTime }
+func (i *fileFSInfo) IsDir() bool        { return i.dir }
+func (*fileFSInfo) Sys() any             { return nil }
+
+func (i *fileFSInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
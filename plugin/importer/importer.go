@@ -0,0 +1,120 @@
+// Package importer converts the exports of other note-taking apps into memos.
+package importer
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"mime"
+	"path"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/store"
+)
+
+// Result is the records converted from a source.
+//
+// The IDs of the memos are only unique in the result, and the memo IDs of the resources and the relations refer to them.
+// The blobs of the resources are only read when they're opened.
+type Result struct {
+	Memos     []*store.Memo
+	Resources []*Resource
+	Relations []*store.MemoRelation
+	// Warnings are the problems that don't stop the import, e.g. a missing attachment.
+	Warnings []string
+}
+
+// Resource is a resource converted from a source, along with the way to open its blob.
+type Resource struct {
+	*store.Resource
+	// Open opens the blob, which is closed by the caller.
+	Open func() (io.ReadCloser, error)
+}
+
+// Importer converts the files of a source into records.
+type Importer interface {
+	Import(fsys fs.FS) (*Result, error)
+}
+
+var importers = map[string]Importer{
+	"flomo":    &FlomoImporter{},
+	"obsidian": &ObsidianImporter{},
+	"keep":     &KeepImporter{},
+	"enex":     &ENEXImporter{},
+}
+
+// GetImporter returns the importer of the source.
+func GetImporter(source string) (Importer, error) {
+	importer, ok := importers[source]
+	if !ok {
+		return nil, errors.Errorf("unsupported import source %s", source)
+	}
+	return importer, nil
+}
+
+// ListSources returns the names of the supported sources.
+func ListSources() []string {
+	sources := []string{}
+	for source := range importers {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+func (r *Result) addMemo(memo *store.Memo) *store.Memo {
+	memo.ID = int32(len(r.Memos) + 1)
+	if memo.RowStatus == "" {
+		memo.RowStatus = store.Normal
+	}
+	if memo.Visibility == "" {
+		memo.Visibility = store.Private
+	}
+	if memo.UpdatedTs == 0 {
+		memo.UpdatedTs = memo.CreatedTs
+	}
+	r.Memos = append(r.Memos, memo)
+	return memo
+}
+
+func (r *Result) addResource(memo *store.Memo, filename string, mimeType string, size int64, open func() (io.ReadCloser, error)) {
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(path.Ext(filename))
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	r.Resources = append(r.Resources, &Resource{
+		Resource: &store.Resource{
+			CreatedTs: memo.CreatedTs,
+			UpdatedTs: memo.CreatedTs,
+			Filename:  filename,
+			Type:      mimeType,
+			Size:      size,
+			MemoID:    &memo.ID,
+		},
+		Open: open,
+	})
+}
+
+// addBlobResource adds the blob decoded from the source as a resource of the memo.
+func (r *Result) addBlobResource(memo *store.Memo, filename string, mimeType string, blob []byte) {
+	r.addResource(memo, filename, mimeType, int64(len(blob)), func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(blob)), nil
+	})
+}
+
+// addFileResource adds the file in the source as a resource of the memo, or a warning if it's missing.
+// The file is only read when the resource is saved.
+func (r *Result) addFileResource(fsys fs.FS, memo *store.Memo, name string) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil || !info.Mode().IsRegular() {
+		r.Warnings = append(r.Warnings, "missing attachment "+name)
+		return
+	}
+	r.addResource(memo, path.Base(name), "", info.Size(), func() (io.ReadCloser, error) {
+		return fsys.Open(name)
+	})
+}
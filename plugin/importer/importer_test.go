@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/store"
+)
+
+func TestConvertHTMLToMarkdown(t *testing.T) {
+	tests := []struct {
+		html     string
+		markdown string
+	}{
+		{
+			html:     `<p>Hello <strong>world</strong></p><p>Second line<br>Third line</p>`,
+			markdown: "Hello **world**\n\nSecond line\nThird line",
+		},
+		{
+			html:     `<ul><li>a</li><li><em>b</em></li></ul><ol><li>c</li></ol>`,
+			markdown: "- a\n- *b*\n\n1. c",
+		},
+		{
+			html:     `<p><a href="https://usememos.com">memos</a> <a href="https://github.com">https://github.com</a></p>`,
+			markdown: "[memos](https://usememos.com) https://github.com",
+		},
+		{
+			html:     `<en-note><div><en-todo checked="true"/>done</div><div><en-todo/>todo</div></en-note>`,
+			markdown: "- [x] done\n\n- [ ] todo",
+		},
+	}
+	for _, test := range tests {
+		markdown, err := convertHTMLToMarkdown(strings.NewReader(test.html))
+		require.NoError(t, err)
+		require.Equal(t, test.markdown, markdown)
+	}
+}
+
+func TestFlomoImporter(t *testing.T) {
+	fsys := fstest.MapFS{
+		"flomo.html": {Data: []byte(`<html><body><div class="memos">
+<div class="memo"><div class="time">2023-01-02 15:04:05</div><div class="content"><p>Hello #flomo</p></div>
+<div class="files"><img src="file/2023-01-02/1/image.png" /></div></div>
+<div class="memo"><div class="time">2023-01-03 15:04:05</div><div class="content"><p>Missing</p></div>
+<div class="files"><img src="file/missing.png" /></div></div>
+</div></body></html>`)},
+		"file/2023-01-02/1/image.png": {Data: []byte("image")},
+	}
+	result, err := (&FlomoImporter{}).Import(fsys)
+	require.NoError(t, err)
+	require.Len(t, result.Memos, 2)
+	require.Equal(t, "Hello #flomo", result.Memos[0].Content)
+	require.Equal(t, time.Date(2023, 1, 2, 15, 4, 5, 0, time.Local).Unix(), result.Memos[0].CreatedTs)
+	require.Equal(t, store.Private, result.Memos[0].Visibility)
+	require.Len(t, result.Resources, 1)
+	require.Equal(t, "image.png", result.Resources[0].Filename)
+	require.Equal(t, "image/png", result.Resources[0].Type)
+	require.Equal(t, result.Memos[0].ID, *result.Resources[0].MemoID)
+	require.Equal(t, int64(5), result.Resources[0].Size)
+	requireResourceBlob(t, result.Resources[0], "image")
+	require.Equal(t, []string{"missing attachment file/missing.png"}, result.Warnings)
+}
+
+// requireResourceBlob requires the blob opened by the resource to be the content.
+func requireResourceBlob(t *testing.T, resource *Resource, content string) {
+	reader, err := resource.Open()
+	require.NoError(t, err)
+	defer reader.Close()
+	blob, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, content, string(blob))
+}
+
+func TestObsidianImporter(t *testing.T) {
+	modTime := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"Inbox/Idea.md":           {Data: []byte("---\ntags: [idea, work]\n---\nSee [[Project|the project]] and [[Missing]].\n![[diagram.png]]"), ModTime: modTime},
+		"Project.md":              {Data: []byte("Back to [[Inbox/Idea#Heading]]."), ModTime: modTime},
+		"attachments/diagram.png": {Data: []byte("image"), ModTime: modTime},
+		".obsidian/app.json":      {Data: []byte("{}"), ModTime: modTime},
+	}
+	result, err := (&ObsidianImporter{}).Import(fsys)
+	require.NoError(t, err)
+	require.Len(t, result.Memos, 2)
+	require.Equal(t, "# Idea\n\nSee the project and Missing.\n\n#idea #work", result.Memos[0].Content)
+	require.Equal(t, "# Project\n\nBack to Inbox/Idea.", result.Memos[1].Content)
+	require.Equal(t, modTime.Unix(), result.Memos[0].CreatedTs)
+	require.Len(t, result.Resources, 1)
+	require.Equal(t, "diagram.png", result.Resources[0].Filename)
+	require.Equal(t, []*store.MemoRelation{
+		{MemoID: 1, RelatedMemoID: 2, Type: store.MemoRelationReference},
+		{MemoID: 2, RelatedMemoID: 1, Type: store.MemoRelationReference},
+	}, result.Relations)
+	require.Equal(t, []string{"unresolved link [[Missing]] in Inbox/Idea.md"}, result.Warnings)
+}
+
+func TestKeepImporter(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Takeout/Keep/Groceries.json": {Data: []byte(`{"title":"Groceries","listContent":[{"text":"milk","isChecked":true},{"text":"eggs","isChecked":false}],
+"labels":[{"name":"home"}],"isPinned":true,"createdTimestampUsec":1672671845000000,"userEditedTimestampUsec":1672758245000000,
+"attachments":[{"filePath":"photo.jpeg","mimetype":"image/jpeg"}]}`)},
+		"Takeout/Keep/photo.jpg":     {Data: []byte("image")},
+		"Takeout/Keep/Trashed.json":  {Data: []byte(`{"textContent":"trashed","isTrashed":true,"createdTimestampUsec":1672671845000000}`)},
+		"Takeout/Keep/Archived.json": {Data: []byte(`{"textContent":"archived","isArchived":true,"createdTimestampUsec":1672671845000000}`)},
+		"Takeout/Keep/Labels.json":   {Data: []byte(`{}`)},
+	}
+	result, err := (&KeepImporter{}).Import(fsys)
+	require.NoError(t, err)
+	require.Len(t, result.Memos, 2)
+	require.Equal(t, "archived", result.Memos[0].Content)
+	require.Equal(t, store.Archived, result.Memos[0].RowStatus)
+	require.Equal(t, "# Groceries\n\n- [x] milk\n- [ ] eggs\n\n#home", result.Memos[1].Content)
+	require.True(t, result.Memos[1].Pinned)
+	require.Equal(t, int64(1672671845), result.Memos[1].CreatedTs)
+	require.Equal(t, int64(1672758245), result.Memos[1].UpdatedTs)
+	require.Len(t, result.Resources, 1)
+	require.Equal(t, "photo.jpg", result.Resources[0].Filename)
+	require.Equal(t, result.Memos[1].ID, *result.Resources[0].MemoID)
+	require.Empty(t, result.Warnings)
+}
+
+func TestENEXImporter(t *testing.T) {
+	fsys := fstest.MapFS{
+		"notes.enex": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE en-export SYSTEM "http://xml.evernote.com/pub/evernote-export3.dtd">
+<en-export>
+<note><title>Trip</title><content><![CDATA[<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<!DOCTYPE en-note SYSTEM "http://xml.evernote.com/pub/enml2.dtd">
+<en-note><div>Pack <b>passport</b></div><en-media hash="abc" type="image/png"/></en-note>]]></content>
+<created>20230102T150405Z</created><updated>20230103T150405Z</updated><tag>travel</tag>
+<resource><data encoding="base64">` + base64.StdEncoding.EncodeToString([]byte("image")) + `</data><mime>image/png</mime>
+<resource-attributes><file-name>map.png</file-name></resource-attributes></resource></note>
+</en-export>`)},
+	}
+	result, err := (&ENEXImporter{}).Import(fsys)
+	require.NoError(t, err)
+	require.Len(t, result.Memos, 1)
+	require.Equal(t, "# Trip\n\nPack **passport**\n\n#travel", result.Memos[0].Content)
+	require.Equal(t, time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC).Unix(), result.Memos[0].CreatedTs)
+	require.Equal(t, time.Date(2023, 1, 3, 15, 4, 5, 0, time.UTC).Unix(), result.Memos[0].UpdatedTs)
+	require.Len(t, result.Resources, 1)
+	require.Equal(t, "map.png", result.Resources[0].Filename)
+	requireResourceBlob(t, result.Resources[0], "image")
+}
+
+func TestOpenFS(t *testing.T) {
+	data := []byte("<en-export></en-export>")
+	fsys, err := OpenFS(bytes.NewReader(data), int64(len(data)), "/tmp/notes.enex")
+	require.NoError(t, err)
+	require.NoError(t, fstest.TestFS(fsys, "notes.enex"))
+}
@@ -0,0 +1,132 @@
+package importer
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/store"
+)
+
+// KeepImporter imports the Google Keep folder of a Google Takeout export, in which each note is a JSON file.
+// The trashed notes are skipped and the archived ones are imported as archived memos.
+type KeepImporter struct{}
+
+type keepNote struct {
+	Title                   string           `json:"title"`
+	TextContent             string           `json:"textContent"`
+	ListContent             []keepListItem   `json:"listContent"`
+	Labels                  []keepLabel      `json:"labels"`
+	Attachments             []keepAttachment `json:"attachments"`
+	IsTrashed               bool             `json:"isTrashed"`
+	IsArchived              bool             `json:"isArchived"`
+	IsPinned                bool             `json:"isPinned"`
+	CreatedTimestampUsec    int64            `json:"createdTimestampUsec"`
+	UserEditedTimestampUsec int64            `json:"userEditedTimestampUsec"`
+}
+
+type keepListItem struct {
+	Text      string `json:"text"`
+	IsChecked bool   `json:"isChecked"`
+}
+
+type keepLabel struct {
+	Name string `json:"name"`
+}
+
+type keepAttachment struct {
+	FilePath string `json:"filePath"`
+}
+
+func (*KeepImporter) Import(fsys fs.FS) (*Result, error) {
+	names := []string{}
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && strings.ToLower(path.Ext(name)) == ".json" {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		note := &keepNote{}
+		if err := json.Unmarshal(data, note); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", name)
+		}
+		// The other JSON files in the export, like the labels, aren't notes.
+		if note.CreatedTimestampUsec == 0 && note.UserEditedTimestampUsec == 0 {
+			continue
+		}
+		if note.IsTrashed {
+			continue
+		}
+
+		memo := &store.Memo{
+			CreatedTs: note.CreatedTimestampUsec / 1000000,
+			UpdatedTs: note.UserEditedTimestampUsec / 1000000,
+			Content:   convertKeepNoteToMarkdown(note),
+			Pinned:    note.IsPinned,
+		}
+		if memo.CreatedTs == 0 {
+			memo.CreatedTs = memo.UpdatedTs
+		}
+		if note.IsArchived {
+			memo.RowStatus = store.Archived
+		}
+		result.addMemo(memo)
+
+		for _, attachment := range note.Attachments {
+			name := path.Join(path.Dir(name), attachment.FilePath)
+			// Takeout names the JPEG attachments .jpg while referring to them as .jpeg, and vice versa.
+			if _, err := fs.Stat(fsys, name); err != nil {
+				ext := path.Ext(name)
+				switch strings.ToLower(ext) {
+				case ".jpeg":
+					name = strings.TrimSuffix(name, ext) + ".jpg"
+				case ".jpg":
+					name = strings.TrimSuffix(name, ext) + ".jpeg"
+				}
+			}
+			result.addFileResource(fsys, memo, name)
+		}
+	}
+	return result, nil
+}
+
+func convertKeepNoteToMarkdown(note *keepNote) string {
+	lines := []string{}
+	if title := strings.TrimSpace(note.Title); title != "" {
+		lines = append(lines, "# "+title, "")
+	}
+	if text := strings.TrimSpace(note.TextContent); text != "" {
+		lines = append(lines, text)
+	}
+	for _, item := range note.ListContent {
+		if item.IsChecked {
+			lines = append(lines, "- [x] "+item.Text)
+		} else {
+			lines = append(lines, "- [ ] "+item.Text)
+		}
+	}
+	if len(note.Labels) > 0 {
+		tags := []string{}
+		for _, label := range note.Labels {
+			tags = append(tags, "#"+strings.ReplaceAll(strings.TrimSpace(label.Name), " ", "-"))
+		}
+		lines = append(lines, "", strings.Join(tags, " "))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
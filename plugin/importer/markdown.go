@@ -0,0 +1,184 @@
+package importer
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+
+// convertHTMLToMarkdown converts the rich text of the notes, i.e. HTML and ENML, to Markdown.
+// Only the common blocks and inline styles are kept, the others are converted to their texts.
+func convertHTMLToMarkdown(r io.Reader) (string, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return "", err
+	}
+	converter := &markdownConverter{}
+	converter.convert(root)
+	markdown := blankLinesRegexp.ReplaceAllString(converter.builder.String(), "\n\n")
+	return strings.TrimSpace(markdown), nil
+}
+
+type markdownConverter struct {
+	builder strings.Builder
+	// lists are the kinds of the nested lists, true for the ordered ones.
+	lists []bool
+}
+
+func (c *markdownConverter) convert(node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		c.builder.WriteString(node.Data)
+		return
+	case html.ElementNode:
+	default:
+		c.convertChildren(node)
+		return
+	}
+
+	switch node.DataAtom {
+	case atom.Script, atom.Style, atom.Head:
+	case atom.Br:
+		c.builder.WriteString("\n")
+	case atom.Hr:
+		c.builder.WriteString("\n\n---\n\n")
+	case atom.P, atom.Div:
+		c.convertBlock(node, "")
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(node.Data[1] - '0')
+		c.convertBlock(node, strings.Repeat("#", level)+" ")
+	case atom.Blockquote:
+		c.convertBlock(node, "> ")
+	case atom.Pre:
+		c.builder.WriteString("\n\n```\n")
+		c.builder.WriteString(strings.TrimSpace(getText(node)))
+		c.builder.WriteString("\n```\n\n")
+	case atom.Ul, atom.Ol:
+		c.lists = append(c.lists, node.DataAtom == atom.Ol)
+		c.builder.WriteString("\n")
+		c.convertChildren(node)
+		c.lists = c.lists[:len(c.lists)-1]
+		c.builder.WriteString("\n")
+	case atom.Li:
+		c.builder.WriteString("\n")
+		if len(c.lists) > 0 {
+			c.builder.WriteString(strings.Repeat("  ", len(c.lists)-1))
+			if c.lists[len(c.lists)-1] {
+				c.builder.WriteString("1. ")
+			} else {
+				c.builder.WriteString("- ")
+			}
+		} else {
+			c.builder.WriteString("- ")
+		}
+		c.convertChildren(node)
+	case atom.Strong, atom.B:
+		c.convertInline(node, "**")
+	case atom.Em, atom.I:
+		c.convertInline(node, "*")
+	case atom.S, atom.Del, atom.Strike:
+		c.convertInline(node, "~~")
+	case atom.Code:
+		c.convertInline(node, "`")
+	case atom.A:
+		href := getAttribute(node, "href")
+		text := strings.TrimSpace(getText(node))
+		if href == "" || text == "" {
+			c.convertChildren(node)
+		} else if text == href {
+			c.builder.WriteString(href)
+		} else {
+			c.builder.WriteString("[" + text + "](" + href + ")")
+		}
+	case atom.Img:
+		// The images are imported as resources.
+	default:
+		// The HTML parser doesn't self-close the unknown elements, so the text after <en-todo/> is in it.
+		if node.Data == "en-todo" {
+			if getAttribute(node, "checked") == "true" {
+				c.builder.WriteString("- [x] ")
+			} else {
+				c.builder.WriteString("- [ ] ")
+			}
+		}
+		c.convertChildren(node)
+	}
+}
+
+func (c *markdownConverter) convertChildren(node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		c.convert(child)
+	}
+}
+
+func (c *markdownConverter) convertBlock(node *html.Node, prefix string) {
+	c.builder.WriteString("\n\n" + prefix)
+	c.convertChildren(node)
+	c.builder.WriteString("\n\n")
+}
+
+func (c *markdownConverter) convertInline(node *html.Node, marker string) {
+	text := getText(node)
+	if strings.TrimSpace(text) == "" {
+		c.builder.WriteString(text)
+		return
+	}
+	c.builder.WriteString(marker + strings.TrimSpace(text) + marker)
+}
+
+func getText(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	builder := strings.Builder{}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		builder.WriteString(getText(child))
+	}
+	return builder.String()
+}
+
+func getAttribute(node *html.Node, key string) string {
+	for _, attribute := range node.Attr {
+		if attribute.Key == key {
+			return attribute.Val
+		}
+	}
+	return ""
+}
+
+// findElements returns the elements whose tag is the data and whose class contains the class if it isn't empty.
+func findElements(node *html.Node, data string, class string) []*html.Node {
+	elements := []*html.Node{}
+	if node.Type == html.ElementNode && node.Data == data && (class == "" || hasClass(node, class)) {
+		return append(elements, node)
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		elements = append(elements, findElements(child, data, class)...)
+	}
+	return elements
+}
+
+func hasClass(node *html.Node, class string) bool {
+	for _, name := range strings.Fields(getAttribute(node, "class")) {
+		if name == class {
+			return true
+		}
+	}
+	return false
+}
+
+// renderChildren renders the children of the node back to HTML.
+func renderChildren(node *html.Node) (string, error) {
+	builder := strings.Builder{}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if err := html.Render(&builder, child); err != nil {
+			return "", err
+		}
+	}
+	return builder.String(), nil
+}
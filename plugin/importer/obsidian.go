@@ -0,0 +1,175 @@
+package importer
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/usememos/memos/store"
+)
+
+var (
+	// wikilinkRegexp matches the [[Note]], [[Note#Heading]], [[Note|Alias]] and ![[attachment.png]] links.
+	wikilinkRegexp = regexp.MustCompile(`(!?)\[\[([^\[\]|#]*)(#[^\[\]|]*)?(\|[^\[\]]*)?\]\]`)
+	// frontmatterRegexp matches the YAML frontmatter at the beginning of a note.
+	frontmatterRegexp = regexp.MustCompile(`\A---\r?\n((?s:.*?)\r?\n)?---\r?\n?`)
+)
+
+// ObsidianImporter imports an Obsidian vault folder.
+//
+// Each note becomes a memo titled by its filename, and its [[wikilinks]] to the other notes become REFERENCE relations.
+// The embedded attachments, i.e. ![[image.png]], become the resources of the memo.
+type ObsidianImporter struct{}
+
+type obsidianNote struct {
+	name  string
+	memo  *store.Memo
+	links []string
+}
+
+func (*ObsidianImporter) Import(fsys fs.FS) (*Result, error) {
+	notes := []*obsidianNote{}
+	// attachments are the paths of the non-note files by their lowercased basenames.
+	attachments := map[string]string{}
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if name != "." && (strings.HasPrefix(entry.Name(), ".") || entry.Name() == "_resources") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			return nil
+		}
+		if strings.ToLower(path.Ext(name)) != ".md" {
+			attachments[strings.ToLower(entry.Name())] = name
+			return nil
+		}
+		notes = append(notes, &obsidianNote{name: name})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	// memos are the memos by the lowercased note names, with and without the folders.
+	memos := map[string]*store.Memo{}
+	for _, note := range notes {
+		fileInfo, err := fs.Stat(fsys, note.name)
+		if err != nil {
+			return nil, err
+		}
+		data, err := fs.ReadFile(fsys, note.name)
+		if err != nil {
+			return nil, err
+		}
+		title := strings.TrimSuffix(path.Base(note.name), path.Ext(note.name))
+		body, tags := parseObsidianFrontmatter(string(data))
+
+		embeds := []string{}
+		body = wikilinkRegexp.ReplaceAllStringFunc(body, func(link string) string {
+			matches := wikilinkRegexp.FindStringSubmatch(link)
+			target := strings.TrimSpace(matches[2])
+			if matches[1] == "!" && strings.ToLower(path.Ext(target)) != ".md" && path.Ext(target) != "" {
+				embeds = append(embeds, target)
+				return ""
+			}
+			if target != "" {
+				note.links = append(note.links, target)
+			}
+			if alias := strings.TrimPrefix(matches[4], "|"); alias != "" {
+				return alias
+			}
+			if target == "" {
+				return strings.TrimPrefix(matches[3], "#")
+			}
+			return target
+		})
+
+		content := "# " + title + "\n\n" + strings.TrimSpace(body)
+		if len(tags) > 0 {
+			content += "\n\n#" + strings.Join(tags, " #")
+		}
+		note.memo = result.addMemo(&store.Memo{
+			CreatedTs: fileInfo.ModTime().Unix(),
+			Content:   strings.TrimSpace(content),
+		})
+		memos[strings.ToLower(title)] = note.memo
+		memos[strings.ToLower(strings.TrimSuffix(note.name, path.Ext(note.name)))] = note.memo
+
+		for _, embed := range embeds {
+			name, ok := attachments[strings.ToLower(path.Base(embed))]
+			if !ok {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("missing attachment %s in %s", embed, note.name))
+				continue
+			}
+			result.addFileResource(fsys, note.memo, name)
+		}
+	}
+
+	for _, note := range notes {
+		related := map[int32]bool{}
+		for _, link := range note.links {
+			memo, ok := memos[strings.ToLower(strings.TrimSuffix(link, ".md"))]
+			if !ok {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("unresolved link [[%s]] in %s", link, note.name))
+				continue
+			}
+			if memo.ID == note.memo.ID || related[memo.ID] {
+				continue
+			}
+			related[memo.ID] = true
+			result.Relations = append(result.Relations, &store.MemoRelation{
+				MemoID:        note.memo.ID,
+				RelatedMemoID: memo.ID,
+				Type:          store.MemoRelationReference,
+			})
+		}
+	}
+	return result, nil
+}
+
+// parseObsidianFrontmatter strips the frontmatter of the note and returns its tags.
+func parseObsidianFrontmatter(data string) (string, []string) {
+	matches := frontmatterRegexp.FindStringSubmatch(data)
+	if matches == nil {
+		return data, nil
+	}
+	body := data[len(matches[0]):]
+
+	frontmatter := struct {
+		Tags any `yaml:"tags"`
+	}{}
+	if err := yaml.Unmarshal([]byte(matches[1]), &frontmatter); err != nil {
+		return body, nil
+	}
+	tags := []string{}
+	switch value := frontmatter.Tags.(type) {
+	case string:
+		tags = append(tags, strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || r == ' '
+		})...)
+	case []any:
+		for _, tag := range value {
+			tags = append(tags, fmt.Sprint(tag))
+		}
+	}
+	cleanTags := []string{}
+	for _, tag := range tags {
+		tag = strings.ReplaceAll(strings.TrimPrefix(strings.TrimSpace(tag), "#"), " ", "-")
+		if tag != "" {
+			cleanTags = append(cleanTags, tag)
+		}
+	}
+	sort.Strings(cleanTags)
+	return body, cleanTags
+}
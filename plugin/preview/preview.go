@@ -0,0 +1,148 @@
+// Package preview renders the preview images of the files, i.e. the images themselves, the first pages of the PDFs
+// and the poster frames of the videos.
+// The images are decoded in pure Go, while the PDFs and the videos are rendered by pdftoppm and ffmpeg if they're installed.
+package preview
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/disintegration/imaging"
+	"github.com/pkg/errors"
+	// webp decoder, as the others are registered by imaging.
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels is the max number of the pixels of the images decoded, which is checked by their headers before decoding them,
+// so that an image of huge dimensions in a small file doesn't take up the memory.
+const MaxPixels = 64 << 20
+
+var (
+	// ErrUnsupported is returned when the preview of the type can't be rendered.
+	ErrUnsupported = errors.New("unsupported preview type")
+	// ErrTooLarge is returned when the image has more pixels than MaxPixels.
+	ErrTooLarge = errors.New("image is too large to preview")
+)
+
+var (
+	imageTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp", "image/bmp", "image/tiff"}
+
+	findPDFRenderer   = sync.OnceValue(func() string { return lookPath("pdftoppm") })
+	findVideoRenderer = sync.OnceValue(func() string { return lookPath("ffmpeg") })
+)
+
+// IsSupported returns whether the preview of the MIME type can be rendered.
+func IsSupported(mimeType string) bool {
+	mimeType = strings.ToLower(mimeType)
+	switch {
+	case isImage(mimeType):
+		return true
+	case mimeType == "application/pdf":
+		return findPDFRenderer() != ""
+	case strings.HasPrefix(mimeType, "video/"):
+		return findVideoRenderer() != ""
+	default:
+		return false
+	}
+}
+
+// Render returns the preview image of the file of the MIME type.
+// The PDFs are rendered in the size as the max width or height, and the others are in their own sizes.
+func Render(ctx context.Context, r io.Reader, mimeType string, size int) (image.Image, error) {
+	mimeType = strings.ToLower(mimeType)
+	switch {
+	case isImage(mimeType):
+		return decodeImage(r)
+	case mimeType == "application/pdf" && findPDFRenderer() != "":
+		return renderByCommand(ctx, r, func(input, output string) *exec.Cmd {
+			// The output is written to the root of the output path with the extension.
+			return exec.CommandContext(ctx, findPDFRenderer(), "-png", "-singlefile", "-f", "1", "-l", "1", "-scale-to", strconv.Itoa(size), input, strings.TrimSuffix(output, ".png"))
+		})
+	case strings.HasPrefix(mimeType, "video/") && findVideoRenderer() != "":
+		return renderByCommand(ctx, r, func(input, output string) *exec.Cmd {
+			return exec.CommandContext(ctx, findVideoRenderer(), "-v", "error", "-i", input, "-frames:v", "1", output)
+		})
+	default:
+		return nil, ErrUnsupported
+	}
+}
+
+// decodeImage decodes the image after checking its pixels by its header.
+func decodeImage(r io.Reader) (image.Image, error) {
+	header := &bytes.Buffer{}
+	config, _, err := image.DecodeConfig(io.TeeReader(r, header))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode image config")
+	}
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, errors.Wrapf(ErrTooLarge, "%dx%d", config.Width, config.Height)
+	}
+	img, err := imaging.Decode(io.MultiReader(header, r), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode image")
+	}
+	return img, nil
+}
+
+func isImage(mimeType string) bool {
+	for _, imageType := range imageTypes {
+		if mimeType == imageType {
+			return true
+		}
+	}
+	return false
+}
+
+// renderByCommand writes the file into a temporary directory, and decodes the PNG image rendered by the command.
+// The renderers need the files rather than the pipes, as they seek in the files.
+func renderByCommand(ctx context.Context, r io.Reader, command func(input, output string) *exec.Cmd) (image.Image, error) {
+	dir, err := os.MkdirTemp("", "memos-preview-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input")
+	file, err := os.Create(input)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temporary file")
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return nil, errors.Wrap(err, "failed to write temporary file")
+	}
+	if err := file.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close temporary file")
+	}
+
+	output := filepath.Join(dir, "output.png")
+	cmd := command(input, output)
+	if message, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, errors.Wrapf(err, "failed to run %s: %s", filepath.Base(cmd.Path), strings.TrimSpace(string(message)))
+	}
+	file, err = os.Open(output)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open rendered image")
+	}
+	defer file.Close()
+	return decodeImage(file)
+}
+
+func lookPath(name string) string {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return ""
+	}
+	return path
+}
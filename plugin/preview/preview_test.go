@@ -0,0 +1,35 @@
+package preview
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderImage(t *testing.T) {
+	encoded := &bytes.Buffer{}
+	require.NoError(t, png.Encode(encoded, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+	img, err := Render(context.Background(), bytes.NewReader(encoded.Bytes()), "image/png", 256)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 40, 20), img.Bounds())
+
+	// The image of too many pixels is rejected by its header, before its pixels are decoded.
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr, 100000)
+	binary.BigEndian.PutUint32(ihdr[4:], 100000)
+	ihdr[8], ihdr[9] = 8, 6
+	header := &bytes.Buffer{}
+	header.WriteString("\x89PNG\r\n\x1a\n")
+	require.NoError(t, binary.Write(header, binary.BigEndian, uint32(len(ihdr))))
+	header.WriteString("IHDR")
+	header.Write(ihdr)
+	require.NoError(t, binary.Write(header, binary.BigEndian, crc32.ChecksumIEEE(append([]byte("IHDR"), ihdr...))))
+	_, err = Render(context.Background(), header, "image/png", 256)
+	require.ErrorIs(t, err, ErrTooLarge)
+}
@@ -0,0 +1,187 @@
+package probe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// mp3Bitrates are the bitrates of MPEG audio layer III in kbit/s by their indexes, for MPEG-1 and MPEG-2/2.5.
+	mp3Bitrates = [2][16]int64{
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	}
+	// mp3SampleRates are the sample rates by their indexes, for MPEG-2.5, reserved, MPEG-2 and MPEG-1.
+	mp3SampleRates = [4][3]int64{
+		{11025, 12000, 8000},
+		{},
+		{22050, 24000, 16000},
+		{44100, 48000, 32000},
+	}
+)
+
+// probeWAV probes the WAV files by the byte rate and the size of the data.
+func probeWAV(r io.ReadSeeker) (*Info, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	header, err := readAt(r, 0, 12)
+	if err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != "RIFF" || string(header[8:]) != "WAVE" {
+		return nil, errors.New("invalid WAV header")
+	}
+
+	byteRate := int64(0)
+	for offset := int64(12); offset+8 <= end; {
+		chunk, err := readAt(r, offset, 8)
+		if err != nil {
+			return nil, err
+		}
+		size := int64(binary.LittleEndian.Uint32(chunk[4:]))
+		switch string(chunk[:4]) {
+		case "fmt ":
+			format, err := readAt(r, offset+8, 16)
+			if err != nil {
+				return nil, err
+			}
+			byteRate = int64(binary.LittleEndian.Uint32(format[8:]))
+		case "data":
+			// The size of the data streamed may be unknown, in which case it extends to the end.
+			size = min(size, end-offset-8)
+			return &Info{Duration: samplesDuration(size, byteRate)}, nil
+		}
+		// The chunks are aligned to 2 bytes.
+		offset += 8 + size + size%2
+	}
+	return &Info{}, nil
+}
+
+// probeMP3 probes the MP3 files by the frame count in the Xing or VBRI header, or by the bitrate of the first frame otherwise.
+func probeMP3(r io.ReadSeeker) (*Info, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	// The ID3v2 tag precedes the frames, with the size in 7-bit bytes.
+	offset := int64(0)
+	if header, err := readAt(r, 0, 10); err == nil && string(header[:3]) == "ID3" {
+		offset = 10 + (int64(header[6])<<21 | int64(header[7])<<14 | int64(header[8])<<7 | int64(header[9]))
+		if header[5]&0x10 != 0 {
+			offset += 10
+		}
+	}
+	// The ID3v1 tag follows the frames.
+	if tag, err := readAt(r, end-128, 3); err == nil && string(tag) == "TAG" {
+		end -= 128
+	}
+
+	data, err := readAt(r, offset, int(min(end-offset, 4096)))
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i+4 <= len(data); i++ {
+		if data[i] != 0xFF || data[i+1]&0xE0 != 0xE0 {
+			continue
+		}
+		version, layer := data[i+1]>>3&0x03, data[i+1]>>1&0x03
+		bitrateIndex, sampleRateIndex := data[i+2]>>4, data[i+2]>>2&0x03
+		// Only layer III is probed, with the valid bitrate and sample rate.
+		if version == 1 || layer != 1 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+			continue
+		}
+		isMPEG1, isMono := version == 3, data[i+3]>>6 == 3
+		sampleRate := mp3SampleRates[version][sampleRateIndex]
+		// The Xing header follows the side information, whose size depends on the version and the channels.
+		samplesPerFrame, sideInfoSize, bitrates := int64(576), 17, mp3Bitrates[1]
+		if isMono {
+			sideInfoSize = 9
+		}
+		if isMPEG1 {
+			samplesPerFrame, sideInfoSize, bitrates = 1152, 32, mp3Bitrates[0]
+			if isMono {
+				sideInfoSize = 17
+			}
+		}
+
+		frame := data[i:]
+		if xing := 4 + sideInfoSize; len(frame) >= xing+12 && (bytes.HasPrefix(frame[xing:], []byte("Xing")) || bytes.HasPrefix(frame[xing:], []byte("Info"))) {
+			if binary.BigEndian.Uint32(frame[xing+4:])&0x01 != 0 {
+				frames := int64(binary.BigEndian.Uint32(frame[xing+8:]))
+				return &Info{Duration: samplesDuration(frames*samplesPerFrame, sampleRate)}, nil
+			}
+		}
+		if len(frame) >= 36+18 && bytes.HasPrefix(frame[36:], []byte("VBRI")) {
+			frames := int64(binary.BigEndian.Uint32(frame[36+14:]))
+			return &Info{Duration: samplesDuration(frames*samplesPerFrame, sampleRate)}, nil
+		}
+		// The constant bitrate files are probed by their sizes.
+		bitrate := bitrates[bitrateIndex] * 1000
+		return &Info{Duration: samplesDuration((end-offset-int64(i))*8, bitrate)}, nil
+	}
+	return nil, errors.New("no MP3 frame found")
+}
+
+// probeOgg probes the Ogg Vorbis and Opus files by the sample rate in the first page and the granule position of the last page.
+func probeOgg(r io.ReadSeeker) (*Info, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	page, err := readAt(r, 0, int(min(end, 512)))
+	if err != nil {
+		return nil, err
+	}
+	if len(page) < 28 || string(page[:4]) != "OggS" || len(page) < 27+int(page[26]) {
+		return nil, errors.New("invalid Ogg page")
+	}
+	serial := page[14:18]
+	packet := page[27+int(page[26]):]
+	sampleRate, preSkip := int64(0), int64(0)
+	switch {
+	case bytes.HasPrefix(packet, []byte("\x01vorbis")) && len(packet) >= 16:
+		sampleRate = int64(binary.LittleEndian.Uint32(packet[12:]))
+	case bytes.HasPrefix(packet, []byte("OpusHead")) && len(packet) >= 12:
+		// The granule positions of Opus are always in 48 kHz.
+		sampleRate, preSkip = 48000, int64(binary.LittleEndian.Uint16(packet[10:]))
+	default:
+		// The other codecs, such as Theora, aren't probed.
+		return &Info{}, nil
+	}
+
+	tailOffset := max(0, end-65536)
+	tail, err := readAt(r, tailOffset, int(end-tailOffset))
+	if err != nil {
+		return nil, err
+	}
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if i+18 > len(tail) || !bytes.Equal(tail[i+14:i+18], serial) {
+			continue
+		}
+		// The granule position is -1 for the pages in which no packet ends.
+		if granule := int64(binary.LittleEndian.Uint64(tail[i+6:])); granule >= 0 {
+			return &Info{Duration: samplesDuration(max(0, granule-preSkip), sampleRate)}, nil
+		}
+	}
+	return &Info{}, nil
+}
+
+// probeFLAC probes the FLAC files by the total samples and the sample rate in the stream information.
+func probeFLAC(r io.ReadSeeker) (*Info, error) {
+	header, err := readAt(r, 0, 4+4+18)
+	if err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != "fLaC" || header[4]&0x7F != 0 {
+		return nil, errors.New("invalid FLAC header")
+	}
+	// The sample rate is in 20 bits, followed by the channels, the bits per sample, and the total samples in 36 bits.
+	value := binary.BigEndian.Uint64(header[8+10:])
+	sampleRate, samples := int64(value>>44), int64(value&(1<<36-1))
+	return &Info{Duration: samplesDuration(samples, sampleRate)}, nil
+}
@@ -0,0 +1,170 @@
+package probe
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"math/bits"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Matroska element IDs.
+const (
+	idSegment       = 0x18538067
+	idInfo          = 0x1549A966
+	idTimecodeScale = 0x2AD7B1
+	idDuration      = 0x4489
+	idTracks        = 0x1654AE6B
+	idTrackEntry    = 0xAE
+	idVideo         = 0xE0
+	idPixelWidth    = 0xB0
+	idPixelHeight   = 0xBA
+	idCluster       = 0x1F43B675
+)
+
+// errStop stops walking the elements once the information is found.
+var errStop = errors.New("stop")
+
+// probeMatroska probes the Matroska and WebM files by the segment information and the video tracks.
+// The files recorded by the browsers are streamed without the durations, in which case they're zero.
+func probeMatroska(r io.ReadSeeker) (*Info, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	info := &Info{}
+	timecodeScale, duration := uint64(1000000), float64(0)
+	var visit func(id uint32, offset, size int64) error
+	visit = func(id uint32, offset, size int64) error {
+		switch id {
+		case idSegment, idInfo, idTracks, idTrackEntry, idVideo:
+			return walkElements(r, offset, offset+size, visit)
+		case idTimecodeScale:
+			value, err := readUint(r, offset, size)
+			if err != nil {
+				return err
+			}
+			timecodeScale = value
+		case idDuration:
+			value, err := readFloat(r, offset, size)
+			if err != nil {
+				return err
+			}
+			duration = value
+		case idPixelWidth, idPixelHeight:
+			value, err := readUint(r, offset, size)
+			if err != nil {
+				return err
+			}
+			if info.Width == 0 || info.Height == 0 {
+				if id == idPixelWidth {
+					info.Width = int(value)
+				} else {
+					info.Height = int(value)
+				}
+			}
+		case idCluster:
+			// The information precedes the clusters of the media.
+			return errStop
+		}
+		return nil
+	}
+	if err := walkElements(r, 0, end, visit); err != nil && !errors.Is(err, errStop) {
+		return nil, err
+	}
+	info.Duration = durationOf(duration * float64(timecodeScale))
+	return info, nil
+}
+
+// walkElements visits the EBML elements between the offsets with the offsets and the sizes of their data.
+// The element of the unknown size extends to the end.
+func walkElements(r io.ReadSeeker, start, end int64, visit func(id uint32, offset, size int64) error) error {
+	for offset := start; offset < end; {
+		id, idLength, err := readVint(r, offset, false)
+		if err != nil {
+			return err
+		}
+		size, sizeLength, err := readVint(r, offset+int64(idLength), true)
+		if err != nil {
+			return err
+		}
+		dataOffset := offset + int64(idLength+sizeLength)
+		dataSize := int64(size)
+		if size == math.MaxUint64 || dataOffset+dataSize > end {
+			dataSize = end - dataOffset
+		}
+		if err := visit(uint32(id), dataOffset, dataSize); err != nil {
+			return err
+		}
+		offset = dataOffset + dataSize
+	}
+	return nil
+}
+
+// readVint reads the variable-length integer at the offset, with its length marker for the IDs.
+// It returns math.MaxUint64 for the sizes of all ones, which are unknown.
+func readVint(r io.ReadSeeker, offset int64, isSize bool) (uint64, int, error) {
+	first, err := readAt(r, offset, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	length := bits.LeadingZeros8(first[0]) + 1
+	if length > 8 {
+		return 0, 0, errors.Errorf("invalid variable-length integer at %d", offset)
+	}
+	data, err := readAt(r, offset, length)
+	if err != nil {
+		return 0, 0, err
+	}
+	value := uint64(0)
+	for _, b := range data {
+		value = value<<8 | uint64(b)
+	}
+	if !isSize {
+		return value, length, nil
+	}
+	mask := uint64(1)<<(7*length) - 1
+	if value&mask == mask {
+		return math.MaxUint64, length, nil
+	}
+	return value & mask, length, nil
+}
+
+func readUint(r io.ReadSeeker, offset, size int64) (uint64, error) {
+	if size > 8 {
+		return 0, errors.Errorf("invalid unsigned integer at %d", offset)
+	}
+	data, err := readAt(r, offset, int(size))
+	if err != nil {
+		return 0, err
+	}
+	value := uint64(0)
+	for _, b := range data {
+		value = value<<8 | uint64(b)
+	}
+	return value, nil
+}
+
+func readFloat(r io.ReadSeeker, offset, size int64) (float64, error) {
+	if size != 4 && size != 8 {
+		return 0, errors.Errorf("invalid float at %d", offset)
+	}
+	data, err := readAt(r, offset, int(size))
+	if err != nil {
+		return 0, err
+	}
+	if size == 4 {
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), nil
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+}
+
+// durationOf returns the duration of the nanoseconds, or zero if it's invalid.
+func durationOf(nanoseconds float64) time.Duration {
+	if math.IsNaN(nanoseconds) || nanoseconds <= 0 || nanoseconds > math.MaxInt64 {
+		return 0
+	}
+	return time.Duration(nanoseconds)
+}
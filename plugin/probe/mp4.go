@@ -0,0 +1,108 @@
+package probe
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// probeMP4 probes the ISO base media files, i.e. MP4, QuickTime and M4A, by the movie header and the track headers.
+func probeMP4(r io.ReadSeeker) (*Info, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	info := &Info{}
+	var visit func(boxType string, offset, size int64) error
+	visit = func(boxType string, offset, size int64) error {
+		switch boxType {
+		case "moov", "trak":
+			return walkBoxes(r, offset, offset+size, visit)
+		case "mvhd":
+			return readMovieHeader(r, offset, info)
+		case "tkhd":
+			// The dimensions are of the first video track, as the audio tracks have none.
+			if info.Width == 0 {
+				return readTrackHeader(r, offset, info)
+			}
+		}
+		return nil
+	}
+	if err := walkBoxes(r, 0, end, visit); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// walkBoxes visits the boxes between the offsets with the offsets and the sizes of their payloads.
+func walkBoxes(r io.ReadSeeker, start, end int64, visit func(boxType string, offset, size int64) error) error {
+	for offset := start; offset+8 <= end; {
+		header, err := readAt(r, offset, 8)
+		if err != nil {
+			return err
+		}
+		size, headerSize := int64(binary.BigEndian.Uint32(header)), int64(8)
+		switch size {
+		case 0:
+			// The last box extends to the end.
+			size = end - offset
+		case 1:
+			largeSize, err := readAt(r, offset+8, 8)
+			if err != nil {
+				return err
+			}
+			size, headerSize = int64(binary.BigEndian.Uint64(largeSize)), 16
+		}
+		if size < headerSize || offset+size > end {
+			return errors.Errorf("invalid box size at %d", offset)
+		}
+		if err := visit(string(header[4:8]), offset+headerSize, size-headerSize); err != nil {
+			return err
+		}
+		offset += size
+	}
+	return nil
+}
+
+func readMovieHeader(r io.ReadSeeker, offset int64, info *Info) error {
+	header, err := readAt(r, offset, 32)
+	if err != nil {
+		return err
+	}
+	var timescale, duration uint64
+	if header[0] == 1 {
+		timescale, duration = uint64(binary.BigEndian.Uint32(header[20:])), binary.BigEndian.Uint64(header[24:])
+	} else {
+		timescale, duration = uint64(binary.BigEndian.Uint32(header[12:])), uint64(binary.BigEndian.Uint32(header[16:]))
+	}
+	// The duration is all ones if it's unknown.
+	if duration != 1<<64-1 && duration != 1<<32-1 {
+		info.Duration = samplesDuration(int64(duration), int64(timescale))
+	}
+	return nil
+}
+
+func readTrackHeader(r io.ReadSeeker, offset int64, info *Info) error {
+	version, err := readAt(r, offset, 1)
+	if err != nil {
+		return err
+	}
+	// The matrix and the dimensions follow the fields of the version.
+	fieldsSize := int64(20)
+	if version[0] == 1 {
+		fieldsSize = 32
+	}
+	header, err := readAt(r, offset+4+fieldsSize+16, 44)
+	if err != nil {
+		return err
+	}
+	// The dimensions are 16.16 fixed-point numbers.
+	width, height := int(binary.BigEndian.Uint32(header[36:])>>16), int(binary.BigEndian.Uint32(header[40:])>>16)
+	// The videos rotated by 90 or 270 degrees, such as the ones recorded by the phones, are displayed in the swapped dimensions.
+	if binary.BigEndian.Uint32(header[0:]) == 0 && binary.BigEndian.Uint32(header[16:]) == 0 {
+		width, height = height, width
+	}
+	info.Width, info.Height = width, height
+	return nil
+}
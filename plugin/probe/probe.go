@@ -0,0 +1,190 @@
+// Package probe sniffs the MIME types of the files by their contents, and probes the dimensions of the images and
+// the videos and the durations of the audio and the videos.
+// The media are probed by their headers in pure Go, so the files are read in part rather than decoded.
+package probe
+
+import (
+	"bytes"
+	"image"
+	// The decoders of the images probed.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// SniffLength is the number of the first bytes the MIME types are sniffed by.
+const SniffLength = 512
+
+// unknownType is the MIME type of the contents which can't be sniffed.
+const unknownType = "application/octet-stream"
+
+var (
+	// ftypBrands are the MIME types of the ISO base media files by their major brands, which aren't sniffed by http.DetectContentType.
+	ftypBrands = map[string]string{
+		"heic": "image/heic",
+		"heix": "image/heic",
+		"mif1": "image/heif",
+		"msf1": "image/heif",
+		"avif": "image/avif",
+		"avis": "image/avif",
+		"qt  ": "video/quicktime",
+		"M4A ": "audio/mp4",
+	}
+	// textApplicationTypes are the application types of the text contents.
+	textApplicationTypes = []string{"application/json", "application/javascript", "application/xml", "application/yaml", "application/x-yaml"}
+	// containerTypes are the MIME types of the containers which may hold either audio or video.
+	containerTypes = []string{"video/mp4", "video/webm", "video/quicktime", "audio/mp4", "application/ogg"}
+
+	probers = map[string]func(io.ReadSeeker) (*Info, error){
+		"image/png":        probeImage,
+		"image/jpeg":       probeImage,
+		"image/gif":        probeImage,
+		"image/webp":       probeImage,
+		"image/bmp":        probeImage,
+		"image/tiff":       probeImage,
+		"video/mp4":        probeMP4,
+		"video/quicktime":  probeMP4,
+		"video/x-m4v":      probeMP4,
+		"video/3gpp":       probeMP4,
+		"audio/mp4":        probeMP4,
+		"audio/x-m4a":      probeMP4,
+		"video/webm":       probeMatroska,
+		"audio/webm":       probeMatroska,
+		"video/x-matroska": probeMatroska,
+		"audio/x-matroska": probeMatroska,
+		"audio/wave":       probeWAV,
+		"audio/wav":        probeWAV,
+		"audio/x-wav":      probeWAV,
+		"audio/vnd.wave":   probeWAV,
+		"audio/mpeg":       probeMP3,
+		"audio/mp3":        probeMP3,
+		"application/ogg":  probeOgg,
+		"audio/ogg":        probeOgg,
+		"audio/opus":       probeOgg,
+		"audio/flac":       probeFLAC,
+		"audio/x-flac":     probeFLAC,
+	}
+)
+
+// Info is the information probed from a media file, in which the fields which don't apply are zero.
+type Info struct {
+	// Width and Height are the dimensions of the images and the videos.
+	Width  int
+	Height int
+	// Duration is the length of the audio and the videos.
+	Duration time.Duration
+}
+
+// Sniff returns the MIME type of the content by its first bytes, up to SniffLength of them.
+// The claimed type is kept as is if it's consistent with the content, e.g. the office documents sniffed as ZIP,
+// or the content is unknown, while the content of the other type such as the HTML claimed as an image is sniffed as it is.
+func Sniff(head []byte, claimed string) string {
+	mediaType := normalize(claimed)
+	detected := detect(head)
+	switch {
+	case mediaType == "":
+		return detected
+	case detected == unknownType, mediaType == detected, isConsistent(mediaType, detected):
+		return claimed
+	default:
+		return detected
+	}
+}
+
+// Probe returns the information of the media of the MIME type, or the zero Info for the other types.
+// The reader is read from its start.
+func Probe(r io.ReadSeeker, mimeType string) (*Info, error) {
+	prober, ok := probers[normalize(mimeType)]
+	if !ok {
+		return &Info{}, nil
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return prober(r)
+}
+
+// normalize returns the MIME type in lower case without the parameters, or empty if it's invalid.
+func normalize(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil || !strings.Contains(mediaType, "/") {
+		return ""
+	}
+	return mediaType
+}
+
+func detect(head []byte) string {
+	if len(head) >= 12 && string(head[4:8]) == "ftyp" {
+		if mimeType, ok := ftypBrands[string(head[8:12])]; ok {
+			return mimeType
+		}
+	}
+	if bytes.HasPrefix(head, []byte("fLaC")) {
+		return "audio/flac"
+	}
+	return normalize(http.DetectContentType(head))
+}
+
+// isConsistent returns whether the claimed type is a more specific type of the content of the detected type.
+func isConsistent(claimed, detected string) bool {
+	group := claimed[:strings.Index(claimed, "/")]
+	switch {
+	case detected == "text/plain" || detected == "text/xml":
+		return group == "text" || contains(textApplicationTypes, claimed) || strings.HasSuffix(claimed, "+xml") || strings.HasSuffix(claimed, "+json")
+	case detected == "text/html":
+		return group == "text"
+	case detected == "application/zip":
+		return group == "application"
+	case contains(containerTypes, detected):
+		return group == "audio" || group == "video"
+	default:
+		return false
+	}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func probeImage(r io.ReadSeeker) (*Info, error) {
+	config, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode image config")
+	}
+	return &Info{Width: config.Width, Height: config.Height}, nil
+}
+
+// readAt reads the bytes at the offset.
+func readAt(r io.ReadSeeker, offset int64, n int) ([]byte, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %d bytes at %d", n, offset)
+	}
+	return buf, nil
+}
+
+// samplesDuration returns the duration of the samples in the sample rate, without overflowing for the long media.
+func samplesDuration(samples, rate int64) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	return time.Duration(samples/rate*int64(time.Second) + samples%rate*int64(time.Second)/rate)
+}
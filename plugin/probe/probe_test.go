@@ -0,0 +1,199 @@
+package probe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSniff(t *testing.T) {
+	pngBlob := &bytes.Buffer{}
+	require.NoError(t, png.Encode(pngBlob, image.NewNRGBA(image.Rect(0, 0, 1, 1))))
+
+	tests := []struct {
+		name     string
+		head     []byte
+		claimed  string
+		expected string
+	}{
+		{name: "image claimed as another image", head: pngBlob.Bytes(), claimed: "image/jpg", expected: "image/png"},
+		{name: "HTML claimed as an image", head: []byte("<html><script>alert(1)</script></html>"), claimed: "image/png", expected: "text/html"},
+		{name: "text claimed as markdown", head: []byte("# memos"), claimed: "text/markdown; charset=utf-8", expected: "text/markdown; charset=utf-8"},
+		{name: "text without the claim", head: []byte("memos"), claimed: "", expected: "text/plain"},
+		{name: "SVG", head: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), claimed: "image/svg+xml", expected: "image/svg+xml"},
+		{name: "office document in ZIP", head: []byte("PK\x03\x04\x14\x00"), claimed: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", expected: "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{name: "audio in MP4", head: buildBox("ftyp", []byte("isommp42\x00\x00\x00\x00isommp42")), claimed: "audio/mp4", expected: "audio/mp4"},
+		{name: "HEIC without the claim", head: buildBox("ftyp", []byte("heic\x00\x00\x00\x00mif1heic")), claimed: "", expected: "image/heic"},
+		{name: "unknown binary", head: []byte("\x00\x01\x02\x03"), claimed: "application/x-memos", expected: "application/x-memos"},
+		{name: "unknown binary without the claim", head: []byte("\x00\x01\x02\x03"), claimed: "invalid", expected: "application/octet-stream"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, Sniff(test.head, test.claimed))
+		})
+	}
+}
+
+func TestProbe(t *testing.T) {
+	pngBlob := &bytes.Buffer{}
+	require.NoError(t, png.Encode(pngBlob, image.NewNRGBA(image.Rect(0, 0, 30, 20))))
+
+	tests := []struct {
+		name     string
+		blob     []byte
+		mimeType string
+		expected *Info
+	}{
+		{name: "PNG", blob: pngBlob.Bytes(), mimeType: "image/png", expected: &Info{Width: 30, Height: 20}},
+		{name: "MP4", blob: buildMP4(false), mimeType: "video/mp4", expected: &Info{Width: 1920, Height: 1080, Duration: 2500 * time.Millisecond}},
+		{name: "MP4 rotated", blob: buildMP4(true), mimeType: "video/quicktime", expected: &Info{Width: 1080, Height: 1920, Duration: 2500 * time.Millisecond}},
+		{name: "WebM", blob: buildWebM(), mimeType: "video/webm", expected: &Info{Width: 640, Height: 360, Duration: 3 * time.Second}},
+		{name: "WAV", blob: buildWAV(), mimeType: "audio/wave", expected: &Info{Duration: 2 * time.Second}},
+		{name: "MP3", blob: buildMP3(false), mimeType: "audio/mpeg", expected: &Info{Duration: 2606250 * time.Microsecond}},
+		{name: "MP3 with Xing header", blob: buildMP3(true), mimeType: "audio/mpeg", expected: &Info{Duration: 26122448979 * time.Nanosecond}},
+		{name: "Opus", blob: buildOpus(), mimeType: "audio/ogg", expected: &Info{Duration: 2 * time.Second}},
+		{name: "FLAC", blob: buildFLAC(), mimeType: "audio/flac", expected: &Info{Duration: 2 * time.Second}},
+		{name: "other type", blob: []byte("memos"), mimeType: "text/plain", expected: &Info{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info, err := Probe(bytes.NewReader(test.blob), test.mimeType)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, info)
+		})
+	}
+
+	_, err := Probe(bytes.NewReader([]byte("memos")), "image/png")
+	require.Error(t, err)
+}
+
+func buildBox(boxType string, payloads ...[]byte) []byte {
+	payload := bytes.Join(payloads, nil)
+	box := binary.BigEndian.AppendUint32(nil, uint32(8+len(payload)))
+	return append(append(box, boxType...), payload...)
+}
+
+// buildMP4 builds an MP4 file of 2.5 seconds with an audio track and a 1920x1080 video track, which may be rotated by 90 degrees.
+func buildMP4(rotated bool) []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:], 2500)
+	tkhd := func(width, height uint32) []byte {
+		header := make([]byte, 84)
+		matrix := []uint32{0x10000, 0, 0, 0, 0x10000, 0, 0, 0, 0x40000000}
+		if rotated {
+			matrix = []uint32{0, 0x10000, 0, 0xFFFF0000, 0, 0, 0, 0, 0x40000000}
+		}
+		for i, value := range matrix {
+			binary.BigEndian.PutUint32(header[40+i*4:], value)
+		}
+		binary.BigEndian.PutUint32(header[76:], width<<16)
+		binary.BigEndian.PutUint32(header[80:], height<<16)
+		return buildBox("tkhd", header)
+	}
+	return bytes.Join([][]byte{
+		buildBox("ftyp", []byte("isom\x00\x00\x02\x00isomiso2mp41")),
+		buildBox("mdat", make([]byte, 1024)),
+		buildBox("moov", buildBox("mvhd", mvhd), buildBox("trak", tkhd(0, 0)), buildBox("trak", tkhd(1920, 1080))),
+	}, nil)
+}
+
+func buildElement(id uint32, data ...[]byte) []byte {
+	payload := bytes.Join(data, nil)
+	element := binary.BigEndian.AppendUint32(nil, id)
+	for len(element) > 1 && element[0] == 0 {
+		element = element[1:]
+	}
+	// The sizes are in 8 bytes.
+	element = append(element, 0x01)
+	element = append(element, binary.BigEndian.AppendUint64(nil, uint64(len(payload)))[1:]...)
+	return append(element, payload...)
+}
+
+// buildWebM builds a WebM file of 3 seconds with a 640x360 video track, in the segment of the unknown size as streamed.
+func buildWebM() []byte {
+	segment := bytes.Join([][]byte{
+		buildElement(idInfo,
+			buildElement(idTimecodeScale, []byte{0x0F, 0x42, 0x40}),
+			buildElement(idDuration, binary.BigEndian.AppendUint64(nil, math.Float64bits(3000))),
+		),
+		buildElement(idTracks, buildElement(idTrackEntry, buildElement(idVideo,
+			buildElement(idPixelWidth, []byte{0x02, 0x80}),
+			buildElement(idPixelHeight, []byte{0x01, 0x68}),
+		))),
+		buildElement(idCluster, make([]byte, 1024)),
+	}, nil)
+	return bytes.Join([][]byte{
+		buildElement(0x1A45DFA3, buildElement(0x4282, []byte("webm"))),
+		{0x18, 0x53, 0x80, 0x67, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+		segment,
+	}, nil)
+}
+
+// buildWAV builds a WAV file of 2 seconds in 8 kHz, 16-bit mono, with a chunk of the odd size before the data.
+func buildWAV() []byte {
+	format := make([]byte, 16)
+	binary.LittleEndian.PutUint16(format[0:], 1)
+	binary.LittleEndian.PutUint16(format[2:], 1)
+	binary.LittleEndian.PutUint32(format[4:], 8000)
+	binary.LittleEndian.PutUint32(format[8:], 16000)
+	chunk := func(id string, data []byte) []byte {
+		chunk := append([]byte(id), binary.LittleEndian.AppendUint32(nil, uint32(len(data)))...)
+		chunk = append(chunk, data...)
+		if len(data)%2 == 1 {
+			chunk = append(chunk, 0)
+		}
+		return chunk
+	}
+	chunks := bytes.Join([][]byte{chunk("fmt ", format), chunk("LIST", []byte("abc")), chunk("data", make([]byte, 32000))}, nil)
+	return bytes.Join([][]byte{[]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(4+len(chunks))), []byte("WAVE"), chunks}, nil)
+}
+
+// buildMP3 builds an MP3 file of 100 frames in 128 kbit/s and 44.1 kHz with the ID3 tags,
+// of which the first frame may be the Xing header telling 1000 frames.
+func buildMP3(xing bool) []byte {
+	blob := []byte("ID3\x03\x00\x00\x00\x00\x00\x14")
+	blob = append(blob, make([]byte, 20)...)
+	for i := 0; i < 100; i++ {
+		frame := make([]byte, 417)
+		copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+		if xing && i == 0 {
+			copy(frame[4+32:], "Xing\x00\x00\x00\x01")
+			binary.BigEndian.PutUint32(frame[4+32+8:], 1000)
+		}
+		blob = append(blob, frame...)
+	}
+	tag := make([]byte, 128)
+	copy(tag, "TAG")
+	return append(blob, tag...)
+}
+
+// buildOpus builds an Ogg Opus file of 2 seconds, with a page in which no packet ends at the end.
+func buildOpus() []byte {
+	page := func(granule int64, packet []byte) []byte {
+		header := make([]byte, 27)
+		copy(header, "OggS")
+		binary.LittleEndian.PutUint64(header[6:], uint64(granule))
+		binary.LittleEndian.PutUint32(header[14:], 1)
+		header[26] = 1
+		return append(append(header, byte(len(packet))), packet...)
+	}
+	head := []byte("OpusHead\x01\x02")
+	head = binary.LittleEndian.AppendUint16(head, 312)
+	head = binary.LittleEndian.AppendUint32(head, 48000)
+	head = append(head, 0, 0, 0)
+	return bytes.Join([][]byte{page(0, head), page(96312, make([]byte, 100)), page(-1, make([]byte, 100))}, nil)
+}
+
+// buildFLAC builds a FLAC file of 2 seconds in 44.1 kHz.
+func buildFLAC() []byte {
+	streamInfo := make([]byte, 34)
+	binary.BigEndian.PutUint64(streamInfo[10:], 44100<<44|1<<41|15<<36|88200)
+	return bytes.Join([][]byte{[]byte("fLaC"), {0x80, 0x00, 0x00, 0x22}, streamInfo}, nil)
+}
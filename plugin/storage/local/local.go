@@ -0,0 +1,148 @@
+// Package local is the storage backend of the local file system.
+package local
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/plugin/storage"
+)
+
+// Backend stores the objects as the files under the root directory.
+// The absolute keys are used as they are, as the resources saved before keep their absolute paths.
+type Backend struct {
+	Root string
+}
+
+func New(root string) *Backend {
+	return &Backend{
+		Root: root,
+	}
+}
+
+// Path returns the path of the file of the key.
+func (b *Backend) Path(key string) string {
+	if filepath.IsAbs(key) {
+		return key
+	}
+	return filepath.Join(b.Root, filepath.FromSlash(key))
+}
+
+// Put writes the object into a temporary file first, so the file of the key is never partially written.
+func (b *Backend) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	path := b.Path(key)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return errors.Wrap(err, "failed to create directory")
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create file")
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return errors.Wrap(err, "failed to write file")
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return errors.Wrap(err, "failed to close file")
+	}
+	// The temporary files are only readable by the owner.
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		os.Remove(file.Name())
+		return errors.Wrap(err, "failed to change file mode")
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		os.Remove(file.Name())
+		return errors.Wrap(err, "failed to rename file")
+	}
+	return nil
+}
+
+func (b *Backend) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	file, err := os.Open(b.Path(key))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return file, nil
+}
+
+func (b *Backend) GetRange(_ context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	file, err := os.Open(b.Path(key))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if length < 0 {
+		return file, nil
+	}
+	return &limitedReadCloser{Reader: io.LimitReader(file, length), Closer: file}, nil
+}
+
+func (b *Backend) Delete(_ context.Context, key string) error {
+	if err := os.Remove(b.Path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (b *Backend) Stat(_ context.Context, key string) (*storage.ObjectInfo, error) {
+	fileInfo, err := os.Stat(b.Path(key))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &storage.ObjectInfo{
+		Size:    fileInfo.Size(),
+		ModTime: fileInfo.ModTime(),
+	}, nil
+}
+
+// List lists the files in the directory, except the temporary ones of Put.
+func (b *Backend) List(_ context.Context, dir string) ([]*storage.Object, error) {
+	entries, err := os.ReadDir(b.Path(dir))
+	if os.IsNotExist(err) {
+		return []*storage.Object{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	objects := []*storage.Object{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, &storage.Object{
+			Key: path.Join(dir, entry.Name()),
+			ObjectInfo: storage.ObjectInfo{
+				Size:    fileInfo.Size(),
+				ModTime: fileInfo.ModTime(),
+			},
+		})
+	}
+	return objects, nil
+}
+
+func convertError(err error) error {
+	if os.IsNotExist(err) {
+		return storage.ErrNotFound
+	}
+	return err
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
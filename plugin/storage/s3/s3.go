@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	s3config "github.com/aws/aws-sdk-go-v2/config"
@@ -13,16 +15,24 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/usememos/memos/plugin/storage"
 )
 
 type Config struct {
-	AccessKey string
-	SecretKey string
-	Bucket    string
-	EndPoint  string
-	Region    string
-	URLPrefix string
-	URLSuffix string
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	Bucket    string `json:"bucket"`
+	EndPoint  string `json:"endPoint"`
+	Region    string `json:"region"`
+	URLPrefix string `json:"urlPrefix"`
+	URLSuffix string `json:"urlSuffix"`
+	// Private keeps the objects private in the bucket, so they are only read through the server.
+	Private bool `json:"private"`
+	// PresignExpiry is the seconds the presigned URLs of the private objects are valid for.
+	// The private objects are proxied by the server instead if it's 0.
+	PresignExpiry int64 `json:"presignExpiry"`
 }
 
 type Client struct {
@@ -85,3 +95,166 @@ func (client *Client) UploadFile(ctx context.Context, filename string, fileType
 	}
 	return link, nil
 }
+
+// GetObjectKey returns the key of the object from its link returned by UploadFile.
+// It returns false if the link is not an object of the bucket.
+func (client *Client) GetObjectKey(link string) (string, bool) {
+	if client.Config.URLPrefix != "" {
+		prefix := client.Config.URLPrefix + "/"
+		if !strings.HasPrefix(link, prefix) || !strings.HasSuffix(link, client.Config.URLSuffix) {
+			return "", false
+		}
+		return strings.TrimSuffix(strings.TrimPrefix(link, prefix), client.Config.URLSuffix), true
+	}
+
+	// The location is either in the path style, e.g. https://endpoint/bucket/key,
+	// or in the virtual hosted style, e.g. https://bucket.endpoint/key.
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", false
+	}
+	endpoint, err := url.Parse(client.Config.EndPoint)
+	if err != nil {
+		return "", false
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	if u.Host == endpoint.Host {
+		if !strings.HasPrefix(key, client.Config.Bucket+"/") {
+			return "", false
+		}
+		return strings.TrimPrefix(key, client.Config.Bucket+"/"), true
+	}
+	if u.Host == client.Config.Bucket+"."+endpoint.Host && key != "" {
+		return key, true
+	}
+	return "", false
+}
+
+func (client *Client) DeleteObject(ctx context.Context, key string) error {
+	_, err := client.Client.DeleteObject(ctx, &awss3.DeleteObjectInput{
+		Bucket: aws.String(client.Config.Bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// PutObject uploads a private object, unlike UploadFile whose objects are public to read.
+func (client *Client) PutObject(ctx context.Context, key string, fileType string, src io.Reader) error {
+	uploader := manager.NewUploader(client.Client)
+	_, err := uploader.Upload(ctx, &awss3.PutObjectInput{
+		Bucket:      aws.String(client.Config.Bucket),
+		Key:         aws.String(key),
+		Body:        src,
+		ContentType: aws.String(fileType),
+	})
+	return err
+}
+
+// GetObject returns the content of the object, which should be closed by the caller.
+func (client *Client) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	output, err := client.Client.GetObject(ctx, &awss3.GetObjectInput{
+		Bucket: aws.String(client.Config.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+// PresignGetObject returns a presigned URL to get the object, which is valid for the duration.
+func (client *Client) PresignGetObject(ctx context.Context, key string, expires time.Duration) (string, error) {
+	presignClient := awss3.NewPresignClient(client.Client)
+	request, err := presignClient.PresignGetObject(ctx, &awss3.GetObjectInput{
+		Bucket: aws.String(client.Config.Bucket),
+		Key:    aws.String(key),
+	}, awss3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+	return request.URL, nil
+}
+
+// List lists the objects under the directory, with the slash as the delimiter of the subdirectories.
+func (client *Client) List(ctx context.Context, dir string) ([]*storage.Object, error) {
+	objects := []*storage.Object{}
+	paginator := awss3.NewListObjectsV2Paginator(client.Client, &awss3.ListObjectsV2Input{
+		Bucket:    aws.String(client.Config.Bucket),
+		Prefix:    aws.String(strings.TrimSuffix(dir, "/") + "/"),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, content := range output.Contents {
+			objects = append(objects, &storage.Object{
+				Key: aws.ToString(content.Key),
+				ObjectInfo: storage.ObjectInfo{
+					Size:    content.Size,
+					ModTime: aws.ToTime(content.LastModified),
+				},
+			})
+		}
+	}
+	return objects, nil
+}
+
+// Put uploads the object privately, which implements storage.Backend.
+func (client *Client) Put(ctx context.Context, key string, r io.Reader, _ int64, contentType string) error {
+	return client.PutObject(ctx, key, contentType, r)
+}
+
+func (client *Client) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, err := client.GetObject(ctx, key)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return body, nil
+}
+
+func (client *Client) GetRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	byteRange := fmt.Sprintf("bytes=%d-", offset)
+	if length >= 0 {
+		if length == 0 {
+			return io.NopCloser(strings.NewReader("")), nil
+		}
+		byteRange = fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	}
+	output, err := client.Client.GetObject(ctx, &awss3.GetObjectInput{
+		Bucket: aws.String(client.Config.Bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(byteRange),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return output.Body, nil
+}
+
+func (client *Client) Delete(ctx context.Context, key string) error {
+	return client.DeleteObject(ctx, key)
+}
+
+func (client *Client) Stat(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	output, err := client.Client.HeadObject(ctx, &awss3.HeadObjectInput{
+		Bucket: aws.String(client.Config.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &storage.ObjectInfo{
+		Size:    output.ContentLength,
+		ModTime: aws.ToTime(output.LastModified),
+	}, nil
+}
+
+func convertError(err error) error {
+	var apiError smithy.APIError
+	if errors.As(err, &apiError) && (apiError.ErrorCode() == "NoSuchKey" || apiError.ErrorCode() == "NotFound") {
+		return storage.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetObjectKey(t *testing.T) {
+	tests := []struct {
+		config *Config
+		link   string
+		key    string
+		ok     bool
+	}{
+		{
+			config: &Config{EndPoint: "https://s3.example.com", Bucket: "memos"},
+			link:   "https://s3.example.com/memos/assets/1700000000_test.png",
+			key:    "assets/1700000000_test.png",
+			ok:     true,
+		},
+		{
+			config: &Config{EndPoint: "https://oss-cn-hangzhou.aliyuncs.com", Bucket: "memos"},
+			link:   "https://memos.oss-cn-hangzhou.aliyuncs.com/test%20file.png",
+			key:    "test file.png",
+			ok:     true,
+		},
+		{
+			config: &Config{EndPoint: "https://s3.example.com", Bucket: "memos", URLPrefix: "https://cdn.example.com", URLSuffix: "?x-oss-process=style/compress"},
+			link:   "https://cdn.example.com/assets/test.png?x-oss-process=style/compress",
+			key:    "assets/test.png",
+			ok:     true,
+		},
+		{
+			config: &Config{EndPoint: "https://s3.example.com", Bucket: "memos"},
+			link:   "https://s3.example.com/other/test.png",
+			ok:     false,
+		},
+		{
+			config: &Config{EndPoint: "https://s3.example.com", Bucket: "memos", URLPrefix: "https://cdn.example.com"},
+			link:   "https://example.com/test.png",
+			ok:     false,
+		},
+	}
+	for _, test := range tests {
+		client := &Client{Config: test.config}
+		key, ok := client.GetObjectKey(test.link)
+		require.Equal(t, test.ok, ok, test.link)
+		require.Equal(t, test.key, key, test.link)
+	}
+}
@@ -0,0 +1,256 @@
+// Package sftp is the storage backend of a SFTP server.
+package sftp
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/usememos/memos/plugin/storage"
+)
+
+const dialTimeout = 10 * time.Second
+
+type Config struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	// Either the password or the private key in PEM is used to authenticate.
+	Password   string `json:"password"`
+	PrivateKey string `json:"privateKey"`
+	// HostKey is the public key of the server in the authorized_keys format, e.g. "ssh-ed25519 AAAA...".
+	// It's required unless InsecureIgnoreHostKey is set.
+	HostKey string `json:"hostKey"`
+	// InsecureIgnoreHostKey skips verifying the server without the host key, e.g. for the servers in the trusted networks.
+	InsecureIgnoreHostKey bool `json:"insecureIgnoreHostKey"`
+}
+
+// ErrHostKeyRequired is returned if neither the host key nor InsecureIgnoreHostKey is set.
+var ErrHostKeyRequired = errors.New("host key is required unless insecureIgnoreHostKey is set")
+
+// Client connects to the server for each operation, so no connection is kept idle.
+// The keys are the paths of the files, relative to the login directory unless they are absolute.
+type Client struct {
+	Config    *Config
+	sshConfig *ssh.ClientConfig
+}
+
+func NewClient(config *Config) (*Client, error) {
+	sshConfig := &ssh.ClientConfig{
+		User:    config.Username,
+		Timeout: dialTimeout,
+	}
+	if config.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(config.PrivateKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid private key")
+		}
+		sshConfig.Auth = append(sshConfig.Auth, ssh.PublicKeys(signer))
+	}
+	if config.Password != "" {
+		sshConfig.Auth = append(sshConfig.Auth, ssh.Password(config.Password))
+	}
+	switch {
+	case config.HostKey != "":
+		hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(config.HostKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid host key")
+		}
+		sshConfig.HostKeyCallback = ssh.FixedHostKey(hostKey)
+	case config.InsecureIgnoreHostKey:
+		sshConfig.HostKeyCallback = ssh.InsecureIgnoreHostKey()
+	default:
+		return nil, ErrHostKeyRequired
+	}
+	return &Client{
+		Config:    config,
+		sshConfig: sshConfig,
+	}, nil
+}
+
+func (c *Client) Put(ctx context.Context, key string, r io.Reader, _ int64, _ string) error {
+	return c.run(ctx, func(client *sftp.Client) error {
+		filePath := key
+		if err := client.MkdirAll(path.Dir(filePath)); err != nil {
+			return errors.Wrap(err, "failed to create directory")
+		}
+		// Write a temporary file first, so the file of the key is never partially written.
+		tempPath := path.Join(path.Dir(filePath), "."+path.Base(filePath)+".tmp")
+		file, err := client.Create(tempPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to create file")
+		}
+		if _, err := file.ReadFrom(r); err != nil {
+			file.Close()
+			_ = client.Remove(tempPath)
+			return errors.Wrap(err, "failed to write file")
+		}
+		if err := file.Close(); err != nil {
+			_ = client.Remove(tempPath)
+			return errors.Wrap(err, "failed to close file")
+		}
+		if err := client.PosixRename(tempPath, filePath); err != nil {
+			// Not all the servers support the POSIX rename extension, with which the existing file is replaced.
+			_ = client.Remove(filePath)
+			if err := client.Rename(tempPath, filePath); err != nil {
+				_ = client.Remove(tempPath)
+				return errors.Wrap(err, "failed to rename file")
+			}
+		}
+		return nil
+	})
+}
+
+func (c *Client) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return c.GetRange(ctx, key, 0, -1)
+}
+
+func (c *Client) GetRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	sshClient, client, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	file, err := client.Open(key)
+	if err != nil {
+		client.Close()
+		sshClient.Close()
+		return nil, convertError(err)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		client.Close()
+		sshClient.Close()
+		return nil, err
+	}
+	var reader io.Reader = file
+	if length >= 0 {
+		reader = io.LimitReader(file, length)
+	}
+	return &fileReadCloser{Reader: reader, closers: []io.Closer{file, client, sshClient}}, nil
+}
+
+func (c *Client) Delete(ctx context.Context, key string) error {
+	return c.run(ctx, func(client *sftp.Client) error {
+		if err := client.Remove(key); err != nil && !errors.Is(convertError(err), storage.ErrNotFound) {
+			return err
+		}
+		return nil
+	})
+}
+
+func (c *Client) Stat(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	var objectInfo *storage.ObjectInfo
+	err := c.run(ctx, func(client *sftp.Client) error {
+		fileInfo, err := client.Stat(key)
+		if err != nil {
+			return convertError(err)
+		}
+		objectInfo = &storage.ObjectInfo{
+			Size:    fileInfo.Size(),
+			ModTime: fileInfo.ModTime(),
+		}
+		return nil
+	})
+	return objectInfo, err
+}
+
+// List lists the files in the directory, except the temporary ones of Put.
+func (c *Client) List(ctx context.Context, dir string) ([]*storage.Object, error) {
+	objects := []*storage.Object{}
+	err := c.run(ctx, func(client *sftp.Client) error {
+		fileInfos, err := client.ReadDir(dir)
+		if err != nil {
+			if errors.Is(convertError(err), storage.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		for _, fileInfo := range fileInfos {
+			if fileInfo.IsDir() || strings.HasPrefix(fileInfo.Name(), ".") {
+				continue
+			}
+			objects = append(objects, &storage.Object{
+				Key: path.Join(dir, fileInfo.Name()),
+				ObjectInfo: storage.ObjectInfo{
+					Size:    fileInfo.Size(),
+					ModTime: fileInfo.ModTime(),
+				},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+func (c *Client) connect(ctx context.Context) (*ssh.Client, *sftp.Client, error) {
+	port := c.Config.Port
+	if port == 0 {
+		port = 22
+	}
+	addr := net.JoinHostPort(c.Config.Host, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to dial %s", addr)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, c.sshConfig)
+	if err != nil {
+		conn.Close()
+		return nil, nil, errors.Wrap(err, "failed to connect ssh")
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, nil, errors.Wrap(err, "failed to start sftp")
+	}
+	return sshClient, client, nil
+}
+
+func (c *Client) run(ctx context.Context, fn func(client *sftp.Client) error) error {
+	sshClient, client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer sshClient.Close()
+	defer client.Close()
+	return fn(client)
+}
+
+func convertError(err error) error {
+	if errors.Is(err, os.ErrNotExist) {
+		return storage.ErrNotFound
+	}
+	var statusError *sftp.StatusError
+	if errors.As(err, &statusError) && statusError.FxCode() == sftp.ErrSSHFxNoSuchFile {
+		return storage.ErrNotFound
+	}
+	return err
+}
+
+type fileReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *fileReadCloser) Close() error {
+	var err error
+	for _, closer := range r.closers {
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+// Package storage defines the backends storing the blobs of the resources, e.g. the local file system or a S3 bucket.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned when the object of the key doesn't exist.
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo is the information of an object.
+type ObjectInfo struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// Object is a listed object with its key.
+type Object struct {
+	Key string
+	ObjectInfo
+}
+
+// Backend stores the objects by their keys, which are slash-separated paths like "assets/image.png".
+type Backend interface {
+	// Put stores the object, replacing the existing one of the key.
+	// The size is -1 if it's unknown.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns the content of the object, which should be closed by the caller.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// GetRange returns the content of the object from the offset in the length, or to the end if the length is -1.
+	GetRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error)
+	// Delete deletes the object, and succeeds if it doesn't exist.
+	Delete(ctx context.Context, key string) error
+	// Stat returns the information of the object.
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+}
+
+// Lister is implemented by the backends which can list their objects, e.g. to prune the backups uploaded to them.
+type Lister interface {
+	// List returns the objects directly in the directory, excluding the subdirectories.
+	// It returns no objects if the directory doesn't exist.
+	List(ctx context.Context, dir string) ([]*Object, error)
+}
@@ -0,0 +1,272 @@
+// Package webdav is the storage backend of a WebDAV server, e.g. Nextcloud or Apache mod_dav.
+package webdav
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/plugin/storage"
+)
+
+type Config struct {
+	// URL is the URL of the directory to store the objects in, e.g. https://cloud.example.com/remote.php/dav/files/user/memos.
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type Client struct {
+	Config *Config
+	client *http.Client
+}
+
+func NewClient(config *Config) (*Client, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid url")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("invalid url scheme %s", u.Scheme)
+	}
+	return &Client{
+		Config: config,
+		client: &http.Client{},
+	}, nil
+}
+
+func (c *Client) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if err := c.makeCollections(ctx, path.Dir(key)); err != nil {
+		return err
+	}
+	request, err := c.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	if size >= 0 {
+		request.ContentLength = size
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	response, err := c.do(request, http.StatusOK, http.StatusCreated, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+func (c *Client) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return c.GetRange(ctx, key, 0, -1)
+}
+
+func (c *Client) GetRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if length == 0 {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+	request, err := c.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 || length > 0 {
+		byteRange := fmt.Sprintf("bytes=%d-", offset)
+		if length > 0 {
+			byteRange += strconv.FormatInt(offset+length-1, 10)
+		}
+		request.Header.Set("Range", byteRange)
+	}
+	response, err := c.do(request, http.StatusOK, http.StatusPartialContent)
+	if err != nil {
+		return nil, err
+	}
+	if request.Header.Get("Range") != "" && response.StatusCode == http.StatusOK {
+		// The server ignores the range, so skip to it.
+		if _, err := io.CopyN(io.Discard, response.Body, offset); err != nil {
+			response.Body.Close()
+			return nil, err
+		}
+		if length > 0 {
+			return &limitedReadCloser{Reader: io.LimitReader(response.Body, length), Closer: response.Body}, nil
+		}
+	}
+	return response.Body, nil
+}
+
+func (c *Client) Delete(ctx context.Context, key string) error {
+	request, err := c.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	response, err := c.do(request, http.StatusOK, http.StatusNoContent, http.StatusAccepted)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+func (c *Client) Stat(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	request, err := c.newRequest(ctx, http.MethodHead, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.do(request, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	objectInfo := &storage.ObjectInfo{
+		Size: response.ContentLength,
+	}
+	if lastModified := response.Header.Get("Last-Modified"); lastModified != "" {
+		if modTime, err := http.ParseTime(lastModified); err == nil {
+			objectInfo.ModTime = modTime
+		}
+	}
+	return objectInfo, nil
+}
+
+// propfindBody requests the properties of the objects listed by List.
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?><propfind xmlns="DAV:"><prop><getcontentlength/><getlastmodified/><resourcetype/></prop></propfind>`
+
+// multistatus is the response of PROPFIND, whose elements are matched by their names in any namespace.
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Prop struct {
+				ContentLength int64  `xml:"getcontentlength"`
+				LastModified  string `xml:"getlastmodified"`
+				ResourceType  struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+			} `xml:"prop"`
+			// Status is like "HTTP/1.1 200 OK", and the properties not found are in the propstat of 404.
+			Status string `xml:"status"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// List lists the objects in the collection of the directory by PROPFIND, skipping the collections in it.
+func (c *Client) List(ctx context.Context, dir string) ([]*storage.Object, error) {
+	dir = strings.TrimSuffix(dir, "/")
+	request, err := c.newRequest(ctx, "PROPFIND", dir+"/", strings.NewReader(propfindBody))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Depth", "1")
+	request.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	response, err := c.do(request, http.StatusMultiStatus)
+	if errors.Is(err, storage.ErrNotFound) {
+		return []*storage.Object{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	result := &multistatus{}
+	if err := xml.NewDecoder(response.Body).Decode(result); err != nil {
+		return nil, errors.Wrap(err, "failed to decode PROPFIND response")
+	}
+
+	objects := []*storage.Object{}
+	for _, item := range result.Responses {
+		href, err := url.PathUnescape(item.Href)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid href %s", item.Href)
+		}
+		object, isCollection := &storage.Object{Key: path.Join(dir, path.Base(href))}, false
+		for _, propstat := range item.Propstat {
+			if !strings.Contains(propstat.Status, " 200 ") {
+				continue
+			}
+			isCollection = propstat.Prop.ResourceType.Collection != nil
+			object.Size = propstat.Prop.ContentLength
+			if modTime, err := http.ParseTime(propstat.Prop.LastModified); err == nil {
+				object.ModTime = modTime
+			}
+		}
+		if !isCollection {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+// makeCollections creates the collections of the directory one by one, as MKCOL doesn't create the parents.
+func (c *Client) makeCollections(ctx context.Context, dir string) error {
+	if dir == "." || dir == "/" || dir == "" {
+		return nil
+	}
+	current := ""
+	for _, name := range strings.Split(strings.Trim(dir, "/"), "/") {
+		current = path.Join(current, name)
+		request, err := c.newRequest(ctx, "MKCOL", current+"/", nil)
+		if err != nil {
+			return err
+		}
+		response, err := c.client.Do(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to make collection")
+		}
+		response.Body.Close()
+		// 405 Method Not Allowed is returned if the collection exists.
+		if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusMethodNotAllowed {
+			return errors.Errorf("failed to make collection %s, status %s", current, response.Status)
+		}
+	}
+	return nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	u, err := url.Parse(c.Config.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid url")
+	}
+	u = u.JoinPath(key)
+	if strings.HasSuffix(key, "/") && !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Config.Username != "" || c.Config.Password != "" {
+		request.SetBasicAuth(c.Config.Username, c.Config.Password)
+	}
+	return request, nil
+}
+
+// do sends the request and returns the response if its status is one of the expected ones.
+func (c *Client) do(request *http.Request, statuses ...int) (*http.Response, error) {
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		if response.StatusCode == status {
+			return response, nil
+		}
+	}
+	response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, storage.ErrNotFound
+	}
+	return nil, errors.Errorf("unexpected status %s of %s %s", response.Status, request.Method, request.URL.Path)
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
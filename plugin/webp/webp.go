@@ -0,0 +1,408 @@
+// Package webp encodes the images in the lossless format of WebP, i.e. VP8L.
+// Only the subtract green and the predictor transforms are used, without the backward references or the color cache,
+// which keeps the encoder simple while it's still smaller than PNG for most of the thumbnails.
+package webp
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxDimension is the max width and height of the images, which are written in 14 bits.
+	maxDimension = 1 << 14
+	// predictorBits is the log-2 size of the tiles of the predictor transform, which is the max allowed.
+	predictorBits = 9
+	// predictorMode is the predictor of all the tiles, which predicts the pixel by the gradient clamped, i.e. L + T - TL.
+	predictorMode = 12
+
+	transformTypePredictor     = 0
+	transformTypeSubtractGreen = 2
+
+	// The alphabet sizes of the prefix codes, where the green one includes the 24 length prefix codes.
+	greenAlphabetSize    = 256 + 24
+	colorAlphabetSize    = 256
+	distanceAlphabetSize = 40
+	maxCodeLength        = 15
+	maxCodeLengthCodeLen = 7
+)
+
+// codeLengthCodeOrder is the order in which the lengths of the code length codes are written.
+var codeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// Encode writes the image to w in the lossless WebP format.
+func Encode(w io.Writer, m image.Image) error {
+	bounds := m.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > maxDimension || height > maxDimension {
+		return errors.Errorf("invalid image size %dx%d", width, height)
+	}
+
+	// The pixels are non-premultiplied in the order of RGBA, as the ones decoded.
+	src := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), m, bounds.Min, draw.Src)
+	pix := src.Pix
+	alphaUsed := uint32(0)
+	for p := 0; p < len(pix); p += 4 {
+		if pix[p+3] != 0xff {
+			alphaUsed = 1
+		}
+		pix[p+0] -= pix[p+1]
+		pix[p+2] -= pix[p+1]
+	}
+	residuals := predict(pix, width, height)
+
+	bw := &bitWriter{}
+	bw.write(0x2f, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	bw.write(alphaUsed, 1)
+	bw.write(0, 3)
+
+	// The transforms are inverted by the decoder in reverse order, i.e. the predictor first.
+	bw.write(1, 1)
+	bw.write(transformTypeSubtractGreen, 2)
+	bw.write(1, 1)
+	bw.write(transformTypePredictor, 2)
+	bw.write(predictorBits-2, 3)
+	// All the tiles use the same predictor, so each prefix code of the sub-image has the only symbol, which takes no bits.
+	bw.write(0, 1)
+	for _, symbol := range []uint32{predictorMode, 0, 0, 0, 0} {
+		writeSimpleCode(bw, []uint32{symbol})
+	}
+	bw.write(0, 1)
+
+	// The main image is written without the color cache and the meta prefix codes.
+	bw.write(0, 1)
+	bw.write(0, 1)
+	histograms := [5][]uint32{
+		make([]uint32, greenAlphabetSize),
+		make([]uint32, colorAlphabetSize),
+		make([]uint32, colorAlphabetSize),
+		make([]uint32, colorAlphabetSize),
+		make([]uint32, distanceAlphabetSize),
+	}
+	for p := 0; p < len(residuals); p += 4 {
+		histograms[0][residuals[p+1]]++
+		histograms[1][residuals[p+0]]++
+		histograms[2][residuals[p+2]]++
+		histograms[3][residuals[p+3]]++
+	}
+	codes := [5]*prefixCode{}
+	for i, histogram := range histograms {
+		codes[i] = writePrefixCode(bw, histogram)
+	}
+	for p := 0; p < len(residuals); p += 4 {
+		codes[0].write(bw, residuals[p+1])
+		codes[1].write(bw, residuals[p+0])
+		codes[2].write(bw, residuals[p+2])
+		codes[3].write(bw, residuals[p+3])
+	}
+	data := bw.bytes()
+
+	header := make([]byte, 20)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(12+len(data)+len(data)%2))
+	copy(header[8:16], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:20], uint32(len(data)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if len(data)%2 == 1 {
+		data = append(data, 0)
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return nil
+}
+
+// predict returns the residuals of the pixels from their predictions, by the same rules as the decoder:
+// the top-left pixel is predicted as opaque black, the rest of the first row by the left pixels,
+// the first column by the top pixels, and the others by predictorMode.
+func predict(pix []byte, width, height int) []byte {
+	residuals := make([]byte, len(pix))
+	stride := 4 * width
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p := y*stride + 4*x
+			for c := 0; c < 4; c++ {
+				var prediction byte
+				switch {
+				case x == 0 && y == 0:
+					if c == 3 {
+						prediction = 0xff
+					}
+				case y == 0:
+					prediction = pix[p-4+c]
+				case x == 0:
+					prediction = pix[p-stride+c]
+				default:
+					prediction = clampAddSubtractFull(pix[p-4+c], pix[p-stride+c], pix[p-stride-4+c])
+				}
+				residuals[p+c] = pix[p+c] - prediction
+			}
+		}
+	}
+	return residuals
+}
+
+func clampAddSubtractFull(left, top, topLeft byte) byte {
+	value := int(left) + int(top) - int(topLeft)
+	if value < 0 {
+		return 0
+	}
+	if value > 0xff {
+		return 0xff
+	}
+	return byte(value)
+}
+
+// prefixCode is the canonical prefix code of the symbols, whose codes are reversed to be written from the least significant bit.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint32
+}
+
+func (c *prefixCode) write(bw *bitWriter, symbol byte) {
+	bw.write(c.codes[symbol], uint(c.lengths[symbol]))
+}
+
+// writePrefixCode writes the prefix code of the histogram, and returns it to write the symbols.
+func writePrefixCode(bw *bitWriter, histogram []uint32) *prefixCode {
+	symbols := []uint32{}
+	for symbol, count := range histogram {
+		if count > 0 {
+			symbols = append(symbols, uint32(symbol))
+		}
+	}
+	if len(symbols) <= 2 {
+		return writeSimpleCode(bw, symbols)
+	}
+
+	lengths := buildCodeLengths(histogram, maxCodeLength)
+	tokens := encodeCodeLengths(lengths)
+	tokenHistogram := make([]uint32, len(codeLengthCodeOrder))
+	for _, token := range tokens {
+		tokenHistogram[token.symbol]++
+	}
+	tokenCode := newPrefixCode(buildCodeLengths(tokenHistogram, maxCodeLengthCodeLen))
+
+	bw.write(0, 1)
+	count := 4
+	for i, symbol := range codeLengthCodeOrder {
+		if tokenCode.lengths[symbol] > 0 && i+1 > count {
+			count = i + 1
+		}
+	}
+	bw.write(uint32(count-4), 4)
+	for _, symbol := range codeLengthCodeOrder[:count] {
+		bw.write(uint32(tokenCode.lengths[symbol]), 3)
+	}
+	// The lengths of all the symbols are written, rather than the max symbol.
+	bw.write(0, 1)
+	for _, token := range tokens {
+		tokenCode.write(bw, token.symbol)
+		switch token.symbol {
+		case 17:
+			bw.write(token.extra, 3)
+		case 18:
+			bw.write(token.extra, 7)
+		}
+	}
+	return newPrefixCode(lengths)
+}
+
+// writeSimpleCode writes the prefix code of one or two symbols, where the only symbol takes no bits.
+func writeSimpleCode(bw *bitWriter, symbols []uint32) *prefixCode {
+	if len(symbols) == 0 {
+		symbols = []uint32{0}
+	}
+	bw.write(1, 1)
+	bw.write(uint32(len(symbols)-1), 1)
+	bw.write(1, 1)
+	code := &prefixCode{
+		lengths: make([]uint8, colorAlphabetSize),
+		codes:   make([]uint32, colorAlphabetSize),
+	}
+	for i, symbol := range symbols {
+		bw.write(symbol, 8)
+		code.lengths[symbol] = uint8(len(symbols) - 1)
+		code.codes[symbol] = uint32(i)
+	}
+	return code
+}
+
+func newPrefixCode(lengths []uint8) *prefixCode {
+	// The codes are assigned in the order of the lengths and then the symbols, as the decoder does.
+	count := [maxCodeLength + 1]uint32{}
+	for _, length := range lengths {
+		count[length]++
+	}
+	count[0] = 0
+	next := [maxCodeLength + 1]uint32{}
+	code := uint32(0)
+	for length := 1; length <= maxCodeLength; length++ {
+		code = (code + count[length-1]) << 1
+		next[length] = code
+	}
+	codes := make([]uint32, len(lengths))
+	for symbol, length := range lengths {
+		if length == 0 {
+			continue
+		}
+		codes[symbol] = reverseBits(next[length], length)
+		next[length]++
+	}
+	return &prefixCode{
+		lengths: lengths,
+		codes:   codes,
+	}
+}
+
+func reverseBits(code uint32, length uint8) uint32 {
+	reversed := uint32(0)
+	for i := uint8(0); i < length; i++ {
+		reversed = reversed<<1 | code&1
+		code >>= 1
+	}
+	return reversed
+}
+
+// codeLengthToken is a symbol of the code length code, with the extra bits of the repeated zeros.
+type codeLengthToken struct {
+	symbol byte
+	extra  uint32
+}
+
+// encodeCodeLengths encodes the code lengths, where the runs of zeros are repeated by the symbols 17 and 18.
+func encodeCodeLengths(lengths []uint8) []codeLengthToken {
+	tokens := []codeLengthToken{}
+	for i := 0; i < len(lengths); {
+		if lengths[i] != 0 {
+			tokens = append(tokens, codeLengthToken{symbol: lengths[i]})
+			i++
+			continue
+		}
+		run := 1
+		for i+run < len(lengths) && lengths[i+run] == 0 && run < 138 {
+			run++
+		}
+		switch {
+		case run >= 11:
+			tokens = append(tokens, codeLengthToken{symbol: 18, extra: uint32(run - 11)})
+		case run >= 3:
+			tokens = append(tokens, codeLengthToken{symbol: 17, extra: uint32(run - 3)})
+		default:
+			for j := 0; j < run; j++ {
+				tokens = append(tokens, codeLengthToken{symbol: 0})
+			}
+		}
+		i += run
+	}
+	return tokens
+}
+
+// buildCodeLengths returns the lengths of the Huffman codes of the histogram, limited to the max length.
+// The counts are flattened until the lengths fit, and the only symbol is paired with another to keep the code complete.
+func buildCodeLengths(histogram []uint32, maxLength uint8) []uint8 {
+	lengths := make([]uint8, len(histogram))
+	for minCount := 1; ; minCount *= 2 {
+		nodes := huffmanHeap{}
+		for symbol, count := range histogram {
+			if count > 0 {
+				nodes = append(nodes, &huffmanNode{count: max(int(count), minCount), symbol: symbol, order: symbol})
+			}
+		}
+		switch len(nodes) {
+		case 0:
+			return lengths
+		case 1:
+			lengths[nodes[0].symbol] = 1
+			if nodes[0].symbol == 0 {
+				lengths[1] = 1
+			} else {
+				lengths[0] = 1
+			}
+			return lengths
+		}
+
+		heap.Init(&nodes)
+		for order := len(histogram); nodes.Len() > 1; order++ {
+			left := heap.Pop(&nodes).(*huffmanNode)
+			right := heap.Pop(&nodes).(*huffmanNode)
+			heap.Push(&nodes, &huffmanNode{count: left.count + right.count, symbol: -1, order: order, left: left, right: right})
+		}
+		for i := range lengths {
+			lengths[i] = 0
+		}
+		if nodes[0].assignLengths(lengths, 0) <= int(maxLength) {
+			return lengths
+		}
+	}
+}
+
+type huffmanNode struct {
+	count  int
+	symbol int
+	// order breaks the ties of the counts, so the codes are deterministic.
+	order       int
+	left, right *huffmanNode
+}
+
+// assignLengths sets the lengths of the leaves under the node at the depth, and returns the max one.
+func (n *huffmanNode) assignLengths(lengths []uint8, depth int) int {
+	if n.left == nil {
+		lengths[n.symbol] = uint8(min(depth, 0xff))
+		return depth
+	}
+	return max(n.left.assignLengths(lengths, depth+1), n.right.assignLengths(lengths, depth+1))
+}
+
+type huffmanHeap []*huffmanNode
+
+func (h huffmanHeap) Len() int { return len(h) }
+func (h huffmanHeap) Less(i, j int) bool {
+	if h[i].count != h[j].count {
+		return h[i].count < h[j].count
+	}
+	return h[i].order < h[j].order
+}
+func (h huffmanHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *huffmanHeap) Push(x any)   { *h = append(*h, x.(*huffmanNode)) }
+func (h *huffmanHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// bitWriter writes the bits from the least significant one of each byte, as VP8L reads them.
+type bitWriter struct {
+	buf  []byte
+	acc  uint64
+	nBit uint
+}
+
+func (w *bitWriter) write(value uint32, n uint) {
+	w.acc |= uint64(value) << w.nBit
+	w.nBit += n
+	for w.nBit >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nBit -= 8
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.nBit > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nBit = 0, 0
+	}
+	return w.buf
+}
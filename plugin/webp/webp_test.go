@@ -0,0 +1,82 @@
+package webp
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/webp"
+)
+
+func TestEncode(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		pixel  func(x, y int) color.NRGBA
+	}{
+		{
+			name:   "single pixel",
+			width:  1,
+			height: 1,
+			pixel: func(_, _ int) color.NRGBA {
+				return color.NRGBA{R: 10, G: 20, B: 30, A: 0xff}
+			},
+		},
+		{
+			name:   "solid color",
+			width:  64,
+			height: 48,
+			pixel: func(_, _ int) color.NRGBA {
+				return color.NRGBA{R: 200, G: 100, B: 50, A: 0xff}
+			},
+		},
+		{
+			name:   "gradient across tiles",
+			width:  600,
+			height: 530,
+			pixel: func(x, y int) color.NRGBA {
+				return color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(x + y), A: 0xff}
+			},
+		},
+		{
+			name:   "transparent noise",
+			width:  97,
+			height: 31,
+			pixel: func(_, _ int) color.NRGBA {
+				return color.NRGBA{R: uint8(random.Intn(256)), G: uint8(random.Intn(256)), B: uint8(random.Intn(256)), A: uint8(random.Intn(256))}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := image.NewNRGBA(image.Rect(0, 0, test.width, test.height))
+			for y := 0; y < test.height; y++ {
+				for x := 0; x < test.width; x++ {
+					src.SetNRGBA(x, y, test.pixel(x, y))
+				}
+			}
+			buf := &bytes.Buffer{}
+			require.NoError(t, Encode(buf, src))
+
+			decoded, err := webp.Decode(buf)
+			require.NoError(t, err)
+			require.Equal(t, src.Bounds(), decoded.Bounds())
+			for y := 0; y < test.height; y++ {
+				for x := 0; x < test.width; x++ {
+					require.Equal(t, src.NRGBAAt(x, y), decoded.(*image.NRGBA).NRGBAAt(x, y), "pixel (%d, %d)", x, y)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeInvalidSize(t *testing.T) {
+	require.Error(t, Encode(&bytes.Buffer{}, image.NewNRGBA(image.Rect(0, 0, 0, 10))))
+	require.Error(t, Encode(&bytes.Buffer{}, image.NewNRGBA(image.Rect(0, 0, maxDimension+1, 1))))
+}
@@ -19,11 +19,17 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	MemoService_CreateMemo_FullMethodName        = "/memos.api.v2.MemoService/CreateMemo"
-	MemoService_ListMemos_FullMethodName         = "/memos.api.v2.MemoService/ListMemos"
-	MemoService_GetMemo_FullMethodName           = "/memos.api.v2.MemoService/GetMemo"
-	MemoService_CreateMemoComment_FullMethodName = "/memos.api.v2.MemoService/CreateMemoComment"
-	MemoService_ListMemoComments_FullMethodName  = "/memos.api.v2.MemoService/ListMemoComments"
+	MemoService_CreateMemo_FullMethodName          = "/memos.api.v2.MemoService/CreateMemo"
+	MemoService_ListMemos_FullMethodName           = "/memos.api.v2.MemoService/ListMemos"
+	MemoService_SearchMemos_FullMethodName         = "/memos.api.v2.MemoService/SearchMemos"
+	MemoService_GetMemo_FullMethodName             = "/memos.api.v2.MemoService/GetMemo"
+	MemoService_CreateMemoComment_FullMethodName   = "/memos.api.v2.MemoService/CreateMemoComment"
+	MemoService_ListMemoComments_FullMethodName    = "/memos.api.v2.MemoService/ListMemoComments"
+	MemoService_ToggleMemoTask_FullMethodName      = "/memos.api.v2.MemoService/ToggleMemoTask"
+	MemoService_ListTodos_FullMethodName           = "/memos.api.v2.MemoService/ListTodos"
+	MemoService_ListMemoRevisions_FullMethodName   = "/memos.api.v2.MemoService/ListMemoRevisions"
+	MemoService_GetMemoRevisionDiff_FullMethodName = "/memos.api.v2.MemoService/GetMemoRevisionDiff"
+	MemoService_RestoreMemoRevision_FullMethodName = "/memos.api.v2.MemoService/RestoreMemoRevision"
 )
 
 // MemoServiceClient is the client API for MemoService service.
@@ -32,9 +38,21 @@ const (
 type MemoServiceClient interface {
 	CreateMemo(ctx context.Context, in *CreateMemoRequest, opts ...grpc.CallOption) (*CreateMemoResponse, error)
 	ListMemos(ctx context.Context, in *ListMemosRequest, opts ...grpc.CallOption) (*ListMemosResponse, error)
+	// SearchMemos searches the memos visible to the current user by full-text, ordered by relevance.
+	SearchMemos(ctx context.Context, in *SearchMemosRequest, opts ...grpc.CallOption) (*SearchMemosResponse, error)
 	GetMemo(ctx context.Context, in *GetMemoRequest, opts ...grpc.CallOption) (*GetMemoResponse, error)
 	CreateMemoComment(ctx context.Context, in *CreateMemoCommentRequest, opts ...grpc.CallOption) (*CreateMemoCommentResponse, error)
 	ListMemoComments(ctx context.Context, in *ListMemoCommentsRequest, opts ...grpc.CallOption) (*ListMemoCommentsResponse, error)
+	// ToggleMemoTask toggles the completion of the task at the index in the memo content.
+	ToggleMemoTask(ctx context.Context, in *ToggleMemoTaskRequest, opts ...grpc.CallOption) (*ToggleMemoTaskResponse, error)
+	// ListTodos lists the unchecked tasks across all memos of the current user.
+	ListTodos(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (*ListTodosResponse, error)
+	// ListMemoRevisions lists the previous revisions of the memo, the latest first.
+	ListMemoRevisions(ctx context.Context, in *ListMemoRevisionsRequest, opts ...grpc.CallOption) (*ListMemoRevisionsResponse, error)
+	// GetMemoRevisionDiff gets the unified diff of the content between two revisions of the memo.
+	GetMemoRevisionDiff(ctx context.Context, in *GetMemoRevisionDiffRequest, opts ...grpc.CallOption) (*GetMemoRevisionDiffResponse, error)
+	// RestoreMemoRevision restores the content and visibility of the memo from the revision as a new edit.
+	RestoreMemoRevision(ctx context.Context, in *RestoreMemoRevisionRequest, opts ...grpc.CallOption) (*RestoreMemoRevisionResponse, error)
 }
 
 type memoServiceClient struct {
@@ -63,6 +81,15 @@ func (c *memoServiceClient) ListMemos(ctx context.Context, in *ListMemosRequest,
 	return out, nil
 }
 
+func (c *memoServiceClient) SearchMemos(ctx context.Context, in *SearchMemosRequest, opts ...grpc.CallOption) (*SearchMemosResponse, error) {
+	out := new(SearchMemosResponse)
+	err := c.cc.Invoke(ctx, MemoService_SearchMemos_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *memoServiceClient) GetMemo(ctx context.Context, in *GetMemoRequest, opts ...grpc.CallOption) (*GetMemoResponse, error) {
 	out := new(GetMemoResponse)
 	err := c.cc.Invoke(ctx, MemoService_GetMemo_FullMethodName, in, out, opts...)
@@ -90,15 +117,72 @@ func (c *memoServiceClient) ListMemoComments(ctx context.Context, in *ListMemoCo
 	return out, nil
 }
 
+func (c *memoServiceClient) ToggleMemoTask(ctx context.Context, in *ToggleMemoTaskRequest, opts ...grpc.CallOption) (*ToggleMemoTaskResponse, error) {
+	out := new(ToggleMemoTaskResponse)
+	err := c.cc.Invoke(ctx, MemoService_ToggleMemoTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoServiceClient) ListTodos(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (*ListTodosResponse, error) {
+	out := new(ListTodosResponse)
+	err := c.cc.Invoke(ctx, MemoService_ListTodos_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoServiceClient) ListMemoRevisions(ctx context.Context, in *ListMemoRevisionsRequest, opts ...grpc.CallOption) (*ListMemoRevisionsResponse, error) {
+	out := new(ListMemoRevisionsResponse)
+	err := c.cc.Invoke(ctx, MemoService_ListMemoRevisions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoServiceClient) GetMemoRevisionDiff(ctx context.Context, in *GetMemoRevisionDiffRequest, opts ...grpc.CallOption) (*GetMemoRevisionDiffResponse, error) {
+	out := new(GetMemoRevisionDiffResponse)
+	err := c.cc.Invoke(ctx, MemoService_GetMemoRevisionDiff_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoServiceClient) RestoreMemoRevision(ctx context.Context, in *RestoreMemoRevisionRequest, opts ...grpc.CallOption) (*RestoreMemoRevisionResponse, error) {
+	out := new(RestoreMemoRevisionResponse)
+	err := c.cc.Invoke(ctx, MemoService_RestoreMemoRevision_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MemoServiceServer is the server API for MemoService service.
 // All implementations must embed UnimplementedMemoServiceServer
 // for forward compatibility
 type MemoServiceServer interface {
 	CreateMemo(context.Context, *CreateMemoRequest) (*CreateMemoResponse, error)
 	ListMemos(context.Context, *ListMemosRequest) (*ListMemosResponse, error)
+	// SearchMemos searches the memos visible to the current user by full-text, ordered by relevance.
+	SearchMemos(context.Context, *SearchMemosRequest) (*SearchMemosResponse, error)
 	GetMemo(context.Context, *GetMemoRequest) (*GetMemoResponse, error)
 	CreateMemoComment(context.Context, *CreateMemoCommentRequest) (*CreateMemoCommentResponse, error)
 	ListMemoComments(context.Context, *ListMemoCommentsRequest) (*ListMemoCommentsResponse, error)
+	// ToggleMemoTask toggles the completion of the task at the index in the memo content.
+	ToggleMemoTask(context.Context, *ToggleMemoTaskRequest) (*ToggleMemoTaskResponse, error)
+	// ListTodos lists the unchecked tasks across all memos of the current user.
+	ListTodos(context.Context, *ListTodosRequest) (*ListTodosResponse, error)
+	// ListMemoRevisions lists the previous revisions of the memo, the latest first.
+	ListMemoRevisions(context.Context, *ListMemoRevisionsRequest) (*ListMemoRevisionsResponse, error)
+	// GetMemoRevisionDiff gets the unified diff of the content between two revisions of the memo.
+	GetMemoRevisionDiff(context.Context, *GetMemoRevisionDiffRequest) (*GetMemoRevisionDiffResponse, error)
+	// RestoreMemoRevision restores the content and visibility of the memo from the revision as a new edit.
+	RestoreMemoRevision(context.Context, *RestoreMemoRevisionRequest) (*RestoreMemoRevisionResponse, error)
 	mustEmbedUnimplementedMemoServiceServer()
 }
 
@@ -112,6 +196,9 @@ func (UnimplementedMemoServiceServer) CreateMemo(context.Context, *CreateMemoReq
 func (UnimplementedMemoServiceServer) ListMemos(context.Context, *ListMemosRequest) (*ListMemosResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListMemos not implemented")
 }
+func (UnimplementedMemoServiceServer) SearchMemos(context.Context, *SearchMemosRequest) (*SearchMemosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchMemos not implemented")
+}
 func (UnimplementedMemoServiceServer) GetMemo(context.Context, *GetMemoRequest) (*GetMemoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMemo not implemented")
 }
@@ -121,6 +208,21 @@ func (UnimplementedMemoServiceServer) CreateMemoComment(context.Context, *Create
 func (UnimplementedMemoServiceServer) ListMemoComments(context.Context, *ListMemoCommentsRequest) (*ListMemoCommentsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListMemoComments not implemented")
 }
+func (UnimplementedMemoServiceServer) ToggleMemoTask(context.Context, *ToggleMemoTaskRequest) (*ToggleMemoTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ToggleMemoTask not implemented")
+}
+func (UnimplementedMemoServiceServer) ListTodos(context.Context, *ListTodosRequest) (*ListTodosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodos not implemented")
+}
+func (UnimplementedMemoServiceServer) ListMemoRevisions(context.Context, *ListMemoRevisionsRequest) (*ListMemoRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMemoRevisions not implemented")
+}
+func (UnimplementedMemoServiceServer) GetMemoRevisionDiff(context.Context, *GetMemoRevisionDiffRequest) (*GetMemoRevisionDiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMemoRevisionDiff not implemented")
+}
+func (UnimplementedMemoServiceServer) RestoreMemoRevision(context.Context, *RestoreMemoRevisionRequest) (*RestoreMemoRevisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreMemoRevision not implemented")
+}
 func (UnimplementedMemoServiceServer) mustEmbedUnimplementedMemoServiceServer() {}
 
 // UnsafeMemoServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -170,6 +272,24 @@ func _MemoService_ListMemos_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MemoService_SearchMemos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchMemosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoServiceServer).SearchMemos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoService_SearchMemos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoServiceServer).SearchMemos(ctx, req.(*SearchMemosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MemoService_GetMemo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetMemoRequest)
 	if err := dec(in); err != nil {
@@ -224,6 +344,96 @@ func _MemoService_ListMemoComments_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MemoService_ToggleMemoTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ToggleMemoTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoServiceServer).ToggleMemoTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoService_ToggleMemoTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoServiceServer).ToggleMemoTask(ctx, req.(*ToggleMemoTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoService_ListTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoServiceServer).ListTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoService_ListTodos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoServiceServer).ListTodos(ctx, req.(*ListTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoService_ListMemoRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMemoRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoServiceServer).ListMemoRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoService_ListMemoRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoServiceServer).ListMemoRevisions(ctx, req.(*ListMemoRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoService_GetMemoRevisionDiff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMemoRevisionDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoServiceServer).GetMemoRevisionDiff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoService_GetMemoRevisionDiff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoServiceServer).GetMemoRevisionDiff(ctx, req.(*GetMemoRevisionDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoService_RestoreMemoRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreMemoRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoServiceServer).RestoreMemoRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoService_RestoreMemoRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoServiceServer).RestoreMemoRevision(ctx, req.(*RestoreMemoRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MemoService_ServiceDesc is the grpc.ServiceDesc for MemoService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -239,6 +449,10 @@ var MemoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListMemos",
 			Handler:    _MemoService_ListMemos_Handler,
 		},
+		{
+			MethodName: "SearchMemos",
+			Handler:    _MemoService_SearchMemos_Handler,
+		},
 		{
 			MethodName: "GetMemo",
 			Handler:    _MemoService_GetMemo_Handler,
@@ -251,6 +465,26 @@ var MemoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListMemoComments",
 			Handler:    _MemoService_ListMemoComments_Handler,
 		},
+		{
+			MethodName: "ToggleMemoTask",
+			Handler:    _MemoService_ToggleMemoTask_Handler,
+		},
+		{
+			MethodName: "ListTodos",
+			Handler:    _MemoService_ListTodos_Handler,
+		},
+		{
+			MethodName: "ListMemoRevisions",
+			Handler:    _MemoService_ListMemoRevisions_Handler,
+		},
+		{
+			MethodName: "GetMemoRevisionDiff",
+			Handler:    _MemoService_GetMemoRevisionDiff_Handler,
+		},
+		{
+			MethodName: "RestoreMemoRevision",
+			Handler:    _MemoService_RestoreMemoRevision_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v2/memo_service.proto",
@@ -25,6 +25,10 @@ const (
 	UserService_ListUserAccessTokens_FullMethodName  = "/memos.api.v2.UserService/ListUserAccessTokens"
 	UserService_CreateUserAccessToken_FullMethodName = "/memos.api.v2.UserService/CreateUserAccessToken"
 	UserService_DeleteUserAccessToken_FullMethodName = "/memos.api.v2.UserService/DeleteUserAccessToken"
+	UserService_ListUserSavedFilters_FullMethodName  = "/memos.api.v2.UserService/ListUserSavedFilters"
+	UserService_CreateUserSavedFilter_FullMethodName = "/memos.api.v2.UserService/CreateUserSavedFilter"
+	UserService_UpdateUserSavedFilter_FullMethodName = "/memos.api.v2.UserService/UpdateUserSavedFilter"
+	UserService_DeleteUserSavedFilter_FullMethodName = "/memos.api.v2.UserService/DeleteUserSavedFilter"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -40,6 +44,14 @@ type UserServiceClient interface {
 	CreateUserAccessToken(ctx context.Context, in *CreateUserAccessTokenRequest, opts ...grpc.CallOption) (*CreateUserAccessTokenResponse, error)
 	// DeleteUserAccessToken deletes an access token for a user.
 	DeleteUserAccessToken(ctx context.Context, in *DeleteUserAccessTokenRequest, opts ...grpc.CallOption) (*DeleteUserAccessTokenResponse, error)
+	// ListUserSavedFilters returns a list of saved memo filters for a user.
+	ListUserSavedFilters(ctx context.Context, in *ListUserSavedFiltersRequest, opts ...grpc.CallOption) (*ListUserSavedFiltersResponse, error)
+	// CreateUserSavedFilter creates a new saved memo filter for a user.
+	CreateUserSavedFilter(ctx context.Context, in *CreateUserSavedFilterRequest, opts ...grpc.CallOption) (*CreateUserSavedFilterResponse, error)
+	// UpdateUserSavedFilter updates a saved memo filter of a user.
+	UpdateUserSavedFilter(ctx context.Context, in *UpdateUserSavedFilterRequest, opts ...grpc.CallOption) (*UpdateUserSavedFilterResponse, error)
+	// DeleteUserSavedFilter deletes a saved memo filter of a user.
+	DeleteUserSavedFilter(ctx context.Context, in *DeleteUserSavedFilterRequest, opts ...grpc.CallOption) (*DeleteUserSavedFilterResponse, error)
 }
 
 type userServiceClient struct {
@@ -104,6 +116,42 @@ func (c *userServiceClient) DeleteUserAccessToken(ctx context.Context, in *Delet
 	return out, nil
 }
 
+func (c *userServiceClient) ListUserSavedFilters(ctx context.Context, in *ListUserSavedFiltersRequest, opts ...grpc.CallOption) (*ListUserSavedFiltersResponse, error) {
+	out := new(ListUserSavedFiltersResponse)
+	err := c.cc.Invoke(ctx, UserService_ListUserSavedFilters_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateUserSavedFilter(ctx context.Context, in *CreateUserSavedFilterRequest, opts ...grpc.CallOption) (*CreateUserSavedFilterResponse, error) {
+	out := new(CreateUserSavedFilterResponse)
+	err := c.cc.Invoke(ctx, UserService_CreateUserSavedFilter_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateUserSavedFilter(ctx context.Context, in *UpdateUserSavedFilterRequest, opts ...grpc.CallOption) (*UpdateUserSavedFilterResponse, error) {
+	out := new(UpdateUserSavedFilterResponse)
+	err := c.cc.Invoke(ctx, UserService_UpdateUserSavedFilter_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteUserSavedFilter(ctx context.Context, in *DeleteUserSavedFilterRequest, opts ...grpc.CallOption) (*DeleteUserSavedFilterResponse, error) {
+	out := new(DeleteUserSavedFilterResponse)
+	err := c.cc.Invoke(ctx, UserService_DeleteUserSavedFilter_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility
@@ -117,6 +165,14 @@ type UserServiceServer interface {
 	CreateUserAccessToken(context.Context, *CreateUserAccessTokenRequest) (*CreateUserAccessTokenResponse, error)
 	// DeleteUserAccessToken deletes an access token for a user.
 	DeleteUserAccessToken(context.Context, *DeleteUserAccessTokenRequest) (*DeleteUserAccessTokenResponse, error)
+	// ListUserSavedFilters returns a list of saved memo filters for a user.
+	ListUserSavedFilters(context.Context, *ListUserSavedFiltersRequest) (*ListUserSavedFiltersResponse, error)
+	// CreateUserSavedFilter creates a new saved memo filter for a user.
+	CreateUserSavedFilter(context.Context, *CreateUserSavedFilterRequest) (*CreateUserSavedFilterResponse, error)
+	// UpdateUserSavedFilter updates a saved memo filter of a user.
+	UpdateUserSavedFilter(context.Context, *UpdateUserSavedFilterRequest) (*UpdateUserSavedFilterResponse, error)
+	// DeleteUserSavedFilter deletes a saved memo filter of a user.
+	DeleteUserSavedFilter(context.Context, *DeleteUserSavedFilterRequest) (*DeleteUserSavedFilterResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -142,6 +198,18 @@ func (UnimplementedUserServiceServer) CreateUserAccessToken(context.Context, *Cr
 func (UnimplementedUserServiceServer) DeleteUserAccessToken(context.Context, *DeleteUserAccessTokenRequest) (*DeleteUserAccessTokenResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserAccessToken not implemented")
 }
+func (UnimplementedUserServiceServer) ListUserSavedFilters(context.Context, *ListUserSavedFiltersRequest) (*ListUserSavedFiltersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserSavedFilters not implemented")
+}
+func (UnimplementedUserServiceServer) CreateUserSavedFilter(context.Context, *CreateUserSavedFilterRequest) (*CreateUserSavedFilterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUserSavedFilter not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateUserSavedFilter(context.Context, *UpdateUserSavedFilterRequest) (*UpdateUserSavedFilterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUserSavedFilter not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteUserSavedFilter(context.Context, *DeleteUserSavedFilterRequest) (*DeleteUserSavedFilterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserSavedFilter not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 
 // UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -263,6 +331,78 @@ func _UserService_DeleteUserAccessToken_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_ListUserSavedFilters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserSavedFiltersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUserSavedFilters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListUserSavedFilters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUserSavedFilters(ctx, req.(*ListUserSavedFiltersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateUserSavedFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserSavedFilterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateUserSavedFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreateUserSavedFilter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateUserSavedFilter(ctx, req.(*CreateUserSavedFilterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateUserSavedFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserSavedFilterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateUserSavedFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_UpdateUserSavedFilter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateUserSavedFilter(ctx, req.(*UpdateUserSavedFilterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteUserSavedFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserSavedFilterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteUserSavedFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteUserSavedFilter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteUserSavedFilter(ctx, req.(*DeleteUserSavedFilterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -294,6 +434,22 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUserAccessToken",
 			Handler:    _UserService_DeleteUserAccessToken_Handler,
 		},
+		{
+			MethodName: "ListUserSavedFilters",
+			Handler:    _UserService_ListUserSavedFilters_Handler,
+		},
+		{
+			MethodName: "CreateUserSavedFilter",
+			Handler:    _UserService_CreateUserSavedFilter_Handler,
+		},
+		{
+			MethodName: "UpdateUserSavedFilter",
+			Handler:    _UserService_UpdateUserSavedFilter_Handler,
+		},
+		{
+			MethodName: "DeleteUserSavedFilter",
+			Handler:    _UserService_DeleteUserSavedFilter_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v2/user_service.proto",
@@ -10,6 +10,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	apiresource "github.com/usememos/memos/api/resource"
 	apiv1 "github.com/usememos/memos/api/v1"
 	"github.com/usememos/memos/plugin/telegram"
 	"github.com/usememos/memos/store"
@@ -101,11 +102,12 @@ func (t *TelegramHandler) MessageHandle(ctx context.Context, bot *telegram.Bot,
 			return err
 		}
 
-		_, err = t.store.CreateResource(ctx, &create)
+		resource, err := t.store.CreateResource(ctx, &create)
 		if err != nil {
 			_, err := bot.EditMessage(ctx, message.Chat.ID, reply.MessageID, fmt.Sprintf("Failed to CreateResource: %s", err), nil)
 			return err
 		}
+		apiresource.EnqueueResourcePreviews(t.store, resource)
 	}
 
 	keyboard := generateKeyboardForMemoID(memoMessage.ID)
@@ -26,7 +26,7 @@ type Profile struct {
 	// DSN points to where memos stores its own data
 	DSN string `json:"-"`
 	// Driver is the database driver
-	// sqlite, mysql
+	// sqlite, mysql, postgres
 	Driver string `json:"-"`
 	// Version is the current version of server
 	Version string `json:"version"`
@@ -20,6 +20,9 @@ import (
 	"github.com/usememos/memos/server/profile"
 	"github.com/usememos/memos/server/service/backup"
 	"github.com/usememos/memos/server/service/metric"
+	"github.com/usememos/memos/server/service/preview"
+	"github.com/usememos/memos/server/service/trash"
+	"github.com/usememos/memos/server/service/upload"
 	"github.com/usememos/memos/store"
 )
 
@@ -35,8 +38,11 @@ type Server struct {
 	apiV2Service *apiv2.APIV2Service
 
 	// Asynchronous runners.
-	backupRunner *backup.BackupRunner
-	telegramBot  *telegram.Bot
+	backupRunner  *backup.BackupRunner
+	trashRunner   *trash.TrashRunner
+	uploadRunner  *upload.UploadRunner
+	previewRunner *preview.PreviewRunner
+	telegramBot   *telegram.Bot
 }
 
 func NewServer(ctx context.Context, profile *profile.Profile, store *store.Store) (*Server, error) {
@@ -51,8 +57,11 @@ func NewServer(ctx context.Context, profile *profile.Profile, store *store.Store
 		Profile: profile,
 
 		// Asynchronous runners.
-		backupRunner: backup.NewBackupRunner(store),
-		telegramBot:  telegram.NewBotWithHandler(integration.NewTelegramHandler(store)),
+		backupRunner:  backup.NewBackupRunner(store),
+		trashRunner:   trash.NewTrashRunner(store),
+		uploadRunner:  upload.NewUploadRunner(store),
+		previewRunner: preview.NewPreviewRunner(),
+		telegramBot:   telegram.NewBotWithHandler(integration.NewTelegramHandler(store)),
 	}
 
 	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
@@ -113,6 +122,9 @@ func NewServer(ctx context.Context, profile *profile.Profile, store *store.Store
 func (s *Server) Start(ctx context.Context) error {
 	go s.telegramBot.Start(ctx)
 	go s.backupRunner.Run(ctx)
+	go s.trashRunner.Run(ctx)
+	go s.uploadRunner.Run(ctx)
+	go s.previewRunner.Run(ctx)
 
 	metric.Enqueue("server start")
 	return s.e.Start(fmt.Sprintf("%s:%d", s.Profile.Addr, s.Profile.Port))
@@ -2,20 +2,38 @@ package backup
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 
 	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/internal/cron"
 	"github.com/usememos/memos/internal/log"
+	storepb "github.com/usememos/memos/proto/gen/store"
 	"github.com/usememos/memos/store"
 )
 
+const (
+	backupJobID       = "backup"
+	backupTimeLayout  = "-20060102-150405.bak"
+	backupTimePattern = "-????????-??????.bak"
+)
+
 // nolint
 type BackupRunner struct {
 	Store *store.Store
+
+	// mutex makes sure that only one backup runs at a time.
+	mutex sync.Mutex
 }
 
 func NewBackupRunner(store *store.Store) *BackupRunner {
@@ -24,42 +42,200 @@ func NewBackupRunner(store *store.Store) *BackupRunner {
 	}
 }
 
+// Run schedules the backups with the backup config until the context is done.
+// The config is reloaded every minute, so changes of it take effect without a restart.
 func (r *BackupRunner) Run(ctx context.Context) {
-	intervalStr := r.Store.GetSystemSettingValueWithDefault(ctx, apiv1.SystemSettingAutoBackupIntervalName.String(), "")
-	if intervalStr == "" {
-		log.Debug("no SystemSettingAutoBackupIntervalName setting, disable auto backup")
-		return
+	c := cron.New()
+	var backupConfig *storepb.BackupConfig
+	reload := func() {
+		config, err := r.GetBackupConfig(ctx)
+		if err != nil {
+			log.Error("fail to get backup config", zap.Error(err))
+			return
+		}
+		if proto.Equal(config, backupConfig) {
+			return
+		}
+		backupConfig = config
+
+		c.Remove(backupJobID)
+		if !config.Enabled {
+			log.Debug("auto backup is disabled")
+			return
+		}
+		if err := c.Add(backupJobID, config.Cron, func() {
+			if err := r.Backup(ctx); err != nil {
+				log.Error("fail to create backup", zap.Error(err))
+			}
+		}); err != nil {
+			log.Error(fmt.Sprintf("invalid backup cron %s, disable auto backup", config.Cron), zap.Error(err))
+			return
+		}
+		log.Info("enable auto backup with cron " + config.Cron)
+	}
+	reload()
+	// The reload job is the only one touching backupConfig, and it runs once a minute.
+	c.MustAdd("reload-backup-config", "* * * * *", reload)
+	c.Start()
+
+	<-ctx.Done()
+	c.Stop()
+	log.Info("stop auto backup graceful.")
+}
+
+// GetBackupConfig returns the backup config. Without one, it falls back to the legacy auto backup interval.
+func (r *BackupRunner) GetBackupConfig(ctx context.Context) (*storepb.BackupConfig, error) {
+	backupConfig, err := r.Store.GetBackupConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if backupConfig != nil {
+		return backupConfig, nil
 	}
 
+	backupConfig = &storepb.BackupConfig{}
+	intervalStr := r.Store.GetSystemSettingValueWithDefault(ctx, apiv1.SystemSettingAutoBackupIntervalName.String(), "0")
 	interval, err := strconv.Atoi(intervalStr)
-	if err != nil || interval < 0 {
-		log.Error(fmt.Sprintf("invalid SystemSettingAutoBackupIntervalName value %s, disable auto backup", intervalStr), zap.Error(err))
-		return
+	if err != nil || interval <= 0 {
+		return backupConfig, nil
 	}
+	backupConfig.Enabled = true
+	backupConfig.Cron = convertIntervalToCron(interval)
+	return backupConfig, nil
+}
+
+// Backup creates a backup, verifies it, prunes the backups beyond the max keep of the config,
+// uploads it to the storage of the config if any, and records the status of the run.
+func (r *BackupRunner) Backup(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 
-	if interval == 0 {
-		println("AutoBackupIntervalName value is 0, disable auto backup")
-		return
+	backupConfig, err := r.GetBackupConfig(ctx)
+	if err != nil {
+		return err
 	}
 
-	log.Info("enable auto backup every " + intervalStr + " seconds")
-	ticker := time.NewTicker(time.Duration(interval) * time.Second)
-	defer ticker.Stop()
+	now := time.Now()
+	filename := r.getBackupPrefix() + now.Format(backupTimeLayout)
+	backupStatus := &storepb.BackupStatus{
+		RunTs:    now.Unix(),
+		Filename: filename,
+	}
+	backupErr := r.backup(ctx, filename)
+	if backupErr == nil {
+		if fileInfo, err := os.Stat(filename); err == nil {
+			backupStatus.Size = fileInfo.Size()
+		}
+		backupErr = r.prune(backupConfig.MaxKeep)
+	}
+	if backupErr == nil && backupConfig.StorageId > 0 {
+		backupStatus.RemoteKey, backupErr = r.upload(ctx, backupConfig.StorageId, backupConfig.MaxKeep, filename)
+	}
+	if backupErr != nil {
+		backupStatus.Error = backupErr.Error()
+	}
+	if err := r.Store.UpsertBackupStatus(ctx, backupStatus); err != nil {
+		return errors.Wrap(err, "failed to upsert backup status")
+	}
+	return backupErr
+}
 
-	var t time.Time
-	for {
-		select {
-		case <-ctx.Done():
-			log.Info("stop auto backup graceful.")
-			return
-		case t = <-ticker.C:
+func (r *BackupRunner) backup(ctx context.Context, filename string) error {
+	log.Info(fmt.Sprintf("create backup to %s", filename))
+	if err := r.Store.BackupTo(ctx, filename); err != nil {
+		return err
+	}
+	if r.Store.Profile.Driver != "sqlite" {
+		return nil
+	}
+
+	// A corrupted backup is removed so that it never replaces a good one during pruning.
+	if err := checkSQLiteIntegrity(ctx, filename); err != nil {
+		if err := os.Remove(filename); err != nil {
+			log.Error(fmt.Sprintf("fail to remove corrupted backup %s", filename), zap.Error(err))
 		}
+		return err
+	}
+	return nil
+}
 
-		filename := r.Store.Profile.DSN + t.Format("-20060102-150405.bak")
-		log.Info(fmt.Sprintf("create backup to %s", filename))
-		err := r.Store.BackupTo(ctx, filename)
-		if err != nil {
-			log.Error("fail to create backup", zap.Error(err))
+// prune removes the oldest backups beyond the max keep.
+func (r *BackupRunner) prune(maxKeep int32) error {
+	if maxKeep <= 0 {
+		return nil
+	}
+
+	filenames, err := filepath.Glob(r.getBackupPrefix() + backupTimePattern)
+	if err != nil {
+		return errors.Wrap(err, "failed to list backups")
+	}
+	if len(filenames) <= int(maxKeep) {
+		return nil
+	}
+
+	// The timestamps in the filenames sort them from the oldest to the newest.
+	sort.Strings(filenames)
+	for _, filename := range filenames[:len(filenames)-int(maxKeep)] {
+		log.Info(fmt.Sprintf("remove backup %s", filename))
+		if err := os.Remove(filename); err != nil {
+			return errors.Wrapf(err, "failed to remove backup %s", filename)
+		}
+	}
+	return nil
+}
+
+// getBackupPrefix returns the path prefix of the backup files, which are followed by their timestamps.
+func (r *BackupRunner) getBackupPrefix() string {
+	if r.Store.Profile.Driver == "sqlite" {
+		return r.Store.Profile.DSN
+	}
+	// The DSN of the other drivers isn't a path, their logical dumps are kept in the data directory.
+	return filepath.Join(r.Store.Profile.Data, fmt.Sprintf("memos_%s", r.Store.Profile.Driver))
+}
+
+func checkSQLiteIntegrity(ctx context.Context, filename string) error {
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return errors.Wrap(err, "failed to open backup")
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return errors.Wrap(err, "failed to check backup integrity")
+	}
+	defer rows.Close()
+
+	problems := []string{}
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return errors.Wrap(err, "failed to scan integrity check result")
+		}
+		if result != "ok" {
+			problems = append(problems, result)
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to check backup integrity")
+	}
+	if len(problems) > 0 {
+		return errors.Errorf("backup %s failed the integrity check: %v", filename, problems)
+	}
+	return nil
+}
+
+// convertIntervalToCron converts the legacy auto backup interval in seconds to the closest cron expression.
+func convertIntervalToCron(interval int) string {
+	minutes := interval / 60
+	switch {
+	case minutes >= 24*60:
+		return "0 0 * * *"
+	case minutes >= 60:
+		return fmt.Sprintf("0 */%d * * *", minutes/60)
+	case minutes > 1:
+		return fmt.Sprintf("*/%d * * * *", minutes)
+	default:
+		return "* * * * *"
+	}
 }
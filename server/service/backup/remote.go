@@ -0,0 +1,183 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	apiresource "github.com/usememos/memos/api/resource"
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/internal/log"
+	"github.com/usememos/memos/plugin/storage"
+	"github.com/usememos/memos/store"
+)
+
+const (
+	// RemoteBackupDir is the directory of the backups uploaded to the storages. Each instance uploads to its own subdirectory
+	// named by its server ID, so that the retention of an instance doesn't remove the backups of the others sharing the storage.
+	RemoteBackupDir    = "backups"
+	remoteBackupSuffix = ".tar.gz"
+)
+
+// GetRemoteBackend returns the backend of the storage to upload the backups to.
+func GetRemoteBackend(ctx context.Context, s *store.Store, storageID int32) (storage.Backend, error) {
+	storage, err := s.GetStorage(ctx, &store.FindStorage{ID: &storageID})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find storage")
+	}
+	if storage == nil {
+		return nil, errors.Errorf("storage %d not found", storageID)
+	}
+	return apiresource.NewStorageBackend(ctx, storage)
+}
+
+// GetRemoteBackupDir returns the directory of the backups of this instance in the storages.
+func GetRemoteBackupDir(ctx context.Context, s *store.Store) (string, error) {
+	serverIDSetting, err := s.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: apiv1.SystemSettingServerIDName.String(),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find server id")
+	}
+	if serverIDSetting == nil || serverIDSetting.Value == "" {
+		return "", errors.New("server id is not set, please start the server once")
+	}
+	return path.Join(RemoteBackupDir, serverIDSetting.Value), nil
+}
+
+// ListRemoteBackups returns the backups in the directory of the storage from the oldest to the newest.
+func ListRemoteBackups(ctx context.Context, backend storage.Backend, dir string) ([]*storage.Object, error) {
+	lister, ok := backend.(storage.Lister)
+	if !ok {
+		return nil, errors.New("storage doesn't support listing the backups")
+	}
+	objects, err := lister.List(ctx, dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list remote backups")
+	}
+
+	backups := []*storage.Object{}
+	for _, object := range objects {
+		if strings.HasSuffix(object.Key, remoteBackupSuffix) {
+			backups = append(backups, object)
+		}
+	}
+	// The timestamps in the keys sort them from the oldest to the newest.
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Key < backups[j].Key
+	})
+	return backups, nil
+}
+
+// upload uploads the backup with the local resources as an archive to the directory of this instance in the storage,
+// prunes the remote backups of this instance beyond the max keep, and returns the key of the archive.
+func (r *BackupRunner) upload(ctx context.Context, storageID int32, maxKeep int32, filename string) (string, error) {
+	backend, err := GetRemoteBackend(ctx, r.Store, storageID)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := backend.(storage.Lister); !ok && maxKeep > 0 {
+		return "", errors.New("storage doesn't support listing the backups to keep")
+	}
+	dir, err := GetRemoteBackupDir(ctx, r.Store)
+	if err != nil {
+		return "", err
+	}
+	resources, err := r.Store.ListResources(ctx, &store.FindResource{})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list resources")
+	}
+
+	key := path.Join(dir, filepath.Base(filename)+remoteBackupSuffix)
+	log.Info(fmt.Sprintf("upload backup to %s", key))
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(r.writeArchive(writer, filename, resources))
+	}()
+	err = backend.Put(ctx, key, reader, -1, "application/gzip")
+	// Unblock the archive writer if the upload stops early.
+	reader.Close()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to upload backup")
+	}
+
+	if maxKeep <= 0 {
+		return key, nil
+	}
+	backups, err := ListRemoteBackups(ctx, backend, dir)
+	if err != nil {
+		return key, err
+	}
+	if len(backups) <= int(maxKeep) {
+		return key, nil
+	}
+	for _, backup := range backups[:len(backups)-int(maxKeep)] {
+		log.Info(fmt.Sprintf("remove remote backup %s", backup.Key))
+		if err := backend.Delete(ctx, backup.Key); err != nil {
+			return key, errors.Wrapf(err, "failed to remove remote backup %s", backup.Key)
+		}
+	}
+	return key, nil
+}
+
+// writeArchive writes the backup file and the resources in the local storage as a gzipped tarball.
+// The resources are put in the resources directory with their paths relative to the data directory.
+func (r *BackupRunner) writeArchive(w io.Writer, filename string, resources []*store.Resource) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	if err := addArchiveFile(tarWriter, filepath.Base(filename), filename); err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		// The blobs in the other storages are kept by them.
+		if resource.InternalPath == "" || resource.StorageID != 0 {
+			continue
+		}
+		name, err := filepath.Rel(r.Store.Profile.Data, resource.InternalPath)
+		if err != nil || strings.HasPrefix(name, "..") {
+			name = filepath.Join(strconv.Itoa(int(resource.ID)), filepath.Base(resource.InternalPath))
+		}
+		if err := addArchiveFile(tarWriter, filepath.ToSlash(filepath.Join("resources", name)), resource.InternalPath); err != nil {
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return errors.Wrap(err, "failed to close archive")
+	}
+	return gzipWriter.Close()
+}
+
+func addArchiveFile(tarWriter *tar.Writer, name string, filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", filename)
+	}
+	defer file.Close()
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "failed to stat %s", filename)
+	}
+
+	header, err := tar.FileInfoHeader(fileInfo, "")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create archive header of %s", filename)
+	}
+	header.Name = name
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return errors.Wrapf(err, "failed to write archive header of %s", filename)
+	}
+	if _, err := io.Copy(tarWriter, file); err != nil {
+		return errors.Wrapf(err, "failed to write %s to archive", filename)
+	}
+	return nil
+}
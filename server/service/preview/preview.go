@@ -0,0 +1,34 @@
+package preview
+
+import (
+	"context"
+	"sync"
+
+	apiresource "github.com/usememos/memos/api/resource"
+	"github.com/usememos/memos/internal/log"
+)
+
+// workerCount is the number of the previews generated at the same time, which bounds the memory and CPU they take.
+const workerCount = 2
+
+// nolint
+type PreviewRunner struct{}
+
+func NewPreviewRunner() *PreviewRunner {
+	return &PreviewRunner{}
+}
+
+// Run generates the previews of the resources queued by the workers until the context is done.
+// The resources are queued along with their stores, so the runner doesn't hold one.
+func (*PreviewRunner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			apiresource.RunPreviewWorker(ctx)
+		}()
+	}
+	wg.Wait()
+	log.Info("stop generating resource previews graceful.")
+}
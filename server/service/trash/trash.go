@@ -0,0 +1,77 @@
+package trash
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/internal/cron"
+	"github.com/usememos/memos/internal/log"
+	"github.com/usememos/memos/store"
+)
+
+// nolint
+type TrashRunner struct {
+	Store *store.Store
+}
+
+func NewTrashRunner(store *store.Store) *TrashRunner {
+	return &TrashRunner{
+		Store: store,
+	}
+}
+
+// Run purges the trash and the memo revisions older than their retention at the beginning of every hour until the context is done.
+func (r *TrashRunner) Run(ctx context.Context) {
+	c := cron.New()
+	c.MustAdd("purge-trash", "0 * * * *", func() {
+		if err := r.Purge(ctx); err != nil {
+			log.Error("fail to purge trash", zap.Error(err))
+		}
+	})
+	c.MustAdd("purge-memo-revisions", "0 * * * *", func() {
+		if err := r.Store.PurgeMemoRevisions(ctx); err != nil {
+			log.Error("fail to purge memo revisions", zap.Error(err))
+		}
+	})
+	c.Start()
+
+	<-ctx.Done()
+	c.Stop()
+	log.Info("stop purging trash graceful.")
+}
+
+// Purge permanently deletes the memos which have been in the trash for longer than the trash retention,
+// along with their resources.
+func (r *TrashRunner) Purge(ctx context.Context) error {
+	retentionStr := r.Store.GetSystemSettingValueWithDefault(ctx, apiv1.SystemSettingTrashRetentionName.String(), strconv.Itoa(apiv1.DefaultTrashRetention))
+	retention, err := strconv.Atoi(retentionStr)
+	if err != nil || retention < 0 {
+		return errors.Errorf("invalid trash retention %s", retentionStr)
+	}
+
+	// The memos are moved to the trash with their updated time set.
+	deletedStatus := store.Deleted
+	updatedTsBefore := time.Now().AddDate(0, 0, -retention).Unix()
+	memos, err := r.Store.ListMemos(ctx, &store.FindMemo{
+		RowStatus:       &deletedStatus,
+		UpdatedTsBefore: &updatedTsBefore,
+		ExcludeContent:  true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list memos in trash")
+	}
+	for _, memo := range memos {
+		if err := apiv1.PurgeMemo(ctx, r.Store, memo); err != nil {
+			return errors.Wrapf(err, "failed to purge memo %d", memo.ID)
+		}
+	}
+	if len(memos) > 0 {
+		log.Info("purged memos in trash", zap.Int("count", len(memos)))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package upload
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/internal/cron"
+	"github.com/usememos/memos/internal/log"
+	"github.com/usememos/memos/store"
+)
+
+// nolint
+type UploadRunner struct {
+	Store *store.Store
+}
+
+func NewUploadRunner(store *store.Store) *UploadRunner {
+	return &UploadRunner{
+		Store: store,
+	}
+}
+
+// Run purges the abandoned resource uploads at the half of every hour until the context is done.
+func (r *UploadRunner) Run(ctx context.Context) {
+	c := cron.New()
+	c.MustAdd("purge-resource-uploads", "30 * * * *", func() {
+		count, err := apiv1.PurgeResourceUploads(ctx, r.Store)
+		if err != nil {
+			log.Error("fail to purge resource uploads", zap.Error(err))
+		}
+		if count > 0 {
+			log.Info("purged resource uploads", zap.Int("count", count))
+		}
+	})
+	c.Start()
+
+	<-ctx.Done()
+	c.Stop()
+	log.Info("stop purging resource uploads graceful.")
+}
@@ -8,8 +8,22 @@ const (
 	Normal RowStatus = "NORMAL"
 	// Archived is the status for an archived row.
 	Archived RowStatus = "ARCHIVED"
+	// Deleted is the status for a row in the trash, which is purged later.
+	Deleted RowStatus = "DELETED"
 )
 
 func (r RowStatus) String() string {
 	return string(r)
 }
+
+// PageCursor is the sort key of the last item of a page in keyset pagination.
+// The items are ordered by the timestamp and ID in descending order, and the next page starts after the cursor.
+type PageCursor struct {
+	// Pinned is only used by memos, as the pinned ones are listed first.
+	Pinned bool
+	// Rank is only used by the memo search results, as they're ordered by relevance first.
+	Rank float64
+	// Ts is the created_ts, or the updated_ts if the items are ordered by it.
+	Ts int64
+	ID int32
+}
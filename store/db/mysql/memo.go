@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 
@@ -68,11 +69,20 @@ func (d *DB) ListMemos(ctx context.Context, find *store.FindMemo) ([]*store.Memo
 	if v := find.ID; v != nil {
 		where, args = append(where, "`memo`.`id` = ?"), append(args, *v)
 	}
+	if v := find.IDList; len(v) != 0 {
+		list := []string{}
+		for _, id := range v {
+			list, args = append(list, "?"), append(args, id)
+		}
+		where = append(where, fmt.Sprintf("`memo`.`id` IN (%s)", strings.Join(list, ",")))
+	}
 	if v := find.CreatorID; v != nil {
 		where, args = append(where, "`memo`.`creator_id` = ?"), append(args, *v)
 	}
 	if v := find.RowStatus; v != nil {
 		where, args = append(where, "`memo`.`row_status` = ?"), append(args, *v)
+	} else {
+		where, args = append(where, "`memo`.`row_status` != ?"), append(args, store.Deleted)
 	}
 	if v := find.CreatedTsBefore; v != nil {
 		where, args = append(where, "UNIX_TIMESTAMP(`memo`.`created_ts`) < ?"), append(args, *v)
@@ -80,14 +90,36 @@ func (d *DB) ListMemos(ctx context.Context, find *store.FindMemo) ([]*store.Memo
 	if v := find.CreatedTsAfter; v != nil {
 		where, args = append(where, "UNIX_TIMESTAMP(`memo`.`created_ts`) > ?"), append(args, *v)
 	}
+	if v := find.UpdatedTsBefore; v != nil {
+		where, args = append(where, "UNIX_TIMESTAMP(`memo`.`updated_ts`) < ?"), append(args, *v)
+	}
+	if v := find.ParentID; v != nil {
+		where, args = append(where, "`memo`.`id` IN (SELECT `memo_id` FROM `memo_relation` WHERE `related_memo_id` = ? AND `type` = 'COMMENT')"), append(args, *v)
+	}
 	if v := find.Pinned; v != nil {
 		where = append(where, "`memo_organizer`.`pinned` = 1")
 	}
+	if v := find.Filter; v != nil {
+		condition, filterArgs, err := convertMemoFilterToWhere(v)
+		if err != nil {
+			return nil, err
+		}
+		where, args = append(where, condition), append(args, filterArgs...)
+	}
 	if v := find.ContentSearch; len(v) != 0 {
 		for _, s := range v {
 			where, args = append(where, "`memo`.`content` LIKE ?"), append(args, "%"+s+"%")
 		}
 	}
+	if v := find.Cursor; v != nil {
+		ts := "UNIX_TIMESTAMP(`memo`.`created_ts`)"
+		if find.OrderByUpdatedTs {
+			ts = "UNIX_TIMESTAMP(`memo`.`updated_ts`)"
+		}
+		pinned := "EXISTS (SELECT 1 FROM `memo_organizer` WHERE `memo_organizer`.`memo_id` = `memo`.`id` AND `memo_organizer`.`user_id` = `memo`.`creator_id` AND `memo_organizer`.`pinned` = 1)"
+		where = append(where, fmt.Sprintf("(%s < ? OR (%s = ? AND (%s < ? OR (%s = ? AND `memo`.`id` < ?))))", pinned, pinned, ts, ts))
+		args = append(args, v.Pinned, v.Pinned, v.Ts, v.Ts, v.ID)
+	}
 	if v := find.VisibilityList; len(v) != 0 {
 		list := []string{}
 		for _, visibility := range v {
@@ -104,7 +136,7 @@ func (d *DB) ListMemos(ctx context.Context, find *store.FindMemo) ([]*store.Memo
 	}
 	orders = append(orders, "`id` DESC")
 
-	query := "SELECT `memo`.`id` AS `id`, `memo`.`creator_id` AS `creator_id`, UNIX_TIMESTAMP(`memo`.`created_ts`) AS `created_ts`, UNIX_TIMESTAMP(`memo`.`updated_ts`) AS `updated_ts`, `memo`.`row_status` AS `row_status`, `memo`.`content` AS `content`, `memo`.`visibility` AS `visibility`, MAX(CASE WHEN `memo_organizer`.`pinned` = 1 THEN 1 ELSE 0 END) AS `pinned`, GROUP_CONCAT(`resource`.`id`) AS `resource_id_list`, (SELECT GROUP_CONCAT(`memo_id`,':',`related_memo_id`,':',`type`) FROM `memo_relation` WHERE `memo_relation`.`memo_id` = `memo`.`id` OR `memo_relation`.`related_memo_id` = `memo`.`id` ) AS `relation_list` FROM `memo` LEFT JOIN `memo_organizer` ON `memo`.`id` = `memo_organizer`.`memo_id` LEFT JOIN `resource` ON `memo`.`id` = `resource`.`memo_id` WHERE " + strings.Join(where, " AND ") + " GROUP BY `memo`.`id` ORDER BY " + strings.Join(orders, ", ")
+	query := "SELECT `memo`.`id` AS `id`, `memo`.`creator_id` AS `creator_id`, UNIX_TIMESTAMP(`memo`.`created_ts`) AS `created_ts`, UNIX_TIMESTAMP(`memo`.`updated_ts`) AS `updated_ts`, `memo`.`row_status` AS `row_status`, `memo`.`content` AS `content`, `memo`.`visibility` AS `visibility`, MAX(CASE WHEN `memo_organizer`.`pinned` = 1 THEN 1 ELSE 0 END) AS `pinned`, GROUP_CONCAT(`resource`.`id`) AS `resource_id_list`, (SELECT GROUP_CONCAT(`memo_id`,':',`related_memo_id`,':',`type`) FROM `memo_relation` WHERE `memo_relation`.`memo_id` = `memo`.`id` OR `memo_relation`.`related_memo_id` = `memo`.`id` ) AS `relation_list` FROM `memo` LEFT JOIN `memo_organizer` ON `memo`.`id` = `memo_organizer`.`memo_id` AND `memo_organizer`.`user_id` = `memo`.`creator_id` LEFT JOIN `resource` ON `memo`.`id` = `resource`.`memo_id` WHERE " + strings.Join(where, " AND ") + " GROUP BY `memo`.`id` ORDER BY " + strings.Join(orders, ", ")
 	if find.Limit != nil {
 		query = fmt.Sprintf("%s LIMIT %d", query, *find.Limit)
 		if find.Offset != nil {
@@ -220,10 +252,19 @@ func (d *DB) UpdateMemo(ctx context.Context, update *store.UpdateMemo) error {
 	args = append(args, update.ID)
 
 	stmt := "UPDATE `memo` SET " + strings.Join(set, ", ") + " WHERE `id` = ?"
-	if _, err := d.db.ExecContext(ctx, stmt, args...); err != nil {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
 		return err
 	}
-	return nil
+	defer tx.Rollback()
+	// The revision is written with the update, so it's always the content right before it.
+	if err := createMemoRevision(ctx, tx, update); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (d *DB) DeleteMemo(ctx context.Context, delete *store.DeleteMemo) error {
@@ -276,6 +317,85 @@ func (d *DB) FindMemosVisibilityList(ctx context.Context, memoIDs []int32) ([]st
 	return visibilityList, nil
 }
 
+func (d *DB) SearchMemos(ctx context.Context, find *store.SearchMemo) ([]*store.MemoSearchResult, error) {
+	terms := store.SplitSearchQuery(find.Query)
+	where, args := []string{"1 = 1"}, []any{}
+	matchTerms := []string{}
+	for _, term := range terms {
+		// The ngram parser can't match the terms shorter than the token size.
+		if utf8.RuneCountInString(term) < 2 {
+			where, args = append(where, "`memo`.`content` LIKE ? ESCAPE '\\\\'"), append(args, "%"+store.EscapeLikePattern(term)+"%")
+			continue
+		}
+		matchTerms = append(matchTerms, `+"`+strings.ReplaceAll(term, `"`, "")+`"`)
+	}
+	rank, rankArgs := "0", []any{}
+	if len(matchTerms) > 0 {
+		against := strings.Join(matchTerms, " ")
+		rank, rankArgs = "MATCH(`memo`.`content`) AGAINST(? IN BOOLEAN MODE)", []any{against}
+		where, args = append(where, "MATCH(`memo`.`content`) AGAINST(? IN BOOLEAN MODE)"), append(args, against)
+	}
+
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, "`memo`.`creator_id` = ?"), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, "`memo`.`row_status` = ?"), append(args, *v)
+	} else {
+		where, args = append(where, "`memo`.`row_status` != ?"), append(args, store.Deleted)
+	}
+	if v := find.VisibilityList; len(v) != 0 {
+		list := []string{}
+		for _, visibility := range v {
+			list = append(list, "?")
+			args = append(args, visibility)
+		}
+		where = append(where, fmt.Sprintf("`memo`.`visibility` IN (%s)", strings.Join(list, ",")))
+	}
+	if v := find.Filter; v != nil {
+		condition, filterArgs, err := convertMemoFilterToWhere(v)
+		if err != nil {
+			return nil, err
+		}
+		where, args = append(where, condition), append(args, filterArgs...)
+	}
+	// The results are ranked in a derived table so that the cursor can refer to the rank.
+	having := []string{"1 = 1"}
+	if v := find.Cursor; v != nil {
+		having, args = append(having, "(`rank` < ? OR (`rank` = ? AND (`created_ts` < ? OR (`created_ts` = ? AND `id` < ?))))"), append(args, v.Rank, v.Rank, v.Ts, v.Ts, v.ID)
+	}
+
+	query := "SELECT `id`, `content`, `created_ts`, `rank` FROM (" +
+		"SELECT `memo`.`id` AS `id`, `memo`.`content` AS `content`, UNIX_TIMESTAMP(`memo`.`created_ts`) AS `created_ts`, " + rank + " AS `rank` FROM `memo` WHERE " + strings.Join(where, " AND ") +
+		") AS `search_result` WHERE " + strings.Join(having, " AND ") + " ORDER BY `rank` DESC, `created_ts` DESC, `id` DESC"
+	if find.Limit != nil {
+		query = fmt.Sprintf("%s LIMIT %d", query, *find.Limit)
+	}
+
+	rows, err := d.db.QueryContext(ctx, query, append(rankArgs, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]*store.MemoSearchResult, 0)
+	for rows.Next() {
+		result := &store.MemoSearchResult{}
+		var content string
+		if err := rows.Scan(&result.MemoID, &content, &result.CreatedTs, &result.Rank); err != nil {
+			return nil, err
+		}
+		result.Snippet = store.BuildSearchSnippet(content, terms)
+		list = append(list, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
 func vacuumMemo(ctx context.Context, tx *sql.Tx) error {
 	stmt := "DELETE FROM `memo` WHERE `creator_id` NOT IN (SELECT `id` FROM `user`)"
 	_, err := tx.ExecContext(ctx, stmt)
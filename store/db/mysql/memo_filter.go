@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/store"
+)
+
+// convertMemoFilterToWhere compiles the memo filter into a where condition and its args.
+func convertMemoFilterToWhere(filter *store.MemoFilter) (string, []any, error) {
+	switch filter.Operator {
+	case store.MemoFilterAnd, store.MemoFilterOr:
+		if len(filter.Children) == 0 {
+			return "", nil, errors.Errorf("operator %s requires operands", filter.Operator)
+		}
+		conditions, args := []string{}, []any{}
+		for _, child := range filter.Children {
+			condition, childArgs, err := convertMemoFilterToWhere(child)
+			if err != nil {
+				return "", nil, err
+			}
+			conditions, args = append(conditions, condition), append(args, childArgs...)
+		}
+		return "(" + strings.Join(conditions, " "+string(filter.Operator)+" ") + ")", args, nil
+	case store.MemoFilterNot:
+		if len(filter.Children) != 1 {
+			return "", nil, errors.Errorf("operator %s requires one operand", filter.Operator)
+		}
+		condition, args, err := convertMemoFilterToWhere(filter.Children[0])
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT " + condition, args, nil
+	case store.MemoFilterContains:
+		if filter.Field != store.MemoFilterFieldContent {
+			return "", nil, errors.Errorf("operator %s is not supported on field %s", filter.Operator, filter.Field)
+		}
+		return "(`memo`.`content` LIKE ? ESCAPE '\\\\')", []any{"%" + store.EscapeLikePattern(fmt.Sprint(filter.Value)) + "%"}, nil
+	}
+
+	switch filter.Field {
+	case store.MemoFilterFieldCreator:
+		switch filter.Operator {
+		case store.MemoFilterEqual:
+			return "(`memo`.`creator_id` IN (SELECT `id` FROM `user` WHERE `username` = ?))", []any{filter.Value}, nil
+		case store.MemoFilterNotEqual:
+			return "(`memo`.`creator_id` NOT IN (SELECT `id` FROM `user` WHERE `username` = ?))", []any{filter.Value}, nil
+		}
+	case store.MemoFilterFieldRowStatus, store.MemoFilterFieldVisibility:
+		if filter.Operator != store.MemoFilterEqual && filter.Operator != store.MemoFilterNotEqual {
+			break
+		}
+		return fmt.Sprintf("(`memo`.`%s` %s ?)", filter.Field, filter.Operator), []any{filter.Value}, nil
+	case store.MemoFilterFieldCreatedTs, store.MemoFilterFieldUpdatedTs:
+		return fmt.Sprintf("(UNIX_TIMESTAMP(`memo`.`%s`) %s ?)", filter.Field, filter.Operator), []any{filter.Value}, nil
+	case store.MemoFilterFieldTag:
+		if filter.Operator != store.MemoFilterEqual && filter.Operator != store.MemoFilterNotEqual {
+			break
+		}
+		conditions, args := []string{}, []any{}
+		for _, pattern := range store.GetMemoTagPatterns(fmt.Sprint(filter.Value)) {
+			conditions, args = append(conditions, "`memo`.`content` LIKE ? ESCAPE '\\\\'"), append(args, pattern)
+		}
+		condition := "(" + strings.Join(conditions, " OR ") + ")"
+		if filter.Operator == store.MemoFilterNotEqual {
+			condition = "NOT " + condition
+		}
+		return condition, args, nil
+	case store.MemoFilterFieldPinned, store.MemoFilterFieldHasResource:
+		value, ok := filter.Value.(bool)
+		if !ok || (filter.Operator != store.MemoFilterEqual && filter.Operator != store.MemoFilterNotEqual) {
+			break
+		}
+		condition, args := "EXISTS (SELECT 1 FROM `memo_organizer` WHERE `memo_organizer`.`memo_id` = `memo`.`id` AND `memo_organizer`.`user_id` = ? AND `memo_organizer`.`pinned` = 1)", []any{filter.UserID}
+		if filter.Field == store.MemoFilterFieldHasResource {
+			condition, args = "EXISTS (SELECT 1 FROM `resource` WHERE `resource`.`memo_id` = `memo`.`id`)", nil
+		}
+		if value != (filter.Operator == store.MemoFilterEqual) {
+			condition = "NOT " + condition
+		}
+		return "(" + condition + ")", args, nil
+	}
+	return "", nil, errors.Errorf("operator %s is not supported on field %s", filter.Operator, filter.Field)
+}
@@ -0,0 +1,128 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/store"
+)
+
+func (d *DB) CreateMemoRevision(ctx context.Context, create *store.MemoRevision) (*store.MemoRevision, error) {
+	fields := []string{"`memo_id`", "`content`", "`visibility`"}
+	placeholder := []string{"?", "?", "?"}
+	args := []any{create.MemoID, create.Content, create.Visibility}
+	if create.CreatedTs != 0 {
+		fields, placeholder, args = append(fields, "`created_ts`"), append(placeholder, "FROM_UNIXTIME(?)"), append(args, create.CreatedTs)
+	}
+
+	stmt := "INSERT INTO `memo_revision` (" + strings.Join(fields, ", ") + ") VALUES (" + strings.Join(placeholder, ", ") + ")"
+	result, err := d.db.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	id32 := int32(id)
+	list, err := d.ListMemoRevisions(ctx, &store.FindMemoRevision{ID: &id32})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) != 1 {
+		return nil, errors.Errorf("unexpected memo revision count: %d", len(list))
+	}
+	return list[0], nil
+}
+
+func (d *DB) ListMemoRevisions(ctx context.Context, find *store.FindMemoRevision) ([]*store.MemoRevision, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "`id` = ?"), append(args, *v)
+	}
+	if v := find.MemoID; v != nil {
+		where, args = append(where, "`memo_id` = ?"), append(args, *v)
+	}
+
+	query := "SELECT `id`, `memo_id`, UNIX_TIMESTAMP(`created_ts`), `content`, `visibility` FROM `memo_revision` WHERE " + strings.Join(where, " AND ") + " ORDER BY `created_ts` DESC, `id` DESC"
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*store.MemoRevision{}
+	for rows.Next() {
+		memoRevision := &store.MemoRevision{}
+		if err := rows.Scan(
+			&memoRevision.ID,
+			&memoRevision.MemoID,
+			&memoRevision.CreatedTs,
+			&memoRevision.Content,
+			&memoRevision.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, memoRevision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *DB) DeleteMemoRevision(ctx context.Context, delete *store.DeleteMemoRevision) error {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := delete.ID; v != nil {
+		where, args = append(where, "`id` = ?"), append(args, *v)
+	}
+	if v := delete.MemoID; v != nil {
+		where, args = append(where, "`memo_id` = ?"), append(args, *v)
+	}
+	if v := delete.CreatedTsBefore; v != nil {
+		where, args = append(where, "UNIX_TIMESTAMP(`created_ts`) < ?"), append(args, *v)
+	}
+	stmt := "DELETE FROM `memo_revision` WHERE " + strings.Join(where, " AND ")
+	if _, err := d.db.ExecContext(ctx, stmt, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func vacuumMemoRevision(ctx context.Context, tx *sql.Tx) error {
+	stmt := "DELETE FROM `memo_revision` WHERE `memo_id` NOT IN (SELECT `id` FROM `memo`)"
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// createMemoRevision keeps the current content and visibility of the memo as a revision if the update changes them.
+func createMemoRevision(ctx context.Context, tx *sql.Tx, update *store.UpdateMemo) error {
+	if update.Content == nil && update.Visibility == nil {
+		return nil
+	}
+	var content string
+	var visibility store.Visibility
+	if err := tx.QueryRowContext(ctx, "SELECT `content`, `visibility` FROM `memo` WHERE `id` = ? FOR UPDATE", update.ID).Scan(&content, &visibility); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	contentChanged := update.Content != nil && *update.Content != content
+	visibilityChanged := update.Visibility != nil && *update.Visibility != visibility
+	if !contentChanged && !visibilityChanged {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, "INSERT INTO `memo_revision` (`memo_id`, `content`, `visibility`) VALUES (?, ?, ?)", update.ID, content, visibility)
+	return err
+}
@@ -6,7 +6,9 @@ DROP TABLE IF EXISTS `user_setting`;
 DROP TABLE IF EXISTS `memo`;
 DROP TABLE IF EXISTS `memo_organizer`;
 DROP TABLE IF EXISTS `memo_relation`;
+DROP TABLE IF EXISTS `memo_revision`;
 DROP TABLE IF EXISTS `resource`;
+DROP TABLE IF EXISTS `resource_upload`;
 DROP TABLE IF EXISTS `tag`;
 DROP TABLE IF EXISTS `activity`;
 DROP TABLE IF EXISTS `storage`;
@@ -56,7 +58,8 @@ CREATE TABLE `memo` (
   `updated_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `row_status` VARCHAR(255) NOT NULL DEFAULT 'NORMAL',
   `content` TEXT NOT NULL,
-  `visibility` VARCHAR(255) NOT NULL DEFAULT 'PRIVATE'
+  `visibility` VARCHAR(255) NOT NULL DEFAULT 'PRIVATE',
+  FULLTEXT INDEX `idx_memo_content` (`content`) WITH PARSER ngram
 );
 
 -- memo_organizer
@@ -75,6 +78,17 @@ CREATE TABLE `memo_relation` (
   UNIQUE(`memo_id`,`related_memo_id`,`type`)
 );
 
+-- memo_revision
+CREATE TABLE `memo_revision` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `memo_id` INT NOT NULL,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `content` TEXT NOT NULL,
+  `visibility` VARCHAR(255) NOT NULL DEFAULT 'PRIVATE'
+);
+
+CREATE INDEX `idx_memo_revision_memo_id` ON `memo_revision` (`memo_id`);
+
 -- resource
 CREATE TABLE `resource` (
   `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
@@ -87,9 +101,34 @@ CREATE TABLE `resource` (
   `type` VARCHAR(255) NOT NULL DEFAULT '',
   `size` INT NOT NULL DEFAULT '0',
   `internal_path` VARCHAR(255) NOT NULL DEFAULT '',
-  `memo_id` INT DEFAULT NULL
+  `memo_id` INT DEFAULT NULL,
+  `storage_id` INT NOT NULL DEFAULT '0',
+  `hash` VARCHAR(64) NOT NULL DEFAULT '',
+  `captured_ts` BIGINT NOT NULL DEFAULT 0,
+  `width` INT NOT NULL DEFAULT 0,
+  `height` INT NOT NULL DEFAULT 0,
+  `duration` BIGINT NOT NULL DEFAULT 0
 );
 
+CREATE INDEX `idx_resource_hash` ON `resource` (`hash`);
+
+-- resource_upload
+CREATE TABLE `resource_upload` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `creator_id` INT NOT NULL,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `filename` TEXT NOT NULL,
+  `type` VARCHAR(256) NOT NULL DEFAULT '',
+  `size` BIGINT NOT NULL DEFAULT 0,
+  `storage_id` INT NOT NULL DEFAULT 0,
+  `upload_offset` BIGINT NOT NULL DEFAULT 0,
+  `part_count` INT NOT NULL DEFAULT 0,
+  `hash_state` BLOB
+);
+
+CREATE INDEX `idx_resource_upload_updated_ts` ON `resource_upload` (`updated_ts`);
+
 -- tag
 CREATE TABLE `tag` (
   `name` VARCHAR(255) NOT NULL,
@@ -0,0 +1,2 @@
+-- The index is built from the existing memos when it's added.
+ALTER TABLE `memo` ADD FULLTEXT INDEX `idx_memo_content` (`content`) WITH PARSER ngram;
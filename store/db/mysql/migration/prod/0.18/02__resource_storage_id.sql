@@ -0,0 +1,2 @@
+-- The storage in which internal_path is the key of the blob, or 0 for the database, the local storage and the external links.
+ALTER TABLE `resource` ADD COLUMN `storage_id` INT NOT NULL DEFAULT '0';
@@ -0,0 +1,4 @@
+-- The SHA-256 hash of the blob, with which the same blobs are shared.
+ALTER TABLE `resource` ADD COLUMN `hash` VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX `idx_resource_hash` ON `resource` (`hash`);
@@ -0,0 +1,16 @@
+-- resource_upload
+CREATE TABLE `resource_upload` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `creator_id` INT NOT NULL,
+  `created_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_ts` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `filename` TEXT NOT NULL,
+  `type` VARCHAR(256) NOT NULL DEFAULT '',
+  `size` BIGINT NOT NULL DEFAULT 0,
+  `storage_id` INT NOT NULL DEFAULT 0,
+  `upload_offset` BIGINT NOT NULL DEFAULT 0,
+  `part_count` INT NOT NULL DEFAULT 0,
+  `hash_state` BLOB
+);
+
+CREATE INDEX `idx_resource_upload_updated_ts` ON `resource_upload` (`updated_ts`);
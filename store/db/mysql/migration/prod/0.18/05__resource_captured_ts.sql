@@ -0,0 +1,2 @@
+-- The time when the photo was taken, read from its EXIF.
+ALTER TABLE `resource` ADD COLUMN `captured_ts` BIGINT NOT NULL DEFAULT 0;
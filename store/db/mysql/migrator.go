@@ -75,7 +75,7 @@ func (d *DB) nonProdMigrate(ctx context.Context) error {
 }
 
 func (d *DB) prodMigrate(ctx context.Context) error {
-	currentVersion := version.GetCurrentVersion(d.profile.Mode)
+	currentVersion := getLatestSchemaVersion(version.GetCurrentVersion(d.profile.Mode))
 	migrationHistoryList, err := d.FindMigrationHistoryList(ctx, &MigrationHistoryFind{})
 	// If there is no migration history, we should apply the latest schema.
 	if err != nil || len(migrationHistoryList) == 0 {
@@ -204,3 +204,17 @@ func getMinorVersionList() []string {
 
 	return minorVersionList
 }
+
+// getLatestSchemaVersion returns the version of the prod LATEST schema, which includes the migrations of every minor version.
+// It's the newest minor version in migration/prod while it's still in development, so that its migrations aren't applied again
+// once it's released.
+func getLatestSchemaVersion(currentVersion string) string {
+	minorVersionList := getMinorVersionList()
+	if len(minorVersionList) == 0 {
+		return currentVersion
+	}
+	if latestVersion := minorVersionList[len(minorVersionList)-1] + ".0"; version.IsVersionGreaterThan(latestVersion, currentVersion) {
+		return latestVersion
+	}
+	return currentVersion
+}
@@ -67,6 +67,9 @@ func (d *DB) Vacuum(ctx context.Context) error {
 	if err := vacuumMemoRelations(ctx, tx); err != nil {
 		return err
 	}
+	if err := vacuumMemoRevision(ctx, tx); err != nil {
+		return err
+	}
 	if err := vacuumTag(ctx, tx); err != nil {
 		// Prevent revive warning.
 		return err
@@ -75,8 +78,9 @@ func (d *DB) Vacuum(ctx context.Context) error {
 	return tx.Commit()
 }
 
-func (*DB) BackupTo(context.Context, string) error {
-	return errors.New("Please use mysqldump to backup")
+// BackupTo writes the logical dump archive of the database into the file.
+func (d *DB) BackupTo(ctx context.Context, filename string) error {
+	return store.DumpToFile(ctx, d, d.profile.Version, filename)
 }
 
 func (d *DB) GetCurrentDBSize(ctx context.Context) (int64, error) {
@@ -12,9 +12,9 @@ import (
 )
 
 func (d *DB) CreateResource(ctx context.Context, create *store.Resource) (*store.Resource, error) {
-	fields := []string{"`filename`", "`blob`", "`external_link`", "`type`", "`size`", "`creator_id`", "`internal_path`"}
-	placeholder := []string{"?", "?", "?", "?", "?", "?", "?"}
-	args := []any{create.Filename, create.Blob, create.ExternalLink, create.Type, create.Size, create.CreatorID, create.InternalPath}
+	fields := []string{"`filename`", "`blob`", "`external_link`", "`type`", "`size`", "`creator_id`", "`internal_path`", "`storage_id`", "`hash`", "`captured_ts`", "`width`", "`height`", "`duration`"}
+	placeholder := []string{"?", "?", "?", "?", "?", "?", "?", "?", "?", "?", "?", "?", "?"}
+	args := []any{create.Filename, create.Blob, create.ExternalLink, create.Type, create.Size, create.CreatorID, create.InternalPath, create.StorageID, create.Hash, create.CapturedTs, create.Width, create.Height, create.Duration}
 
 	if create.ID != 0 {
 		fields = append(fields, "`id`")
@@ -40,11 +40,29 @@ func (d *DB) CreateResource(ctx context.Context, create *store.Resource) (*store
 		args = append(args, *create.MemoID)
 	}
 
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if create.SharedBlob {
+		references, _, err := lockResourceBlobReferences(ctx, tx, create)
+		if err != nil {
+			return nil, err
+		}
+		if len(references) == 0 {
+			return nil, store.ErrResourceBlobDeleted
+		}
+	}
+
 	stmt := "INSERT INTO `resource` (" + strings.Join(fields, ", ") + ") VALUES (" + strings.Join(placeholder, ", ") + ")"
-	result, err := d.db.ExecContext(ctx, stmt, args...)
+	result, err := tx.ExecContext(ctx, stmt, args...)
 	if err != nil {
 		return nil, err
 	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
@@ -78,16 +96,31 @@ func (d *DB) ListResources(ctx context.Context, find *store.FindResource) ([]*st
 	if v := find.MemoID; v != nil {
 		where, args = append(where, "`memo_id` = ?"), append(args, *v)
 	}
+	if v := find.Hash; v != nil {
+		where, args = append(where, "`hash` = ?"), append(args, *v)
+	}
+	if v := find.StorageID; v != nil {
+		where, args = append(where, "`storage_id` = ?"), append(args, *v)
+	}
+	if v := find.InternalPath; v != nil {
+		where, args = append(where, "`internal_path` = ?"), append(args, *v)
+	}
+	if v := find.ExternalLink; v != nil {
+		where, args = append(where, "`external_link` = ?"), append(args, *v)
+	}
 	if find.HasRelatedMemo {
 		where = append(where, "`memo_id` IS NOT NULL")
 	}
+	if v := find.Cursor; v != nil {
+		where, args = append(where, "(UNIX_TIMESTAMP(`created_ts`) < ? OR (UNIX_TIMESTAMP(`created_ts`) = ? AND `id` < ?))"), append(args, v.Ts, v.Ts, v.ID)
+	}
 
-	fields := []string{"`id`", "`filename`", "`external_link`", "`type`", "`size`", "`creator_id`", "UNIX_TIMESTAMP(`created_ts`)", "UNIX_TIMESTAMP(`updated_ts`)", "`internal_path`", "`memo_id`"}
+	fields := []string{"`id`", "`filename`", "`external_link`", "`type`", "`size`", "`creator_id`", "UNIX_TIMESTAMP(`created_ts`)", "UNIX_TIMESTAMP(`updated_ts`)", "`internal_path`", "`memo_id`", "`storage_id`", "`hash`", "`captured_ts`", "`width`", "`height`", "`duration`"}
 	if find.GetBlob {
 		fields = append(fields, "`blob`")
 	}
 
-	query := fmt.Sprintf("SELECT %s FROM `resource` WHERE %s GROUP BY `id` ORDER BY `created_ts` DESC", strings.Join(fields, ", "), strings.Join(where, " AND "))
+	query := fmt.Sprintf("SELECT %s FROM `resource` WHERE %s GROUP BY `id` ORDER BY `created_ts` DESC, `id` DESC", strings.Join(fields, ", "), strings.Join(where, " AND "))
 	if find.Limit != nil {
 		query = fmt.Sprintf("%s LIMIT %d", query, *find.Limit)
 		if find.Offset != nil {
@@ -116,6 +149,12 @@ func (d *DB) ListResources(ctx context.Context, find *store.FindResource) ([]*st
 			&resource.UpdatedTs,
 			&resource.InternalPath,
 			&memoID,
+			&resource.StorageID,
+			&resource.Hash,
+			&resource.CapturedTs,
+			&resource.Width,
+			&resource.Height,
+			&resource.Duration,
 		}
 		if find.GetBlob {
 			dests = append(dests, &resource.Blob)
@@ -148,6 +187,15 @@ func (d *DB) UpdateResource(ctx context.Context, update *store.UpdateResource) (
 	if v := update.InternalPath; v != nil {
 		set, args = append(set, "`internal_path` = ?"), append(args, *v)
 	}
+	if v := update.ExternalLink; v != nil {
+		set, args = append(set, "`external_link` = ?"), append(args, *v)
+	}
+	if v := update.StorageID; v != nil {
+		set, args = append(set, "`storage_id` = ?"), append(args, *v)
+	}
+	if v := update.Hash; v != nil {
+		set, args = append(set, "`hash` = ?"), append(args, *v)
+	}
 	if v := update.MemoID; v != nil {
 		set, args = append(set, "`memo_id` = ?"), append(args, *v)
 	}
@@ -172,22 +220,89 @@ func (d *DB) UpdateResource(ctx context.Context, update *store.UpdateResource) (
 	return list[0], nil
 }
 
-func (d *DB) DeleteResource(ctx context.Context, delete *store.DeleteResource) error {
-	stmt := "DELETE FROM `resource` WHERE `id` = ?"
-	result, err := d.db.ExecContext(ctx, stmt, delete.ID)
+func (d *DB) GetResourceBlob(ctx context.Context, find *store.FindResourceBlob) (*store.ResourceBlob, error) {
+	// The positions of SUBSTRING start from 1.
+	stmt, args := "SELECT SUBSTRING(`blob`, ?), COALESCE(LENGTH(`blob`), 0) FROM resource WHERE id = ?", []any{find.Offset + 1, find.ID}
+	if find.Length >= 0 {
+		stmt, args = "SELECT SUBSTRING(`blob`, ?, ?), COALESCE(LENGTH(`blob`), 0) FROM resource WHERE id = ?", []any{find.Offset + 1, find.Length, find.ID}
+	}
+	blob := &store.ResourceBlob{}
+	if err := d.db.QueryRowContext(ctx, stmt, args...).Scan(&blob.Blob, &blob.Size); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return blob, nil
+}
+
+func (d *DB) DeleteResource(ctx context.Context, delete *store.DeleteResource) (bool, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return false, err
 	}
-	if _, err := result.RowsAffected(); err != nil {
-		return err
+	defer tx.Rollback()
+
+	resource, blob := &store.Resource{ID: delete.ID}, []byte{}
+	stmt := "SELECT `storage_id`, `internal_path`, `external_link`, `hash`, `blob` FROM `resource` WHERE `id` = ? FOR UPDATE"
+	if err := tx.QueryRowContext(ctx, stmt, delete.ID).Scan(&resource.StorageID, &resource.InternalPath, &resource.ExternalLink, &resource.Hash, &blob); err != nil {
+		return false, err
+	}
+	references, held, err := lockResourceBlobReferences(ctx, tx, resource)
+	if err != nil {
+		return false, err
+	}
+	// Hand over the blob in the database to another resource sharing it, if none of them holds it.
+	if len(blob) > 0 && len(references) > 0 && !held {
+		if _, err := tx.ExecContext(ctx, "UPDATE `resource` SET `blob` = ? WHERE `id` = ?", blob, references[0]); err != nil {
+			return false, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM `resource` WHERE `id` = ?", delete.ID); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
 	}
 
 	if err := d.Vacuum(ctx); err != nil {
 		// Prevent linter warning.
-		return err
+		return false, err
 	}
 
-	return nil
+	return len(references) == 0, nil
+}
+
+// lockResourceBlobReferences returns the IDs of the other resources sharing the blob of the resource, the same as
+// store.ListResourceBlobReferences, and whether any of them holds the blob in the database.
+// They're locked for update, so the references can't change until the end of the transaction.
+func lockResourceBlobReferences(ctx context.Context, tx *sql.Tx, resource *store.Resource) ([]int32, bool, error) {
+	where, args := []string{"`id` != ?", "`storage_id` = ?", "`internal_path` = ?", "`external_link` = ?"}, []any{resource.ID, resource.StorageID, resource.InternalPath, resource.ExternalLink}
+	if resource.InternalPath == "" && resource.ExternalLink == "" {
+		if resource.Hash == "" {
+			return []int32{}, false, nil
+		}
+		where, args = append(where, "`hash` = ?"), append(args, resource.Hash)
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT `id`, COALESCE(LENGTH(`blob`), 0) > 0 FROM `resource` WHERE "+strings.Join(where, " AND ")+" ORDER BY `id` FOR UPDATE", args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	references, held := []int32{}, false
+	for rows.Next() {
+		var id int32
+		var holding bool
+		if err := rows.Scan(&id, &holding); err != nil {
+			return nil, false, err
+		}
+		references, held = append(references, id), held || holding
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	return references, held, nil
 }
 
 func vacuumResource(ctx context.Context, tx *sql.Tx) error {
@@ -67,6 +67,12 @@ func (d *DB) UpsertUserSettingV1(ctx context.Context, upsert *storepb.UserSettin
 			return nil, err
 		}
 		valueString = string(valueBytes)
+	} else if upsert.Key == storepb.UserSettingKey_USER_SETTING_SAVED_FILTERS {
+		valueBytes, err := protojson.Marshal(upsert.GetSavedFilters())
+		if err != nil {
+			return nil, err
+		}
+		valueString = string(valueBytes)
 	} else {
 		return nil, errors.New("invalid user setting key")
 	}
@@ -115,6 +121,14 @@ func (d *DB) ListUserSettingsV1(ctx context.Context, find *store.FindUserSetting
 			userSetting.Value = &storepb.UserSetting_AccessTokens{
 				AccessTokens: accessTokensUserSetting,
 			}
+		} else if userSetting.Key == storepb.UserSettingKey_USER_SETTING_SAVED_FILTERS {
+			savedFiltersUserSetting := &storepb.SavedFiltersUserSetting{}
+			if err := protojson.Unmarshal([]byte(valueString), savedFiltersUserSetting); err != nil {
+				return nil, err
+			}
+			userSetting.Value = &storepb.UserSetting_SavedFilters{
+				SavedFilters: savedFiltersUserSetting,
+			}
 		} else {
 			// Skip unknown user setting v1 key.
 			continue
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var (
+	protojsonUnmarshaler = protojson.UnmarshalOptions{
+		DiscardUnknown: true,
+	}
+)
+
+// placeholder returns the n-th positional parameter, e.g. $1.
+func placeholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// placeholders returns the positional parameters from $1 to $n separated by commas.
+func placeholders(n int) string {
+	list := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		list = append(list, placeholder(i))
+	}
+	return strings.Join(list, ", ")
+}
+
+// syncIDSequence moves the id sequence of the table to its max id,
+// so that the rows created later don't collide with the ones created with explicit ids.
+func (d *DB) syncIDSequence(ctx context.Context, table string) error {
+	stmt := fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('%s', 'id'), (SELECT MAX(id) FROM %s))`, table, table)
+	if _, err := d.db.ExecContext(ctx, stmt); err != nil {
+		return errors.Wrapf(err, "failed to sync the id sequence of %s", table)
+	}
+	return nil
+}
@@ -0,0 +1,430 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/internal/util"
+	"github.com/usememos/memos/store"
+)
+
+func (d *DB) CreateMemo(ctx context.Context, create *store.Memo) (*store.Memo, error) {
+	fields := []string{"creator_id", "content", "visibility"}
+	args := []any{create.CreatorID, create.Content, create.Visibility}
+
+	if create.ID != 0 {
+		fields, args = append(fields, "id"), append(args, create.ID)
+	}
+	if create.CreatedTs != 0 {
+		fields, args = append(fields, "created_ts"), append(args, create.CreatedTs)
+	}
+	if create.UpdatedTs != 0 {
+		fields, args = append(fields, "updated_ts"), append(args, create.UpdatedTs)
+	}
+	if create.RowStatus != "" {
+		fields, args = append(fields, "row_status"), append(args, create.RowStatus)
+	}
+
+	explicitID := create.ID != 0
+	stmt := "INSERT INTO memo (" + strings.Join(fields, ", ") + ") VALUES (" + placeholders(len(args)) + ") RETURNING id, created_ts, updated_ts, row_status"
+	if err := d.db.QueryRowContext(ctx, stmt, args...).Scan(
+		&create.ID,
+		&create.CreatedTs,
+		&create.UpdatedTs,
+		&create.RowStatus,
+	); err != nil {
+		return nil, err
+	}
+	if explicitID {
+		if err := d.syncIDSequence(ctx, `memo`); err != nil {
+			return nil, err
+		}
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListMemos(ctx context.Context, find *store.FindMemo) ([]*store.Memo, error) {
+	where, args := []string{"1 = 1"}, []any{}
+
+	if v := find.ID; v != nil {
+		where, args = append(where, "memo.id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.IDList; len(v) != 0 {
+		list := []string{}
+		for _, id := range v {
+			list, args = append(list, placeholder(len(args)+1)), append(args, id)
+		}
+		where = append(where, fmt.Sprintf("memo.id IN (%s)", strings.Join(list, ",")))
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, "memo.creator_id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, "memo.row_status = "+placeholder(len(args)+1)), append(args, *v)
+	} else {
+		where, args = append(where, "memo.row_status != "+placeholder(len(args)+1)), append(args, store.Deleted)
+	}
+	if v := find.CreatedTsBefore; v != nil {
+		where, args = append(where, "memo.created_ts < "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsAfter; v != nil {
+		where, args = append(where, "memo.created_ts > "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.UpdatedTsBefore; v != nil {
+		where, args = append(where, "memo.updated_ts < "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.ParentID; v != nil {
+		where, args = append(where, "memo.id IN (SELECT memo_id FROM memo_relation WHERE related_memo_id = "+placeholder(len(args)+1)+" AND type = 'COMMENT')"), append(args, *v)
+	}
+	if v := find.Filter; v != nil {
+		condition, filterArgs, err := convertMemoFilterToWhere(v, args)
+		if err != nil {
+			return nil, err
+		}
+		where, args = append(where, condition), filterArgs
+	}
+	if v := find.ContentSearch; len(v) != 0 {
+		for _, s := range v {
+			where, args = append(where, "memo.content ILIKE "+placeholder(len(args)+1)), append(args, "%"+s+"%")
+		}
+	}
+	if v := find.VisibilityList; len(v) != 0 {
+		list := []string{}
+		for _, visibility := range v {
+			list, args = append(list, placeholder(len(args)+1)), append(args, visibility)
+		}
+		where = append(where, fmt.Sprintf("memo.visibility IN (%s)", strings.Join(list, ",")))
+	}
+	pinned := "EXISTS (SELECT 1 FROM memo_organizer WHERE memo_organizer.memo_id = memo.id AND memo_organizer.user_id = memo.creator_id AND memo_organizer.pinned = 1)"
+	if v := find.Pinned; v != nil {
+		where = append(where, pinned)
+	}
+	if v := find.Cursor; v != nil {
+		ts := "memo.created_ts"
+		if find.OrderByUpdatedTs {
+			ts = "memo.updated_ts"
+		}
+		pinnedArg, tsArg, idArg := placeholder(len(args)+1), placeholder(len(args)+2), placeholder(len(args)+3)
+		where = append(where, fmt.Sprintf("(%s < %s OR (%s = %s AND (%s < %s OR (%s = %s AND memo.id < %s))))", pinned, pinnedArg, pinned, pinnedArg, ts, tsArg, ts, tsArg, idArg))
+		args = append(args, v.Pinned, v.Ts, v.ID)
+	}
+	having := []string{"1 = 1"}
+	if v := find.HasParent; v != nil {
+		if *v {
+			having = append(having, "parent_id IS NOT NULL")
+		} else {
+			having = append(having, "parent_id IS NULL")
+		}
+	}
+
+	orders := []string{"pinned DESC"}
+	if find.OrderByUpdatedTs {
+		orders = append(orders, "updated_ts DESC")
+	} else {
+		orders = append(orders, "created_ts DESC")
+	}
+	orders = append(orders, "id DESC")
+
+	fields := []string{
+		`memo.id AS id,`,
+		`memo.creator_id AS creator_id,`,
+		`memo.created_ts AS created_ts,`,
+		`memo.updated_ts AS updated_ts,`,
+		`memo.row_status AS row_status,`,
+		`memo.visibility AS visibility,`,
+	}
+	if !find.ExcludeContent {
+		fields = append(fields, `memo.content AS content,`)
+	}
+
+	// The memos are listed from a derived table so that the conditions on the parent_id can refer to it.
+	query := `
+	SELECT * FROM (
+		SELECT
+		` + strings.Join(fields, "\n") + `
+		MAX(CASE WHEN mo.pinned = 1 THEN 1 ELSE 0 END) AS pinned,
+		(
+			SELECT
+				related_memo_id
+			FROM
+				memo_relation
+			WHERE
+				memo_relation.memo_id = memo.id AND memo_relation.type = 'COMMENT'
+			LIMIT 1
+		) AS parent_id,
+		STRING_AGG(DISTINCT resource.id::TEXT, ',') AS resource_id_list,
+		(
+			SELECT
+				STRING_AGG(CONCAT_WS(':', memo_relation.memo_id, memo_relation.related_memo_id, memo_relation.type), ',')
+			FROM
+				memo_relation
+			WHERE
+				memo_relation.memo_id = memo.id OR memo_relation.related_memo_id = memo.id
+		) AS relation_list
+		FROM
+			memo
+		LEFT JOIN
+			memo_organizer mo ON memo.id = mo.memo_id AND mo.user_id = memo.creator_id
+		LEFT JOIN
+			resource ON memo.id = resource.memo_id
+		WHERE ` + strings.Join(where, " AND ") + `
+		GROUP BY memo.id
+	) AS memo_list
+	WHERE ` + strings.Join(having, " AND ") + `
+	ORDER BY ` + strings.Join(orders, ", ")
+	if find.Limit != nil {
+		query = fmt.Sprintf("%s LIMIT %d", query, *find.Limit)
+		if find.Offset != nil {
+			query = fmt.Sprintf("%s OFFSET %d", query, *find.Offset)
+		}
+	}
+
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]*store.Memo, 0)
+	for rows.Next() {
+		var memo store.Memo
+		var memoResourceIDList sql.NullString
+		var memoRelationList sql.NullString
+		dests := []any{
+			&memo.ID,
+			&memo.CreatorID,
+			&memo.CreatedTs,
+			&memo.UpdatedTs,
+			&memo.RowStatus,
+			&memo.Visibility,
+		}
+		if !find.ExcludeContent {
+			dests = append(dests, &memo.Content)
+		}
+		dests = append(dests, &memo.Pinned, &memo.ParentID, &memoResourceIDList, &memoRelationList)
+		if err := rows.Scan(dests...); err != nil {
+			return nil, err
+		}
+
+		if memoResourceIDList.Valid {
+			idStringList := strings.Split(memoResourceIDList.String, ",")
+			memo.ResourceIDList = make([]int32, 0, len(idStringList))
+			for _, idString := range idStringList {
+				id, err := util.ConvertStringToInt32(idString)
+				if err != nil {
+					return nil, err
+				}
+				memo.ResourceIDList = append(memo.ResourceIDList, id)
+			}
+		}
+		if memoRelationList.Valid {
+			memo.RelationList = make([]*store.MemoRelation, 0)
+			relatedMemoTypeList := strings.Split(memoRelationList.String, ",")
+			for _, relatedMemoType := range relatedMemoTypeList {
+				relatedMemoTypeList := strings.Split(relatedMemoType, ":")
+				if len(relatedMemoTypeList) != 3 {
+					return nil, errors.New("invalid relation format")
+				}
+				memoID, err := util.ConvertStringToInt32(relatedMemoTypeList[0])
+				if err != nil {
+					return nil, err
+				}
+				relatedMemoID, err := util.ConvertStringToInt32(relatedMemoTypeList[1])
+				if err != nil {
+					return nil, err
+				}
+				relationType := store.MemoRelationType(relatedMemoTypeList[2])
+				memo.RelationList = append(memo.RelationList, &store.MemoRelation{
+					MemoID:        memoID,
+					RelatedMemoID: relatedMemoID,
+					Type:          relationType,
+				})
+			}
+		}
+		list = append(list, &memo)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *DB) UpdateMemo(ctx context.Context, update *store.UpdateMemo) error {
+	set, args := []string{}, []any{}
+	if v := update.CreatedTs; v != nil {
+		set, args = append(set, "created_ts = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.UpdatedTs; v != nil {
+		set, args = append(set, "updated_ts = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.RowStatus; v != nil {
+		set, args = append(set, "row_status = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.Content; v != nil {
+		set, args = append(set, "content = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.Visibility; v != nil {
+		set, args = append(set, "visibility = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	args = append(args, update.ID)
+
+	stmt := "UPDATE memo SET " + strings.Join(set, ", ") + " WHERE id = " + placeholder(len(args))
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	// The revision is written with the update, so it's always the content right before it.
+	if err := createMemoRevision(ctx, tx, update); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (d *DB) DeleteMemo(ctx context.Context, delete *store.DeleteMemo) error {
+	result, err := d.db.ExecContext(ctx, "DELETE FROM memo WHERE id = $1", delete.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := result.RowsAffected(); err != nil {
+		return err
+	}
+
+	if err := d.Vacuum(ctx); err != nil {
+		// Prevent linter warning.
+		return err
+	}
+	return nil
+}
+
+func (d *DB) FindMemosVisibilityList(ctx context.Context, memoIDs []int32) ([]store.Visibility, error) {
+	args := make([]any, 0, len(memoIDs))
+	list := make([]string, 0, len(memoIDs))
+	for _, memoID := range memoIDs {
+		list, args = append(list, placeholder(len(args)+1)), append(args, memoID)
+	}
+
+	where := fmt.Sprintf("id IN (%s)", strings.Join(list, ","))
+	query := "SELECT DISTINCT(visibility) FROM memo WHERE " + where
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	visibilityList := make([]store.Visibility, 0)
+	for rows.Next() {
+		var visibility store.Visibility
+		if err := rows.Scan(&visibility); err != nil {
+			return nil, err
+		}
+		visibilityList = append(visibilityList, visibility)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return visibilityList, nil
+}
+
+func (d *DB) SearchMemos(ctx context.Context, find *store.SearchMemo) ([]*store.MemoSearchResult, error) {
+	terms := store.SplitSearchQuery(find.Query)
+	where, args := []string{"1 = 1"}, []any{}
+	// Every term is matched case-insensitively with the pg_trgm index of the content,
+	// and the memos are ranked by the similarity of the query to the closest words in them.
+	for _, term := range terms {
+		where, args = append(where, "memo.content ILIKE "+placeholder(len(args)+1)+" ESCAPE '\\'"), append(args, "%"+store.EscapeLikePattern(term)+"%")
+	}
+	rank := "word_similarity(" + placeholder(len(args)+1) + ", memo.content)"
+	args = append(args, strings.Join(terms, " "))
+
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, "memo.creator_id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, "memo.row_status = "+placeholder(len(args)+1)), append(args, *v)
+	} else {
+		where, args = append(where, "memo.row_status != "+placeholder(len(args)+1)), append(args, store.Deleted)
+	}
+	if v := find.VisibilityList; len(v) != 0 {
+		list := []string{}
+		for _, visibility := range v {
+			list, args = append(list, placeholder(len(args)+1)), append(args, visibility)
+		}
+		where = append(where, fmt.Sprintf("memo.visibility IN (%s)", strings.Join(list, ",")))
+	}
+	if v := find.Filter; v != nil {
+		condition, filterArgs, err := convertMemoFilterToWhere(v, args)
+		if err != nil {
+			return nil, err
+		}
+		where, args = append(where, condition), filterArgs
+	}
+	// The results are ranked in a derived table so that the cursor can refer to the rank.
+	having := []string{"1 = 1"}
+	if v := find.Cursor; v != nil {
+		rankArg, tsArg, idArg := placeholder(len(args)+1), placeholder(len(args)+2), placeholder(len(args)+3)
+		having = append(having, fmt.Sprintf("(rank < %s OR (rank = %s AND (created_ts < %s OR (created_ts = %s AND id < %s))))", rankArg, rankArg, tsArg, tsArg, idArg))
+		args = append(args, v.Rank, v.Ts, v.ID)
+	}
+
+	query := `
+	SELECT id, content, created_ts, rank FROM (
+		SELECT
+			memo.id AS id,
+			memo.content AS content,
+			memo.created_ts AS created_ts,
+			CAST(` + rank + ` AS DOUBLE PRECISION) AS rank
+		FROM memo
+		WHERE ` + strings.Join(where, " AND ") + `
+	) AS search_result
+	WHERE ` + strings.Join(having, " AND ") + `
+	ORDER BY rank DESC, created_ts DESC, id DESC`
+	if find.Limit != nil {
+		query = fmt.Sprintf("%s LIMIT %d", query, *find.Limit)
+	}
+
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]*store.MemoSearchResult, 0)
+	for rows.Next() {
+		result := &store.MemoSearchResult{}
+		var content string
+		if err := rows.Scan(&result.MemoID, &content, &result.CreatedTs, &result.Rank); err != nil {
+			return nil, err
+		}
+		result.Snippet = store.BuildSearchSnippet(content, terms)
+		list = append(list, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func vacuumMemo(ctx context.Context, tx *sql.Tx) error {
+	stmt := `DELETE FROM memo WHERE creator_id NOT IN (SELECT id FROM "user")`
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/store"
+)
+
+func (d *DB) CreateMemoRevision(ctx context.Context, create *store.MemoRevision) (*store.MemoRevision, error) {
+	fields := []string{"memo_id", "content", "visibility"}
+	args := []any{create.MemoID, create.Content, create.Visibility}
+	if create.CreatedTs != 0 {
+		fields, args = append(fields, "created_ts"), append(args, create.CreatedTs)
+	}
+
+	stmt := "INSERT INTO memo_revision (" + strings.Join(fields, ", ") + ") VALUES (" + placeholders(len(args)) + ") RETURNING id, created_ts"
+	if err := d.db.QueryRowContext(ctx, stmt, args...).Scan(
+		&create.ID,
+		&create.CreatedTs,
+	); err != nil {
+		return nil, err
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListMemoRevisions(ctx context.Context, find *store.FindMemoRevision) ([]*store.MemoRevision, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.MemoID; v != nil {
+		where, args = append(where, "memo_id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+
+	query := "SELECT id, memo_id, created_ts, content, visibility FROM memo_revision WHERE " + strings.Join(where, " AND ") + " ORDER BY created_ts DESC, id DESC"
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*store.MemoRevision{}
+	for rows.Next() {
+		memoRevision := &store.MemoRevision{}
+		if err := rows.Scan(
+			&memoRevision.ID,
+			&memoRevision.MemoID,
+			&memoRevision.CreatedTs,
+			&memoRevision.Content,
+			&memoRevision.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, memoRevision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *DB) DeleteMemoRevision(ctx context.Context, delete *store.DeleteMemoRevision) error {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := delete.ID; v != nil {
+		where, args = append(where, "id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := delete.MemoID; v != nil {
+		where, args = append(where, "memo_id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := delete.CreatedTsBefore; v != nil {
+		where, args = append(where, "created_ts < "+placeholder(len(args)+1)), append(args, *v)
+	}
+	stmt := "DELETE FROM memo_revision WHERE " + strings.Join(where, " AND ")
+	if _, err := d.db.ExecContext(ctx, stmt, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func vacuumMemoRevision(ctx context.Context, tx *sql.Tx) error {
+	stmt := "DELETE FROM memo_revision WHERE memo_id NOT IN (SELECT id FROM memo)"
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// createMemoRevision keeps the current content and visibility of the memo as a revision if the update changes them.
+func createMemoRevision(ctx context.Context, tx *sql.Tx, update *store.UpdateMemo) error {
+	if update.Content == nil && update.Visibility == nil {
+		return nil
+	}
+	var content string
+	var visibility store.Visibility
+	if err := tx.QueryRowContext(ctx, "SELECT content, visibility FROM memo WHERE id = $1 FOR UPDATE", update.ID).Scan(&content, &visibility); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	contentChanged := update.Content != nil && *update.Content != content
+	visibilityChanged := update.Visibility != nil && *update.Visibility != visibility
+	if !contentChanged && !visibilityChanged {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, "INSERT INTO memo_revision (memo_id, content, visibility) VALUES ($1, $2, $3)", update.ID, content, visibility)
+	return err
+}
@@ -0,0 +1,317 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/usememos/memos/store"
+)
+
+func (d *DB) CreateResource(ctx context.Context, create *store.Resource) (*store.Resource, error) {
+	fields := []string{"filename", "blob", "external_link", "type", "size", "creator_id", "internal_path", "storage_id", "hash", "captured_ts", "width", "height", "duration"}
+	args := []any{create.Filename, create.Blob, create.ExternalLink, create.Type, create.Size, create.CreatorID, create.InternalPath, create.StorageID, create.Hash, create.CapturedTs, create.Width, create.Height, create.Duration}
+
+	if create.ID != 0 {
+		fields, args = append(fields, "id"), append(args, create.ID)
+	}
+	if create.CreatedTs != 0 {
+		fields, args = append(fields, "created_ts"), append(args, create.CreatedTs)
+	}
+	if create.UpdatedTs != 0 {
+		fields, args = append(fields, "updated_ts"), append(args, create.UpdatedTs)
+	}
+	if create.MemoID != nil {
+		fields, args = append(fields, "memo_id"), append(args, *create.MemoID)
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if create.SharedBlob {
+		references, _, err := lockResourceBlobReferences(ctx, tx, create)
+		if err != nil {
+			return nil, err
+		}
+		if len(references) == 0 {
+			return nil, store.ErrResourceBlobDeleted
+		}
+	}
+
+	explicitID := create.ID != 0
+	stmt := "INSERT INTO resource (" + strings.Join(fields, ", ") + ") VALUES (" + placeholders(len(args)) + ") RETURNING id, created_ts, updated_ts"
+	if err := tx.QueryRowContext(ctx, stmt, args...).Scan(&create.ID, &create.CreatedTs, &create.UpdatedTs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if explicitID {
+		if err := d.syncIDSequence(ctx, `resource`); err != nil {
+			return nil, err
+		}
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListResources(ctx context.Context, find *store.FindResource) ([]*store.Resource, error) {
+	where, args := []string{"1 = 1"}, []any{}
+
+	if v := find.ID; v != nil {
+		where, args = append(where, "id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, "creator_id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.Filename; v != nil {
+		where, args = append(where, "filename = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.MemoID; v != nil {
+		where, args = append(where, "memo_id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.Hash; v != nil {
+		where, args = append(where, "hash = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.StorageID; v != nil {
+		where, args = append(where, "storage_id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.InternalPath; v != nil {
+		where, args = append(where, "internal_path = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.ExternalLink; v != nil {
+		where, args = append(where, "external_link = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if find.HasRelatedMemo {
+		where = append(where, "memo_id IS NOT NULL")
+	}
+	if v := find.Cursor; v != nil {
+		where = append(where, fmt.Sprintf("(created_ts < %s OR (created_ts = %s AND id < %s))", placeholder(len(args)+1), placeholder(len(args)+1), placeholder(len(args)+2)))
+		args = append(args, v.Ts, v.ID)
+	}
+
+	fields := []string{"id", "filename", "external_link", "type", "size", "creator_id", "created_ts", "updated_ts", "internal_path", "memo_id", "storage_id", "hash", "captured_ts", "width", "height", "duration"}
+	if find.GetBlob {
+		fields = append(fields, "blob")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s
+		FROM resource
+		WHERE %s
+		ORDER BY created_ts DESC, id DESC
+	`, strings.Join(fields, ", "), strings.Join(where, " AND "))
+	if find.Limit != nil {
+		query = fmt.Sprintf("%s LIMIT %d", query, *find.Limit)
+		if find.Offset != nil {
+			query = fmt.Sprintf("%s OFFSET %d", query, *find.Offset)
+		}
+	}
+
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]*store.Resource, 0)
+	for rows.Next() {
+		resource := store.Resource{}
+		var memoID sql.NullInt32
+		dests := []any{
+			&resource.ID,
+			&resource.Filename,
+			&resource.ExternalLink,
+			&resource.Type,
+			&resource.Size,
+			&resource.CreatorID,
+			&resource.CreatedTs,
+			&resource.UpdatedTs,
+			&resource.InternalPath,
+			&memoID,
+			&resource.StorageID,
+			&resource.Hash,
+			&resource.CapturedTs,
+			&resource.Width,
+			&resource.Height,
+			&resource.Duration,
+		}
+		if find.GetBlob {
+			dests = append(dests, &resource.Blob)
+		}
+		if err := rows.Scan(dests...); err != nil {
+			return nil, err
+		}
+		if memoID.Valid {
+			resource.MemoID = &memoID.Int32
+		}
+		list = append(list, &resource)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *DB) UpdateResource(ctx context.Context, update *store.UpdateResource) (*store.Resource, error) {
+	set, args := []string{}, []any{}
+
+	if v := update.UpdatedTs; v != nil {
+		set, args = append(set, "updated_ts = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.Filename; v != nil {
+		set, args = append(set, "filename = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.InternalPath; v != nil {
+		set, args = append(set, "internal_path = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.ExternalLink; v != nil {
+		set, args = append(set, "external_link = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.StorageID; v != nil {
+		set, args = append(set, "storage_id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.Hash; v != nil {
+		set, args = append(set, "hash = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.MemoID; v != nil {
+		set, args = append(set, "memo_id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.Blob; v != nil {
+		set, args = append(set, "blob = "+placeholder(len(args)+1)), append(args, v)
+	}
+
+	args = append(args, update.ID)
+	fields := []string{"id", "filename", "external_link", "type", "size", "creator_id", "created_ts", "updated_ts", "internal_path", "storage_id", "hash", "captured_ts", "width", "height", "duration"}
+	stmt := `
+		UPDATE resource
+		SET ` + strings.Join(set, ", ") + `
+		WHERE id = ` + placeholder(len(args)) + `
+		RETURNING ` + strings.Join(fields, ", ")
+	resource := store.Resource{}
+	dests := []any{
+		&resource.ID,
+		&resource.Filename,
+		&resource.ExternalLink,
+		&resource.Type,
+		&resource.Size,
+		&resource.CreatorID,
+		&resource.CreatedTs,
+		&resource.UpdatedTs,
+		&resource.InternalPath,
+		&resource.StorageID,
+		&resource.Hash,
+		&resource.CapturedTs,
+		&resource.Width,
+		&resource.Height,
+		&resource.Duration,
+	}
+	if err := d.db.QueryRowContext(ctx, stmt, args...).Scan(dests...); err != nil {
+		return nil, err
+	}
+
+	return &resource, nil
+}
+
+func (d *DB) GetResourceBlob(ctx context.Context, find *store.FindResourceBlob) (*store.ResourceBlob, error) {
+	// The positions of substring start from 1.
+	stmt, args := "SELECT substring(blob FROM $1), COALESCE(octet_length(blob), 0) FROM resource WHERE id = $2", []any{find.Offset + 1, find.ID}
+	if find.Length >= 0 {
+		stmt, args = "SELECT substring(blob FROM $1 FOR $2), COALESCE(octet_length(blob), 0) FROM resource WHERE id = $3", []any{find.Offset + 1, find.Length, find.ID}
+	}
+	blob := &store.ResourceBlob{}
+	if err := d.db.QueryRowContext(ctx, stmt, args...).Scan(&blob.Blob, &blob.Size); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return blob, nil
+}
+
+func (d *DB) DeleteResource(ctx context.Context, delete *store.DeleteResource) (bool, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	resource, blob := &store.Resource{ID: delete.ID}, []byte{}
+	stmt := "SELECT storage_id, internal_path, external_link, hash, blob FROM resource WHERE id = $1 FOR UPDATE"
+	if err := tx.QueryRowContext(ctx, stmt, delete.ID).Scan(&resource.StorageID, &resource.InternalPath, &resource.ExternalLink, &resource.Hash, &blob); err != nil {
+		return false, err
+	}
+	references, held, err := lockResourceBlobReferences(ctx, tx, resource)
+	if err != nil {
+		return false, err
+	}
+	// Hand over the blob in the database to another resource sharing it, if none of them holds it.
+	if len(blob) > 0 && len(references) > 0 && !held {
+		if _, err := tx.ExecContext(ctx, "UPDATE resource SET blob = $1 WHERE id = $2", blob, references[0]); err != nil {
+			return false, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM resource WHERE id = $1", delete.ID); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	if err := d.Vacuum(ctx); err != nil {
+		// Prevent linter warning.
+		return false, err
+	}
+
+	return len(references) == 0, nil
+}
+
+// lockResourceBlobReferences returns the IDs of the other resources sharing the blob of the resource, the same as
+// store.ListResourceBlobReferences, and whether any of them holds the blob in the database.
+// They're locked for update, so the references can't change until the end of the transaction.
+func lockResourceBlobReferences(ctx context.Context, tx *sql.Tx, resource *store.Resource) ([]int32, bool, error) {
+	where, args := []string{"id != $1", "storage_id = $2", "internal_path = $3", "external_link = $4"}, []any{resource.ID, resource.StorageID, resource.InternalPath, resource.ExternalLink}
+	if resource.InternalPath == "" && resource.ExternalLink == "" {
+		if resource.Hash == "" {
+			return []int32{}, false, nil
+		}
+		where, args = append(where, "hash = "+placeholder(len(args)+1)), append(args, resource.Hash)
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT id, COALESCE(LENGTH(blob), 0) > 0 FROM resource WHERE "+strings.Join(where, " AND ")+" ORDER BY id FOR UPDATE", args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	references, held := []int32{}, false
+	for rows.Next() {
+		var id int32
+		var holding bool
+		if err := rows.Scan(&id, &holding); err != nil {
+			return nil, false, err
+		}
+		references, held = append(references, id), held || holding
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	return references, held, nil
+}
+
+func vacuumResource(ctx context.Context, tx *sql.Tx) error {
+	stmt := `DELETE FROM resource WHERE creator_id NOT IN (SELECT id FROM "user")`
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
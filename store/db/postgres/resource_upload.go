@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/usememos/memos/store"
+)
+
+func (d *DB) CreateResourceUpload(ctx context.Context, create *store.ResourceUpload) (*store.ResourceUpload, error) {
+	fields := []string{"creator_id", "filename", "type", "size", "storage_id", "upload_offset", "part_count", "hash_state"}
+	args := []any{create.CreatorID, create.Filename, create.Type, create.Size, create.StorageID, create.Offset, create.PartCount, create.HashState}
+
+	stmt := "INSERT INTO resource_upload (" + strings.Join(fields, ", ") + ") VALUES (" + placeholders(len(args)) + ") RETURNING id, created_ts, updated_ts"
+	if err := d.db.QueryRowContext(ctx, stmt, args...).Scan(
+		&create.ID,
+		&create.CreatedTs,
+		&create.UpdatedTs,
+	); err != nil {
+		return nil, err
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListResourceUploads(ctx context.Context, find *store.FindResourceUpload) ([]*store.ResourceUpload, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, "creator_id = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := find.UpdatedTsBefore; v != nil {
+		where, args = append(where, "updated_ts < "+placeholder(len(args)+1)), append(args, *v)
+	}
+
+	query := "SELECT id, creator_id, created_ts, updated_ts, filename, type, size, storage_id, upload_offset, part_count, hash_state FROM resource_upload WHERE " + strings.Join(where, " AND ") + " ORDER BY id DESC"
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*store.ResourceUpload{}
+	for rows.Next() {
+		resourceUpload := &store.ResourceUpload{}
+		if err := rows.Scan(
+			&resourceUpload.ID,
+			&resourceUpload.CreatorID,
+			&resourceUpload.CreatedTs,
+			&resourceUpload.UpdatedTs,
+			&resourceUpload.Filename,
+			&resourceUpload.Type,
+			&resourceUpload.Size,
+			&resourceUpload.StorageID,
+			&resourceUpload.Offset,
+			&resourceUpload.PartCount,
+			&resourceUpload.HashState,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, resourceUpload)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *DB) UpdateResourceUpload(ctx context.Context, update *store.UpdateResourceUpload) (*store.ResourceUpload, error) {
+	set, args := []string{}, []any{}
+	if v := update.UpdatedTs; v != nil {
+		set, args = append(set, "updated_ts = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.Offset; v != nil {
+		set, args = append(set, "upload_offset = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.PartCount; v != nil {
+		set, args = append(set, "part_count = "+placeholder(len(args)+1)), append(args, *v)
+	}
+	if v := update.HashState; v != nil {
+		set, args = append(set, "hash_state = "+placeholder(len(args)+1)), append(args, v)
+	}
+	args = append(args, update.ID)
+
+	stmt := "UPDATE resource_upload SET " + strings.Join(set, ", ") + " WHERE id = " + placeholder(len(args)) + " RETURNING id, creator_id, created_ts, updated_ts, filename, type, size, storage_id, upload_offset, part_count, hash_state"
+	resourceUpload := &store.ResourceUpload{}
+	if err := d.db.QueryRowContext(ctx, stmt, args...).Scan(
+		&resourceUpload.ID,
+		&resourceUpload.CreatorID,
+		&resourceUpload.CreatedTs,
+		&resourceUpload.UpdatedTs,
+		&resourceUpload.Filename,
+		&resourceUpload.Type,
+		&resourceUpload.Size,
+		&resourceUpload.StorageID,
+		&resourceUpload.Offset,
+		&resourceUpload.PartCount,
+		&resourceUpload.HashState,
+	); err != nil {
+		return nil, err
+	}
+
+	return resourceUpload, nil
+}
+
+func (d *DB) DeleteResourceUpload(ctx context.Context, delete *store.DeleteResourceUpload) error {
+	stmt := "DELETE FROM resource_upload WHERE id = " + placeholder(1)
+	if _, err := d.db.ExecContext(ctx, stmt, delete.ID); err != nil {
+		return err
+	}
+	return nil
+}
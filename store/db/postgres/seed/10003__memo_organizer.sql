@@ -0,0 +1,5 @@
+INSERT INTO
+  memo_organizer (memo_id, user_id, pinned)
+VALUES
+  (1, 101, 1),
+  (3, 101, 1);
@@ -0,0 +1,6 @@
+INSERT INTO
+  tag (name, creator_id)
+VALUES
+  ('Hello', 101),
+  ('TODO', 101),
+  ('TODO', 102);
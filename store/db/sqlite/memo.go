@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 
@@ -60,11 +61,20 @@ func (d *DB) ListMemos(ctx context.Context, find *store.FindMemo) ([]*store.Memo
 	if v := find.ID; v != nil {
 		where, args = append(where, "memo.id = ?"), append(args, *v)
 	}
+	if v := find.IDList; len(v) != 0 {
+		list := []string{}
+		for _, id := range v {
+			list, args = append(list, "?"), append(args, id)
+		}
+		where = append(where, fmt.Sprintf("memo.id IN (%s)", strings.Join(list, ",")))
+	}
 	if v := find.CreatorID; v != nil {
 		where, args = append(where, "memo.creator_id = ?"), append(args, *v)
 	}
 	if v := find.RowStatus; v != nil {
 		where, args = append(where, "memo.row_status = ?"), append(args, *v)
+	} else {
+		where, args = append(where, "memo.row_status != ?"), append(args, store.Deleted)
 	}
 	if v := find.CreatedTsBefore; v != nil {
 		where, args = append(where, "memo.created_ts < ?"), append(args, *v)
@@ -72,6 +82,19 @@ func (d *DB) ListMemos(ctx context.Context, find *store.FindMemo) ([]*store.Memo
 	if v := find.CreatedTsAfter; v != nil {
 		where, args = append(where, "memo.created_ts > ?"), append(args, *v)
 	}
+	if v := find.UpdatedTsBefore; v != nil {
+		where, args = append(where, "memo.updated_ts < ?"), append(args, *v)
+	}
+	if v := find.ParentID; v != nil {
+		where, args = append(where, "memo.id IN (SELECT memo_id FROM memo_relation WHERE related_memo_id = ? AND type = 'COMMENT')"), append(args, *v)
+	}
+	if v := find.Filter; v != nil {
+		condition, filterArgs, err := convertMemoFilterToWhere(v)
+		if err != nil {
+			return nil, err
+		}
+		where, args = append(where, condition), append(args, filterArgs...)
+	}
 	if v := find.ContentSearch; len(v) != 0 {
 		for _, s := range v {
 			where, args = append(where, "memo.content LIKE ?"), append(args, "%"+s+"%")
@@ -88,6 +111,15 @@ func (d *DB) ListMemos(ctx context.Context, find *store.FindMemo) ([]*store.Memo
 	if v := find.Pinned; v != nil {
 		where = append(where, "memo_organizer.pinned = 1")
 	}
+	if v := find.Cursor; v != nil {
+		ts := "memo.created_ts"
+		if find.OrderByUpdatedTs {
+			ts = "memo.updated_ts"
+		}
+		pinned := "EXISTS (SELECT 1 FROM memo_organizer WHERE memo_organizer.memo_id = memo.id AND memo_organizer.user_id = memo.creator_id AND memo_organizer.pinned = 1)"
+		where = append(where, fmt.Sprintf("(%s < ? OR (%s = ? AND (%s < ? OR (%s = ? AND memo.id < ?))))", pinned, pinned, ts, ts))
+		args = append(args, v.Pinned, v.Pinned, v.Ts, v.Ts, v.ID)
+	}
 	if v := find.HasParent; v != nil {
 		if *v {
 			where = append(where, "parent_id IS NOT NULL")
@@ -141,7 +173,7 @@ func (d *DB) ListMemos(ctx context.Context, find *store.FindMemo) ([]*store.Memo
 		FROM
 			memo
 		LEFT JOIN
-			memo_organizer mo ON memo.id = mo.memo_id
+			memo_organizer mo ON memo.id = mo.memo_id AND mo.user_id = memo.creator_id
 		LEFT JOIN
 			resource ON memo.id = resource.memo_id
 		WHERE ` + strings.Join(where, " AND ") + `
@@ -250,10 +282,19 @@ func (d *DB) UpdateMemo(ctx context.Context, update *store.UpdateMemo) error {
 		SET ` + strings.Join(set, ", ") + `
 		WHERE id = ?
 	`
-	if _, err := d.db.ExecContext(ctx, stmt, args...); err != nil {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
 		return err
 	}
-	return nil
+	defer tx.Rollback()
+	// The revision is written with the update, so it's always the content right before it.
+	if err := createMemoRevision(ctx, tx, update); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (d *DB) DeleteMemo(ctx context.Context, delete *store.DeleteMemo) error {
@@ -306,6 +347,93 @@ func (d *DB) FindMemosVisibilityList(ctx context.Context, memoIDs []int32) ([]st
 	return visibilityList, nil
 }
 
+func (d *DB) SearchMemos(ctx context.Context, find *store.SearchMemo) ([]*store.MemoSearchResult, error) {
+	terms := store.SplitSearchQuery(find.Query)
+	where, args := []string{"1 = 1"}, []any{}
+	matchTerms := []string{}
+	for _, term := range terms {
+		// The trigram tokenizer can't match the terms shorter than three characters.
+		if utf8.RuneCountInString(term) < 3 {
+			where, args = append(where, "memo.content LIKE ? ESCAPE '\\'"), append(args, "%"+store.EscapeLikePattern(term)+"%")
+			continue
+		}
+		matchTerms = append(matchTerms, `"`+strings.ReplaceAll(term, `"`, `""`)+`"`)
+	}
+	from, rank := "memo", "0"
+	if len(matchTerms) > 0 {
+		from, rank = "memo_fts JOIN memo ON memo.id = memo_fts.rowid", "-bm25(memo_fts)"
+		where, args = append(where, "memo_fts MATCH ?"), append(args, strings.Join(matchTerms, " "))
+	}
+
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, "memo.creator_id = ?"), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, "memo.row_status = ?"), append(args, *v)
+	} else {
+		where, args = append(where, "memo.row_status != ?"), append(args, store.Deleted)
+	}
+	if v := find.VisibilityList; len(v) != 0 {
+		list := []string{}
+		for _, visibility := range v {
+			list = append(list, "?")
+			args = append(args, visibility)
+		}
+		where = append(where, fmt.Sprintf("memo.visibility IN (%s)", strings.Join(list, ",")))
+	}
+	if v := find.Filter; v != nil {
+		condition, filterArgs, err := convertMemoFilterToWhere(v)
+		if err != nil {
+			return nil, err
+		}
+		where, args = append(where, condition), append(args, filterArgs...)
+	}
+	// The results are ranked in a derived table so that the cursor can refer to the rank.
+	having := []string{"1 = 1"}
+	if v := find.Cursor; v != nil {
+		having, args = append(having, "(rank < ? OR (rank = ? AND (created_ts < ? OR (created_ts = ? AND id < ?))))"), append(args, v.Rank, v.Rank, v.Ts, v.Ts, v.ID)
+	}
+
+	query := `
+	SELECT id, content, created_ts, rank FROM (
+		SELECT
+			memo.id AS id,
+			memo.content AS content,
+			memo.created_ts AS created_ts,
+			` + rank + ` AS rank
+		FROM ` + from + `
+		WHERE ` + strings.Join(where, " AND ") + `
+	)
+	WHERE ` + strings.Join(having, " AND ") + `
+	ORDER BY rank DESC, created_ts DESC, id DESC`
+	if find.Limit != nil {
+		query = fmt.Sprintf("%s LIMIT %d", query, *find.Limit)
+	}
+
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]*store.MemoSearchResult, 0)
+	for rows.Next() {
+		result := &store.MemoSearchResult{}
+		var content string
+		if err := rows.Scan(&result.MemoID, &content, &result.CreatedTs, &result.Rank); err != nil {
+			return nil, err
+		}
+		result.Snippet = store.BuildSearchSnippet(content, terms)
+		list = append(list, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
 func vacuumMemo(ctx context.Context, tx *sql.Tx) error {
 	stmt := `
 	DELETE FROM 
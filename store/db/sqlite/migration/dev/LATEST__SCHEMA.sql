@@ -4,9 +4,12 @@ DROP TABLE IF EXISTS system_setting;
 DROP TABLE IF EXISTS user;
 DROP TABLE IF EXISTS user_setting;
 DROP TABLE IF EXISTS memo;
+DROP TABLE IF EXISTS memo_fts;
 DROP TABLE IF EXISTS memo_organizer;
 DROP TABLE IF EXISTS memo_relation;
+DROP TABLE IF EXISTS memo_revision;
 DROP TABLE IF EXISTS resource;
+DROP TABLE IF EXISTS resource_upload;
 DROP TABLE IF EXISTS tag;
 DROP TABLE IF EXISTS activity;
 DROP TABLE IF EXISTS storage;
@@ -57,7 +60,7 @@ CREATE TABLE memo (
   creator_id INTEGER NOT NULL,
   created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
   updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
-  row_status TEXT NOT NULL CHECK (row_status IN ('NORMAL', 'ARCHIVED')) DEFAULT 'NORMAL',
+  row_status TEXT NOT NULL CHECK (row_status IN ('NORMAL', 'ARCHIVED', 'DELETED')) DEFAULT 'NORMAL',
   content TEXT NOT NULL DEFAULT '',
   visibility TEXT NOT NULL CHECK (visibility IN ('PUBLIC', 'PROTECTED', 'PRIVATE')) DEFAULT 'PRIVATE'
 );
@@ -66,6 +69,22 @@ CREATE INDEX idx_memo_creator_id ON memo (creator_id);
 CREATE INDEX idx_memo_content ON memo (content);
 CREATE INDEX idx_memo_visibility ON memo (visibility);
 
+-- memo_fts
+CREATE VIRTUAL TABLE memo_fts USING fts5(content, content='memo', content_rowid='id', tokenize='trigram');
+
+CREATE TRIGGER memo_fts_after_insert AFTER INSERT ON memo BEGIN
+  INSERT INTO memo_fts (rowid, content) VALUES (new.id, new.content);
+END;
+
+CREATE TRIGGER memo_fts_after_update AFTER UPDATE OF content ON memo BEGIN
+  INSERT INTO memo_fts (memo_fts, rowid, content) VALUES ('delete', old.id, old.content);
+  INSERT INTO memo_fts (rowid, content) VALUES (new.id, new.content);
+END;
+
+CREATE TRIGGER memo_fts_after_delete AFTER DELETE ON memo BEGIN
+  INSERT INTO memo_fts (memo_fts, rowid, content) VALUES ('delete', old.id, old.content);
+END;
+
 -- memo_organizer
 CREATE TABLE memo_organizer (
   memo_id INTEGER NOT NULL,
@@ -82,6 +101,17 @@ CREATE TABLE memo_relation (
   UNIQUE(memo_id, related_memo_id, type)
 );
 
+-- memo_revision
+CREATE TABLE memo_revision (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  memo_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  content TEXT NOT NULL DEFAULT '',
+  visibility TEXT NOT NULL CHECK (visibility IN ('PUBLIC', 'PROTECTED', 'PRIVATE')) DEFAULT 'PRIVATE'
+);
+
+CREATE INDEX idx_memo_revision_memo_id ON memo_revision (memo_id);
+
 -- resource
 CREATE TABLE resource (
   id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -94,13 +124,38 @@ CREATE TABLE resource (
   type TEXT NOT NULL DEFAULT '',
   size INTEGER NOT NULL DEFAULT 0,
   internal_path TEXT NOT NULL DEFAULT '',
-  memo_id INTEGER
+  memo_id INTEGER,
+  storage_id INTEGER NOT NULL DEFAULT 0,
+  hash TEXT NOT NULL DEFAULT '',
+  captured_ts BIGINT NOT NULL DEFAULT 0,
+  width INTEGER NOT NULL DEFAULT 0,
+  height INTEGER NOT NULL DEFAULT 0,
+  duration BIGINT NOT NULL DEFAULT 0
 );
 
 CREATE INDEX idx_resource_creator_id ON resource (creator_id);
 
+CREATE INDEX idx_resource_hash ON resource (hash);
+
 CREATE INDEX idx_resource_memo_id ON resource (memo_id);
 
+-- resource_upload
+CREATE TABLE resource_upload (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  creator_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  filename TEXT NOT NULL DEFAULT '',
+  type TEXT NOT NULL DEFAULT '',
+  size INTEGER NOT NULL DEFAULT 0,
+  storage_id INTEGER NOT NULL DEFAULT 0,
+  upload_offset INTEGER NOT NULL DEFAULT 0,
+  part_count INTEGER NOT NULL DEFAULT 0,
+  hash_state BLOB DEFAULT NULL
+);
+
+CREATE INDEX idx_resource_upload_updated_ts ON resource_upload (updated_ts);
+
 -- tag
 CREATE TABLE tag (
   name TEXT NOT NULL,
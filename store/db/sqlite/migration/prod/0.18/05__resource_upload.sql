@@ -0,0 +1,16 @@
+-- resource_upload
+CREATE TABLE resource_upload (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  creator_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  filename TEXT NOT NULL DEFAULT '',
+  type TEXT NOT NULL DEFAULT '',
+  size INTEGER NOT NULL DEFAULT 0,
+  storage_id INTEGER NOT NULL DEFAULT 0,
+  upload_offset INTEGER NOT NULL DEFAULT 0,
+  part_count INTEGER NOT NULL DEFAULT 0,
+  hash_state BLOB DEFAULT NULL
+);
+
+CREATE INDEX idx_resource_upload_updated_ts ON resource_upload (updated_ts);
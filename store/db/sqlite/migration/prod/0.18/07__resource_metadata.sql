@@ -0,0 +1,4 @@
+-- The dimensions of the images and the videos, and the durations of the audio and the videos in milliseconds.
+ALTER TABLE resource ADD COLUMN width INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE resource ADD COLUMN height INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE resource ADD COLUMN duration BIGINT NOT NULL DEFAULT 0;
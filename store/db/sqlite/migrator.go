@@ -24,6 +24,7 @@ var seedFS embed.FS
 // Migrate applies the latest schema to the database.
 func (d *DB) Migrate(ctx context.Context) error {
 	currentVersion := version.GetCurrentVersion(d.profile.Mode)
+	schemaVersion := getLatestSchemaVersion(currentVersion)
 	if d.profile.Mode == "prod" {
 		_, err := os.Stat(d.profile.DSN)
 		if err != nil {
@@ -34,7 +35,7 @@ func (d *DB) Migrate(ctx context.Context) error {
 				}
 				// Upsert the newest version to migration_history.
 				if _, err := d.UpsertMigrationHistory(ctx, &MigrationHistoryUpsert{
-					Version: currentVersion,
+					Version: schemaVersion,
 				}); err != nil {
 					return errors.Wrap(err, "failed to upsert migration history")
 				}
@@ -69,7 +70,7 @@ func (d *DB) Migrate(ctx context.Context) error {
 			sort.Sort(version.SortVersion(migrationHistoryVersionList))
 			latestMigrationHistoryVersion := migrationHistoryVersionList[len(migrationHistoryVersionList)-1]
 
-			if version.IsVersionGreaterThan(version.GetSchemaVersion(currentVersion), latestMigrationHistoryVersion) {
+			if version.IsVersionGreaterThan(version.GetSchemaVersion(schemaVersion), latestMigrationHistoryVersion) {
 				minorVersionList := getMinorVersionList()
 				// backup the raw database file before migration
 				rawBytes, err := os.ReadFile(d.profile.DSN)
@@ -84,7 +85,7 @@ func (d *DB) Migrate(ctx context.Context) error {
 				println("start migrate")
 				for _, minorVersion := range minorVersionList {
 					normalizedVersion := minorVersion + ".0"
-					if version.IsVersionGreaterThan(normalizedVersion, latestMigrationHistoryVersion) && version.IsVersionGreaterOrEqualThan(currentVersion, normalizedVersion) {
+					if version.IsVersionGreaterThan(normalizedVersion, latestMigrationHistoryVersion) && version.IsVersionGreaterOrEqualThan(schemaVersion, normalizedVersion) {
 						println("applying migration for", normalizedVersion)
 						if err := d.applyMigrationForMinorVersion(ctx, minorVersion); err != nil {
 							return errors.Wrap(err, "failed to apply minor version migration")
@@ -231,3 +232,17 @@ func getMinorVersionList() []string {
 
 	return minorVersionList
 }
+
+// getLatestSchemaVersion returns the version of the prod LATEST schema, which includes the migrations of every minor version.
+// It's the newest minor version in migration/prod while it's still in development, so that its migrations aren't applied again
+// once it's released.
+func getLatestSchemaVersion(currentVersion string) string {
+	minorVersionList := getMinorVersionList()
+	if len(minorVersionList) == 0 {
+		return currentVersion
+	}
+	if latestVersion := minorVersionList[len(minorVersionList)-1] + ".0"; version.IsVersionGreaterThan(latestVersion, currentVersion) {
+		return latestVersion
+	}
+	return currentVersion
+}
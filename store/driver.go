@@ -28,20 +28,35 @@ type Driver interface {
 	CreateResource(ctx context.Context, create *Resource) (*Resource, error)
 	ListResources(ctx context.Context, find *FindResource) ([]*Resource, error)
 	UpdateResource(ctx context.Context, update *UpdateResource) (*Resource, error)
-	DeleteResource(ctx context.Context, delete *DeleteResource) error
+	DeleteResource(ctx context.Context, delete *DeleteResource) (bool, error)
+	// GetResourceBlob reads the range of the blob by the database, and returns nil if the resource is not found.
+	GetResourceBlob(ctx context.Context, find *FindResourceBlob) (*ResourceBlob, error)
+
+	// ResourceUpload model related methods.
+	CreateResourceUpload(ctx context.Context, create *ResourceUpload) (*ResourceUpload, error)
+	ListResourceUploads(ctx context.Context, find *FindResourceUpload) ([]*ResourceUpload, error)
+	UpdateResourceUpload(ctx context.Context, update *UpdateResourceUpload) (*ResourceUpload, error)
+	DeleteResourceUpload(ctx context.Context, delete *DeleteResourceUpload) error
 
 	// Memo model related methods.
 	CreateMemo(ctx context.Context, create *Memo) (*Memo, error)
 	ListMemos(ctx context.Context, find *FindMemo) ([]*Memo, error)
+	// UpdateMemo writes the revision of the changed content or visibility in the same transaction as the update.
 	UpdateMemo(ctx context.Context, update *UpdateMemo) error
 	DeleteMemo(ctx context.Context, delete *DeleteMemo) error
 	FindMemosVisibilityList(ctx context.Context, memoIDs []int32) ([]Visibility, error)
+	SearchMemos(ctx context.Context, find *SearchMemo) ([]*MemoSearchResult, error)
 
 	// MemoRelation model related methods.
 	UpsertMemoRelation(ctx context.Context, create *MemoRelation) (*MemoRelation, error)
 	ListMemoRelations(ctx context.Context, find *FindMemoRelation) ([]*MemoRelation, error)
 	DeleteMemoRelation(ctx context.Context, delete *DeleteMemoRelation) error
 
+	// MemoRevision model related methods.
+	CreateMemoRevision(ctx context.Context, create *MemoRevision) (*MemoRevision, error)
+	ListMemoRevisions(ctx context.Context, find *FindMemoRevision) ([]*MemoRevision, error)
+	DeleteMemoRevision(ctx context.Context, delete *DeleteMemoRevision) error
+
 	// MemoOrganizer model related methods.
 	UpsertMemoOrganizer(ctx context.Context, upsert *MemoOrganizer) (*MemoOrganizer, error)
 	ListMemoOrganizer(ctx context.Context, find *FindMemoOrganizer) ([]*MemoOrganizer, error)
@@ -0,0 +1,443 @@
+package store
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	storepb "github.com/usememos/memos/proto/gen/store"
+)
+
+// DumpFormat is the version of the dump archive format.
+// It's increased whenever a change of the format can't be read by the previous versions.
+const DumpFormat = 1
+
+// dumpResourcePageSize is the number of resources with blobs that are loaded at a time.
+const dumpResourcePageSize = 100
+
+// DumpHeader is the first record of the dump archive.
+type DumpHeader struct {
+	Format int `json:"format"`
+	// Version is the memos version which created the dump.
+	Version   string `json:"version"`
+	CreatedTs int64  `json:"createdTs"`
+}
+
+// dumpRecord is a row of a table in the dump archive.
+type dumpRecord struct {
+	Table string          `json:"table"`
+	Row   json.RawMessage `json:"row"`
+}
+
+// dumpActivity and dumpInbox keep the payloads in their protojson forms.
+type dumpActivity struct {
+	ID        int32
+	CreatorID int32
+	CreatedTs int64
+	Type      ActivityType
+	Level     ActivityLevel
+	Payload   json.RawMessage
+}
+
+type dumpInbox struct {
+	ID         int32
+	CreatedTs  int64
+	SenderID   int32
+	ReceiverID int32
+	Status     InboxStatus
+	Message    json.RawMessage
+}
+
+// Dump writes all the rows of the driver into the writer as a gzip compressed archive of JSON lines,
+// which starts with a DumpHeader and can be restored into an empty database of any driver.
+// The resource uploads in progress aren't dumped.
+func Dump(ctx context.Context, driver Driver, version string, w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	encoder := json.NewEncoder(gzipWriter)
+	if err := encoder.Encode(&DumpHeader{
+		Format:    DumpFormat,
+		Version:   version,
+		CreatedTs: time.Now().Unix(),
+	}); err != nil {
+		return errors.Wrap(err, "failed to write dump header")
+	}
+
+	write := func(table string, row any) error {
+		bytes, err := json.Marshal(row)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal %s row", table)
+		}
+		if err := encoder.Encode(&dumpRecord{Table: table, Row: bytes}); err != nil {
+			return errors.Wrapf(err, "failed to write %s row", table)
+		}
+		return nil
+	}
+
+	systemSettings, err := driver.ListSystemSettings(ctx, &FindSystemSetting{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list system settings")
+	}
+	for _, systemSetting := range systemSettings {
+		if err := write("system_setting", systemSetting); err != nil {
+			return err
+		}
+	}
+
+	users, err := driver.ListUsers(ctx, &FindUser{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list users")
+	}
+	for _, user := range users {
+		if err := write("user", user); err != nil {
+			return err
+		}
+	}
+
+	userSettings, err := driver.ListUserSettings(ctx, &FindUserSetting{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list user settings")
+	}
+	for _, userSetting := range userSettings {
+		if err := write("user_setting", userSetting); err != nil {
+			return err
+		}
+	}
+
+	identityProviders, err := driver.ListIdentityProviders(ctx, &FindIdentityProvider{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list identity providers")
+	}
+	for _, identityProvider := range identityProviders {
+		if err := write("idp", identityProvider); err != nil {
+			return err
+		}
+	}
+
+	storages, err := driver.ListStorages(ctx, &FindStorage{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list storages")
+	}
+	for _, storage := range storages {
+		if err := write("storage", storage); err != nil {
+			return err
+		}
+	}
+
+	// The memos in the trash are only listed on request.
+	memos, err := driver.ListMemos(ctx, &FindMemo{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list memos")
+	}
+	deleted := Deleted
+	deletedMemos, err := driver.ListMemos(ctx, &FindMemo{RowStatus: &deleted})
+	if err != nil {
+		return errors.Wrap(err, "failed to list deleted memos")
+	}
+	for _, memo := range append(memos, deletedMemos...) {
+		if err := write("memo", memo); err != nil {
+			return err
+		}
+	}
+
+	memoOrganizers, err := driver.ListMemoOrganizer(ctx, &FindMemoOrganizer{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list memo organizers")
+	}
+	for _, memoOrganizer := range memoOrganizers {
+		if err := write("memo_organizer", memoOrganizer); err != nil {
+			return err
+		}
+	}
+
+	memoRelations, err := driver.ListMemoRelations(ctx, &FindMemoRelation{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list memo relations")
+	}
+	for _, memoRelation := range memoRelations {
+		if err := write("memo_relation", memoRelation); err != nil {
+			return err
+		}
+	}
+
+	memoRevisions, err := driver.ListMemoRevisions(ctx, &FindMemoRevision{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list memo revisions")
+	}
+	for _, memoRevision := range memoRevisions {
+		if err := write("memo_revision", memoRevision); err != nil {
+			return err
+		}
+	}
+
+	// The resources are loaded page by page to keep the blobs out of memory.
+	limit := dumpResourcePageSize
+	var cursor *PageCursor
+	for {
+		resources, err := driver.ListResources(ctx, &FindResource{GetBlob: true, Limit: &limit, Cursor: cursor})
+		if err != nil {
+			return errors.Wrap(err, "failed to list resources")
+		}
+		for _, resource := range resources {
+			if err := write("resource", resource); err != nil {
+				return err
+			}
+		}
+		if len(resources) < limit {
+			break
+		}
+		last := resources[len(resources)-1]
+		cursor = &PageCursor{Ts: last.CreatedTs, ID: last.ID}
+	}
+
+	tags, err := driver.ListTags(ctx, &FindTag{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list tags")
+	}
+	for _, tag := range tags {
+		if err := write("tag", tag); err != nil {
+			return err
+		}
+	}
+
+	activities, err := driver.ListActivities(ctx, &FindActivity{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list activities")
+	}
+	for _, activity := range activities {
+		payload, err := protojson.Marshal(activity.Payload)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal activity payload")
+		}
+		if err := write("activity", &dumpActivity{
+			ID:        activity.ID,
+			CreatorID: activity.CreatorID,
+			CreatedTs: activity.CreatedTs,
+			Type:      activity.Type,
+			Level:     activity.Level,
+			Payload:   payload,
+		}); err != nil {
+			return err
+		}
+	}
+
+	inboxes, err := driver.ListInboxes(ctx, &FindInbox{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list inboxes")
+	}
+	for _, inbox := range inboxes {
+		message, err := protojson.Marshal(inbox.Message)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal inbox message")
+		}
+		if err := write("inbox", &dumpInbox{
+			ID:         inbox.ID,
+			CreatedTs:  inbox.CreatedTs,
+			SenderID:   inbox.SenderID,
+			ReceiverID: inbox.ReceiverID,
+			Status:     inbox.Status,
+			Message:    message,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return gzipWriter.Close()
+}
+
+// DumpToFile writes the dump archive of the driver into the file.
+// The archive is written to a temporary file first, so that an incomplete one never replaces the file.
+func DumpToFile(ctx context.Context, driver Driver, version, filename string) error {
+	tmpFilename := filename + ".tmp"
+	file, err := os.Create(tmpFilename)
+	if err != nil {
+		return errors.Wrap(err, "failed to create dump file")
+	}
+	if err := Dump(ctx, driver, version, file); err != nil {
+		file.Close()
+		os.Remove(tmpFilename)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpFilename)
+		return errors.Wrap(err, "failed to close dump file")
+	}
+	if err := os.Rename(tmpFilename, filename); err != nil {
+		return errors.Wrap(err, "failed to rename dump file")
+	}
+	return nil
+}
+
+// Restore loads the dump archive into the driver, whose database must be migrated and empty.
+// The rows keep their ids, so the references between them stay the same.
+// It returns the header of the archive.
+func Restore(ctx context.Context, driver Driver, r io.Reader) (*DumpHeader, error) {
+	users, err := driver.ListUsers(ctx, &FindUser{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list users")
+	}
+	if len(users) > 0 {
+		return nil, errors.New("the database is not empty")
+	}
+
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read dump archive")
+	}
+	defer gzipReader.Close()
+	decoder := json.NewDecoder(gzipReader)
+
+	header := &DumpHeader{}
+	if err := decoder.Decode(header); err != nil {
+		return nil, errors.Wrap(err, "failed to read dump header")
+	}
+	if header.Format < 1 || header.Format > DumpFormat {
+		return nil, errors.Errorf("unsupported dump format %d", header.Format)
+	}
+
+	for {
+		record := &dumpRecord{}
+		if err := decoder.Decode(record); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrap(err, "failed to read dump record")
+		}
+		if err := restoreRecord(ctx, driver, record); err != nil {
+			return nil, errors.Wrapf(err, "failed to restore %s row", record.Table)
+		}
+	}
+	return header, nil
+}
+
+func restoreRecord(ctx context.Context, driver Driver, record *dumpRecord) error {
+	var err error
+	switch record.Table {
+	case "system_setting":
+		row := &SystemSetting{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		_, err = driver.UpsertSystemSetting(ctx, row)
+	case "user":
+		row := &User{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		_, err = driver.CreateUser(ctx, row)
+	case "user_setting":
+		row := &UserSetting{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		_, err = driver.UpsertUserSetting(ctx, row)
+	case "idp":
+		row := &IdentityProvider{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		_, err = driver.CreateIdentityProvider(ctx, row)
+	case "storage":
+		row := &Storage{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		_, err = driver.CreateStorage(ctx, row)
+	case "memo":
+		row := &Memo{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		_, err = driver.CreateMemo(ctx, &Memo{
+			ID:         row.ID,
+			RowStatus:  row.RowStatus,
+			CreatorID:  row.CreatorID,
+			CreatedTs:  row.CreatedTs,
+			UpdatedTs:  row.UpdatedTs,
+			Content:    row.Content,
+			Visibility: row.Visibility,
+		})
+	case "memo_organizer":
+		row := &MemoOrganizer{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		_, err = driver.UpsertMemoOrganizer(ctx, row)
+	case "memo_relation":
+		row := &MemoRelation{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		_, err = driver.UpsertMemoRelation(ctx, row)
+	case "memo_revision":
+		row := &MemoRevision{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		_, err = driver.CreateMemoRevision(ctx, row)
+	case "resource":
+		row := &Resource{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		_, err = driver.CreateResource(ctx, row)
+	case "tag":
+		row := &Tag{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		_, err = driver.UpsertTag(ctx, row)
+	case "activity":
+		row := &dumpActivity{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		payload := &storepb.ActivityPayload{}
+		if err := protojson.Unmarshal(row.Payload, payload); err != nil {
+			return err
+		}
+		_, err = driver.CreateActivity(ctx, &Activity{
+			ID:        row.ID,
+			CreatorID: row.CreatorID,
+			CreatedTs: row.CreatedTs,
+			Type:      row.Type,
+			Level:     row.Level,
+			Payload:   payload,
+		})
+	case "inbox":
+		row := &dumpInbox{}
+		if err := json.Unmarshal(record.Row, row); err != nil {
+			return err
+		}
+		message := &storepb.InboxMessage{}
+		if err := protojson.Unmarshal(row.Message, message); err != nil {
+			return err
+		}
+		_, err = driver.CreateInbox(ctx, &Inbox{
+			ID:         row.ID,
+			CreatedTs:  row.CreatedTs,
+			SenderID:   row.SenderID,
+			ReceiverID: row.ReceiverID,
+			Status:     row.Status,
+			Message:    message,
+		})
+	default:
+		// Skip the tables unknown to this version.
+	}
+	return err
+}
+
+// Dump writes the dump archive of the store into the writer.
+func (s *Store) Dump(ctx context.Context, w io.Writer) error {
+	return Dump(ctx, s.driver, s.Profile.Version, w)
+}
+
+// Restore loads the dump archive into the empty store.
+func (s *Store) Restore(ctx context.Context, r io.Reader) (*DumpHeader, error) {
+	return Restore(ctx, s.driver, r)
+}
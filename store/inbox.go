@@ -37,6 +37,11 @@ type FindInbox struct {
 	SenderID   *int32
 	ReceiverID *int32
 	Status     *InboxStatus
+
+	// Pagination
+	Limit *int
+	// Cursor lists the inboxes after it by created_ts and ID.
+	Cursor *PageCursor
 }
 
 type DeleteInbox struct {
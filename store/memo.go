@@ -51,24 +51,33 @@ type Memo struct {
 }
 
 type FindMemo struct {
-	ID *int32
+	ID     *int32
+	IDList []int32
 
 	// Standard fields
+	// RowStatus lists the memos in the trash only if it's Deleted, they are excluded otherwise.
 	RowStatus       *RowStatus
 	CreatorID       *int32
 	CreatedTsAfter  *int64
 	CreatedTsBefore *int64
+	UpdatedTsBefore *int64
 
 	// Domain specific fields
 	ContentSearch  []string
 	VisibilityList []Visibility
 	Pinned         *bool
 	HasParent      *bool
+	// ParentID lists the comments of the memo.
+	ParentID       *int32
 	ExcludeContent bool
+	// Filter is an additional condition on top of the other fields.
+	Filter *MemoFilter
 
 	// Pagination
-	Limit            *int
-	Offset           *int
+	Limit  *int
+	Offset *int
+	// Cursor lists the memos after it, ordered the same way.
+	Cursor           *PageCursor
 	OrderByUpdatedTs bool
 }
 
@@ -106,6 +115,7 @@ func (s *Store) GetMemo(ctx context.Context, find *FindMemo) (*Memo, error) {
 	return memo, nil
 }
 
+// UpdateMemo updates the memo, and keeps its content and visibility as a revision if the update changes them.
 func (s *Store) UpdateMemo(ctx context.Context, update *UpdateMemo) error {
 	return s.driver.UpdateMemo(ctx, update)
 }
@@ -0,0 +1,79 @@
+package store
+
+import "strings"
+
+// MemoFilterOperator is the operator of a memo filter node.
+type MemoFilterOperator string
+
+const (
+	// Logical operators, whose operands are the children.
+	MemoFilterAnd MemoFilterOperator = "AND"
+	MemoFilterOr  MemoFilterOperator = "OR"
+	MemoFilterNot MemoFilterOperator = "NOT"
+
+	// Comparison operators, whose operands are the field and the value.
+	MemoFilterEqual        MemoFilterOperator = "="
+	MemoFilterNotEqual     MemoFilterOperator = "!="
+	MemoFilterLess         MemoFilterOperator = "<"
+	MemoFilterLessEqual    MemoFilterOperator = "<="
+	MemoFilterGreater      MemoFilterOperator = ">"
+	MemoFilterGreaterEqual MemoFilterOperator = ">="
+	// MemoFilterContains matches the memos whose field contains the string value.
+	MemoFilterContains MemoFilterOperator = "CONTAINS"
+)
+
+// MemoFilterField is the memo field compared in a memo filter node.
+type MemoFilterField string
+
+const (
+	// MemoFilterFieldCreator is compared with the username of the creator.
+	MemoFilterFieldCreator MemoFilterField = "creator"
+	// MemoFilterFieldRowStatus is compared with a RowStatus value.
+	MemoFilterFieldRowStatus MemoFilterField = "row_status"
+	// MemoFilterFieldCreatedTs and MemoFilterFieldUpdatedTs are compared with an int64 unix timestamp.
+	MemoFilterFieldCreatedTs MemoFilterField = "created_ts"
+	MemoFilterFieldUpdatedTs MemoFilterField = "updated_ts"
+	// MemoFilterFieldContent is matched with a string value by MemoFilterContains.
+	MemoFilterFieldContent MemoFilterField = "content"
+	// MemoFilterFieldVisibility is compared with a Visibility value.
+	MemoFilterFieldVisibility MemoFilterField = "visibility"
+	// MemoFilterFieldTag is equal to a string value if the memo has the tag or any of its subtags.
+	MemoFilterFieldTag MemoFilterField = "tag"
+	// MemoFilterFieldPinned and MemoFilterFieldHasResource are compared with a bool value.
+	MemoFilterFieldPinned      MemoFilterField = "pinned"
+	MemoFilterFieldHasResource MemoFilterField = "has_resource"
+)
+
+// MemoFilter is a node of the boolean expression tree that filters memos.
+// The drivers compile it into the where clause of their queries.
+type MemoFilter struct {
+	Operator MemoFilterOperator
+
+	// Children are the operands of the logical operators.
+	Children []*MemoFilter
+
+	// Field and Value are the operands of the comparison operators.
+	Field MemoFilterField
+	Value any
+	// UserID is the user whose memo organizers are compared by MemoFilterFieldPinned.
+	UserID int32
+}
+
+// memoTagDelimiters are the characters a tag ends at, see the tag parser of gomark, and the separator of the subtags.
+var memoTagDelimiters = []string{" ", "\t", "\n", "\r", "\u3000", ",", "#", "/"}
+
+// EscapeLikePattern escapes the wildcards in the string to be matched literally by LIKE with the backslash as the escape character.
+func EscapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// GetMemoTagPatterns returns the LIKE patterns escaped with the backslash, any of which matches the content with the tag,
+// i.e. the tag followed by a delimiter or the end of the content, so "#work" doesn't match "#workshop".
+func GetMemoTagPatterns(tag string) []string {
+	prefix := "%#" + EscapeLikePattern(tag)
+	patterns := []string{prefix}
+	for _, delimiter := range memoTagDelimiters {
+		patterns = append(patterns, prefix+delimiter+"%")
+	}
+	return patterns
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// MemoRevision is a snapshot of the content and visibility of a memo before they are changed.
+type MemoRevision struct {
+	ID        int32
+	MemoID    int32
+	CreatedTs int64
+
+	Content    string
+	Visibility Visibility
+}
+
+type FindMemoRevision struct {
+	ID     *int32
+	MemoID *int32
+}
+
+type DeleteMemoRevision struct {
+	ID              *int32
+	MemoID          *int32
+	CreatedTsBefore *int64
+}
+
+// MemoRevisionRetentionSettingName is the name of the system setting of how many days the memo revisions are kept.
+// The revisions are kept forever if the setting is absent or 0.
+const MemoRevisionRetentionSettingName = "memo-revision-retention"
+
+func (s *Store) CreateMemoRevision(ctx context.Context, create *MemoRevision) (*MemoRevision, error) {
+	return s.driver.CreateMemoRevision(ctx, create)
+}
+
+func (s *Store) ListMemoRevisions(ctx context.Context, find *FindMemoRevision) ([]*MemoRevision, error) {
+	return s.driver.ListMemoRevisions(ctx, find)
+}
+
+func (s *Store) GetMemoRevision(ctx context.Context, find *FindMemoRevision) (*MemoRevision, error) {
+	list, err := s.ListMemoRevisions(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+func (s *Store) DeleteMemoRevision(ctx context.Context, delete *DeleteMemoRevision) error {
+	return s.driver.DeleteMemoRevision(ctx, delete)
+}
+
+// PurgeMemoRevisions removes the revisions older than the retention.
+func (s *Store) PurgeMemoRevisions(ctx context.Context) error {
+	retentionDays, err := strconv.Atoi(s.GetSystemSettingValueWithDefault(ctx, MemoRevisionRetentionSettingName, "0"))
+	if err != nil || retentionDays <= 0 {
+		return nil
+	}
+	createdTsBefore := time.Now().AddDate(0, 0, -retentionDays).Unix()
+	return s.DeleteMemoRevision(ctx, &DeleteMemoRevision{
+		CreatedTsBefore: &createdTsBefore,
+	})
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SnippetHighlightStart and SnippetHighlightEnd wrap the matched terms in a search snippet.
+	SnippetHighlightStart = "<mark>"
+	SnippetHighlightEnd   = "</mark>"
+
+	// snippetContextLength is the number of runes kept before the first matched term in a snippet.
+	snippetContextLength = 32
+	// snippetMaxLength is the maximum number of runes of a snippet, excluding the highlight tags.
+	snippetMaxLength = 160
+)
+
+type SearchMemo struct {
+	// Query is the full-text search query, whose terms are separated by whitespace.
+	// A memo matches only if it contains all the terms.
+	Query string
+
+	// Standard fields
+	// RowStatus lists the memos in the trash only if it's Deleted, they are excluded otherwise.
+	RowStatus *RowStatus
+	CreatorID *int32
+
+	// Domain specific fields
+	VisibilityList []Visibility
+	Filter         *MemoFilter
+
+	// Pagination
+	Limit *int
+	// Cursor lists the results after it, ordered the same way.
+	Cursor *PageCursor
+}
+
+type MemoSearchResult struct {
+	MemoID    int32
+	CreatedTs int64
+	// Rank is the relevance of the memo to the query, the higher the more relevant.
+	Rank float64
+	// Snippet is an excerpt of the memo content with the matched terms highlighted.
+	// The content is HTML-escaped, so that the highlight tags are its only markup.
+	Snippet string
+}
+
+// SearchMemos returns the memos matching the query, ordered by relevance, then by created_ts and id.
+func (s *Store) SearchMemos(ctx context.Context, find *SearchMemo) ([]*MemoSearchResult, error) {
+	if len(SplitSearchQuery(find.Query)) == 0 {
+		return nil, errors.New("search query is empty")
+	}
+	return s.driver.SearchMemos(ctx, find)
+}
+
+// SplitSearchQuery splits the search query into terms.
+func SplitSearchQuery(query string) []string {
+	return strings.Fields(query)
+}
+
+// BuildSearchSnippet returns an excerpt of the HTML-escaped content around the first matched term,
+// with all the case-insensitive occurrences of the terms wrapped in highlight tags.
+func BuildSearchSnippet(content string, terms []string) string {
+	runes := []rune(content)
+	lowerRunes := make([]rune, len(runes))
+	for i, r := range runes {
+		lowerRunes[i] = unicode.ToLower(r)
+	}
+	lowerTerms := [][]rune{}
+	for _, term := range terms {
+		if term == "" {
+			continue
+		}
+		lowerTerms = append(lowerTerms, []rune(strings.ToLower(term)))
+	}
+
+	// Find the matched ranges, longer terms win when they overlap.
+	matched := make([]int, len(runes))
+	first := -1
+	for i := range lowerRunes {
+		for _, term := range lowerTerms {
+			if matched[i] < len(term) && hasRunePrefix(lowerRunes[i:], term) {
+				matched[i] = len(term)
+			}
+		}
+		if matched[i] > 0 && first == -1 {
+			first = i
+		}
+	}
+
+	start := 0
+	if first > snippetContextLength {
+		start = first - snippetContextLength
+	}
+	end := start + snippetMaxLength
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	var snippet strings.Builder
+	if start > 0 {
+		snippet.WriteString("...")
+	}
+	for i := start; i < end; {
+		if length := matched[i]; length > 0 {
+			if i+length > end {
+				length = end - i
+			}
+			snippet.WriteString(SnippetHighlightStart)
+			snippet.WriteString(html.EscapeString(string(runes[i : i+length])))
+			snippet.WriteString(SnippetHighlightEnd)
+			i += length
+			continue
+		}
+		snippet.WriteString(html.EscapeString(string(runes[i])))
+		i++
+	}
+	if end < len(runes) {
+		snippet.WriteString("...")
+	}
+	return snippet.String()
+}
+
+func hasRunePrefix(runes, prefix []rune) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+	for i, r := range prefix {
+		if runes[i] != r {
+			return false
+		}
+	}
+	return true
+}
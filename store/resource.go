@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/pkg/errors"
 
@@ -12,10 +13,14 @@ import (
 )
 
 const (
-	// thumbnailImagePath is the directory to store image thumbnails.
+	// thumbnailImagePath is the directory of the previews of the resources.
 	thumbnailImagePath = ".thumbnail_cache"
 )
 
+// ErrResourceBlobDeleted is returned when a resource is created to share the blob of the others,
+// but all of them have been deleted along with the blob in the meantime.
+var ErrResourceBlobDeleted = errors.New("the shared resource blob has been deleted")
+
 type Resource struct {
 	ID int32
 
@@ -32,6 +37,21 @@ type Resource struct {
 	Type         string
 	Size         int64
 	MemoID       *int32
+	// StorageID is the storage in which InternalPath is the key of the blob.
+	// It is 0 for the blobs in the database, the files in the local storage and the external links.
+	StorageID int32
+	// Hash is the hex SHA-256 hash of the blob, with which the resources of the same blob share it.
+	Hash string
+	// CapturedTs is the time when the photo was taken, read from its EXIF. It is 0 if it's unknown.
+	CapturedTs int64
+	// Width and Height are the dimensions of the images and the videos, and Duration is the length of the audio
+	// and the videos in milliseconds. They are 0 if they don't apply or can't be probed.
+	Width    int32
+	Height   int32
+	Duration int64
+	// SharedBlob is only used on creation, if the resource shares the blob of the others by its location or hash.
+	// The resource is created in one transaction with the check that any of them is left.
+	SharedBlob bool
 }
 
 type FindResource struct {
@@ -41,8 +61,29 @@ type FindResource struct {
 	Filename       *string
 	MemoID         *int32
 	HasRelatedMemo bool
+	Hash           *string
+	StorageID      *int32
+	InternalPath   *string
+	ExternalLink   *string
 	Limit          *int
 	Offset         *int
+	// Cursor lists the resources after it by created_ts and ID.
+	Cursor *PageCursor
+}
+
+// FindResourceBlob finds a range of the blob in the database of the resource.
+type FindResourceBlob struct {
+	ID     int32
+	Offset int64
+	// Length is the number of the bytes from the offset, or -1 to read to the end of the blob.
+	Length int64
+}
+
+// ResourceBlob is a range of the blob in the database, read by the database without loading the whole blob.
+type ResourceBlob struct {
+	Blob []byte
+	// Size is the size of the whole blob.
+	Size int64
 }
 
 type UpdateResource struct {
@@ -50,6 +91,9 @@ type UpdateResource struct {
 	UpdatedTs    *int64
 	Filename     *string
 	InternalPath *string
+	ExternalLink *string
+	StorageID    *int32
+	Hash         *string
 	MemoID       *int32
 	Blob         []byte
 }
@@ -81,27 +125,192 @@ func (s *Store) GetResource(ctx context.Context, find *FindResource) (*Resource,
 }
 
 func (s *Store) UpdateResource(ctx context.Context, update *UpdateResource) (*Resource, error) {
+	// Clearing the blob hands it over to the other resources sharing it.
+	if update.Blob != nil && len(update.Blob) == 0 {
+		resource, err := s.GetResource(ctx, &FindResource{ID: &update.ID})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get resource")
+		}
+		if resource != nil {
+			if err := s.handOverResourceBlob(ctx, resource); err != nil {
+				return nil, err
+			}
+		}
+	}
 	return s.driver.UpdateResource(ctx, update)
 }
 
-func (s *Store) DeleteResource(ctx context.Context, delete *DeleteResource) error {
+// DeleteResource deletes the resource, and returns true if it was the last resource referencing its blob.
+// The references are checked in one transaction with the deletion, and the blob in the database is handed over
+// to the other resources sharing it. The blob in the local storage is deleted, while the others are left to the caller.
+func (s *Store) DeleteResource(ctx context.Context, delete *DeleteResource) (bool, error) {
 	resource, err := s.GetResource(ctx, &FindResource{ID: &delete.ID})
 	if err != nil {
-		return errors.Wrap(err, "failed to get resource")
+		return false, errors.Wrap(err, "failed to get resource")
 	}
 	if resource == nil {
-		return errors.Wrap(nil, "resource not found")
+		return false, errors.Wrap(nil, "resource not found")
 	}
 
-	// Delete the local file.
-	if resource.InternalPath != "" {
+	last, err := s.driver.DeleteResource(ctx, delete)
+	if err != nil {
+		return false, err
+	}
+	if resource.InternalPath != "" && resource.StorageID == 0 && last {
 		_ = os.Remove(resource.InternalPath)
 	}
-	// Delete the thumbnail.
+	// Delete the local previews, and the thumbnail generated before the previews, while the others are deleted by the caller.
+	_ = os.RemoveAll(filepath.Join(s.Profile.Data, thumbnailImagePath, strconv.Itoa(int(resource.ID))))
 	if util.HasPrefixes(resource.Type, "image/png", "image/jpeg") {
 		ext := filepath.Ext(resource.Filename)
 		thumbnailPath := filepath.Join(s.Profile.Data, thumbnailImagePath, fmt.Sprintf("%d%s", resource.ID, ext))
 		_ = os.Remove(thumbnailPath)
 	}
-	return s.driver.DeleteResource(ctx, delete)
+	return last, nil
+}
+
+// ListResourceBlobReferences returns the other resources sharing the blob of the resource,
+// i.e. the same file or object, or the blob in the database of the same hash.
+func (s *Store) ListResourceBlobReferences(ctx context.Context, resource *Resource) ([]*Resource, error) {
+	find := &FindResource{
+		StorageID:    &resource.StorageID,
+		InternalPath: &resource.InternalPath,
+		ExternalLink: &resource.ExternalLink,
+	}
+	if resource.InternalPath == "" && resource.ExternalLink == "" {
+		if resource.Hash == "" {
+			return []*Resource{}, nil
+		}
+		find.Hash = &resource.Hash
+	}
+	list, err := s.ListResources(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	references := []*Resource{}
+	for _, reference := range list {
+		if reference.ID != resource.ID {
+			references = append(references, reference)
+		}
+	}
+	return references, nil
+}
+
+// GetResourceBlobRange returns a range of the blob in the database of the resource, or nil if the resource is not found.
+func (s *Store) GetResourceBlobRange(ctx context.Context, find *FindResourceBlob) (*ResourceBlob, error) {
+	return s.driver.GetResourceBlob(ctx, find)
+}
+
+// GetResourceBlobHolder returns the ID of the resource holding the blob in the database of the resource, which is
+// itself or one of the resources of the same hash, and the size of the blob. The blob itself is not read.
+func (s *Store) GetResourceBlobHolder(ctx context.Context, resource *Resource) (int32, int64, error) {
+	blob, err := s.GetResourceBlobRange(ctx, &FindResourceBlob{ID: resource.ID})
+	if err != nil {
+		return 0, 0, err
+	}
+	if blob == nil {
+		return 0, 0, errors.New("resource not found")
+	}
+	if blob.Size > 0 || resource.Hash == "" || resource.InternalPath != "" || resource.ExternalLink != "" {
+		return resource.ID, blob.Size, nil
+	}
+
+	holders, err := s.ListResources(ctx, &FindResource{
+		Hash:         &resource.Hash,
+		StorageID:    &resource.StorageID,
+		InternalPath: &resource.InternalPath,
+		ExternalLink: &resource.ExternalLink,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, holder := range holders {
+		if holder.ID == resource.ID {
+			continue
+		}
+		blob, err := s.GetResourceBlobRange(ctx, &FindResourceBlob{ID: holder.ID})
+		if err != nil {
+			return 0, 0, err
+		}
+		if blob != nil && blob.Size > 0 {
+			return holder.ID, blob.Size, nil
+		}
+	}
+	return resource.ID, 0, nil
+}
+
+// GetResourceBlob returns the blob in the database of the resource, which is held by one of the resources of the same hash.
+// The resource may not be created yet, then its blob is found by its hash.
+func (s *Store) GetResourceBlob(ctx context.Context, resource *Resource) ([]byte, error) {
+	if resource.ID != 0 {
+		var err error
+		if resource, err = s.GetResource(ctx, &FindResource{ID: &resource.ID, GetBlob: true}); err != nil {
+			return nil, err
+		}
+		if resource == nil {
+			return nil, errors.New("resource not found")
+		}
+	}
+	if len(resource.Blob) > 0 || resource.Hash == "" || resource.InternalPath != "" || resource.ExternalLink != "" {
+		return resource.Blob, nil
+	}
+
+	holders, err := s.ListResources(ctx, &FindResource{
+		GetBlob:      true,
+		Hash:         &resource.Hash,
+		StorageID:    &resource.StorageID,
+		InternalPath: &resource.InternalPath,
+		ExternalLink: &resource.ExternalLink,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, holder := range holders {
+		if len(holder.Blob) > 0 {
+			return holder.Blob, nil
+		}
+	}
+	return resource.Blob, nil
+}
+
+// handOverResourceBlob copies the blob in the database of the resource to another resource sharing it,
+// if none of them holds the blob but the resource, before the resource stops holding it.
+func (s *Store) handOverResourceBlob(ctx context.Context, resource *Resource) error {
+	if resource.InternalPath != "" || resource.ExternalLink != "" || resource.Hash == "" {
+		return nil
+	}
+	list, err := s.ListResources(ctx, &FindResource{
+		GetBlob:      true,
+		Hash:         &resource.Hash,
+		StorageID:    &resource.StorageID,
+		InternalPath: &resource.InternalPath,
+		ExternalLink: &resource.ExternalLink,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list resource blob references")
+	}
+
+	var blob []byte
+	references := []*Resource{}
+	for _, reference := range list {
+		if reference.ID != resource.ID {
+			if len(reference.Blob) > 0 {
+				return nil
+			}
+			references = append(references, reference)
+		} else {
+			blob = reference.Blob
+		}
+	}
+	if len(blob) == 0 || len(references) == 0 {
+		return nil
+	}
+	if _, err := s.driver.UpdateResource(ctx, &UpdateResource{
+		ID:   references[0].ID,
+		Blob: blob,
+	}); err != nil {
+		return errors.Wrap(err, "failed to hand over resource blob")
+	}
+	return nil
 }
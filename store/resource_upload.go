@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+)
+
+// ResourceUpload is a resumable upload of a resource, whose blob is received as parts in the storage before the resource is created.
+type ResourceUpload struct {
+	ID int32
+
+	// Standard fields
+	CreatorID int32
+	CreatedTs int64
+	UpdatedTs int64
+
+	// Domain specific fields
+	Filename string
+	Type     string
+	Size     int64
+	// StorageID is the storage the resource is saved into, i.e. the database, the local storage or the ID of a storage,
+	// the same as the storage service ID setting.
+	StorageID int32
+	// Offset is the size of the blob received.
+	Offset int64
+	// PartCount is the number of the parts received, which are concatenated into the blob in order.
+	PartCount int32
+	// HashState is the marshaled state of the SHA-256 hash of the blob received.
+	HashState []byte
+}
+
+type FindResourceUpload struct {
+	ID              *int32
+	CreatorID       *int32
+	UpdatedTsBefore *int64
+}
+
+type UpdateResourceUpload struct {
+	ID        int32
+	UpdatedTs *int64
+	Offset    *int64
+	PartCount *int32
+	HashState []byte
+}
+
+type DeleteResourceUpload struct {
+	ID int32
+}
+
+func (s *Store) CreateResourceUpload(ctx context.Context, create *ResourceUpload) (*ResourceUpload, error) {
+	return s.driver.CreateResourceUpload(ctx, create)
+}
+
+func (s *Store) ListResourceUploads(ctx context.Context, find *FindResourceUpload) ([]*ResourceUpload, error) {
+	return s.driver.ListResourceUploads(ctx, find)
+}
+
+func (s *Store) GetResourceUpload(ctx context.Context, find *FindResourceUpload) (*ResourceUpload, error) {
+	list, err := s.ListResourceUploads(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+func (s *Store) UpdateResourceUpload(ctx context.Context, update *UpdateResourceUpload) (*ResourceUpload, error) {
+	return s.driver.UpdateResourceUpload(ctx, update)
+}
+
+func (s *Store) DeleteResourceUpload(ctx context.Context, delete *DeleteResourceUpload) error {
+	return s.driver.DeleteResourceUpload(ctx, delete)
+}
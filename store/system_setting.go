@@ -2,6 +2,12 @@ package store
 
 import (
 	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	storepb "github.com/usememos/memos/proto/gen/store"
 )
 
 type SystemSetting struct {
@@ -15,7 +21,13 @@ type FindSystemSetting struct {
 }
 
 func (s *Store) UpsertSystemSetting(ctx context.Context, upsert *SystemSetting) (*SystemSetting, error) {
-	return s.driver.UpsertSystemSetting(ctx, upsert)
+	systemSettingMessage, err := s.driver.UpsertSystemSetting(ctx, upsert)
+	if err != nil {
+		return nil, err
+	}
+
+	s.systemSettingCache.Store(systemSettingMessage.Name, systemSettingMessage)
+	return systemSettingMessage, nil
 }
 
 func (s *Store) ListSystemSettings(ctx context.Context, find *FindSystemSetting) ([]*SystemSetting, error) {
@@ -59,3 +71,61 @@ func (s *Store) GetSystemSettingValueWithDefault(ctx context.Context, settingNam
 	}
 	return defaultValue
 }
+
+// GetBackupConfig returns the auto-backup config, or nil if it has not been set.
+func (s *Store) GetBackupConfig(ctx context.Context) (*storepb.BackupConfig, error) {
+	backupConfig := &storepb.BackupConfig{}
+	ok, err := s.getSystemSettingMessage(ctx, storepb.SystemSettingKey_BACKUP_CONFIG, backupConfig)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return backupConfig, nil
+}
+
+func (s *Store) UpsertBackupConfig(ctx context.Context, backupConfig *storepb.BackupConfig) error {
+	return s.upsertSystemSettingMessage(ctx, storepb.SystemSettingKey_BACKUP_CONFIG, backupConfig)
+}
+
+// GetBackupStatus returns the status of the last auto-backup run, or nil if there is none.
+func (s *Store) GetBackupStatus(ctx context.Context) (*storepb.BackupStatus, error) {
+	backupStatus := &storepb.BackupStatus{}
+	ok, err := s.getSystemSettingMessage(ctx, storepb.SystemSettingKey_BACKUP_STATUS, backupStatus)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return backupStatus, nil
+}
+
+func (s *Store) UpsertBackupStatus(ctx context.Context, backupStatus *storepb.BackupStatus) error {
+	return s.upsertSystemSettingMessage(ctx, storepb.SystemSettingKey_BACKUP_STATUS, backupStatus)
+}
+
+// getSystemSettingMessage unmarshals the system setting of the key into the message,
+// and reports whether the setting exists.
+func (s *Store) getSystemSettingMessage(ctx context.Context, key storepb.SystemSettingKey, message proto.Message) (bool, error) {
+	systemSetting, err := s.GetSystemSetting(ctx, &FindSystemSetting{
+		Name: key.String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if systemSetting == nil {
+		return false, nil
+	}
+	if err := protojson.Unmarshal([]byte(systemSetting.Value), message); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal system setting %s", key.String())
+	}
+	return true, nil
+}
+
+func (s *Store) upsertSystemSettingMessage(ctx context.Context, key storepb.SystemSettingKey, message proto.Message) error {
+	value, err := protojson.Marshal(message)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal system setting %s", key.String())
+	}
+	_, err = s.UpsertSystemSetting(ctx, &SystemSetting{
+		Name:  key.String(),
+		Value: string(value),
+	})
+	return err
+}
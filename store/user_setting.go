@@ -123,3 +123,20 @@ func (s *Store) GetUserAccessTokens(ctx context.Context, userID int32) ([]*store
 	accessTokensUserSetting := userSetting.GetAccessTokens()
 	return accessTokensUserSetting.AccessTokens, nil
 }
+
+// GetUserSavedFilters returns the saved memo filters of the user.
+func (s *Store) GetUserSavedFilters(ctx context.Context, userID int32) ([]*storepb.SavedFiltersUserSetting_SavedFilter, error) {
+	userSetting, err := s.GetUserSettingV1(ctx, &FindUserSettingV1{
+		UserID: &userID,
+		Key:    storepb.UserSettingKey_USER_SETTING_SAVED_FILTERS,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if userSetting == nil {
+		return []*storepb.SavedFiltersUserSetting_SavedFilter{}, nil
+	}
+
+	savedFiltersUserSetting := userSetting.GetSavedFilters()
+	return savedFiltersUserSetting.SavedFilters, nil
+}
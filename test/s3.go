@@ -0,0 +1,136 @@
+package test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// S3Server is an in-memory stand-in of a S3-compatible object store, like MinIO, with path-style requests.
+// It only supports putting, getting (with ranges), heading, deleting and listing (v2) objects of single-part uploads.
+type S3Server struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	objects  map[string][]byte
+	modTimes map[string]time.Time
+}
+
+type s3ListBucketResult struct {
+	XMLName     xml.Name           `xml:"ListBucketResult"`
+	Name        string             `xml:"Name"`
+	Prefix      string             `xml:"Prefix"`
+	Delimiter   string             `xml:"Delimiter,omitempty"`
+	KeyCount    int                `xml:"KeyCount"`
+	IsTruncated bool               `xml:"IsTruncated"`
+	Contents    []s3ListBucketItem `xml:"Contents"`
+	// CommonPrefixes are the subdirectories grouped by the delimiter.
+	CommonPrefixes []s3ListBucketPrefix `xml:"CommonPrefixes"`
+}
+
+type s3ListBucketPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type s3ListBucketItem struct {
+	Key          string `xml:"Key"`
+	Size         int64  `xml:"Size"`
+	LastModified string `xml:"LastModified"`
+}
+
+func NewS3Server(t *testing.T) *S3Server {
+	s := &S3Server{
+		objects:  map[string][]byte{},
+		modTimes: map[string]time.Time{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Objects returns the keys of the objects, which are prefixed with their buckets.
+func (s *S3Server) Objects() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	keys := []string{}
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *S3Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		s.list(w, bucket, r.URL.Query().Get("prefix"), r.URL.Query().Get("delimiter"))
+	case r.Method == http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.objects[bucket+"/"+key] = body
+		s.modTimes[bucket+"/"+key] = time.Now().UTC().Truncate(time.Second)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		body, ok := s.objects[bucket+"/"+key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, key, s.modTimes[bucket+"/"+key], bytes.NewReader(body))
+	case r.Method == http.MethodDelete:
+		delete(s.objects, bucket+"/"+key)
+		delete(s.modTimes, bucket+"/"+key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func (s *S3Server) list(w http.ResponseWriter, bucket string, prefix string, delimiter string) {
+	result := &s3ListBucketResult{
+		Name:      bucket,
+		Prefix:    prefix,
+		Delimiter: delimiter,
+	}
+	commonPrefixes := map[string]bool{}
+	for name, body := range s.objects {
+		key := strings.TrimPrefix(name, bucket+"/")
+		if key == name || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			commonPrefix := key[:len(prefix)+i+len(delimiter)]
+			if !commonPrefixes[commonPrefix] {
+				commonPrefixes[commonPrefix] = true
+				result.CommonPrefixes = append(result.CommonPrefixes, s3ListBucketPrefix{Prefix: commonPrefix})
+			}
+			continue
+		}
+		result.Contents = append(result.Contents, s3ListBucketItem{
+			Key:          key,
+			Size:         int64(len(body)),
+			LastModified: time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+	sort.Slice(result.Contents, func(i, j int) bool {
+		return result.Contents[i].Key < result.Contents[j].Key
+	})
+	result.KeyCount = len(result.Contents) + len(result.CommonPrefixes)
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,171 @@
+package testserver
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	storepb "github.com/usememos/memos/proto/gen/store"
+	"github.com/usememos/memos/server/service/backup"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/test"
+)
+
+func TestBackupRunner(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	runner := backup.NewBackupRunner(s.server.Store)
+	// The legacy auto backup interval is used without a backup config.
+	_, err = s.server.Store.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  apiv1.SystemSettingAutoBackupIntervalName.String(),
+		Value: "3600",
+	})
+	require.NoError(t, err)
+	backupConfig, err := runner.GetBackupConfig(ctx)
+	require.NoError(t, err)
+	require.True(t, backupConfig.Enabled)
+	require.Equal(t, "0 */1 * * *", backupConfig.Cron)
+
+	err = s.server.Store.UpsertBackupConfig(ctx, &storepb.BackupConfig{
+		Enabled: true,
+		Cron:    "0 3 * * *",
+		MaxKeep: 2,
+	})
+	require.NoError(t, err)
+	backupConfig, err = runner.GetBackupConfig(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), backupConfig.MaxKeep)
+
+	prefix := s.profile.DSN
+	if s.profile.Driver != "sqlite" {
+		prefix = filepath.Join(s.profile.Data, "memos_"+s.profile.Driver)
+	}
+	oldBackups := []string{prefix + "-20200101-000000.bak", prefix + "-20200102-000000.bak"}
+	for _, filename := range oldBackups {
+		require.NoError(t, os.WriteFile(filename, []byte("old backup"), 0644))
+	}
+
+	err = runner.Backup(ctx)
+	require.NoError(t, err)
+	backupStatus, err := s.server.Store.GetBackupStatus(ctx)
+	require.NoError(t, err)
+	require.Empty(t, backupStatus.Error)
+	require.Greater(t, backupStatus.Size, int64(0))
+
+	// Only the newest backups within the max keep are kept.
+	filenames, err := filepath.Glob(prefix + "-*.bak")
+	require.NoError(t, err)
+	require.Equal(t, []string{oldBackups[1], backupStatus.Filename}, filenames)
+}
+
+func TestBackupRunnerWithStorage(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	s3Server := test.NewS3Server(t)
+	s3Config, err := json.Marshal(&apiv1.StorageS3Config{
+		EndPoint:  s3Server.URL,
+		Region:    "us-east-1",
+		AccessKey: "test_access_key",
+		SecretKey: "test_secret_key",
+		Bucket:    "memos",
+	})
+	require.NoError(t, err)
+	storage, err := s.server.Store.CreateStorage(ctx, &store.Storage{
+		Name:   "test_storage",
+		Type:   apiv1.StorageS3.String(),
+		Config: string(s3Config),
+	})
+	require.NoError(t, err)
+	user, err := s.server.Store.CreateUser(ctx, &store.User{
+		Username: "test",
+		Role:     store.RoleHost,
+		Email:    "test@test.com",
+		Nickname: "test_nickname",
+	})
+	require.NoError(t, err)
+	internalPath := filepath.Join(s.profile.Data, "assets", "test.txt")
+	require.NoError(t, os.MkdirAll(filepath.Dir(internalPath), os.ModePerm))
+	require.NoError(t, os.WriteFile(internalPath, []byte("test_resource"), 0644))
+	_, err = s.server.Store.CreateResource(ctx, &store.Resource{
+		CreatorID:    user.ID,
+		Filename:     "test.txt",
+		Type:         "text/plain",
+		Size:         13,
+		InternalPath: internalPath,
+	})
+	require.NoError(t, err)
+	err = s.server.Store.UpsertBackupConfig(ctx, &storepb.BackupConfig{
+		Enabled:   true,
+		Cron:      "0 3 * * *",
+		MaxKeep:   1,
+		StorageId: storage.ID,
+	})
+	require.NoError(t, err)
+
+	runner := backup.NewBackupRunner(s.server.Store)
+	backend, err := backup.GetRemoteBackend(ctx, s.server.Store, storage.ID)
+	require.NoError(t, err)
+	dir, err := backup.GetRemoteBackupDir(ctx, s.server.Store)
+	require.NoError(t, err)
+	require.NotEqual(t, backup.RemoteBackupDir, dir)
+	err = backend.Put(ctx, path.Join(dir, "memos-20200101-000000.bak.tar.gz"), bytes.NewReader([]byte("old backup")), -1, "application/gzip")
+	require.NoError(t, err)
+	// The backup of another instance sharing the storage.
+	otherDir := path.Join(backup.RemoteBackupDir, "other-server-id")
+	err = backend.Put(ctx, path.Join(otherDir, "memos-20200101-000000.bak.tar.gz"), bytes.NewReader([]byte("other backup")), -1, "application/gzip")
+	require.NoError(t, err)
+
+	err = runner.Backup(ctx)
+	require.NoError(t, err)
+	backupStatus, err := s.server.Store.GetBackupStatus(ctx)
+	require.NoError(t, err)
+	require.Empty(t, backupStatus.Error)
+	require.Equal(t, path.Join(dir, filepath.Base(backupStatus.Filename)+".tar.gz"), backupStatus.RemoteKey)
+
+	// The remote backups of this instance beyond the max keep are removed too, while the ones of the others are kept.
+	backups, err := backup.ListRemoteBackups(ctx, backend, dir)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	require.Equal(t, backupStatus.RemoteKey, backups[0].Key)
+	otherBackups, err := backup.ListRemoteBackups(ctx, backend, otherDir)
+	require.NoError(t, err)
+	require.Len(t, otherBackups, 1)
+
+	// The archive has the backup and the resources in the local storage.
+	reader, err := backend.Get(ctx, backupStatus.RemoteKey)
+	require.NoError(t, err)
+	defer reader.Close()
+	gzipReader, err := gzip.NewReader(reader)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+	files := map[string]string{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+	require.Len(t, files, 2)
+	require.Contains(t, files, filepath.Base(backupStatus.Filename))
+	require.Equal(t, "test_resource", files["resources/assets/test.txt"])
+}
@@ -0,0 +1,213 @@
+package testserver
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+)
+
+func TestExportAndImport(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	user, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	resource, err := s.server.Store.CreateResource(ctx, &store.Resource{
+		CreatorID: user.ID,
+		Filename:  "test.txt",
+		Blob:      []byte("test_blob"),
+		Type:      "text/plain",
+		Size:      9,
+	})
+	require.NoError(t, err)
+	memo, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+		Content:        "test memo",
+		Visibility:     apiv1.Public,
+		ResourceIDList: []int32{resource.ID},
+	})
+	require.NoError(t, err)
+	_, err = s.postMemoOrganizer(memo.ID, &apiv1.UpsertMemoOrganizerRequest{
+		Pinned: true,
+	})
+	require.NoError(t, err)
+	_, err = s.postMemoCreate(&apiv1.CreateMemoRequest{
+		Content: "test comment",
+		RelationList: []*apiv1.UpsertMemoRelationRequest{
+			{
+				RelatedMemoID: memo.ID,
+				Type:          apiv1.MemoRelationComment,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	body, err := s.get("/api/v1/export", nil)
+	require.NoError(t, err)
+	archive, err := io.ReadAll(body)
+	require.NoError(t, err)
+	zipReader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	names := []string{}
+	for _, file := range zipReader.File {
+		names = append(names, file.Name)
+	}
+	require.ElementsMatch(t, []string{
+		"manifest.json",
+		"memos/1.md",
+		"memos/2.md",
+		"resources/1/test.txt",
+	}, names)
+
+	// The archive is imported into another user with new IDs.
+	_, err = s.server.Store.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  apiv1.SystemSettingAllowSignUpName.String(),
+		Value: "true",
+	})
+	require.NoError(t, err)
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser2",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	result, err := s.postImport(archive)
+	require.NoError(t, err)
+	require.Equal(t, &apiv1.ImportResult{MemoCount: 2, ResourceCount: 1, RelationCount: 1}, result)
+	memoList, err := s.getMemoList()
+	require.NoError(t, err)
+	require.Len(t, memoList, 1)
+	importedMemo := memoList[0]
+	require.Equal(t, "test memo", importedMemo.Content)
+	require.Equal(t, apiv1.Public, importedMemo.Visibility)
+	require.Equal(t, memo.CreatedTs, importedMemo.CreatedTs)
+	require.True(t, importedMemo.Pinned)
+	require.Len(t, importedMemo.ResourceList, 1)
+	require.NotEqual(t, resource.ID, importedMemo.ResourceList[0].ID)
+	importedResource, err := s.server.Store.GetResource(ctx, &store.FindResource{
+		ID:      &importedMemo.ResourceList[0].ID,
+		GetBlob: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte("test_blob"), importedResource.Blob)
+	comments, err := s.server.Store.ListMemoRelations(ctx, &store.FindMemoRelation{
+		RelatedMemoID: &importedMemo.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	require.NotEqual(t, memo.ID, comments[0].MemoID)
+}
+
+func TestImportUserDataValidation(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signUpUser, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	user, err := s.server.Store.GetUser(ctx, &store.FindUser{ID: &signUpUser.ID})
+	require.NoError(t, err)
+	importResource := func(manifestResource *apiv1.ExportManifestResource, blob []byte) (*apiv1.ImportResult, error) {
+		archive := buildArchive(t, manifestResource, blob)
+		return apiv1.ImportUserData(ctx, s.server.Store, user, bytes.NewReader(archive), int64(len(archive)))
+	}
+
+	// The size is measured from the blob instead of the manifest.
+	_, err = importResource(&apiv1.ExportManifestResource{ID: 1, Filename: "test.txt", Type: "text/plain", Size: 1, Path: "resources/1/test.txt"}, []byte("test_blob"))
+	require.NoError(t, err)
+	resourceList, err := s.server.Store.ListResources(ctx, &store.FindResource{CreatorID: &user.ID})
+	require.NoError(t, err)
+	require.Len(t, resourceList, 1)
+	require.Equal(t, int64(9), resourceList[0].Size)
+
+	// Only the external links of http and https are imported.
+	_, err = importResource(&apiv1.ExportManifestResource{ID: 1, Filename: "test", ExternalLink: "javascript:alert(1)"}, nil)
+	require.ErrorContains(t, err, "Invalid external link scheme")
+	_, err = importResource(&apiv1.ExportManifestResource{ID: 1, Filename: "test", ExternalLink: "https://example.com/test.png"}, nil)
+	require.NoError(t, err)
+
+	// The records created are rolled back if the import fails, here on a memo missing from the archive.
+	archive := buildArchive(t, &apiv1.ExportManifestResource{ID: 1, Filename: "test.txt", Type: "text/plain", Path: "resources/1/test.txt"}, []byte("test_blob"), &apiv1.ExportManifestMemo{ID: 1, Path: "memos/1.md"})
+	_, err = apiv1.ImportUserData(ctx, s.server.Store, user, bytes.NewReader(archive), int64(len(archive)))
+	require.Error(t, err)
+	resourceList, err = s.server.Store.ListResources(ctx, &store.FindResource{CreatorID: &user.ID})
+	require.NoError(t, err)
+	require.Len(t, resourceList, 2)
+
+	// The blobs are limited by the max upload size.
+	_, err = s.server.Store.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  apiv1.SystemSettingMaxUploadSizeMiBName.String(),
+		Value: "0",
+	})
+	require.NoError(t, err)
+	_, err = importResource(&apiv1.ExportManifestResource{ID: 1, Filename: "test.txt", Type: "text/plain", Size: 0, Path: "resources/1/test.txt"}, []byte("test_blob"))
+	require.ErrorContains(t, err, "File size exceeds allowed limit")
+}
+
+// buildArchive builds the export archive with the resource and its blob, if any.
+// The memos are only listed in the manifest, without their files.
+func buildArchive(t *testing.T, manifestResource *apiv1.ExportManifestResource, blob []byte, manifestMemos ...*apiv1.ExportManifestMemo) []byte {
+	archive := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(archive)
+	manifest, err := zipWriter.Create("manifest.json")
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(manifest).Encode(&apiv1.ExportManifest{
+		Format:    apiv1.ExportFormat,
+		Memos:     manifestMemos,
+		Resources: []*apiv1.ExportManifestResource{manifestResource},
+	}))
+	if manifestResource.Path != "" {
+		file, err := zipWriter.Create(manifestResource.Path)
+		require.NoError(t, err)
+		_, err = file.Write(blob)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	return archive.Bytes()
+}
+
+func (s *TestingServer) postImport(archive []byte) (*apiv1.ImportResult, error) {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	part, err := writer.CreateFormFile("file", "memos.zip")
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to create form file")
+	}
+	if _, err := part.Write(archive); err != nil {
+		return nil, errors.Wrap(err, "fail to write form file")
+	}
+	if err := writer.Close(); err != nil {
+		return nil, errors.Wrap(err, "fail to close multipart writer")
+	}
+
+	body, err := s.request("POST", "/api/v1/import", buf, nil, map[string]string{
+		"Cookie":       s.cookie,
+		"Content-Type": writer.FormDataContentType(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to post request")
+	}
+
+	result := &apiv1.ImportResult{}
+	if err = json.NewDecoder(body).Decode(result); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post import response")
+	}
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package testserver
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/plugin/importer"
+	"github.com/usememos/memos/store"
+)
+
+func TestImportFromSource(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	user, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	for name, content := range map[string]string{
+		"vault/Idea.md":      "See [[Project]].\n![[diagram.png]]",
+		"vault/Project.md":   "Back to [[Idea]] and [[Missing]].",
+		"vault/diagram.png":  "image",
+		"vault/.obsidian/ws": "{}",
+	} {
+		writer, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+
+	report, err := s.postImportFromSource("obsidian", buf.Bytes(), true)
+	require.NoError(t, err)
+	require.Equal(t, &apiv1.ImportSourceReport{
+		Source:        "obsidian",
+		DryRun:        true,
+		MemoCount:     2,
+		ResourceCount: 1,
+		RelationCount: 2,
+		Warnings:      []string{"unresolved link [[Missing]] in vault/Project.md"},
+	}, report)
+	memos, err := s.server.Store.ListMemos(ctx, &store.FindMemo{CreatorID: &user.ID})
+	require.NoError(t, err)
+	require.Len(t, memos, 0)
+
+	report, err = s.postImportFromSource("obsidian", buf.Bytes(), false)
+	require.NoError(t, err)
+	require.False(t, report.DryRun)
+	require.Equal(t, 2, report.MemoCount)
+	memos, err = s.server.Store.ListMemos(ctx, &store.FindMemo{CreatorID: &user.ID})
+	require.NoError(t, err)
+	require.Len(t, memos, 2)
+	for _, memo := range memos {
+		require.Equal(t, store.Private, memo.Visibility)
+	}
+	resources, err := s.server.Store.ListResources(ctx, &store.FindResource{CreatorID: &user.ID, GetBlob: true})
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	require.Equal(t, "diagram.png", resources[0].Filename)
+	require.Equal(t, []byte("image"), resources[0].Blob)
+	require.NotNil(t, resources[0].MemoID)
+	relations, err := s.server.Store.ListMemoRelations(ctx, &store.FindMemoRelation{MemoID: resources[0].MemoID})
+	require.NoError(t, err)
+	require.Len(t, relations, 1)
+	require.Equal(t, store.MemoRelationReference, relations[0].Type)
+
+	_, err = s.postImportFromSource("unknown", buf.Bytes(), true)
+	require.ErrorContains(t, err, "Unsupported import source")
+}
+
+func TestImportSourceResultRollback(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signUpUser, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	user, err := s.server.Store.GetUser(ctx, &store.FindUser{ID: &signUpUser.ID})
+	require.NoError(t, err)
+
+	// The records created before the failure are deleted.
+	memoID := int32(1)
+	newResource := func(filename string, open func() (io.ReadCloser, error)) *importer.Resource {
+		return &importer.Resource{
+			Resource: &store.Resource{Filename: filename, Type: "text/plain", Size: 4, MemoID: &memoID},
+			Open:     open,
+		}
+	}
+	_, err = apiv1.ImportSourceResult(ctx, s.server.Store, user, &importer.Result{
+		Memos: []*store.Memo{{ID: memoID, Content: "test", RowStatus: store.Normal, Visibility: store.Private}},
+		Resources: []*importer.Resource{
+			newResource("test.txt", func() (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("test")), nil
+			}),
+			newResource("missing.txt", func() (io.ReadCloser, error) {
+				return nil, fs.ErrNotExist
+			}),
+		},
+	}, false)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	memos, err := s.server.Store.ListMemos(ctx, &store.FindMemo{CreatorID: &user.ID})
+	require.NoError(t, err)
+	require.Empty(t, memos)
+	resources, err := s.server.Store.ListResources(ctx, &store.FindResource{CreatorID: &user.ID})
+	require.NoError(t, err)
+	require.Empty(t, resources)
+
+	// The resources over the max upload size fail the import before any record is created.
+	_, err = s.server.Store.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  apiv1.SystemSettingMaxUploadSizeMiBName.String(),
+		Value: "0",
+	})
+	require.NoError(t, err)
+	_, err = apiv1.ImportSourceResult(ctx, s.server.Store, user, &importer.Result{
+		Memos: []*store.Memo{{ID: memoID, Content: "test", RowStatus: store.Normal, Visibility: store.Private}},
+		Resources: []*importer.Resource{
+			newResource("test.txt", func() (io.ReadCloser, error) {
+				return nil, errors.New("the blob is opened")
+			}),
+		},
+	}, false)
+	require.ErrorContains(t, err, "exceeds allowed limit")
+	memos, err = s.server.Store.ListMemos(ctx, &store.FindMemo{CreatorID: &user.ID})
+	require.NoError(t, err)
+	require.Empty(t, memos)
+}
+
+func (s *TestingServer) postImportFromSource(source string, archive []byte, dryRun bool) (*apiv1.ImportSourceReport, error) {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	part, err := writer.CreateFormFile("file", source+".zip")
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to create form file")
+	}
+	if _, err := part.Write(archive); err != nil {
+		return nil, errors.Wrap(err, "fail to write form file")
+	}
+	if err := writer.Close(); err != nil {
+		return nil, errors.Wrap(err, "fail to close multipart writer")
+	}
+
+	body, err := s.request("POST", "/api/v1/import/"+source, buf, map[string]string{
+		"dryRun": strconv.FormatBool(dryRun),
+	}, map[string]string{
+		"Cookie":       s.cookie,
+		"Content-Type": writer.FormDataContentType(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to post request")
+	}
+
+	report := &apiv1.ImportSourceReport{}
+	if err = json.NewDecoder(body).Decode(report); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post import response")
+	}
+	return report, nil
+}
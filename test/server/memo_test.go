@@ -5,12 +5,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	apiv1 "github.com/usememos/memos/api/v1"
+	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
+	"github.com/usememos/memos/server/service/trash"
+	"github.com/usememos/memos/store"
 )
 
 func TestMemoServer(t *testing.T) {
@@ -60,6 +65,239 @@ func TestMemoServer(t *testing.T) {
 	require.Len(t, memoList, 0)
 }
 
+func TestMemoTrash(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	memo, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+		Content: "test memo",
+	})
+	require.NoError(t, err)
+
+	// Deleting moves the memo to the trash.
+	err = s.deleteMemo(memo.ID)
+	require.NoError(t, err)
+	memoList, err := s.getMemoList()
+	require.NoError(t, err)
+	require.Len(t, memoList, 0)
+	memo, err = s.postMemoRestore(memo.ID)
+	require.NoError(t, err)
+	require.Equal(t, apiv1.Normal, memo.RowStatus)
+	memoList, err = s.getMemoList()
+	require.NoError(t, err)
+	require.Len(t, memoList, 1)
+
+	// The memos in the trash for longer than the retention are purged.
+	err = s.deleteMemo(memo.ID)
+	require.NoError(t, err)
+	updatedTs := time.Now().AddDate(0, 0, -apiv1.DefaultTrashRetention-1).Unix()
+	err = s.server.Store.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        memo.ID,
+		UpdatedTs: &updatedTs,
+	})
+	require.NoError(t, err)
+	err = trash.NewTrashRunner(s.server.Store).Purge(ctx)
+	require.NoError(t, err)
+	_, err = s.postMemoRestore(memo.ID)
+	require.Error(t, err)
+
+	// The memos in the trash are listed only for their creator, even if they're public.
+	memo, err = s.postMemoCreate(&apiv1.CreateMemoRequest{
+		Content:    "test public memo",
+		Visibility: apiv1.Public,
+	})
+	require.NoError(t, err)
+	err = s.deleteMemo(memo.ID)
+	require.NoError(t, err)
+	trashParams := map[string]string{
+		"creatorId": strconv.Itoa(int(memo.CreatorID)),
+		"rowStatus": string(store.Deleted),
+	}
+	memoList, err = s.getMemoListWithParams(trashParams)
+	require.NoError(t, err)
+	require.Len(t, memoList, 1)
+	_, err = s.server.Store.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  apiv1.SystemSettingAllowSignUpName.String(),
+		Value: "true",
+	})
+	require.NoError(t, err)
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser2",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	_, err = s.getMemoListWithParams(trashParams)
+	require.ErrorContains(t, err, "403")
+	s.cookie = ""
+	_, err = s.getMemoListWithParams(trashParams)
+	require.ErrorContains(t, err, "403")
+}
+
+func TestMemoVisibilityV2(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	_, err = s.server.Store.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  apiv1.SystemSettingAllowSignUpName.String(),
+		Value: "true",
+	})
+	require.NoError(t, err)
+	visibilities := []apiv1.Visibility{apiv1.Public, apiv1.Protected, apiv1.Private}
+	for _, visibility := range visibilities {
+		_, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+			Content:    fmt.Sprintf("%s milk", visibility),
+			Visibility: visibility,
+		})
+		require.NoError(t, err)
+	}
+	user2, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser2",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	_, err = s.postMemoCreate(&apiv1.CreateMemoRequest{
+		Content:    "PRIVATE milk",
+		Visibility: apiv1.Private,
+	})
+	require.NoError(t, err)
+
+	// The memos of all creators are the own memos and the public and protected memos of others.
+	listResponse := &apiv2pb.ListMemosResponse{}
+	require.NoError(t, s.invokeV2("/memos.api.v2.MemoService/ListMemos", &apiv2pb.ListMemosRequest{}, listResponse))
+	require.Len(t, listResponse.Memos, 3)
+	listResponse = &apiv2pb.ListMemosResponse{}
+	require.NoError(t, s.invokeV2("/memos.api.v2.MemoService/ListMemos", &apiv2pb.ListMemosRequest{CreatorId: &user2.ID}, listResponse))
+	require.Len(t, listResponse.Memos, 1)
+
+	// The search covers the same memos, in pages.
+	contents := []string{}
+	pageToken := ""
+	for {
+		searchResponse := &apiv2pb.SearchMemosResponse{}
+		require.NoError(t, s.invokeV2("/memos.api.v2.MemoService/SearchMemos", &apiv2pb.SearchMemosRequest{
+			Query:     "milk",
+			PageSize:  2,
+			PageToken: pageToken,
+		}, searchResponse))
+		for _, result := range searchResponse.Results {
+			contents = append(contents, result.Memo.Content)
+		}
+		if searchResponse.NextPageToken == "" {
+			break
+		}
+		pageToken = searchResponse.NextPageToken
+	}
+	require.ElementsMatch(t, []string{"PUBLIC milk", "PROTECTED milk", "PRIVATE milk"}, contents)
+
+	s.cookie = ""
+	searchResponse := &apiv2pb.SearchMemosResponse{}
+	require.NoError(t, s.invokeV2("/memos.api.v2.MemoService/SearchMemos", &apiv2pb.SearchMemosRequest{Query: "milk"}, searchResponse))
+	require.Len(t, searchResponse.Results, 1)
+	require.Equal(t, "PUBLIC milk", searchResponse.Results[0].Memo.Content)
+}
+
+func TestToggleMemoTask(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	memo, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+		Content: "> - [ ] quoted\n- [ ]  __kept__ ",
+	})
+	require.NoError(t, err)
+
+	toggleResponse := &apiv2pb.ToggleMemoTaskResponse{}
+	require.NoError(t, s.invokeV2("/memos.api.v2.MemoService/ToggleMemoTask", &apiv2pb.ToggleMemoTaskRequest{Id: memo.ID, Index: 1}, toggleResponse))
+	require.Equal(t, "> - [ ] quoted\n- [x]  __kept__ ", toggleResponse.Memo.Content)
+
+	// The tasks of the archived memos are not toggled.
+	archived := store.Archived
+	require.NoError(t, s.server.Store.UpdateMemo(ctx, &store.UpdateMemo{ID: memo.ID, RowStatus: &archived}))
+	require.Error(t, s.invokeV2("/memos.api.v2.MemoService/ToggleMemoTask", &apiv2pb.ToggleMemoTaskRequest{Id: memo.ID, Index: 0}, &apiv2pb.ToggleMemoTaskResponse{}))
+	storedMemo, err := s.server.Store.GetMemo(ctx, &store.FindMemo{ID: &memo.ID})
+	require.NoError(t, err)
+	require.Equal(t, "> - [ ] quoted\n- [x]  __kept__ ", storedMemo.Content)
+}
+
+func TestListMemoCommentsVisibility(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	_, err = s.server.Store.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  apiv1.SystemSettingAllowSignUpName.String(),
+		Value: "true",
+	})
+	require.NoError(t, err)
+	memo, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+		Content:    "test memo",
+		Visibility: apiv1.Public,
+	})
+	require.NoError(t, err)
+	createComment := func(content string, visibility apiv1.Visibility) {
+		_, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+			Content:      content,
+			Visibility:   visibility,
+			RelationList: []*apiv1.UpsertMemoRelationRequest{{RelatedMemoID: memo.ID, Type: apiv1.MemoRelationComment}},
+		})
+		require.NoError(t, err)
+	}
+	createComment("own private", apiv1.Private)
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser2",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	createComment("other public", apiv1.Public)
+	createComment("other protected", apiv1.Protected)
+	createComment("other private", apiv1.Private)
+
+	listMemoCommentsMethod := "/memos.api.v2.MemoService/ListMemoComments"
+	listComments := func() []string {
+		response := &apiv2pb.ListMemoCommentsResponse{}
+		require.NoError(t, s.invokeV2(listMemoCommentsMethod, &apiv2pb.ListMemoCommentsRequest{Id: memo.ID}, response))
+		contents := []string{}
+		for _, comment := range response.Memos {
+			contents = append(contents, comment.Content)
+		}
+		return contents
+	}
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"own private", "other public", "other protected"}, listComments())
+	s.cookie = ""
+	require.Error(t, s.invokeV2(listMemoCommentsMethod, &apiv2pb.ListMemoCommentsRequest{Id: memo.ID}, &apiv2pb.ListMemoCommentsResponse{}))
+}
+
 func (s *TestingServer) getMemo(memoID int32) (*apiv1.Memo, error) {
 	body, err := s.get(fmt.Sprintf("/api/v1/memo/%d", memoID), nil)
 	if err != nil {
@@ -80,7 +318,11 @@ func (s *TestingServer) getMemo(memoID int32) (*apiv1.Memo, error) {
 }
 
 func (s *TestingServer) getMemoList() ([]*apiv1.Memo, error) {
-	body, err := s.get("/api/v1/memo", nil)
+	return s.getMemoListWithParams(nil)
+}
+
+func (s *TestingServer) getMemoListWithParams(params map[string]string) ([]*apiv1.Memo, error) {
+	body, err := s.get("/api/v1/memo", params)
 	if err != nil {
 		return nil, err
 	}
@@ -174,3 +416,22 @@ func (s *TestingServer) postMemoOrganizer(memoID int32, memosOrganizer *apiv1.Up
 	}
 	return memo, err
 }
+
+func (s *TestingServer) postMemoRestore(memoID int32) (*apiv1.Memo, error) {
+	body, err := s.post(fmt.Sprintf("/api/v1/memo/%d/restore", memoID), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_, err = buf.ReadFrom(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to read response body")
+	}
+
+	memo := &apiv1.Memo{}
+	if err = json.Unmarshal(buf.Bytes(), memo); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal restore memo response")
+	}
+	return memo, nil
+}
@@ -0,0 +1,136 @@
+package testserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiresource "github.com/usememos/memos/api/resource"
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+)
+
+func TestResourceBlobSharing(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	user, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	saveResource := func(storageID int32, filename string, content string) *store.Resource {
+		create := &store.Resource{
+			CreatorID: user.ID,
+			Filename:  filename,
+			Type:      "text/plain",
+			Size:      int64(len(content)),
+		}
+		err := apiv1.SaveResourceBlobToStorage(ctx, s.server.Store, storageID, create, strings.NewReader(content))
+		require.NoError(t, err)
+		resource, err := s.server.Store.CreateResource(ctx, create)
+		require.NoError(t, err)
+		return resource
+	}
+
+	// The same file in the local storage is saved once.
+	first := saveResource(apiv1.LocalStorage, "first.txt", "local_resource")
+	second := saveResource(apiv1.LocalStorage, "second.txt", "local_resource")
+	require.Len(t, first.Hash, 64)
+	require.Equal(t, first.Hash, second.Hash)
+	require.Equal(t, first.InternalPath, second.InternalPath)
+	other := saveResource(apiv1.LocalStorage, "other.txt", "other_resource")
+	require.NotEqual(t, first.InternalPath, other.InternalPath)
+	require.NoError(t, apiresource.DeleteResourceWithBlob(ctx, s.server.Store, first))
+	require.FileExists(t, second.InternalPath)
+	require.NoError(t, apiresource.DeleteResourceWithBlob(ctx, s.server.Store, second))
+	require.NoFileExists(t, second.InternalPath)
+
+	// The same blob in the database is held by one resource, and handed over on deletion.
+	first = saveResource(apiv1.DatabaseStorage, "first.txt", "database_resource")
+	second = saveResource(apiv1.DatabaseStorage, "second.txt", "database_resource")
+	requireResourceBlob(ctx, t, s, first.ID, []byte("database_resource"))
+	requireResourceBlob(ctx, t, s, second.ID, nil)
+	blob, err := s.server.Store.GetResourceBlob(ctx, second)
+	require.NoError(t, err)
+	require.Equal(t, []byte("database_resource"), blob)
+	require.NoError(t, apiresource.DeleteResourceWithBlob(ctx, s.server.Store, first))
+	requireResourceBlob(ctx, t, s, second.ID, []byte("database_resource"))
+
+	// The blob moved away is kept for the resources still sharing it.
+	third := saveResource(apiv1.LocalStorage, "third.txt", "moved_resource")
+	fourth := saveResource(apiv1.LocalStorage, "fourth.txt", "moved_resource")
+	moved, err := apiv1.MoveResourceBlob(ctx, s.server.Store, third, apiv1.DatabaseStorage)
+	require.NoError(t, err)
+	require.Empty(t, moved.InternalPath)
+	require.Equal(t, third.Hash, moved.Hash)
+	requireResourceBlob(ctx, t, s, moved.ID, []byte("moved_resource"))
+	require.FileExists(t, fourth.InternalPath)
+}
+
+func TestDeduplicateResourceBlobs(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	user, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	// The resources saved before the hashes.
+	createResource := func(create *store.Resource) *store.Resource {
+		create.CreatorID = user.ID
+		create.Type = "text/plain"
+		create.Size = 13
+		resource, err := s.server.Store.CreateResource(ctx, create)
+		require.NoError(t, err)
+		return resource
+	}
+	first := createResource(&store.Resource{Filename: "first.txt", Blob: []byte("test_resource")})
+	second := createResource(&store.Resource{Filename: "second.txt", Blob: []byte("test_resource")})
+	paths := []string{}
+	for _, filename := range []string{"third.txt", "fourth.txt"} {
+		path := filepath.Join(s.profile.Data, "assets", filename)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte("test_resource"), 0644))
+		paths = append(paths, path)
+	}
+	third := createResource(&store.Resource{Filename: "third.txt", InternalPath: paths[0]})
+	fourth := createResource(&store.Resource{Filename: "fourth.txt", InternalPath: paths[1]})
+	createResource(&store.Resource{Filename: "link.txt", ExternalLink: "https://example.com/link.txt"})
+
+	// Nothing is changed in a dry run.
+	result, err := apiv1.DeduplicateResourceBlobs(ctx, s.server.Store, true)
+	require.NoError(t, err)
+	require.Equal(t, &apiv1.DeduplicateResult{HashedCount: 4, SharedCount: 2, FreedSize: 26}, result)
+	requireResourceBlob(ctx, t, s, second.ID, []byte("test_resource"))
+	require.FileExists(t, paths[1])
+
+	result, err = apiv1.DeduplicateResourceBlobs(ctx, s.server.Store, false)
+	require.NoError(t, err)
+	require.Equal(t, &apiv1.DeduplicateResult{HashedCount: 4, SharedCount: 2, FreedSize: 26}, result)
+	requireResourceBlob(ctx, t, s, first.ID, []byte("test_resource"))
+	requireResourceBlob(ctx, t, s, second.ID, nil)
+	blob, err := s.server.Store.GetResourceBlob(ctx, second)
+	require.NoError(t, err)
+	require.Equal(t, []byte("test_resource"), blob)
+	fourth, err = s.server.Store.GetResource(ctx, &store.FindResource{ID: &fourth.ID})
+	require.NoError(t, err)
+	require.Equal(t, third.InternalPath, fourth.InternalPath)
+	require.NotEmpty(t, fourth.Hash)
+	require.FileExists(t, paths[0])
+	require.NoFileExists(t, paths[1])
+
+	// It's done once.
+	result, err = apiv1.DeduplicateResourceBlobs(ctx, s.server.Store, false)
+	require.NoError(t, err)
+	require.Equal(t, &apiv1.DeduplicateResult{}, result)
+}
@@ -0,0 +1,208 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+)
+
+func TestResourceEXIF(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+
+	photo := buildPhoto(t)
+	capturedTs := time.Date(2023, 5, 1, 10, 30, 0, 0, time.FixedZone("", 8*60*60)).Unix()
+	uploads := map[string]func() *apiv1.Resource{
+		"blob": func() *apiv1.Resource {
+			return s.uploadResourceBlob(t, "photo.jpg", "image/jpeg", photo)
+		},
+		"resumable": func() *apiv1.Resource {
+			response := s.sendResourceUpload(t, http.MethodPost, "/api/v1/resource/upload", strings.NewReader(fmt.Sprintf(`{"filename":"photo.jpg","type":"image/jpeg","size":%d}`, len(photo))), nil)
+			upload := requireResourceUpload(t, response, 0)
+			uri := fmt.Sprintf("/api/v1/resource/upload/%d", upload.ID)
+			// The photo is uploaded in two parts, which are read across as one blob.
+			response = s.sendResourceUpload(t, http.MethodPatch, uri, bytes.NewReader(photo[:100]), map[string]string{"Upload-Offset": "0"})
+			requireResourceUpload(t, response, 100)
+			response = s.sendResourceUpload(t, http.MethodPatch, uri, bytes.NewReader(photo[100:]), map[string]string{"Upload-Offset": "100"})
+			requireResourceUpload(t, response, int64(len(photo)))
+			response = s.sendResourceUpload(t, http.MethodPost, uri+"/finalize", nil, nil)
+			defer response.Body.Close()
+			require.Equal(t, http.StatusOK, response.StatusCode)
+			resource := &apiv1.Resource{}
+			require.NoError(t, json.NewDecoder(response.Body).Decode(resource))
+			return resource
+		},
+	}
+
+	for name, upload := range uploads {
+		t.Run(name, func(t *testing.T) {
+			// The location and device data are stripped by default, and the photo is rotated upright.
+			resource := upload()
+			require.Equal(t, capturedTs, resource.CapturedTs)
+			blob := requireResourceHash(ctx, t, s, resource)
+			require.Equal(t, int64(len(blob)), resource.Size)
+			for _, removed := range []string{"Exif", "TestPhone", "GPSLatitude"} {
+				require.NotContains(t, string(blob), removed)
+			}
+			img, err := jpeg.Decode(bytes.NewReader(blob))
+			require.NoError(t, err)
+			require.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+
+			// The photos are kept as they are if it's disabled, while the capture time is still read.
+			setStripImageMetadata(ctx, t, s, false)
+			defer setStripImageMetadata(ctx, t, s, true)
+			resource = upload()
+			require.Equal(t, capturedTs, resource.CapturedTs)
+			require.Equal(t, photo, requireResourceHash(ctx, t, s, resource))
+		})
+	}
+
+	// The photos whose EXIF can't be parsed are encoded again from their pixels.
+	brokenPhoto := bytes.Replace(photo, []byte("MM\x00*"), []byte("MM\x00\x00"), 1)
+	resource := s.uploadResourceBlob(t, "photo.jpg", "image/jpeg", brokenPhoto)
+	blob := requireResourceHash(ctx, t, s, resource)
+	for _, removed := range []string{"Exif", "TestPhone", "GPSLatitude"} {
+		require.NotContains(t, string(blob), removed)
+	}
+	_, err = jpeg.Decode(bytes.NewReader(blob))
+	require.NoError(t, err)
+	// And the ones which can't be decoded either are rejected.
+	response := s.sendResourceBlob(t, "photo.jpg", "image/jpeg", brokenPhoto[:len(photo)/2])
+	response.Body.Close()
+	require.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// The other files are kept as they are.
+	resource = s.uploadResourceBlob(t, "test.txt", "text/plain", []byte("test_resource"))
+	require.Zero(t, resource.CapturedTs)
+	require.Equal(t, []byte("test_resource"), requireResourceHash(ctx, t, s, resource))
+}
+
+func setStripImageMetadata(ctx context.Context, t *testing.T, s *TestingServer, value bool) {
+	_, err := s.server.Store.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  apiv1.SystemSettingStripImageMetadataName.String(),
+		Value: strconv.FormatBool(value),
+	})
+	require.NoError(t, err)
+}
+
+// uploadResourceBlob uploads the file as a multipart form with the content type.
+func (s *TestingServer) uploadResourceBlob(t *testing.T, filename, contentType string, content []byte) *apiv1.Resource {
+	response := s.sendResourceBlob(t, filename, contentType, content)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	resource := &apiv1.Resource{}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(resource))
+	return resource
+}
+
+func (s *TestingServer) sendResourceBlob(t *testing.T, filename, contentType string, content []byte) *http.Response {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filename))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return s.sendResourceUpload(t, http.MethodPost, "/api/v1/resource/blob", body, map[string]string{"Content-Type": writer.FormDataContentType()})
+}
+
+// requireResourceHash requires the hash of the resource to match the blob it serves, and returns the blob.
+func requireResourceHash(ctx context.Context, t *testing.T, s *TestingServer, resource *apiv1.Resource) []byte {
+	response := s.getWithHeader(t, fmt.Sprintf("/o/r/%d", resource.ID), http.Header{})
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	blob, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	storeResource, err := s.server.Store.GetResource(ctx, &store.FindResource{ID: &resource.ID})
+	require.NoError(t, err)
+	checksum := sha256.Sum256(blob)
+	require.Equal(t, hex.EncodeToString(checksum[:]), storeResource.Hash)
+	return blob
+}
+
+// buildPhoto builds a 40x20 JPEG photo taken by "TestPhone" with the orientation rotating it clockwise,
+// and the location in both EXIF and XMP.
+func buildPhoto(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 6), G: uint8(y * 12), B: 0x80, A: 0xff})
+		}
+	}
+	encoded := &bytes.Buffer{}
+	require.NoError(t, jpeg.Encode(encoded, img, nil))
+
+	// The TIFF structure of EXIF in big-endian, with IFD0 at 8, the EXIF IFD at 100 and the GPS IFD at 200.
+	tiff := make([]byte, 300)
+	copy(tiff, "MM\x00*")
+	binary.BigEndian.PutUint32(tiff[4:], 8)
+	writeIFD := func(offset int, entries [][]any) {
+		binary.BigEndian.PutUint16(tiff[offset:], uint16(len(entries)))
+		valueOffset := offset + 2 + len(entries)*12 + 4
+		for i, entry := range entries {
+			field := tiff[offset+2+i*12:]
+			binary.BigEndian.PutUint16(field, entry[0].(uint16))
+			switch value := entry[1].(type) {
+			case uint16:
+				binary.BigEndian.PutUint16(field[2:], 3)
+				binary.BigEndian.PutUint32(field[4:], 1)
+				binary.BigEndian.PutUint16(field[8:], value)
+			case uint32:
+				binary.BigEndian.PutUint16(field[2:], 4)
+				binary.BigEndian.PutUint32(field[4:], 1)
+				binary.BigEndian.PutUint32(field[8:], value)
+			case string:
+				binary.BigEndian.PutUint16(field[2:], 2)
+				binary.BigEndian.PutUint32(field[4:], uint32(len(value)+1))
+				binary.BigEndian.PutUint32(field[8:], uint32(valueOffset))
+				copy(tiff[valueOffset:], value)
+				valueOffset += len(value) + 1
+			}
+		}
+	}
+	writeIFD(8, [][]any{{uint16(0x010F), "TestPhone"}, {uint16(0x0112), uint16(6)}, {uint16(0x8769), uint32(100)}, {uint16(0x8825), uint32(200)}})
+	writeIFD(100, [][]any{{uint16(0x9003), "2023:05:01 10:30:00"}, {uint16(0x9011), "+08:00"}})
+	writeIFD(200, [][]any{{uint16(0x0001), "N"}})
+
+	photo := &bytes.Buffer{}
+	photo.Write(encoded.Bytes()[:2])
+	for _, payload := range [][]byte{append([]byte("Exif\x00\x00"), tiff...), []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>GPSLatitude</x:xmpmeta>")} {
+		photo.Write([]byte{0xFF, 0xE1})
+		require.NoError(t, binary.Write(photo, binary.BigEndian, uint16(len(payload)+2)))
+		photo.Write(payload)
+	}
+	photo.Write(encoded.Bytes()[2:])
+	return photo.Bytes()
+}
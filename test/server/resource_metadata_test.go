@@ -0,0 +1,109 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
+	"github.com/usememos/memos/store"
+)
+
+func TestResourceMetadata(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+
+	pngBlob := &bytes.Buffer{}
+	require.NoError(t, png.Encode(pngBlob, image.NewNRGBA(image.Rect(0, 0, 30, 20))))
+	pngResource := s.uploadResourceBlob(t, "image.png", "image/jpeg", pngBlob.Bytes())
+	require.Equal(t, "image/png", pngResource.Type)
+	// The photos are probed in the dimensions they're displayed in, whether they're rotated upright or not.
+	photoResource := s.uploadResourceBlob(t, "photo.jpg", "image/jpeg", buildPhoto(t))
+	setStripImageMetadata(ctx, t, s, false)
+	keptPhotoResource := s.uploadResourceBlob(t, "kept.jpg", "image/jpeg", buildPhoto(t))
+	setStripImageMetadata(ctx, t, s, true)
+	wavResource := s.uploadResourceBlob(t, "audio.wav", "audio/wav", buildWAV(2*time.Second))
+	textResource := s.uploadResourceBlob(t, "test.txt", "text/plain", []byte("test_resource"))
+
+	resources := s.listResourcesV2(t)
+	require.Len(t, resources, 5)
+	requireResourceMetadata(ctx, t, s, resources[pngResource.ID], 30, 20, 0)
+	requireResourceMetadata(ctx, t, s, resources[photoResource.ID], 20, 40, 0)
+	requireResourceMetadata(ctx, t, s, resources[keptPhotoResource.ID], 20, 40, 0)
+	requireResourceMetadata(ctx, t, s, resources[wavResource.ID], 0, 0, 2*time.Second)
+	requireResourceMetadata(ctx, t, s, resources[textResource.ID], 0, 0, 0)
+	require.Equal(t, time.Date(2023, 5, 1, 2, 30, 0, 0, time.UTC), resources[photoResource.ID].CapturedTs.AsTime())
+	require.Nil(t, resources[pngResource.ID].CapturedTs)
+
+	// The HTML disguised as an image is saved as HTML, which is served as text without sniffing.
+	htmlResource := s.uploadResourceBlob(t, "image.png", "image/png", []byte("<html><script>alert(1)</script></html>"))
+	require.Equal(t, "text/html", htmlResource.Type)
+	response := s.getWithHeader(t, fmt.Sprintf("/o/r/%d", htmlResource.ID), http.Header{})
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "text/plain; charset=UTF-8", response.Header.Get("Content-Type"))
+	require.Equal(t, "nosniff", response.Header.Get("X-Content-Type-Options"))
+}
+
+// listResourcesV2 lists the resources of the current user by the v2 API, by their IDs.
+func (s *TestingServer) listResourcesV2(t *testing.T) map[int32]*apiv2pb.Resource {
+	response := &apiv2pb.ListResourcesResponse{}
+	require.NoError(t, s.invokeV2("/memos.api.v2.ResourceService/ListResources", &apiv2pb.ListResourcesRequest{}, response))
+	resources := map[int32]*apiv2pb.Resource{}
+	for _, resource := range response.Resources {
+		resources[resource.Id] = resource
+	}
+	return resources
+}
+
+// requireResourceMetadata requires the metadata of the resource, with the checksum of the blob it serves.
+func requireResourceMetadata(ctx context.Context, t *testing.T, s *TestingServer, resource *apiv2pb.Resource, width, height int32, duration time.Duration) {
+	require.NotNil(t, resource)
+	require.Equal(t, width, resource.Width)
+	require.Equal(t, height, resource.Height)
+	if duration == 0 {
+		require.Nil(t, resource.Duration)
+	} else {
+		require.Equal(t, duration, resource.Duration.AsDuration())
+	}
+	storeResource, err := s.server.Store.GetResource(ctx, &store.FindResource{ID: &resource.Id})
+	require.NoError(t, err)
+	require.NotEmpty(t, resource.Checksum)
+	require.Equal(t, storeResource.Hash, resource.Checksum)
+	requireResourceHash(ctx, t, s, &apiv1.Resource{ID: resource.Id})
+}
+
+// buildWAV builds a WAV file of the duration in 8 kHz, 8-bit mono.
+func buildWAV(duration time.Duration) []byte {
+	size := uint32(duration.Seconds() * 8000)
+	blob := []byte("RIFF")
+	blob = binary.LittleEndian.AppendUint32(blob, 36+size)
+	blob = append(blob, "WAVEfmt "...)
+	blob = binary.LittleEndian.AppendUint32(blob, 16)
+	blob = binary.LittleEndian.AppendUint16(blob, 1)
+	blob = binary.LittleEndian.AppendUint16(blob, 1)
+	blob = binary.LittleEndian.AppendUint32(blob, 8000)
+	blob = binary.LittleEndian.AppendUint32(blob, 8000)
+	blob = binary.LittleEndian.AppendUint16(blob, 1)
+	blob = binary.LittleEndian.AppendUint16(blob, 8)
+	blob = append(blob, "data"...)
+	blob = binary.LittleEndian.AppendUint32(blob, size)
+	return append(blob, make([]byte, size)...)
+}
@@ -0,0 +1,215 @@
+package testserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiresource "github.com/usememos/memos/api/resource"
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/test"
+)
+
+func TestMoveResourceBlob(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	s3Server := test.NewS3Server(t)
+	s3Config, err := json.Marshal(&apiv1.StorageS3Config{
+		EndPoint:  s3Server.URL,
+		Region:    "us-east-1",
+		AccessKey: "test_access_key",
+		SecretKey: "test_secret_key",
+		Bucket:    "memos",
+		Path:      "resources/{filename}",
+	})
+	require.NoError(t, err)
+	storage, err := s.server.Store.CreateStorage(ctx, &store.Storage{
+		Name:   "test_storage",
+		Type:   apiv1.StorageS3.String(),
+		Config: string(s3Config),
+	})
+	require.NoError(t, err)
+	user, err := s.server.Store.CreateUser(ctx, &store.User{
+		Username: "test",
+		Role:     store.RoleHost,
+		Email:    "test@test.com",
+		Nickname: "test_nickname",
+	})
+	require.NoError(t, err)
+	resource, err := s.server.Store.CreateResource(ctx, &store.Resource{
+		CreatorID: user.ID,
+		Filename:  "test.txt",
+		Blob:      []byte("test_resource"),
+		Type:      "text/plain",
+		Size:      13,
+	})
+	require.NoError(t, err)
+	storageID, ok, err := apiv1.GetResourceStorageID(ctx, s.server.Store, resource)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, apiv1.DatabaseStorage, storageID)
+
+	// Database to local.
+	resource, err = apiv1.MoveResourceBlob(ctx, s.server.Store, resource, apiv1.LocalStorage)
+	require.NoError(t, err)
+	require.NotEmpty(t, resource.InternalPath)
+	blob, err := os.ReadFile(resource.InternalPath)
+	require.NoError(t, err)
+	require.Equal(t, []byte("test_resource"), blob)
+	requireResourceBlob(ctx, t, s, resource.ID, nil)
+
+	// Local to S3.
+	internalPath := resource.InternalPath
+	resource, err = apiv1.MoveResourceBlob(ctx, s.server.Store, resource, storage.ID)
+	require.NoError(t, err)
+	require.Empty(t, resource.InternalPath)
+	require.NotEmpty(t, resource.ExternalLink)
+	require.NoFileExists(t, internalPath)
+	require.Equal(t, []string{"memos/resources/test.txt"}, s3Server.Objects())
+	storageID, ok, err = apiv1.GetResourceStorageID(ctx, s.server.Store, resource)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, storage.ID, storageID)
+
+	// S3 to database.
+	resource, err = apiv1.MoveResourceBlob(ctx, s.server.Store, resource, apiv1.DatabaseStorage)
+	require.NoError(t, err)
+	require.Empty(t, resource.InternalPath)
+	require.Empty(t, resource.ExternalLink)
+	require.Empty(t, s3Server.Objects())
+	requireResourceBlob(ctx, t, s, resource.ID, []byte("test_resource"))
+
+	// The external links out of the storages can't be moved.
+	link, err := s.server.Store.CreateResource(ctx, &store.Resource{
+		CreatorID:    user.ID,
+		Filename:     "link.png",
+		ExternalLink: "https://example.com/link.png",
+		Type:         "image/png",
+	})
+	require.NoError(t, err)
+	_, ok, err = apiv1.GetResourceStorageID(ctx, s.server.Store, link)
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, err = apiv1.MoveResourceBlob(ctx, s.server.Store, link, apiv1.LocalStorage)
+	require.Error(t, err)
+}
+
+func requireResourceBlob(ctx context.Context, t *testing.T, s *TestingServer, id int32, blob []byte) {
+	resource, err := s.server.Store.GetResource(ctx, &store.FindResource{
+		ID:      &id,
+		GetBlob: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, len(blob), len(resource.Blob))
+	if len(blob) > 0 {
+		require.Equal(t, blob, resource.Blob)
+	}
+}
+
+func TestResourceRemoteStorages(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	webdavDir := t.TempDir()
+	webdavServer := test.NewWebDAVServer(t, webdavDir, "test", "test_password")
+	webdavConfig, err := json.Marshal(&apiv1.StorageWebDAVConfig{
+		URL:      webdavServer.URL,
+		Path:     "resources/{filename}",
+		Username: "test",
+		Password: "test_password",
+	})
+	require.NoError(t, err)
+	webdavStorage, err := s.server.Store.CreateStorage(ctx, &store.Storage{
+		Name:   "test_webdav",
+		Type:   apiv1.StorageWebDAV.String(),
+		Config: string(webdavConfig),
+	})
+	require.NoError(t, err)
+	sftpDir := t.TempDir()
+	sftpServer := test.NewSFTPServer(t, sftpDir, "test", "test_password")
+	sftpConfig, err := json.Marshal(&apiv1.StorageSFTPConfig{
+		Host:     sftpServer.Host,
+		Port:     sftpServer.Port,
+		Path:     "memos/{filename}",
+		Username: "test",
+		Password: "test_password",
+		HostKey:  sftpServer.HostKey,
+	})
+	require.NoError(t, err)
+	sftpStorage, err := s.server.Store.CreateStorage(ctx, &store.Storage{
+		Name:   "test_sftp",
+		Type:   apiv1.StorageSFTP.String(),
+		Config: string(sftpConfig),
+	})
+	require.NoError(t, err)
+	user, err := s.server.Store.CreateUser(ctx, &store.User{
+		Username: "test",
+		Role:     store.RoleHost,
+		Email:    "test@test.com",
+		Nickname: "test_nickname",
+	})
+	require.NoError(t, err)
+
+	// Save into WebDAV.
+	create := &store.Resource{
+		CreatorID: user.ID,
+		Filename:  "test.txt",
+		Type:      "text/plain",
+		Size:      13,
+	}
+	err = apiv1.SaveResourceBlobToStorage(ctx, s.server.Store, webdavStorage.ID, create, strings.NewReader("test_resource"))
+	require.NoError(t, err)
+	require.Equal(t, webdavStorage.ID, create.StorageID)
+	require.Equal(t, "resources/test.txt", create.InternalPath)
+	resource, err := s.server.Store.CreateResource(ctx, create)
+	require.NoError(t, err)
+	blob, err := os.ReadFile(filepath.Join(webdavDir, "resources", "test.txt"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("test_resource"), blob)
+	body, err := s.get(fmt.Sprintf("/o/r/%d", resource.ID), nil)
+	require.NoError(t, err)
+	blob, err = io.ReadAll(body)
+	require.NoError(t, err)
+	require.Equal(t, []byte("test_resource"), blob)
+
+	// WebDAV to SFTP.
+	resource, err = apiv1.MoveResourceBlob(ctx, s.server.Store, resource, sftpStorage.ID)
+	require.NoError(t, err)
+	require.Equal(t, sftpStorage.ID, resource.StorageID)
+	require.NoFileExists(t, filepath.Join(webdavDir, "resources", "test.txt"))
+	blob, err = os.ReadFile(filepath.Join(sftpDir, "memos", "test.txt"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("test_resource"), blob)
+	storageID, ok, err := apiv1.GetResourceStorageID(ctx, s.server.Store, resource)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, sftpStorage.ID, storageID)
+
+	// SFTP to database.
+	resource, err = apiv1.MoveResourceBlob(ctx, s.server.Store, resource, apiv1.DatabaseStorage)
+	require.NoError(t, err)
+	require.Zero(t, resource.StorageID)
+	require.Empty(t, resource.InternalPath)
+	require.NoFileExists(t, filepath.Join(sftpDir, "memos", "test.txt"))
+	requireResourceBlob(ctx, t, s, resource.ID, []byte("test_resource"))
+
+	// The blob in the storage is deleted with the resource.
+	resource, err = apiv1.MoveResourceBlob(ctx, s.server.Store, resource, sftpStorage.ID)
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(sftpDir, "memos", "test.txt"))
+	require.NoError(t, apiresource.DeleteResourceWithBlob(ctx, s.server.Store, resource))
+	require.NoFileExists(t, filepath.Join(sftpDir, "memos", "test.txt"))
+}
@@ -0,0 +1,121 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/webp"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/test"
+)
+
+func TestResourcePreviews(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	user, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	s3Server := test.NewS3Server(t)
+	config, err := json.Marshal(&apiv1.StorageS3Config{
+		EndPoint:  s3Server.URL,
+		Region:    "us-east-1",
+		AccessKey: "test_access_key",
+		SecretKey: "test_secret_key",
+		Bucket:    "memos",
+		Path:      "resources/{filename}",
+		Private:   true,
+	})
+	require.NoError(t, err)
+	storage, err := s.server.Store.CreateStorage(ctx, &store.Storage{
+		Name:   "test_storage",
+		Type:   apiv1.StorageS3.String(),
+		Config: string(config),
+	})
+	require.NoError(t, err)
+
+	src := image.NewNRGBA(image.Rect(0, 0, 800, 600))
+	for y := 0; y < 600; y++ {
+		for x := 0; x < 800; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(x * y), A: 0xff})
+		}
+	}
+	blob := &bytes.Buffer{}
+	require.NoError(t, png.Encode(blob, src))
+
+	for _, storageID := range []int32{apiv1.DatabaseStorage, apiv1.LocalStorage, storage.ID} {
+		create := &store.Resource{
+			CreatorID: user.ID,
+			Filename:  fmt.Sprintf("test_%d.png", storageID),
+			Type:      "image/png",
+			Size:      int64(blob.Len()),
+		}
+		err := apiv1.SaveResourceBlobToStorage(ctx, s.server.Store, storageID, create, bytes.NewReader(blob.Bytes()))
+		require.NoError(t, err)
+		resource, err := s.server.Store.CreateResource(ctx, create)
+		require.NoError(t, err)
+		uri := fmt.Sprintf("/o/r/%d?thumbnail=1", resource.ID)
+
+		// The resource itself is served until its previews are generated in the background.
+		response := s.getWithHeader(t, uri, http.Header{})
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, "Accept", response.Header.Get("Vary"))
+		response.Body.Close()
+		require.Eventually(t, func() bool {
+			response := s.getWithHeader(t, uri, http.Header{})
+			defer response.Body.Close()
+			return strings.HasSuffix(response.Header.Get("ETag"), `-thumbnail-512.png"`)
+		}, 10*time.Second, 50*time.Millisecond)
+
+		for size, width := range map[string]int{"": 512, "200": 256, "1000": 800} {
+			response := s.getWithHeader(t, uri+"&size="+size, http.Header{})
+			require.Equal(t, http.StatusOK, response.StatusCode)
+			require.Equal(t, "image/png", response.Header.Get("Content-Type"))
+			thumbnail, err := png.Decode(response.Body)
+			response.Body.Close()
+			require.NoError(t, err)
+			require.Equal(t, image.Rect(0, 0, width, width*3/4), thumbnail.Bounds())
+		}
+
+		// The WebP variant is served if it's accepted, as it's smaller than the PNG one here.
+		response = s.getWithHeader(t, uri, http.Header{"Accept": {"image/webp,image/*"}})
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, "image/webp", response.Header.Get("Content-Type"))
+		require.True(t, strings.HasSuffix(response.Header.Get("ETag"), `-thumbnail-512.webp"`))
+		thumbnail, err := webp.Decode(response.Body)
+		response.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, image.Rect(0, 0, 512, 384), thumbnail.Bounds())
+
+		previewKey := fmt.Sprintf(".thumbnail_cache/%d/512.png", resource.ID)
+		if storageID == storage.ID {
+			require.Contains(t, s3Server.Objects(), "memos/"+previewKey)
+		} else {
+			require.FileExists(t, filepath.Join(s.profile.Data, filepath.FromSlash(previewKey)))
+		}
+
+		// The previews are deleted along with the resource.
+		_, err = s.delete(fmt.Sprintf("/api/v1/resource/%d", resource.ID), nil)
+		require.NoError(t, err)
+		require.NoDirExists(t, filepath.Join(s.profile.Data, ".thumbnail_cache", strconv.Itoa(int(resource.ID))))
+	}
+	require.Empty(t, s3Server.Objects())
+}
@@ -0,0 +1,267 @@
+package testserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/test"
+)
+
+func TestPrivateS3Resource(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	user, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	s3Server := test.NewS3Server(t)
+	s3Config := &apiv1.StorageS3Config{
+		EndPoint:  s3Server.URL,
+		Region:    "us-east-1",
+		AccessKey: "test_access_key",
+		SecretKey: "test_secret_key",
+		Bucket:    "memos",
+		Path:      "resources/{filename}",
+		Private:   true,
+	}
+	config, err := json.Marshal(s3Config)
+	require.NoError(t, err)
+	storage, err := s.server.Store.CreateStorage(ctx, &store.Storage{
+		Name:   "test_storage",
+		Type:   apiv1.StorageS3.String(),
+		Config: string(config),
+	})
+	require.NoError(t, err)
+
+	// The private objects aren't linked.
+	create := &store.Resource{
+		CreatorID: user.ID,
+		Filename:  "test.txt",
+		Type:      "text/plain",
+		Size:      13,
+	}
+	err = apiv1.SaveResourceBlobToStorage(ctx, s.server.Store, storage.ID, create, strings.NewReader("test_resource"))
+	require.NoError(t, err)
+	require.Empty(t, create.ExternalLink)
+	require.Equal(t, storage.ID, create.StorageID)
+	require.Equal(t, "resources/test.txt", create.InternalPath)
+	require.Equal(t, []string{"memos/resources/test.txt"}, s3Server.Objects())
+	memo, err := s.server.Store.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_memo",
+		Visibility: store.Private,
+	})
+	require.NoError(t, err)
+	create.MemoID = &memo.ID
+	resource, err := s.server.Store.CreateResource(ctx, create)
+	require.NoError(t, err)
+	uri := fmt.Sprintf("/o/r/%d", resource.ID)
+
+	// Proxied to the creator only.
+	response := s.getWithoutRedirect(t, uri, s.cookie)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "private, max-age=31536000, immutable", response.Header.Get("Cache-Control"))
+	requireBody(t, response, "test_resource")
+	response = s.getWithoutRedirect(t, uri, "")
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	response.Body.Close()
+
+	// Redirected to the presigned URL.
+	s3Config.PresignExpiry = 60
+	config, err = json.Marshal(s3Config)
+	require.NoError(t, err)
+	configString := string(config)
+	_, err = s.server.Store.UpdateStorage(ctx, &store.UpdateStorage{
+		ID:     storage.ID,
+		Config: &configString,
+	})
+	require.NoError(t, err)
+	response = s.getWithoutRedirect(t, uri, s.cookie)
+	require.Equal(t, http.StatusFound, response.StatusCode)
+	require.Equal(t, "no-store", response.Header.Get("Cache-Control"))
+	response.Body.Close()
+	link := response.Header.Get("Location")
+	require.True(t, strings.HasPrefix(link, s3Server.URL+"/memos/resources/test.txt?"), link)
+	require.Contains(t, link, "X-Amz-Expires=60")
+	require.Contains(t, link, "X-Amz-Signature=")
+	response, err = http.Get(link)
+	require.NoError(t, err)
+	requireBody(t, response, "test_resource")
+	response = s.getWithoutRedirect(t, uri, "")
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	response.Body.Close()
+}
+
+func TestStreamResourceRange(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	user, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	s3Server := test.NewS3Server(t)
+	config, err := json.Marshal(&apiv1.StorageS3Config{
+		EndPoint:  s3Server.URL,
+		Region:    "us-east-1",
+		AccessKey: "test_access_key",
+		SecretKey: "test_secret_key",
+		Bucket:    "memos",
+		Path:      "resources/{filename}",
+		Private:   true,
+	})
+	require.NoError(t, err)
+	storage, err := s.server.Store.CreateStorage(ctx, &store.Storage{
+		Name:   "test_storage",
+		Type:   apiv1.StorageS3.String(),
+		Config: string(config),
+	})
+	require.NoError(t, err)
+
+	for _, storageID := range []int32{apiv1.DatabaseStorage, apiv1.LocalStorage, storage.ID} {
+		content := fmt.Sprintf("0123456789_%d", storageID)
+		create := &store.Resource{
+			CreatorID: user.ID,
+			Filename:  "test.mp3",
+			Type:      "audio/mpeg",
+			Size:      int64(len(content)),
+		}
+		err := apiv1.SaveResourceBlobToStorage(ctx, s.server.Store, storageID, create, strings.NewReader(content))
+		require.NoError(t, err)
+		resource, err := s.server.Store.CreateResource(ctx, create)
+		require.NoError(t, err)
+		uri := fmt.Sprintf("/o/r/%d", resource.ID)
+
+		response := s.getWithHeader(t, uri, http.Header{})
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, "bytes", response.Header.Get("Accept-Ranges"))
+		require.Equal(t, "audio/mpeg", response.Header.Get("Content-Type"))
+		etag, lastModified := response.Header.Get("ETag"), response.Header.Get("Last-Modified")
+		require.Equal(t, fmt.Sprintf(`"%s"`, resource.Hash), etag)
+		require.NotEmpty(t, lastModified)
+		requireBody(t, response, content)
+
+		response = s.getWithHeader(t, uri, http.Header{"Range": {"bytes=2-4"}})
+		require.Equal(t, http.StatusPartialContent, response.StatusCode)
+		require.Equal(t, fmt.Sprintf("bytes 2-4/%d", len(content)), response.Header.Get("Content-Range"))
+		requireBody(t, response, "234")
+		response = s.getWithHeader(t, uri, http.Header{"Range": {"bytes=-3"}})
+		require.Equal(t, http.StatusPartialContent, response.StatusCode)
+		requireBody(t, response, content[len(content)-3:])
+		response = s.getWithHeader(t, uri, http.Header{"Range": {"bytes=100-"}})
+		require.Equal(t, http.StatusRequestedRangeNotSatisfiable, response.StatusCode)
+		response.Body.Close()
+
+		// The unchanged resource isn't sent again.
+		response = s.getWithHeader(t, uri, http.Header{"If-None-Match": {etag}})
+		require.Equal(t, http.StatusNotModified, response.StatusCode)
+		requireBody(t, response, "")
+		response = s.getWithHeader(t, uri, http.Header{"If-Modified-Since": {lastModified}})
+		require.Equal(t, http.StatusNotModified, response.StatusCode)
+		requireBody(t, response, "")
+		response = s.getWithHeader(t, uri, http.Header{"If-None-Match": {`"changed"`}})
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		requireBody(t, response, content)
+		// The range is ignored if the resource has changed.
+		response = s.getWithHeader(t, uri, http.Header{"Range": {"bytes=2-4"}, "If-Range": {`"changed"`}})
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		requireBody(t, response, content)
+	}
+}
+
+// getWithHeader sends a GET request with the header, and returns the response as it is.
+func TestTrashedMemoResource(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	user, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	memo, err := s.server.Store.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_memo",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	missingMemoID := memo.ID + 1
+	uris := []string{}
+	for _, memoID := range []int32{memo.ID, missingMemoID} {
+		resource, err := s.server.Store.CreateResource(ctx, &store.Resource{
+			CreatorID: user.ID,
+			Filename:  "test.txt",
+			Type:      "text/plain",
+			Blob:      []byte("test_resource"),
+			Size:      13,
+			MemoID:    &memoID,
+		})
+		require.NoError(t, err)
+		uris = append(uris, fmt.Sprintf("/o/r/%d", resource.ID))
+	}
+	response := s.getWithoutRedirect(t, uris[0], "")
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	requireBody(t, response, "test_resource")
+
+	// The resources of the memos in the trash and of the missing memos are served to their creators only.
+	deletedStatus := store.Deleted
+	require.NoError(t, s.server.Store.UpdateMemo(ctx, &store.UpdateMemo{ID: memo.ID, RowStatus: &deletedStatus}))
+	for _, uri := range uris {
+		response := s.getWithoutRedirect(t, uri, "")
+		require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+		response.Body.Close()
+		response = s.getWithoutRedirect(t, uri, s.cookie)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, "private, max-age=31536000, immutable", response.Header.Get("Cache-Control"))
+		requireBody(t, response, "test_resource")
+	}
+}
+
+func (s *TestingServer) getWithHeader(t *testing.T, uri string, header http.Header) *http.Response {
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:%d%s", s.profile.Port, uri), nil)
+	require.NoError(t, err)
+	request.Header = header
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	return response
+}
+
+// getWithoutRedirect sends a GET request with the cookie, and returns the response as it is.
+func (s *TestingServer) getWithoutRedirect(t *testing.T, uri string, cookie string) *http.Response {
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:%d%s", s.profile.Port, uri), nil)
+	require.NoError(t, err)
+	request.Header.Set("Cookie", cookie)
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	response, err := client.Do(request)
+	require.NoError(t, err)
+	return response
+}
+
+func requireBody(t *testing.T, response *http.Response, body string) {
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Equal(t, body, string(data))
+}
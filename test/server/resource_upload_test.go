@@ -0,0 +1,186 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/test"
+)
+
+func TestResourceUpload(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	s3Server := test.NewS3Server(t)
+	config, err := json.Marshal(&apiv1.StorageS3Config{
+		EndPoint:  s3Server.URL,
+		Region:    "us-east-1",
+		AccessKey: "test_access_key",
+		SecretKey: "test_secret_key",
+		Bucket:    "memos",
+		Path:      "resources/{filename}",
+		Private:   true,
+	})
+	require.NoError(t, err)
+	storage, err := s.server.Store.CreateStorage(ctx, &store.Storage{
+		Name:   "test_storage",
+		Type:   apiv1.StorageS3.String(),
+		Config: string(config),
+	})
+	require.NoError(t, err)
+
+	// The uploads larger than the limit are refused at once.
+	response := s.sendResourceUpload(t, http.MethodPost, "/api/v1/resource/upload", strings.NewReader(`{"filename":"test.txt","size":33554433}`), nil)
+	require.Equal(t, http.StatusBadRequest, response.StatusCode)
+	response.Body.Close()
+
+	for _, storageID := range []int32{apiv1.DatabaseStorage, apiv1.LocalStorage, storage.ID} {
+		_, err := s.server.Store.UpsertSystemSetting(ctx, &store.SystemSetting{
+			Name:  apiv1.SystemSettingStorageServiceIDName.String(),
+			Value: strconv.Itoa(int(storageID)),
+		})
+		require.NoError(t, err)
+		filename := fmt.Sprintf("test_%d.txt", storageID)
+		content := fmt.Sprintf("0123456789_%d", storageID)
+
+		response := s.sendResourceUpload(t, http.MethodPost, "/api/v1/resource/upload", strings.NewReader(fmt.Sprintf(`{"filename":"%s","type":"text/plain","size":%d}`, filename, len(content))), nil)
+		upload := requireResourceUpload(t, response, 0)
+		require.Equal(t, fmt.Sprintf("/api/v1/resource/upload/%d", upload.ID), response.Header.Get("Location"))
+		uri := fmt.Sprintf("/api/v1/resource/upload/%d", upload.ID)
+
+		// The chunks are received at the offset only.
+		response = s.sendResourceUpload(t, http.MethodPatch, uri, strings.NewReader(content[:5]), map[string]string{"Upload-Offset": "0"})
+		requireResourceUpload(t, response, 5)
+		response = s.sendResourceUpload(t, http.MethodPatch, uri, strings.NewReader(content[:5]), map[string]string{"Upload-Offset": "0"})
+		require.Equal(t, http.StatusConflict, response.StatusCode)
+		require.Equal(t, "5", response.Header.Get("Upload-Offset"))
+		response.Body.Close()
+		response = s.sendResourceUpload(t, http.MethodHead, uri, nil, nil)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, "5", response.Header.Get("Upload-Offset"))
+		require.Equal(t, strconv.Itoa(len(content)), response.Header.Get("Upload-Length"))
+		response.Body.Close()
+		response = s.sendResourceUpload(t, http.MethodPost, uri+"/finalize", nil, nil)
+		require.Equal(t, http.StatusConflict, response.StatusCode)
+		response.Body.Close()
+		response = s.sendResourceUpload(t, http.MethodPatch, uri, strings.NewReader(content[5:]+"extra"), map[string]string{"Upload-Offset": "5"})
+		require.Equal(t, http.StatusRequestEntityTooLarge, response.StatusCode)
+		response.Body.Close()
+		response = s.sendResourceUpload(t, http.MethodPatch, uri, strings.NewReader(content[5:]), map[string]string{"Upload-Offset": "5"})
+		requireResourceUpload(t, response, int64(len(content)))
+
+		response = s.sendResourceUpload(t, http.MethodPost, uri+"/finalize", nil, nil)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		resource := &apiv1.Resource{}
+		require.NoError(t, json.NewDecoder(response.Body).Decode(resource))
+		response.Body.Close()
+		require.Equal(t, filename, resource.Filename)
+		require.Equal(t, int64(len(content)), resource.Size)
+		storeResource, err := s.server.Store.GetResource(ctx, &store.FindResource{ID: &resource.ID})
+		require.NoError(t, err)
+		checksum := sha256.Sum256([]byte(content))
+		require.Equal(t, hex.EncodeToString(checksum[:]), storeResource.Hash)
+		response = s.getWithHeader(t, fmt.Sprintf("/o/r/%d", resource.ID), http.Header{})
+		requireBody(t, response, content)
+
+		// The upload and its parts are deleted.
+		response = s.sendResourceUpload(t, http.MethodGet, uri, nil, nil)
+		require.Equal(t, http.StatusNotFound, response.StatusCode)
+		response.Body.Close()
+		require.NoDirExists(t, filepath.Join(s.profile.Data, ".upload_cache", strconv.Itoa(int(upload.ID))))
+	}
+	require.Equal(t, []string{fmt.Sprintf("memos/resources/test_%d.txt", storage.ID)}, s3Server.Objects())
+}
+
+func TestPurgeResourceUploads(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	uploads := []*apiv1.ResourceUpload{}
+	for i := 0; i < 2; i++ {
+		response := s.sendResourceUpload(t, http.MethodPost, "/api/v1/resource/upload", strings.NewReader(`{"filename":"test.txt","size":10}`), nil)
+		upload := requireResourceUpload(t, response, 0)
+		response = s.sendResourceUpload(t, http.MethodPatch, fmt.Sprintf("/api/v1/resource/upload/%d", upload.ID), bytes.NewReader([]byte("01234")), map[string]string{"Upload-Offset": "0"})
+		requireResourceUpload(t, response, 5)
+		uploads = append(uploads, upload)
+	}
+	partDir := func(upload *apiv1.ResourceUpload) string {
+		return filepath.Join(s.profile.Data, ".upload_cache", strconv.Itoa(int(upload.ID)))
+	}
+	require.DirExists(t, partDir(uploads[0]))
+
+	// Only the upload abandoned is purged.
+	updatedTs := time.Now().Add(-apiv1.ResourceUploadExpiration - time.Minute).Unix()
+	_, err = s.server.Store.UpdateResourceUpload(ctx, &store.UpdateResourceUpload{
+		ID:        uploads[0].ID,
+		UpdatedTs: &updatedTs,
+	})
+	require.NoError(t, err)
+	count, err := apiv1.PurgeResourceUploads(ctx, s.server.Store)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.NoDirExists(t, partDir(uploads[0]))
+	require.DirExists(t, partDir(uploads[1]))
+	list, err := s.server.Store.ListResourceUploads(ctx, &store.FindResourceUpload{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, uploads[1].ID, list[0].ID)
+
+	// The upload can also be canceled.
+	response := s.sendResourceUpload(t, http.MethodDelete, fmt.Sprintf("/api/v1/resource/upload/%d", uploads[1].ID), nil, nil)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	response.Body.Close()
+	require.NoDirExists(t, partDir(uploads[1]))
+}
+
+// sendResourceUpload sends a request of the resource uploads with the cookie, and returns the response as it is.
+func (s *TestingServer) sendResourceUpload(t *testing.T, method string, uri string, body io.Reader, header map[string]string) *http.Response {
+	request, err := http.NewRequest(method, fmt.Sprintf("http://localhost:%d%s", s.profile.Port, uri), body)
+	require.NoError(t, err)
+	request.Header.Set("Cookie", s.cookie)
+	for key, value := range header {
+		request.Header.Set(key, value)
+	}
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	return response
+}
+
+func requireResourceUpload(t *testing.T, response *http.Response, offset int64) *apiv1.ResourceUpload {
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	upload := &apiv1.ResourceUpload{}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(upload))
+	require.Equal(t, offset, upload.Offset)
+	require.Equal(t, strconv.FormatInt(offset, 10), response.Header.Get("Upload-Offset"))
+	return upload
+}
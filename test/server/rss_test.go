@@ -0,0 +1,81 @@
+package testserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	storepb "github.com/usememos/memos/proto/gen/store"
+)
+
+func TestUserRSSSavedFilter(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	user, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	for _, content := range []string{"#work first", "#home second"} {
+		_, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+			Content:    content,
+			Visibility: apiv1.Public,
+		})
+		require.NoError(t, err)
+	}
+	_, err = s.server.Store.UpsertUserSettingV1(ctx, &storepb.UserSetting{
+		UserId: user.ID,
+		Key:    storepb.UserSettingKey_USER_SETTING_SAVED_FILTERS,
+		Value: &storepb.UserSetting_SavedFilters{
+			SavedFilters: &storepb.SavedFiltersUserSetting{
+				SavedFilters: []*storepb.SavedFiltersUserSetting_SavedFilter{
+					{Name: "work", Filter: `tag == "work"`},
+					{Name: "broken", Filter: `tag ==`},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	rss, err := s.getUserRSS(user.ID, "work")
+	require.NoError(t, err)
+	require.Contains(t, rss, "first")
+	require.NotContains(t, rss, "second")
+	notFound, err := s.getUserRSS(user.ID, "missing")
+	require.NoError(t, err)
+	require.NotContains(t, notFound, "<rss")
+
+	// The stored filter which fails to parse is a server error.
+	_, err = s.getUserRSS(user.ID, "broken")
+	require.ErrorContains(t, err, "500")
+
+	// The filters can't be told from the missing ones without the access token of the user.
+	s.cookie = ""
+	for _, name := range []string{"work", "broken", "missing"} {
+		response, err := s.getUserRSS(user.ID, name)
+		require.NoError(t, err)
+		require.Equal(t, notFound, response)
+	}
+}
+
+func (s *TestingServer) getUserRSS(userID int32, filter string) (string, error) {
+	body, err := s.get(fmt.Sprintf("/u/%d/rss.xml", userID), map[string]string{
+		"filter": filter,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	rss, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	return string(rss), nil
+}
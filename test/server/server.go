@@ -1,7 +1,9 @@
 package testserver
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +13,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
 	// sqlite driver.
 	_ "modernc.org/sqlite"
 
@@ -131,7 +134,7 @@ func (s *TestingServer) request(method, uri string, body io.Reader, params, head
 	}
 
 	if method == "POST" {
-		if strings.Contains(uri, "/api/v1/auth/login") || strings.Contains(uri, "/api/v1/auth/signup") {
+		if strings.Contains(uri, "/api/v1/auth/signin") || strings.Contains(uri, "/api/v1/auth/signup") {
 			cookie := ""
 			h := resp.Header.Get("Set-Cookie")
 			parts := strings.Split(h, "; ")
@@ -179,3 +182,29 @@ func (s *TestingServer) delete(url string, params map[string]string) (io.ReadClo
 		"Cookie": s.cookie,
 	})
 }
+
+// invokeV2 calls the method of the v2 API through gRPC-Web, such as "/memos.api.v2.MemoService/ListMemos".
+func (s *TestingServer) invokeV2(method string, request, response proto.Message) error {
+	rawData, err := proto.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request")
+	}
+	// The messages of gRPC-Web are framed by a flag byte and their lengths.
+	frame := append([]byte{0}, binary.BigEndian.AppendUint32(nil, uint32(len(rawData)))...)
+	body, err := s.request("POST", method, bytes.NewReader(append(frame, rawData...)), nil, map[string]string{
+		"Cookie":       s.cookie,
+		"Content-Type": "application/grpc-web+proto",
+	})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return errors.Wrap(err, "fail to read response body")
+	}
+	if len(data) < 5 || data[0] != 0 || len(data) < 5+int(binary.BigEndian.Uint32(data[1:])) {
+		return errors.Errorf("no message in the response %q", data)
+	}
+	return proto.Unmarshal(data[5:5+binary.BigEndian.Uint32(data[1:])], response)
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTPServer is a SFTP server of a directory, which authenticates the username and the password.
+type SFTPServer struct {
+	Host string
+	Port int
+	// HostKey is the public key of the server in the authorized_keys format.
+	HostKey string
+
+	listener net.Listener
+}
+
+func NewSFTPServer(t *testing.T, dir string, username string, password string) *SFTPServer {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, p []byte) (*ssh.Permissions, error) {
+			if conn.User() != username || string(p) != password {
+				return nil, ssh.ErrNoAuth
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &SFTPServer{
+		Host:     host,
+		Port:     portNumber,
+		HostKey:  string(ssh.MarshalAuthorizedKey(signer.PublicKey())),
+		listener: listener,
+	}
+	go s.serve(config, dir)
+	return s
+}
+
+func (s *SFTPServer) serve(config *ssh.ServerConfig, dir string) {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go serveSFTPConn(conn, config, dir)
+	}
+}
+
+func serveSFTPConn(conn net.Conn, config *ssh.ServerConfig, dir string) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for request := range requests {
+				// The payload of the subsystem request is the length-prefixed name of the subsystem.
+				ok := request.Type == "subsystem" && len(request.Payload) > 4 && string(request.Payload[4:]) == "sftp"
+				_ = request.Reply(ok, nil)
+			}
+		}()
+		go func() {
+			defer channel.Close()
+			server, err := sftp.NewServer(channel, sftp.WithServerWorkingDirectory(dir))
+			if err != nil {
+				return
+			}
+			_ = server.Serve()
+		}()
+	}
+}
@@ -0,0 +1,173 @@
+package teststorage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/plugin/storage"
+	"github.com/usememos/memos/plugin/storage/local"
+	"github.com/usememos/memos/plugin/storage/s3"
+	"github.com/usememos/memos/plugin/storage/sftp"
+	"github.com/usememos/memos/plugin/storage/webdav"
+	"github.com/usememos/memos/test"
+)
+
+func TestLocalBackend(t *testing.T) {
+	testBackend(t, local.New(t.TempDir()))
+}
+
+func TestS3Backend(t *testing.T) {
+	s3Server := test.NewS3Server(t)
+	client, err := s3.NewClient(context.Background(), &s3.Config{
+		AccessKey: "test_access_key",
+		SecretKey: "test_secret_key",
+		Bucket:    "memos",
+		EndPoint:  s3Server.URL,
+		Region:    "us-east-1",
+	})
+	require.NoError(t, err)
+	testBackend(t, client)
+}
+
+func TestWebDAVBackend(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "memos"), os.ModePerm))
+	webdavServer := test.NewWebDAVServer(t, dir, "test", "test_password")
+	client, err := webdav.NewClient(&webdav.Config{
+		URL:      webdavServer.URL + "/memos",
+		Username: "test",
+		Password: "test_password",
+	})
+	require.NoError(t, err)
+	testBackend(t, client)
+
+	client.Config.Password = "wrong_password"
+	_, err = client.Stat(context.Background(), "a/b/test.txt")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestSFTPBackend(t *testing.T) {
+	sftpServer := test.NewSFTPServer(t, t.TempDir(), "test", "test_password")
+	client, err := sftp.NewClient(&sftp.Config{
+		Host:     sftpServer.Host,
+		Port:     sftpServer.Port,
+		Username: "test",
+		Password: "test_password",
+		HostKey:  sftpServer.HostKey,
+	})
+	require.NoError(t, err)
+	testBackend(t, client)
+
+	// The server of another host key is rejected.
+	otherServer := test.NewSFTPServer(t, t.TempDir(), "test", "test_password")
+	client, err = sftp.NewClient(&sftp.Config{
+		Host:     otherServer.Host,
+		Port:     otherServer.Port,
+		Username: "test",
+		Password: "test_password",
+		HostKey:  sftpServer.HostKey,
+	})
+	require.NoError(t, err)
+	_, err = client.Stat(context.Background(), "a/b/test.txt")
+	require.Error(t, err)
+
+	// The host key is required unless it's skipped explicitly.
+	_, err = sftp.NewClient(&sftp.Config{
+		Host:     otherServer.Host,
+		Port:     otherServer.Port,
+		Username: "test",
+		Password: "test_password",
+	})
+	require.ErrorIs(t, err, sftp.ErrHostKeyRequired)
+	client, err = sftp.NewClient(&sftp.Config{
+		Host:                  otherServer.Host,
+		Port:                  otherServer.Port,
+		Username:              "test",
+		Password:              "test_password",
+		InsecureIgnoreHostKey: true,
+	})
+	require.NoError(t, err)
+	_, err = client.Stat(context.Background(), "a/b/test.txt")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+// testBackend tests the behaviors all the backends share.
+func testBackend(t *testing.T, backend storage.Backend) {
+	ctx := context.Background()
+	key := "a/b/test.txt"
+	content := "0123456789"
+
+	_, err := backend.Stat(ctx, key)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = backend.Get(ctx, key)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	require.NoError(t, backend.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"))
+	objectInfo, err := backend.Stat(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(content)), objectInfo.Size)
+	require.False(t, objectInfo.ModTime.IsZero())
+	requireObject(t, backend, key, 0, -1, content)
+	requireObject(t, backend, key, 2, 3, "234")
+	requireObject(t, backend, key, 7, -1, "789")
+	requireObject(t, backend, key, 0, 1, "0")
+
+	// The object is read from where it's seeked.
+	reader := storage.NewReadSeeker(ctx, backend, key, objectInfo.Size)
+	requireRead(t, reader, 3, "012")
+	_, err = reader.Seek(5, io.SeekStart)
+	require.NoError(t, err)
+	requireRead(t, reader, 2, "56")
+	_, err = reader.Seek(1, io.SeekCurrent)
+	require.NoError(t, err)
+	requireRead(t, reader, 10, "89")
+	_, err = reader.Seek(-4, io.SeekEnd)
+	require.NoError(t, err)
+	requireRead(t, reader, 1, "6")
+	require.NoError(t, reader.Close())
+
+	// The object is replaced as a whole.
+	require.NoError(t, backend.Put(ctx, key, strings.NewReader("new"), 3, "text/plain"))
+	requireObject(t, backend, key, 0, -1, "new")
+
+	// The objects directly in the directory are listed, without the ones in the subdirectories.
+	require.NoError(t, backend.Put(ctx, "a/b/c/other.txt", strings.NewReader("other"), 5, "text/plain"))
+	objects, err := backend.(storage.Lister).List(ctx, "a/b")
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	require.Equal(t, key, objects[0].Key)
+	require.Equal(t, int64(3), objects[0].Size)
+	require.False(t, objects[0].ModTime.IsZero())
+	objects, err = backend.(storage.Lister).List(ctx, "missing")
+	require.NoError(t, err)
+	require.Empty(t, objects)
+	require.NoError(t, backend.Delete(ctx, "a/b/c/other.txt"))
+
+	require.NoError(t, backend.Delete(ctx, key))
+	_, err = backend.Stat(ctx, key)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	// Deleting a missing object succeeds.
+	require.NoError(t, backend.Delete(ctx, key))
+}
+
+func requireObject(t *testing.T, backend storage.Backend, key string, offset int64, length int64, content string) {
+	reader, err := backend.GetRange(context.Background(), key, offset, length)
+	require.NoError(t, err)
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, content, string(data))
+}
+
+func requireRead(t *testing.T, reader io.Reader, n int64, content string) {
+	data, err := io.ReadAll(io.LimitReader(reader, n))
+	require.NoError(t, err)
+	require.Equal(t, content, string(data))
+}
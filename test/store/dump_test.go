@@ -0,0 +1,165 @@
+package teststore
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	storepb "github.com/usememos/memos/proto/gen/store"
+	"github.com/usememos/memos/store"
+)
+
+func TestDumpAndRestore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	_, err = ts.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  "test_setting",
+		Value: "test_value",
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertUserSetting(ctx, &store.UserSetting{
+		UserID: user.ID,
+		Key:    "locale",
+		Value:  "\"en\"",
+	})
+	require.NoError(t, err)
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_content",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	comment, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_comment",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoRelation(ctx, &store.MemoRelation{
+		MemoID:        comment.ID,
+		RelatedMemoID: memo.ID,
+		Type:          store.MemoRelationComment,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+		MemoID: memo.ID,
+		UserID: user.ID,
+		Pinned: true,
+	})
+	require.NoError(t, err)
+	content := "test_content_2"
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:      memo.ID,
+		Content: &content,
+	})
+	require.NoError(t, err)
+	deleted := store.Deleted
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        comment.ID,
+		RowStatus: &deleted,
+	})
+	require.NoError(t, err)
+	resource, err := ts.CreateResource(ctx, &store.Resource{
+		CreatorID: user.ID,
+		Filename:  "test.txt",
+		Blob:      []byte("test_blob"),
+		Type:      "text/plain",
+		Size:      9,
+		MemoID:    &memo.ID,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertTag(ctx, &store.Tag{
+		Name:      "test_tag",
+		CreatorID: user.ID,
+	})
+	require.NoError(t, err)
+	activity, err := ts.CreateActivity(ctx, &store.Activity{
+		CreatorID: user.ID,
+		Type:      store.ActivityTypeMemoComment,
+		Level:     store.ActivityLevelInfo,
+		Payload: &storepb.ActivityPayload{
+			MemoComment: &storepb.ActivityMemoCommentPayload{
+				MemoId:        comment.ID,
+				RelatedMemoId: memo.ID,
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = ts.CreateInbox(ctx, &store.Inbox{
+		SenderID:   user.ID,
+		ReceiverID: user.ID,
+		Status:     store.UNREAD,
+		Message: &storepb.InboxMessage{
+			Type:       storepb.InboxMessage_TYPE_MEMO_COMMENT,
+			ActivityId: &activity.ID,
+		},
+	})
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	err = ts.Dump(ctx, buf)
+	require.NoError(t, err)
+	archive := buf.Bytes()
+
+	// The archive can only be restored into an empty database.
+	_, err = ts.Restore(ctx, bytes.NewReader(archive))
+	require.Error(t, err)
+
+	restored := NewTestingStore(ctx, t)
+	header, err := restored.Restore(ctx, bytes.NewReader(archive))
+	require.NoError(t, err)
+	require.Equal(t, store.DumpFormat, header.Format)
+
+	restoredUser, err := restored.GetUser(ctx, &store.FindUser{ID: &user.ID})
+	require.NoError(t, err)
+	require.Equal(t, user.Username, restoredUser.Username)
+	require.Equal(t, user.PasswordHash, restoredUser.PasswordHash)
+	systemSetting, err := restored.GetSystemSetting(ctx, &store.FindSystemSetting{Name: "test_setting"})
+	require.NoError(t, err)
+	require.Equal(t, "test_value", systemSetting.Value)
+	userSettings, err := restored.ListUserSettings(ctx, &store.FindUserSetting{UserID: &user.ID})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(userSettings))
+
+	restoredMemo, err := restored.GetMemo(ctx, &store.FindMemo{ID: &memo.ID})
+	require.NoError(t, err)
+	require.Equal(t, "test_content_2", restoredMemo.Content)
+	require.Equal(t, memo.CreatedTs, restoredMemo.CreatedTs)
+	require.True(t, restoredMemo.Pinned)
+	require.Equal(t, []int32{resource.ID}, restoredMemo.ResourceIDList)
+	deletedMemos, err := restored.ListMemos(ctx, &store.FindMemo{RowStatus: &deleted})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(deletedMemos))
+	require.Equal(t, comment.ID, deletedMemos[0].ID)
+	require.Equal(t, memo.ID, *deletedMemos[0].ParentID)
+	memoRevisions, err := restored.ListMemoRevisions(ctx, &store.FindMemoRevision{MemoID: &memo.ID})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(memoRevisions))
+	require.Equal(t, "test_content", memoRevisions[0].Content)
+
+	restoredResource, err := restored.GetResource(ctx, &store.FindResource{ID: &resource.ID, GetBlob: true})
+	require.NoError(t, err)
+	require.Equal(t, []byte("test_blob"), restoredResource.Blob)
+	tags, err := restored.ListTags(ctx, &store.FindTag{CreatorID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(tags))
+	restoredActivity, err := restored.GetActivity(ctx, &store.FindActivity{ID: &activity.ID})
+	require.NoError(t, err)
+	require.Equal(t, comment.ID, restoredActivity.Payload.MemoComment.MemoId)
+	inboxes, err := restored.ListInboxes(ctx, &store.FindInbox{ReceiverID: &user.ID})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(inboxes))
+	require.Equal(t, activity.ID, *inboxes[0].Message.ActivityId)
+
+	// The store of the restored database keeps working with new rows.
+	_, err = restored.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_content_3",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+}
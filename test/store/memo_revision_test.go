@@ -0,0 +1,101 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/store"
+)
+
+func TestMemoRevisionStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_content",
+		Visibility: store.Private,
+	})
+	require.NoError(t, err)
+
+	// Updating with the same content doesn't create a revision.
+	content := "test_content"
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:      memo.ID,
+		Content: &content,
+	})
+	require.NoError(t, err)
+	memoRevisions, err := ts.ListMemoRevisions(ctx, &store.FindMemoRevision{
+		MemoID: &memo.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(memoRevisions))
+
+	content = "test_content_2"
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:      memo.ID,
+		Content: &content,
+	})
+	require.NoError(t, err)
+	visibility := store.Public
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:         memo.ID,
+		Visibility: &visibility,
+	})
+	require.NoError(t, err)
+	memoRevisions, err = ts.ListMemoRevisions(ctx, &store.FindMemoRevision{
+		MemoID: &memo.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(memoRevisions))
+	require.Equal(t, "test_content_2", memoRevisions[0].Content)
+	require.Equal(t, store.Private, memoRevisions[0].Visibility)
+	require.Equal(t, "test_content", memoRevisions[1].Content)
+
+	// The revisions older than the retention are removed by the purge, not by the updates.
+	_, err = ts.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  store.MemoRevisionRetentionSettingName,
+		Value: "1",
+	})
+	require.NoError(t, err)
+	_, err = ts.CreateMemoRevision(ctx, &store.MemoRevision{
+		MemoID:     memo.ID,
+		CreatedTs:  1,
+		Content:    "outdated_content",
+		Visibility: store.Private,
+	})
+	require.NoError(t, err)
+	content = "test_content_3"
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:      memo.ID,
+		Content: &content,
+	})
+	require.NoError(t, err)
+	memoRevisions, err = ts.ListMemoRevisions(ctx, &store.FindMemoRevision{
+		MemoID: &memo.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, len(memoRevisions))
+	require.NoError(t, ts.PurgeMemoRevisions(ctx))
+	memoRevisions, err = ts.ListMemoRevisions(ctx, &store.FindMemoRevision{
+		MemoID: &memo.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(memoRevisions))
+	for _, memoRevision := range memoRevisions {
+		require.NotEqual(t, "outdated_content", memoRevision.Content)
+	}
+
+	err = ts.DeleteMemo(ctx, &store.DeleteMemo{
+		ID: memo.ID,
+	})
+	require.NoError(t, err)
+	memoRevisions, err = ts.ListMemoRevisions(ctx, &store.FindMemoRevision{
+		MemoID: &memo.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(memoRevisions))
+}
@@ -50,3 +50,415 @@ func TestMemoStore(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 0, len(memoList))
 }
+
+func TestMemoSearch(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	contents := []string{
+		"Buy milk and bread #shopping",
+		"Meeting notes: the MILK supplier is late, ask about milk again",
+		"工作很忙 milk",
+		"Private diary",
+	}
+	memos := []*store.Memo{}
+	for _, content := range contents {
+		memo, err := ts.CreateMemo(ctx, &store.Memo{
+			CreatorID:  user.ID,
+			Content:    content,
+			Visibility: store.Public,
+		})
+		require.NoError(t, err)
+		memos = append(memos, memo)
+	}
+
+	results, err := ts.SearchMemos(ctx, &store.SearchMemo{
+		Query: "milk",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(results))
+	for i, result := range results {
+		if i > 0 {
+			require.GreaterOrEqual(t, results[i-1].Rank, result.Rank)
+		}
+		if result.MemoID == memos[1].ID {
+			require.Equal(t, "Meeting notes: the <mark>MILK</mark> supplier is late, ask about <mark>milk</mark> again", result.Snippet)
+		}
+	}
+
+	results, err = ts.SearchMemos(ctx, &store.SearchMemo{
+		Query: "工作 milk",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(results))
+	require.Equal(t, memos[2].ID, results[0].MemoID)
+	require.Equal(t, "<mark>工作</mark>很忙 <mark>milk</mark>", results[0].Snippet)
+
+	// The wildcards of LIKE in the terms are matched literally.
+	results, err = ts.SearchMemos(ctx, &store.SearchMemo{
+		Query: "%",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(results))
+
+	// The index follows the updates and deletions of memos.
+	content := "Buy eggs"
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:      memos[0].ID,
+		Content: &content,
+	})
+	require.NoError(t, err)
+	err = ts.DeleteMemo(ctx, &store.DeleteMemo{
+		ID: memos[1].ID,
+	})
+	require.NoError(t, err)
+	results, err = ts.SearchMemos(ctx, &store.SearchMemo{
+		Query: "milk",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(results))
+	require.Equal(t, memos[2].ID, results[0].MemoID)
+
+	privateVisibility := store.Private
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:         memos[3].ID,
+		Visibility: &privateVisibility,
+	})
+	require.NoError(t, err)
+	results, err = ts.SearchMemos(ctx, &store.SearchMemo{
+		Query:          "diary",
+		VisibilityList: []store.Visibility{store.Public},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(results))
+
+	_, err = ts.SearchMemos(ctx, &store.SearchMemo{
+		Query: " ",
+	})
+	require.Error(t, err)
+}
+
+func TestMemoSearchCursor(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	// Some results share the same rank and created_ts, so the pages are split by the created_ts and the ID.
+	contents := []string{"milk", "milk and bread", "milk", "milk milk", "bread and milk and eggs", "milk"}
+	for i, content := range contents {
+		_, err := ts.CreateMemo(ctx, &store.Memo{
+			CreatorID:  user.ID,
+			Content:    content,
+			Visibility: store.Public,
+			CreatedTs:  1700000000 + int64(i%2),
+		})
+		require.NoError(t, err)
+	}
+	results, err := ts.SearchMemos(ctx, &store.SearchMemo{
+		Query: "milk",
+	})
+	require.NoError(t, err)
+	require.Equal(t, len(contents), len(results))
+
+	limit := 2
+	var cursor *store.PageCursor
+	pagedResults := []*store.MemoSearchResult{}
+	for {
+		page, err := ts.SearchMemos(ctx, &store.SearchMemo{
+			Query:  "milk",
+			Limit:  &limit,
+			Cursor: cursor,
+		})
+		require.NoError(t, err)
+		pagedResults = append(pagedResults, page...)
+		if len(page) < limit {
+			break
+		}
+		lastResult := page[len(page)-1]
+		cursor = &store.PageCursor{
+			Rank: lastResult.Rank,
+			Ts:   lastResult.CreatedTs,
+			ID:   lastResult.MemoID,
+		}
+	}
+	require.Equal(t, results, pagedResults)
+
+	// The memos of the results are listed in one query.
+	memoIDList := []int32{results[0].MemoID, results[1].MemoID}
+	memoList, err := ts.ListMemos(ctx, &store.FindMemo{
+		IDList: memoIDList,
+	})
+	require.NoError(t, err)
+	require.Len(t, memoList, 2)
+	require.ElementsMatch(t, memoIDList, []int32{memoList[0].ID, memoList[1].ID})
+}
+
+func TestBuildSearchSnippet(t *testing.T) {
+	tests := []struct {
+		content  string
+		terms    []string
+		expected string
+	}{
+		{
+			content:  `<script>alert("milk")</script> & milk`,
+			terms:    []string{"milk"},
+			expected: `&lt;script&gt;alert(&#34;<mark>milk</mark>&#34;)&lt;/script&gt; &amp; <mark>milk</mark>`,
+		},
+		{
+			content:  "a <b>bold</b> term",
+			terms:    []string{"<b>"},
+			expected: "a <mark>&lt;b&gt;</mark>bold&lt;/b&gt; term",
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, store.BuildSearchSnippet(test.content, test.terms))
+	}
+}
+
+func TestMemoFilter(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	workMemo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "Prepare the slides #work",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	todoMemo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "- [ ] todo #life",
+		Visibility: store.Private,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+		MemoID: todoMemo.ID,
+		UserID: user.ID,
+		Pinned: true,
+	})
+	require.NoError(t, err)
+	workshopMemo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "#workshop at 100% of_the capacity, #work/meeting",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	// The memos pinned by the other users aren't pinned for the user.
+	_, err = ts.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+		MemoID: workMemo.ID,
+		UserID: user.ID + 1,
+		Pinned: true,
+	})
+	require.NoError(t, err)
+	_, err = ts.CreateResource(ctx, &store.Resource{
+		CreatorID: user.ID,
+		Filename:  "slides.pdf",
+		Type:      "application/pdf",
+		MemoID:    &workMemo.ID,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		filter   *store.MemoFilter
+		expected []int32
+	}{
+		{
+			// The subtags match their parent tags, while the longer tags don't.
+			filter:   &store.MemoFilter{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldTag, Value: "work"},
+			expected: []int32{workshopMemo.ID, workMemo.ID},
+		},
+		{
+			filter:   &store.MemoFilter{Operator: store.MemoFilterNotEqual, Field: store.MemoFilterFieldTag, Value: "work/"},
+			expected: []int32{todoMemo.ID, workshopMemo.ID, workMemo.ID},
+		},
+		{
+			filter:   &store.MemoFilter{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldTag, Value: "wor_"},
+			expected: []int32{},
+		},
+		{
+			filter:   &store.MemoFilter{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldPinned, Value: true, UserID: user.ID},
+			expected: []int32{todoMemo.ID},
+		},
+		{
+			filter:   &store.MemoFilter{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldPinned, Value: true, UserID: user.ID + 1},
+			expected: []int32{workMemo.ID},
+		},
+		{
+			filter:   &store.MemoFilter{Operator: store.MemoFilterNotEqual, Field: store.MemoFilterFieldHasResource, Value: true},
+			expected: []int32{todoMemo.ID, workshopMemo.ID},
+		},
+		{
+			// The wildcards of LIKE are matched literally.
+			filter:   &store.MemoFilter{Operator: store.MemoFilterContains, Field: store.MemoFilterFieldContent, Value: "100% of_"},
+			expected: []int32{workshopMemo.ID},
+		},
+		{
+			filter:   &store.MemoFilter{Operator: store.MemoFilterContains, Field: store.MemoFilterFieldContent, Value: "100%_of"},
+			expected: []int32{},
+		},
+		{
+			filter: &store.MemoFilter{
+				Operator: store.MemoFilterOr,
+				Children: []*store.MemoFilter{
+					{Operator: store.MemoFilterContains, Field: store.MemoFilterFieldContent, Value: "slides"},
+					{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldVisibility, Value: store.Private},
+				},
+			},
+			expected: []int32{todoMemo.ID, workMemo.ID},
+		},
+		{
+			filter: &store.MemoFilter{
+				Operator: store.MemoFilterAnd,
+				Children: []*store.MemoFilter{
+					{Operator: store.MemoFilterEqual, Field: store.MemoFilterFieldCreator, Value: user.Username},
+					{
+						Operator: store.MemoFilterNot,
+						Children: []*store.MemoFilter{
+							{Operator: store.MemoFilterGreaterEqual, Field: store.MemoFilterFieldCreatedTs, Value: workMemo.CreatedTs},
+						},
+					},
+				},
+			},
+			expected: []int32{},
+		},
+	}
+
+	for _, test := range tests {
+		memoList, err := ts.ListMemos(ctx, &store.FindMemo{
+			Filter: test.filter,
+		})
+		require.NoError(t, err)
+		memoIDs := []int32{}
+		for _, memo := range memoList {
+			memoIDs = append(memoIDs, memo.ID)
+		}
+		require.Equal(t, test.expected, memoIDs)
+	}
+
+	_, err = ts.ListMemos(ctx, &store.FindMemo{
+		Filter: &store.MemoFilter{Operator: store.MemoFilterLess, Field: store.MemoFilterFieldTag, Value: "work"},
+	})
+	require.Error(t, err)
+}
+
+func TestMemoListCursor(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	// The memos share the same created_ts, so the pages are split by the pinned status and the ID.
+	memoIDs := []int32{}
+	for i := 0; i < 5; i++ {
+		memo, err := ts.CreateMemo(ctx, &store.Memo{
+			CreatorID:  user.ID,
+			Content:    "test_content",
+			Visibility: store.Public,
+			CreatedTs:  1700000000,
+		})
+		require.NoError(t, err)
+		memoIDs = append(memoIDs, memo.ID)
+	}
+	_, err = ts.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+		MemoID: memoIDs[1],
+		UserID: user.ID,
+		Pinned: true,
+	})
+	require.NoError(t, err)
+	// The memos pinned by the other users are listed as unpinned.
+	otherUser, err := ts.CreateUser(ctx, &store.User{
+		Username: "other",
+		Role:     store.RoleUser,
+		Email:    "other@test.com",
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+		MemoID: memoIDs[3],
+		UserID: otherUser.ID,
+		Pinned: true,
+	})
+	require.NoError(t, err)
+
+	limit := 2
+	var cursor *store.PageCursor
+	listedIDs := []int32{}
+	for {
+		memoList, err := ts.ListMemos(ctx, &store.FindMemo{
+			CreatorID: &user.ID,
+			Limit:     &limit,
+			Cursor:    cursor,
+		})
+		require.NoError(t, err)
+		for _, memo := range memoList {
+			listedIDs = append(listedIDs, memo.ID)
+		}
+		if len(memoList) < limit {
+			break
+		}
+		lastMemo := memoList[len(memoList)-1]
+		cursor = &store.PageCursor{
+			Pinned: lastMemo.Pinned,
+			Ts:     lastMemo.CreatedTs,
+			ID:     lastMemo.ID,
+		}
+	}
+	require.Equal(t, []int32{memoIDs[1], memoIDs[4], memoIDs[3], memoIDs[2], memoIDs[0]}, listedIDs)
+}
+
+func TestMemoListComments(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	createMemo := func(relatedMemoID int32, relationType store.MemoRelationType) *store.Memo {
+		memo, err := ts.CreateMemo(ctx, &store.Memo{
+			CreatorID:  user.ID,
+			Content:    "test_content",
+			Visibility: store.Public,
+		})
+		require.NoError(t, err)
+		if relatedMemoID != 0 {
+			_, err = ts.UpsertMemoRelation(ctx, &store.MemoRelation{
+				MemoID:        memo.ID,
+				RelatedMemoID: relatedMemoID,
+				Type:          relationType,
+			})
+			require.NoError(t, err)
+		}
+		return memo
+	}
+	parent := createMemo(0, "")
+	commentIDs := []int32{}
+	for i := 0; i < 3; i++ {
+		commentIDs = append(commentIDs, createMemo(parent.ID, store.MemoRelationComment).ID)
+	}
+	// Neither the references nor the comments of the other memos are listed.
+	createMemo(parent.ID, store.MemoRelationReference)
+	createMemo(commentIDs[0], store.MemoRelationComment)
+
+	limit := 2
+	var cursor *store.PageCursor
+	listedIDs := []int32{}
+	for {
+		memoList, err := ts.ListMemos(ctx, &store.FindMemo{
+			ParentID: &parent.ID,
+			Limit:    &limit,
+			Cursor:   cursor,
+		})
+		require.NoError(t, err)
+		for _, memo := range memoList {
+			listedIDs = append(listedIDs, memo.ID)
+		}
+		if len(memoList) < limit {
+			break
+		}
+		lastMemo := memoList[len(memoList)-1]
+		cursor = &store.PageCursor{
+			Pinned: lastMemo.Pinned,
+			Ts:     lastMemo.CreatedTs,
+			ID:     lastMemo.ID,
+		}
+	}
+	require.Equal(t, []int32{commentIDs[2], commentIDs[1], commentIDs[0]}, listedIDs)
+}
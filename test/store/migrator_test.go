@@ -0,0 +1,67 @@
+package teststore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/usememos/memos/server/version"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/store/db"
+	"github.com/usememos/memos/test"
+)
+
+func TestMigrateProdLatestSchema(t *testing.T) {
+	ctx := context.Background()
+	profile := test.GetTestingProfile(t)
+	if profile.Driver != "sqlite" {
+		t.Skip("the prod schema is migrated into a new database file")
+	}
+	profile.Mode = "prod"
+	profile.DSN = fmt.Sprintf("%s/memos_prod.db", profile.Data)
+	profile.Version = version.GetCurrentVersion(profile.Mode)
+
+	migrate := func() store.Driver {
+		dbDriver, err := db.NewDBDriver(profile)
+		require.NoError(t, err)
+		require.NoError(t, dbDriver.Migrate(ctx))
+		return dbDriver
+	}
+	// The fresh install is recorded in the version of the prod LATEST schema, so its migrations aren't applied again.
+	migrate().Close()
+	dbDriver := migrate()
+	defer dbDriver.Close()
+	versions := []string{}
+	rows, err := dbDriver.GetDB().QueryContext(ctx, "SELECT version FROM migration_history")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var version string
+		require.NoError(t, rows.Scan(&version))
+		versions = append(versions, version)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, []string{version.DevVersion}, versions)
+
+	// The prod LATEST schema has the revisions, the search and the trash of the memos.
+	ts := store.New(dbDriver, profile)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_content",
+		Visibility: store.Private,
+	})
+	require.NoError(t, err)
+	content, rowStatus := "test_content_2", store.Deleted
+	require.NoError(t, ts.UpdateMemo(ctx, &store.UpdateMemo{ID: memo.ID, Content: &content}))
+	memoRevisions, err := ts.ListMemoRevisions(ctx, &store.FindMemoRevision{MemoID: &memo.ID})
+	require.NoError(t, err)
+	require.Len(t, memoRevisions, 1)
+	results, err := ts.SearchMemos(ctx, &store.SearchMemo{Query: "content_2"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, ts.UpdateMemo(ctx, &store.UpdateMemo{ID: memo.ID, RowStatus: &rowStatus}))
+}
@@ -51,12 +51,121 @@ func TestResourceStore(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, notFoundResource)
 
-	err = ts.DeleteResource(ctx, &store.DeleteResource{
+	_, err = ts.DeleteResource(ctx, &store.DeleteResource{
 		ID: 1,
 	})
 	require.NoError(t, err)
-	err = ts.DeleteResource(ctx, &store.DeleteResource{
+	_, err = ts.DeleteResource(ctx, &store.DeleteResource{
 		ID: 2,
 	})
 	require.NoError(t, err)
 }
+
+func TestResourceSharedBlob(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	holder, err := ts.CreateResource(ctx, &store.Resource{
+		CreatorID: user.ID,
+		Filename:  "test.txt",
+		Blob:      []byte("test"),
+		Type:      "text/plain",
+		Size:      4,
+		Hash:      "test_hash",
+	})
+	require.NoError(t, err)
+	shared, err := ts.CreateResource(ctx, &store.Resource{
+		CreatorID:  user.ID,
+		Filename:   "test_shared.txt",
+		Type:       "text/plain",
+		Size:       4,
+		Hash:       "test_hash",
+		SharedBlob: true,
+	})
+	require.NoError(t, err)
+
+	// The ranges of the blob are read from the resource holding it.
+	holderID, size, err := ts.GetResourceBlobHolder(ctx, shared)
+	require.NoError(t, err)
+	require.Equal(t, holder.ID, holderID)
+	require.Equal(t, int64(4), size)
+	for find, want := range map[store.FindResourceBlob]string{
+		{ID: holderID, Offset: 1, Length: 2}:   "es",
+		{ID: holderID, Offset: 2, Length: -1}:  "st",
+		{ID: holderID, Offset: 10, Length: -1}: "",
+	} {
+		find := find
+		blob, err := ts.GetResourceBlobRange(ctx, &find)
+		require.NoError(t, err)
+		require.Equal(t, int64(4), blob.Size)
+		require.Equal(t, want, string(blob.Blob))
+	}
+	missingID := shared.ID + 1
+	blobRange, err := ts.GetResourceBlobRange(ctx, &store.FindResourceBlob{ID: missingID, Length: -1})
+	require.NoError(t, err)
+	require.Nil(t, blobRange)
+
+	// The blob is handed over to the other resource sharing it.
+	last, err := ts.DeleteResource(ctx, &store.DeleteResource{
+		ID: holder.ID,
+	})
+	require.NoError(t, err)
+	require.False(t, last)
+	blob, err := ts.GetResourceBlob(ctx, shared)
+	require.NoError(t, err)
+	require.Equal(t, []byte("test"), blob)
+	last, err = ts.DeleteResource(ctx, &store.DeleteResource{
+		ID: shared.ID,
+	})
+	require.NoError(t, err)
+	require.True(t, last)
+
+	// The blob can't be shared once its last resource is deleted.
+	_, err = ts.CreateResource(ctx, &store.Resource{
+		CreatorID:  user.ID,
+		Filename:   "test_shared.txt",
+		Type:       "text/plain",
+		Size:       4,
+		Hash:       "test_hash",
+		SharedBlob: true,
+	})
+	require.ErrorIs(t, err, store.ErrResourceBlobDeleted)
+}
+
+func TestResourceListCursor(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	resourceIDs := []int32{}
+	for i := 0; i < 3; i++ {
+		resource, err := ts.CreateResource(ctx, &store.Resource{
+			CreatorID: 101,
+			Filename:  "test.txt",
+			Blob:      []byte("test"),
+			Type:      "text/plain",
+			Size:      4,
+			CreatedTs: 1700000000,
+		})
+		require.NoError(t, err)
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+
+	limit := 2
+	resourceList, err := ts.ListResources(ctx, &store.FindResource{
+		Limit: &limit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(resourceList))
+	require.Equal(t, resourceIDs[2], resourceList[0].ID)
+	require.Equal(t, resourceIDs[1], resourceList[1].ID)
+	resourceList, err = ts.ListResources(ctx, &store.FindResource{
+		Limit: &limit,
+		Cursor: &store.PageCursor{
+			Ts: resourceList[1].CreatedTs,
+			ID: resourceList[1].ID,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(resourceList))
+	require.Equal(t, resourceIDs[0], resourceList[0].ID)
+}
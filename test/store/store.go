@@ -7,6 +7,8 @@ import (
 
 	// mysql driver.
 	_ "github.com/go-sql-driver/mysql"
+	// postgres driver.
+	_ "github.com/lib/pq"
 	// sqlite driver.
 	_ "modernc.org/sqlite"
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	storepb "github.com/usememos/memos/proto/gen/store"
 	"github.com/usememos/memos/store"
 )
 
@@ -30,3 +31,35 @@ func TestUserSettingStore(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 2, len(list))
 }
+
+func TestUserSettingSavedFilters(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	savedFilters, err := ts.GetUserSavedFilters(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(savedFilters))
+	_, err = ts.UpsertUserSettingV1(ctx, &storepb.UserSetting{
+		UserId: user.ID,
+		Key:    storepb.UserSettingKey_USER_SETTING_SAVED_FILTERS,
+		Value: &storepb.UserSetting_SavedFilters{
+			SavedFilters: &storepb.SavedFiltersUserSetting{
+				SavedFilters: []*storepb.SavedFiltersUserSetting_SavedFilter{
+					{
+						Name:   "work",
+						Filter: `tag == "work" && !has_resource`,
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	list, err := ts.ListUserSettingsV1(ctx, &store.FindUserSettingV1{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(list))
+	require.Equal(t, "work", list[0].GetSavedFilters().SavedFilters[0].Name)
+	require.Equal(t, `tag == "work" && !has_resource`, list[0].GetSavedFilters().SavedFilters[0].Filter)
+}
@@ -1,10 +1,16 @@
 package test
 
 import (
+	"database/sql"
 	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"testing"
+	"time"
+
+	// postgres driver.
+	_ "github.com/lib/pq"
 
 	"github.com/usememos/memos/server/profile"
 	"github.com/usememos/memos/server/version"
@@ -33,6 +39,9 @@ func GetTestingProfile(t *testing.T) *profile.Profile {
 	if driver == "sqlite" {
 		dsn = fmt.Sprintf("%s/memos_%s.db", dir, mode)
 	}
+	if driver == "postgres" {
+		dsn = createPostgresTestingSchema(t, dsn)
+	}
 	println("dsn", dsn, driver)
 	return &profile.Profile{
 		Mode:    mode,
@@ -51,3 +60,35 @@ func getDriverFromEnv() string {
 	}
 	return driver
 }
+
+// createPostgresTestingSchema creates an empty schema for the test in the database of the URL DSN,
+// and returns the DSN whose search path starts with it, so that every test migrates its own tables.
+func createPostgresTestingSchema(t *testing.T, dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("failed to parse postgres dsn: %v", err)
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open postgres: %v", err)
+	}
+	defer db.Close()
+
+	schema := fmt.Sprintf("memos_test_%d", time.Now().UnixNano())
+	if _, err := db.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("failed to create schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			return
+		}
+		defer db.Close()
+		_, _ = db.Exec("DROP SCHEMA " + schema + " CASCADE")
+	})
+
+	query := u.Query()
+	query.Set("search_path", schema+",public")
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -1,4 +1,4 @@
-import { Button, Input, Typography } from "@mui/joy";
+import { Button, Checkbox, Input, Typography } from "@mui/joy";
 import { useEffect, useState } from "react";
 import { toast } from "react-hot-toast";
 import * as api from "@/helpers/api";
@@ -29,6 +29,8 @@ const CreateStorageServiceDialog: React.FC<Props> = (props: Props) => {
     bucket: "",
     urlPrefix: "",
     urlSuffix: "",
+    private: false,
+    presignExpiry: 0,
   });
   const isCreating = storage === undefined;
 
@@ -220,6 +222,30 @@ const CreateStorageServiceDialog: React.FC<Props> = (props: Props) => {
           onChange={(e) => setPartialS3Config({ urlSuffix: e.target.value })}
           fullWidth
         />
+        <Checkbox
+          className="mb-2"
+          label={t("setting.storage-section.private")}
+          checked={s3Config.private}
+          onChange={(e) => setPartialS3Config({ private: e.target.checked })}
+        />
+        {s3Config.private && (
+          <>
+            <Typography className="!mb-1" level="body-sm">
+              {t("setting.storage-section.private-description")}
+            </Typography>
+            <Typography className="!mb-1" level="body-md">
+              {t("setting.storage-section.presign-expiry")}
+            </Typography>
+            <Input
+              className="mb-2"
+              type="number"
+              placeholder={t("setting.storage-section.presign-expiry-placeholder")}
+              value={s3Config.presignExpiry}
+              onChange={(e) => setPartialS3Config({ presignExpiry: Math.max(0, parseInt(e.target.value) || 0) })}
+              fullWidth
+            />
+          </>
+        )}
         <div className="mt-2 w-full flex flex-row justify-end items-center space-x-1">
           <Button variant="plain" color="neutral" onClick={handleCloseBtnClick}>
             {t("common.cancel")}
@@ -1,4 +1,5 @@
 import { useLayoutStore } from "../store/module";
+import SavedFilterList from "./SavedFilterList";
 import SearchBar from "./SearchBar";
 import TagList from "./TagList";
 import UsageHeatMap from "./UsageHeatMap";
@@ -28,6 +29,7 @@ const HomeSidebar = () => {
           <SearchBar />
         </div>
         <UsageHeatMap />
+        <SavedFilterList />
         <TagList />
       </aside>
     </div>
@@ -10,8 +10,8 @@ const MemoFilter = () => {
   const location = useLocation();
   const filterStore = useFilterStore();
   const filter = filterStore.state;
-  const { tag: tagQuery, duration, text: textQuery, visibility } = filter;
-  const showFilter = Boolean(tagQuery || (duration && duration.from < duration.to) || textQuery || visibility);
+  const { tag: tagQuery, duration, text: textQuery, visibility, savedFilter } = filter;
+  const showFilter = Boolean(tagQuery || (duration && duration.from < duration.to) || textQuery || visibility || savedFilter);
 
   useEffect(() => {
     filterStore.clearFilter();
@@ -76,6 +76,18 @@ const MemoFilter = () => {
         <Icon.Search className="w-4 h-auto mr-1 text-gray-500 dark:text-gray-400" /> {textQuery}
         <Icon.X className="w-4 h-auto ml-1 opacity-40" />
       </div>
+      <div
+        className={
+          "max-w-xs flex flex-row justify-start items-center px-2 mr-2 cursor-pointer dark:text-gray-300 bg-gray-200 dark:bg-zinc-700 rounded whitespace-nowrap truncate hover:line-through " +
+          (savedFilter ? "" : "!hidden")
+        }
+        onClick={() => {
+          filterStore.setSavedFilter(undefined);
+        }}
+      >
+        <Icon.Filter className="w-4 h-auto mr-1 text-gray-500 dark:text-gray-400" /> {savedFilter}
+        <Icon.X className="w-4 h-auto ml-1 opacity-40" />
+      </div>
     </div>
   );
 };
@@ -1,6 +1,7 @@
 import { useEffect, useState } from "react";
 import { toast } from "react-hot-toast";
 import { useParams } from "react-router-dom";
+import { memoServiceClient } from "@/grpcweb";
 import { DEFAULT_MEMO_LIMIT } from "@/helpers/consts";
 import { getTimeStampByDate } from "@/helpers/datetime";
 import useCurrentUser from "@/hooks/useCurrentUser";
@@ -19,9 +20,11 @@ const MemoList: React.FC = () => {
   const { memos } = memoStore.state;
   const [isFetching, setIsFetching] = useState<boolean>(true);
   const [isComplete, setIsComplete] = useState<boolean>(false);
+  // savedFilterMemoIds are the ids of the memos matched by the saved filter, which is evaluated by the server.
+  const [savedFilterMemoIds, setSavedFilterMemoIds] = useState<Set<MemoId>>(new Set());
   const user = useCurrentUser();
-  const { tag: tagQuery, duration, text: textQuery, visibility } = filter;
-  const showMemoFilter = Boolean(tagQuery || (duration && duration.from < duration.to) || textQuery || visibility);
+  const { tag: tagQuery, duration, text: textQuery, visibility, savedFilter } = filter;
+  const showMemoFilter = Boolean(tagQuery || (duration && duration.from < duration.to) || textQuery || visibility || savedFilter);
   const username = params.username || user?.username || "";
 
   const shownMemos = (
@@ -58,6 +61,9 @@ const MemoList: React.FC = () => {
           if (visibility) {
             shouldShow = memo.visibility === visibility;
           }
+          if (savedFilter && !savedFilterMemoIds.has(memo.id)) {
+            shouldShow = false;
+          }
 
           return shouldShow;
         })
@@ -90,6 +96,23 @@ const MemoList: React.FC = () => {
       });
   }, [user?.username]);
 
+  useEffect(() => {
+    if (!savedFilter) {
+      setSavedFilterMemoIds(new Set());
+      return;
+    }
+
+    memoServiceClient
+      .listMemos({
+        savedFilter: savedFilter,
+      })
+      .then(({ memos }) => setSavedFilterMemoIds(new Set(memos.map((memo) => memo.id))))
+      .catch((error) => {
+        console.error(error);
+        toast.error(error.details);
+      });
+  }, [savedFilter]);
+
   useEffect(() => {
     const pageWrapper = document.body.querySelector(".page-wrapper");
     if (pageWrapper) {
@@ -0,0 +1,71 @@
+import { useEffect, useState } from "react";
+import { userServiceClient } from "@/grpcweb";
+import useCurrentUser from "@/hooks/useCurrentUser";
+import { useFilterStore } from "@/store/module";
+import { SavedFilter } from "@/types/proto/api/v2/user_service";
+import { useTranslate } from "@/utils/i18n";
+import Icon from "./Icon";
+
+const SavedFilterList = () => {
+  const t = useTranslate();
+  const user = useCurrentUser();
+  const filterStore = useFilterStore();
+  const filter = filterStore.state;
+  const [savedFilters, setSavedFilters] = useState<SavedFilter[]>([]);
+
+  useEffect(() => {
+    if (!user) {
+      return;
+    }
+
+    userServiceClient
+      .listUserSavedFilters({
+        username: user.username,
+      })
+      .then(({ savedFilters }) => setSavedFilters(savedFilters))
+      .catch((error) => {
+        console.error(error);
+      });
+  }, [user?.username]);
+
+  const handleSavedFilterClick = (savedFilter: SavedFilter) => {
+    if (filter.savedFilter === savedFilter.name) {
+      filterStore.setSavedFilter(undefined);
+    } else {
+      filterStore.setSavedFilter(savedFilter.name);
+    }
+  };
+
+  if (savedFilters.length === 0) {
+    return null;
+  }
+
+  return (
+    <div className="flex flex-col justify-start items-start w-full mt-2 h-auto shrink-0 flex-nowrap hide-scrollbar">
+      <div className="flex flex-row justify-start items-center w-full px-4">
+        <span className="text-sm leading-6 font-mono text-gray-400">{t("common.saved-filters")}</span>
+      </div>
+      <div className="flex flex-col justify-start items-start relative w-full h-auto flex-nowrap mt-2 mb-2">
+        {savedFilters.map((savedFilter) => (
+          <div
+            key={savedFilter.name}
+            className="relative group flex flex-row justify-between items-center w-full h-10 py-0 px-4 mt-px first:mt-1 rounded-lg text-base cursor-pointer select-none shrink-0 hover:opacity-60"
+            title={savedFilter.filter}
+            onClick={() => handleSavedFilterClick(savedFilter)}
+          >
+            <div
+              className={`flex flex-row justify-start items-center truncate shrink leading-5 mr-1 text-black dark:text-gray-200 ${
+                filter.savedFilter === savedFilter.name && "text-green-600"
+              }`}
+            >
+              <Icon.Filter className="block w-4 h-auto shrink-0 mr-1 opacity-60" />
+              <span className="truncate">{savedFilter.name}</span>
+            </div>
+          </div>
+        ))}
+      </div>
+    </div>
+  );
+};
+
+export default SavedFilterList;
@@ -1,4 +1,6 @@
 import { Button } from "@mui/joy";
+import { toast } from "react-hot-toast";
+import * as api from "@/helpers/api";
 import useCurrentUser from "@/hooks/useCurrentUser";
 import { useTranslate } from "@/utils/i18n";
 import showChangePasswordDialog from "../ChangePasswordDialog";
@@ -10,6 +12,28 @@ const MyAccountSection = () => {
   const t = useTranslate();
   const user = useCurrentUser();
 
+  const handleExportData = () => {
+    window.open("/api/v1/export");
+  };
+
+  const handleImportData = async (event: React.ChangeEvent<HTMLInputElement>) => {
+    const file = event.target.files?.[0];
+    event.target.value = "";
+    if (!file) {
+      return;
+    }
+
+    const formData = new FormData();
+    formData.append("file", file, file.name);
+    try {
+      const { data: result } = await api.importUserData(formData);
+      toast.success(t("setting.account-section.import-data-succeed", { count: result.memoCount }));
+    } catch (error: any) {
+      console.error(error);
+      toast.error(error.response.data.message);
+    }
+  };
+
   return (
     <>
       <div className="section-container account-section-container">
@@ -29,6 +53,15 @@ const MyAccountSection = () => {
             {t("setting.account-section.change-password")}
           </Button>
         </div>
+        <div className="w-full flex flex-row justify-start items-center mt-2 space-x-2">
+          <Button variant="outlined" onClick={handleExportData}>
+            {t("setting.account-section.export-data")}
+          </Button>
+          <Button variant="outlined" component="label">
+            {t("setting.account-section.import-data")}
+            <input type="file" accept=".zip" hidden onChange={handleImportData} />
+          </Button>
+        </div>
 
         <AccessTokenSection />
       </div>
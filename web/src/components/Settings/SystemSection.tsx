@@ -1,9 +1,11 @@
-import { Button, Divider, Input, Switch, Textarea, Tooltip } from "@mui/joy";
+import { Button, Divider, Input, Option, Select, Switch, Textarea, Tooltip } from "@mui/joy";
 import { useEffect, useState } from "react";
 import { toast } from "react-hot-toast";
+import { systemServiceClient } from "@/grpcweb";
 import * as api from "@/helpers/api";
 import { formatBytes } from "@/helpers/utils";
 import { useGlobalStore } from "@/store/module";
+import { BackupConfig, BackupStatus } from "@/types/proto/api/v2/system_service";
 import { useTranslate } from "@/utils/i18n";
 import { showCommonDialog } from "../Dialog/CommonDialog";
 import showDisablePasswordLoginDialog from "../DisablePasswordLoginDialog";
@@ -20,8 +22,10 @@ interface State {
   additionalStyle: string;
   additionalScript: string;
   maxUploadSizeMiB: number;
-  autoBackupInterval: number;
   memoDisplayWithUpdatedTs: boolean;
+  memoRevisionRetention: number;
+  trashRetention: number;
+  stripImageMetadata: boolean;
 }
 
 const SystemSection = () => {
@@ -36,10 +40,15 @@ const SystemSection = () => {
     additionalScript: systemStatus.additionalScript,
     disablePublicMemos: systemStatus.disablePublicMemos,
     maxUploadSizeMiB: systemStatus.maxUploadSizeMiB,
-    autoBackupInterval: systemStatus.autoBackupInterval,
     memoDisplayWithUpdatedTs: systemStatus.memoDisplayWithUpdatedTs,
+    memoRevisionRetention: systemStatus.memoRevisionRetention,
+    trashRetention: systemStatus.trashRetention,
+    stripImageMetadata: systemStatus.stripImageMetadata,
   });
   const [telegramBotToken, setTelegramBotToken] = useState<string>("");
+  const [backupConfig, setBackupConfig] = useState<BackupConfig>(BackupConfig.fromPartial({ cron: "0 0 * * *" }));
+  const [backupStatus, setBackupStatus] = useState<BackupStatus>();
+  const [storageList, setStorageList] = useState<ObjectStorage[]>([]);
 
   useEffect(() => {
     globalStore.fetchSystemStatus();
@@ -54,6 +63,18 @@ const SystemSection = () => {
     });
   }, []);
 
+  useEffect(() => {
+    systemServiceClient.getSystemInfo({}).then(({ systemInfo }) => {
+      if (systemInfo?.backupConfig) {
+        setBackupConfig(systemInfo.backupConfig);
+      }
+      setBackupStatus(systemInfo?.backupStatus);
+    });
+    api.getStorageList().then(({ data: storageList }) => {
+      setStorageList(storageList);
+    });
+  }, []);
+
   useEffect(() => {
     setState({
       ...state,
@@ -64,8 +85,10 @@ const SystemSection = () => {
       additionalScript: systemStatus.additionalScript,
       disablePublicMemos: systemStatus.disablePublicMemos,
       maxUploadSizeMiB: systemStatus.maxUploadSizeMiB,
-      autoBackupInterval: systemStatus.autoBackupInterval,
-      memoDisplayWithUpdatedTs: systemStatus.memoDisplayWithUpdatedTs,
+        memoDisplayWithUpdatedTs: systemStatus.memoDisplayWithUpdatedTs,
+      memoRevisionRetention: systemStatus.memoRevisionRetention,
+      trashRetention: systemStatus.trashRetention,
+      stripImageMetadata: systemStatus.stripImageMetadata,
     });
   }, [systemStatus]);
 
@@ -187,6 +210,18 @@ const SystemSection = () => {
     });
   };
 
+  const handleStripImageMetadataChanged = async (value: boolean) => {
+    setState({
+      ...state,
+      stripImageMetadata: value,
+    });
+    globalStore.setSystemStatus({ stripImageMetadata: value });
+    await api.upsertSystemSetting({
+      name: "strip-image-metadata",
+      value: JSON.stringify(value),
+    });
+  };
+
   const handleMemoDisplayWithUpdatedTs = async (value: boolean) => {
     setState({
       ...state,
@@ -223,7 +258,54 @@ const SystemSection = () => {
     event.target.select();
   };
 
-  const handleAutoBackupIntervalChanged = async (event: React.FocusEvent<HTMLInputElement>) => {
+  const handleBackupConfigChanged = (config: Partial<BackupConfig>) => {
+    setBackupConfig({
+      ...backupConfig,
+      ...config,
+    });
+  };
+
+  const handleSaveBackupConfig = async () => {
+    try {
+      await systemServiceClient.updateSystemInfo({
+        systemInfo: {
+          backupConfig,
+        },
+        updateMask: ["backup_config"],
+      });
+    } catch (error: any) {
+      toast.error(error.details);
+      console.error(error);
+      return;
+    }
+    toast.success(t("message.update-succeed"));
+  };
+
+  const handleMemoRevisionRetentionChanged = async (event: React.FocusEvent<HTMLInputElement>) => {
+    // fixes cursor skipping position on mobile
+    event.target.selectionEnd = event.target.value.length;
+
+    let num = parseInt(event.target.value);
+    if (Number.isNaN(num)) {
+      num = 0;
+    }
+    setState({
+      ...state,
+      memoRevisionRetention: num,
+    });
+    event.target.value = num.toString();
+    globalStore.setSystemStatus({ memoRevisionRetention: num });
+    await api.upsertSystemSetting({
+      name: "memo-revision-retention",
+      value: JSON.stringify(num),
+    });
+  };
+
+  const handleMemoRevisionRetentionFocus = (event: React.FocusEvent<HTMLInputElement>) => {
+    event.target.select();
+  };
+
+  const handleTrashRetentionChanged = async (event: React.FocusEvent<HTMLInputElement>) => {
     // fixes cursor skipping position on mobile
     event.target.selectionEnd = event.target.value.length;
 
@@ -233,17 +315,17 @@ const SystemSection = () => {
     }
     setState({
       ...state,
-      autoBackupInterval: num,
+      trashRetention: num,
     });
     event.target.value = num.toString();
-    globalStore.setSystemStatus({ autoBackupInterval: num });
+    globalStore.setSystemStatus({ trashRetention: num });
     await api.upsertSystemSetting({
-      name: "auto-backup-interval",
+      name: "trash-retention",
       value: JSON.stringify(num),
     });
   };
 
-  const handleAutoBackupIntervalFocus = (event: React.FocusEvent<HTMLInputElement>) => {
+  const handleTrashRetentionFocus = (event: React.FocusEvent<HTMLInputElement>) => {
     event.target.select();
   };
 
@@ -279,6 +361,15 @@ const SystemSection = () => {
         <span className="normal-text">{t("setting.system-section.display-with-updated-time")}</span>
         <Switch checked={state.memoDisplayWithUpdatedTs} onChange={(event) => handleMemoDisplayWithUpdatedTs(event.target.checked)} />
       </div>
+      <div className="form-label">
+        <div className="flex flex-row items-center">
+          <span className="text-sm mr-1">{t("setting.system-section.strip-image-metadata")}</span>
+          <Tooltip title={t("setting.system-section.strip-image-metadata-hint")} placement="top">
+            <Icon.HelpCircle className="w-4 h-auto" />
+          </Tooltip>
+        </div>
+        <Switch checked={state.stripImageMetadata} onChange={(event) => handleStripImageMetadataChanged(event.target.checked)} />
+      </div>
       <div className="form-label">
         <div className="flex flex-row items-center">
           <span className="text-sm mr-1">{t("setting.system-section.max-upload-size")}</span>
@@ -298,8 +389,93 @@ const SystemSection = () => {
       </div>
       <div className="form-label">
         <div className="flex flex-row items-center">
-          <span className="text-sm mr-1">{t("setting.system-section.auto-backup-interval")}</span>
-          <Tooltip title={t("setting.system-section.auto-backup-interval-hint")} placement="top">
+          <span className="text-sm mr-1">{t("setting.system-section.auto-backup")}</span>
+          <Tooltip title={t("setting.system-section.auto-backup-hint")} placement="top">
+            <Icon.HelpCircle className="w-4 h-auto" />
+          </Tooltip>
+        </div>
+        <Switch checked={backupConfig.enabled} onChange={(event) => handleBackupConfigChanged({ enabled: event.target.checked })} />
+      </div>
+      <div className="form-label">
+        <span className="text-sm mr-1">{t("setting.system-section.auto-backup-cron")}</span>
+        <Input
+          className="w-32"
+          sx={{
+            fontFamily: "monospace",
+          }}
+          value={backupConfig.cron}
+          onChange={(event) => handleBackupConfigChanged({ cron: event.target.value })}
+        />
+      </div>
+      <div className="form-label">
+        <div className="flex flex-row items-center">
+          <span className="text-sm mr-1">{t("setting.system-section.auto-backup-max-keep")}</span>
+          <Tooltip title={t("setting.system-section.auto-backup-max-keep-hint")} placement="top">
+            <Icon.HelpCircle className="w-4 h-auto" />
+          </Tooltip>
+        </div>
+        <Input
+          className="w-16"
+          sx={{
+            fontFamily: "monospace",
+          }}
+          value={backupConfig.maxKeep}
+          onChange={(event) => handleBackupConfigChanged({ maxKeep: parseInt(event.target.value) || 0 })}
+        />
+      </div>
+      <div className="form-label selector">
+        <div className="flex flex-row items-center">
+          <span className="text-sm mr-1">{t("setting.system-section.auto-backup-storage")}</span>
+          <Tooltip title={t("setting.system-section.auto-backup-storage-hint")} placement="top">
+            <Icon.HelpCircle className="w-4 h-auto" />
+          </Tooltip>
+        </div>
+        <Select
+          className="!min-w-fit"
+          value={backupConfig.storageId}
+          onChange={(_, storageId) => handleBackupConfigChanged({ storageId: storageId ?? 0 })}
+        >
+          <Option value={0}>{t("setting.system-section.auto-backup-storage-none")}</Option>
+          {storageList.map((storage) => (
+            <Option key={storage.id} value={storage.id}>
+              {storage.name}
+            </Option>
+          ))}
+        </Select>
+      </div>
+      <div className="form-label">
+        <span className="text-sm text-gray-500 truncate">
+          {backupStatus
+            ? backupStatus.error
+              ? t("setting.system-section.last-backup-failed", { error: backupStatus.error })
+              : t("setting.system-section.last-backup", { time: new Date(backupStatus.runTs * 1000).toLocaleString() })
+            : t("setting.system-section.no-backup-yet")}
+        </span>
+        <Button size="sm" onClick={handleSaveBackupConfig}>
+          {t("common.save")}
+        </Button>
+      </div>
+      <div className="form-label">
+        <div className="flex flex-row items-center">
+          <span className="text-sm mr-1">{t("setting.system-section.memo-revision-retention")}</span>
+          <Tooltip title={t("setting.system-section.memo-revision-retention-hint")} placement="top">
+            <Icon.HelpCircle className="w-4 h-auto" />
+          </Tooltip>
+        </div>
+        <Input
+          className="w-16"
+          sx={{
+            fontFamily: "monospace",
+          }}
+          defaultValue={state.memoRevisionRetention}
+          onFocus={handleMemoRevisionRetentionFocus}
+          onChange={handleMemoRevisionRetentionChanged}
+        />
+      </div>
+      <div className="form-label">
+        <div className="flex flex-row items-center">
+          <span className="text-sm mr-1">{t("setting.system-section.trash-retention")}</span>
+          <Tooltip title={t("setting.system-section.trash-retention-hint")} placement="top">
             <Icon.HelpCircle className="w-4 h-auto" />
           </Tooltip>
         </div>
@@ -308,9 +484,9 @@ const SystemSection = () => {
           sx={{
             fontFamily: "monospace",
           }}
-          defaultValue={state.autoBackupInterval}
-          onFocus={handleAutoBackupIntervalFocus}
-          onChange={handleAutoBackupIntervalChanged}
+          defaultValue={state.trashRetention}
+          onFocus={handleTrashRetentionFocus}
+          onChange={handleTrashRetentionChanged}
         />
       </div>
       <Divider className="!mt-3 !my-4" />
@@ -144,6 +144,10 @@ export function createResourceWithBlob(formData: FormData) {
   return axios.post<Resource>("/api/v1/resource/blob", formData);
 }
 
+export function importUserData(formData: FormData) {
+  return axios.post<ImportResult>("/api/v1/import", formData);
+}
+
 export function getTagSuggestionList() {
   return axios.get<string[]>(`/api/v1/tag/suggestion`);
 }
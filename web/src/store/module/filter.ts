@@ -19,6 +19,7 @@ export const useFilterStore = () => {
           duration: undefined,
           text: undefined,
           visibility: undefined,
+          savedFilter: undefined,
         })
       );
     },
@@ -57,5 +58,12 @@ export const useFilterStore = () => {
         })
       );
     },
+    setSavedFilter: (savedFilter?: string) => {
+      store.dispatch(
+        setFilter({
+          savedFilter: savedFilter,
+        })
+      );
+    },
   };
 };
@@ -17,6 +17,9 @@ export const initialGlobalState = async () => {
       disablePublicMemos: false,
       maxUploadSizeMiB: 0,
       autoBackupInterval: 0,
+      memoRevisionRetention: 0,
+      trashRetention: 30,
+      stripImageMetadata: true,
       additionalStyle: "",
       additionalScript: "",
       memoDisplayWithUpdatedTs: false,
@@ -10,6 +10,7 @@ interface State {
   duration?: Duration;
   text?: string;
   visibility?: Visibility;
+  savedFilter?: string;
 }
 
 export type Filter = State;
@@ -1 +1 @@
-type RowStatus = "NORMAL" | "ARCHIVED";
+type RowStatus = "NORMAL" | "ARCHIVED" | "DELETED";
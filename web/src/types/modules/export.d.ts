@@ -0,0 +1,5 @@
+interface ImportResult {
+  memoCount: number;
+  resourceCount: number;
+  relationCount: number;
+}
@@ -1,6 +1,6 @@
 type StorageId = number;
 
-type StorageType = "S3";
+type StorageType = "S3" | "WEBDAV" | "SFTP";
 
 interface StorageS3Config {
   endPoint: string;
@@ -11,10 +11,32 @@ interface StorageS3Config {
   bucket: string;
   urlPrefix: string;
   urlSuffix: string;
+  private: boolean;
+  presignExpiry: number;
+}
+
+interface StorageWebDAVConfig {
+  url: string;
+  path: string;
+  username: string;
+  password: string;
+}
+
+interface StorageSFTPConfig {
+  host: string;
+  port: number;
+  path: string;
+  username: string;
+  password: string;
+  privateKey: string;
+  hostKey: string;
+  insecureIgnoreHostKey: boolean;
 }
 
 interface StorageConfig {
   s3Config: StorageS3Config;
+  webdavConfig?: StorageWebDAVConfig;
+  sftpConfig?: StorageSFTPConfig;
 }
 
 // Note: Storage is a reserved word in TypeScript. So we use ObjectStorage instead.
@@ -28,6 +28,9 @@ interface SystemStatus {
   storageServiceId: number;
   localStoragePath: string;
   memoDisplayWithUpdatedTs: boolean;
+  memoRevisionRetention: number;
+  trashRetention: number;
+  stripImageMetadata: boolean;
 }
 
 interface SystemSetting {